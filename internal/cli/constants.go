@@ -41,8 +41,22 @@ const (
 // OutputFormatTree formats output as a tree
 const OutputFormatTree = "tree"
 
+// Event stream output formats
+const (
+	// OutputFormatText prints progress for people
+	OutputFormatText = "text"
+
+	// OutputFormatJSONL streams progress events as newline-delimited JSON
+	OutputFormatJSONL = "jsonl"
+)
+
 // OutputFormats contains all valid output formats
 var OutputFormats = []string{OutputFormatTable, OutputFormatJSON, OutputFormatYAML}
 
-// DeleteOutputFormats lists valid output formats for delete dry-run preview.
-var DeleteOutputFormats = []string{OutputFormatTree, OutputFormatJSON, OutputFormatYAML}
+// DeleteOutputFormats lists valid output formats for delete: the dry-run
+// preview formats, plus jsonl to stream a live delete's progress events.
+var DeleteOutputFormats = []string{OutputFormatTree, OutputFormatJSON, OutputFormatYAML, OutputFormatJSONL}
+
+// EventOutputFormats lists valid output formats for commands that stream
+// progress events (deploy, promote, run, cluster up).
+var EventOutputFormats = []string{OutputFormatText, OutputFormatJSONL}
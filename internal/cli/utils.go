@@ -16,6 +16,7 @@ package cli
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -42,6 +43,35 @@ type ReleaseViewModel struct {
 	PodCount     int            `json:"podCount" yaml:"podCount"`
 	ReadyPods    int            `json:"readyPods" yaml:"readyPods"`
 	PodStatus    string         `json:"podStatus" yaml:"podStatus"` // e.g., "3/3", "2/3", "0/3"
+	Lock         *LockViewModel `json:"lock,omitempty" yaml:"lock,omitempty"`
+	// Certificates lists the TLS certificates of selfSigned components.
+	Certificates []CertificateViewModel `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+}
+
+// CertificateViewModel is the output shape for the TLS certificate of a
+// selfSigned component (see [k8s.RenewSelfSignedCerts]).
+type CertificateViewModel struct {
+	Host      string `json:"host" yaml:"host"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Secret    string `json:"secret" yaml:"secret"`
+	ExpiresAt string `json:"expiresAt" yaml:"expiresAt"`
+	// Trusted is true when the local CA issued the certificate, so
+	// browsers that trust the CA accept it.
+	Trusted bool `json:"trusted" yaml:"trusted"`
+	// Renewed is true when this run re-issued the certificate because it
+	// was close to expiring.
+	Renewed bool `json:"renewed" yaml:"renewed"`
+}
+
+// LockViewModel is the output shape for a release lock (see
+// [k8s.GetReleaseLock]).
+type LockViewModel struct {
+	Holder     string `json:"holder" yaml:"holder"`
+	Operation  string `json:"operation,omitempty" yaml:"operation,omitempty"`
+	JobURL     string `json:"jobURL,omitempty" yaml:"jobURL,omitempty"`
+	AcquiredAt string `json:"acquiredAt,omitempty" yaml:"acquiredAt,omitempty"`
+	ExpiresAt  string `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	Expired    bool   `json:"expired" yaml:"expired"`
 }
 
 // extractDeployahLabels extracts project and environment from deployah labels
@@ -120,3 +150,82 @@ func ReleaseToViewModelWithPods(ctx context.Context, k8sClient *k8s.Client, rel
 
 	return vm
 }
+
+// LockToViewModel converts a release lock to its output shape, or returns
+// nil when lock is nil.
+func LockToViewModel(lock *k8s.LockInfo, now time.Time) *LockViewModel {
+	if lock == nil {
+		return nil
+	}
+	vm := &LockViewModel{
+		Holder:    lock.Holder,
+		Operation: lock.Operation,
+		JobURL:    lock.JobURL,
+		Expired:   lock.Expired(now),
+	}
+	if !lock.AcquiredAt.IsZero() {
+		vm.AcquiredAt = lock.AcquiredAt.Format(time.RFC3339)
+	}
+	if !lock.ExpiresAt.IsZero() {
+		vm.ExpiresAt = lock.ExpiresAt.Format(time.RFC3339)
+	}
+	return vm
+}
+
+// CertificatesToViewModel converts TLS certificates to their output shape.
+func CertificatesToViewModel(certs []k8s.SelfSignedCert) []CertificateViewModel {
+	if len(certs) == 0 {
+		return nil
+	}
+	vms := make([]CertificateViewModel, 0, len(certs))
+	for _, cert := range certs {
+		vms = append(vms, CertificateViewModel{
+			Host:      cert.Host,
+			Namespace: cert.Namespace,
+			Secret:    cert.SecretName,
+			ExpiresAt: cert.NotAfter.UTC().Format(time.RFC3339),
+			Trusted:   cert.Trusted,
+			Renewed:   cert.Renewed,
+		})
+	}
+	return vms
+}
+
+// CertificateSummary is the CERT table cell for vms: the earliest expiry
+// date, noting renewals and certificates the local CA did not issue.
+// Empty when there are no certificates.
+func CertificateSummary(vms []CertificateViewModel) string {
+	if len(vms) == 0 {
+		return ""
+	}
+	earliest := vms[0].ExpiresAt
+	renewed, untrusted := false, false
+	for _, vm := range vms {
+		earliest = min(earliest, vm.ExpiresAt) // RFC 3339 in UTC sorts by time
+		renewed = renewed || vm.Renewed
+		untrusted = untrusted || !vm.Trusted
+	}
+	summary := "expires " + strings.SplitN(earliest, "T", 2)[0]
+	if renewed {
+		summary += ", renewed"
+	}
+	if untrusted {
+		summary += ", untrusted"
+	}
+	return summary
+}
+
+// LockSummary is the LOCK table cell for vm: the holder and operation, or
+// "expired" for a lapsed lock. Empty when the release is not locked.
+func LockSummary(vm *LockViewModel) string {
+	if vm == nil {
+		return ""
+	}
+	if vm.Expired {
+		return "expired (" + vm.Holder + ")"
+	}
+	if vm.Operation == "" {
+		return vm.Holder
+	}
+	return vm.Holder + ", " + vm.Operation
+}
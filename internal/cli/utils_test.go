@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v4/pkg/release/common"
 
+	"deployah.dev/deployah/internal/k8s"
+
 	v1 "helm.sh/helm/v4/pkg/release/v1"
 )
 
@@ -224,3 +226,46 @@ func TestReleaseToViewModelWithPods_NilClient(t *testing.T) {
 	assert.Equal(t, 0, vm.ReadyPods)
 	assert.Equal(t, "0/0", vm.PodStatus)
 }
+
+// TestLockToViewModel converts a live and an expired lock, and nil to nil.
+func TestLockToViewModel(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, LockToViewModel(nil, time.Now()))
+
+	acquired := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	lock := &k8s.LockInfo{
+		Release:    "web-production",
+		LockOwner:  k8s.LockOwner{Holder: "ci@runner-7 (pid 42)", Operation: "deploy"},
+		AcquiredAt: acquired,
+		ExpiresAt:  acquired.Add(2 * time.Minute),
+	}
+
+	live := LockToViewModel(lock, acquired.Add(time.Minute))
+	assert.Equal(t, "2025-06-01T12:00:00Z", live.AcquiredAt)
+	assert.False(t, live.Expired)
+	assert.Equal(t, "ci@runner-7 (pid 42), deploy", LockSummary(live))
+
+	expired := LockToViewModel(lock, acquired.Add(time.Hour))
+	assert.True(t, expired.Expired)
+	assert.Equal(t, "expired (ci@runner-7 (pid 42))", LockSummary(expired))
+}
+
+// TestCertificateSummary reports the earliest expiry and flags renewed and
+// untrusted certificates.
+func TestCertificateSummary(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, CertificatesToViewModel(nil))
+	assert.Empty(t, CertificateSummary(nil))
+
+	vms := CertificatesToViewModel([]k8s.SelfSignedCert{
+		{Host: "web.example.test", NotAfter: time.Date(2028, 3, 1, 0, 0, 0, 0, time.UTC), Trusted: true},
+		{Host: "api.example.test", NotAfter: time.Date(2027, 12, 24, 9, 0, 0, 0, time.UTC), Trusted: true, Renewed: true},
+	})
+	assert.Equal(t, "2027-12-24T09:00:00Z", vms[1].ExpiresAt)
+	assert.Equal(t, "expires 2027-12-24, renewed", CertificateSummary(vms))
+
+	vms[0].Trusted = false
+	assert.Equal(t, "expires 2027-12-24, renewed, untrusted", CertificateSummary(vms))
+}
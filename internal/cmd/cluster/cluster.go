@@ -19,6 +19,7 @@ import (
 
 	"nabat.dev/nabat"
 
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/localkube"
 )
 
@@ -48,6 +49,8 @@ func Register(app *nabat.App) {
 	registerDown(group)
 	registerStatus(group)
 	registerKubeconfig(group)
+	registerLoad(group)
+	registerTrust(group)
 }
 
 // closeManager shuts down m, logging any error without failing the command.
@@ -58,13 +61,19 @@ func closeManager(c *nabat.Context, m *localkube.Manager) {
 }
 
 // newManager builds a localkube.Manager wired to the command's logger and a
-// progress-event handler. Callers pass extra options (Kubernetes version,
+// progress-event handler, which also feeds the --output jsonl event stream
+// when the command has one. Callers pass extra options (Kubernetes version,
 // runtime) as needed.
 func newManager(c *nabat.Context, extra ...localkube.Option) (*localkube.Manager, error) {
 	log := c.Logger()
+	logFn := logEvent(log)
+	events := eventlog.FromContext(c)
 	opts := append([]localkube.Option{
 		localkube.WithLogger(log),
-		localkube.WithEventHandler(logEvent(log)),
+		localkube.WithEventHandler(func(e localkube.Event) {
+			logFn(e)
+			emitStep(events, e)
+		}),
 	}, extra...)
 	return localkube.New(opts...)
 }
@@ -92,6 +101,8 @@ func phaseLabel(s localkube.Step) string {
 		return "joining worker nodes"
 	case localkube.StepWaitingForReady:
 		return "waiting for nodes to be ready"
+	case localkube.StepConfiguringMirrors:
+		return "configuring registry mirrors"
 	case localkube.StepDeleting:
 		return "removing cluster"
 	default:
@@ -121,3 +132,21 @@ func logEvent(log *slog.Logger) localkube.EventFunc {
 		}
 	}
 }
+
+// emitStep reports a localkube progress event as a cluster_step event. A
+// failed step without a detail carries its error message instead.
+func emitStep(events *eventlog.Stream, e localkube.Event) {
+	step := eventlog.ClusterStep{Step: string(e.Step), Detail: e.Detail}
+	switch e.Status {
+	case localkube.StepStarted:
+		step.Status = "started"
+	case localkube.StepCompleted:
+		step.Status = "completed"
+	case localkube.StepFailed:
+		step.Status = "failed"
+		if step.Detail == "" && e.Err != nil {
+			step.Detail = e.Err.Error()
+		}
+	}
+	events.Emit(eventlog.TypeClusterStep, step)
+}
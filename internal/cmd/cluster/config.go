@@ -0,0 +1,117 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"deployah.dev/deployah/internal/localkube"
+	"deployah.dev/deployah/internal/spec"
+)
+
+// loadClusterConfig returns the cluster config named by --config or, when
+// the flag is empty, deployah.cluster.yaml in the working directory. It
+// returns nil without an error when neither exists; the cluster is then a
+// single node.
+func loadClusterConfig(path string) (*spec.LocalClusterConfig, error) {
+	if path == "" {
+		if _, err := os.Stat(spec.DefaultLocalClusterPath); err != nil {
+			return nil, nil //nolint:nilnil // no config file is the common case
+		}
+		path = spec.DefaultLocalClusterPath
+	}
+	return spec.LoadLocalCluster(path)
+}
+
+// clusterCreateOptions converts cfg into Create options. Each worker group
+// expands into Count identical nodes.
+func clusterCreateOptions(cfg *spec.LocalClusterConfig) []localkube.CreateOption {
+	if cfg == nil {
+		return nil
+	}
+	nodes := []localkube.NodeConfig{nodeConfig(localkube.NodeRoleControlPlane, cfg.ControlPlane)}
+	for _, g := range cfg.Workers {
+		for range g.Nodes() {
+			nodes = append(nodes, nodeConfig(localkube.NodeRoleWorker, g.LocalClusterNode))
+		}
+	}
+	opts := []localkube.CreateOption{localkube.WithNodes(nodes...)}
+	if len(cfg.FeatureGates) > 0 {
+		opts = append(opts, localkube.WithFeatureGates(cfg.FeatureGates))
+	}
+	return opts
+}
+
+// registryMirrors merges the cluster config's registryMirrors with the
+// --registry-mirror flags, which win for the same registry. The result is
+// sorted by registry.
+func registryMirrors(cfg *spec.LocalClusterConfig, flags []string) ([]localkube.RegistryMirror, error) {
+	byRegistry := map[string]localkube.RegistryMirror{}
+	if cfg != nil {
+		for registry, endpoint := range cfg.RegistryMirrors {
+			m, err := localkube.ParseRegistryMirror(registry + "=" + endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("cluster config: %w", err)
+			}
+			byRegistry[m.Registry] = m
+		}
+	}
+	for _, f := range flags {
+		m, err := localkube.ParseRegistryMirror(f)
+		if err != nil {
+			return nil, fmt.Errorf("--registry-mirror: %w", err)
+		}
+		byRegistry[m.Registry] = m
+	}
+	mirrors := make([]localkube.RegistryMirror, 0, len(byRegistry))
+	for _, m := range byRegistry {
+		mirrors = append(mirrors, m)
+	}
+	slices.SortFunc(mirrors, func(a, b localkube.RegistryMirror) int {
+		return strings.Compare(a.Registry, b.Registry)
+	})
+	return mirrors, nil
+}
+
+// nodeConfig converts one node of the cluster config to localkube's form.
+func nodeConfig(role localkube.NodeRole, n spec.LocalClusterNode) localkube.NodeConfig {
+	node := localkube.NodeConfig{Role: role, Labels: n.Labels}
+	for _, t := range n.Taints {
+		node.Taints = append(node.Taints, localkube.Taint{
+			Key:    t.Key,
+			Value:  t.Value,
+			Effect: localkube.TaintEffect(t.Effect),
+		})
+	}
+	for _, pm := range n.PortMappings {
+		node.PortMappings = append(node.PortMappings, localkube.PortMapping{
+			HostPort:      pm.HostPort,
+			ContainerPort: pm.ContainerPort,
+			Protocol:      localkube.Protocol(pm.Protocol),
+			ListenAddress: pm.ListenAddress,
+		})
+	}
+	for _, m := range n.Mounts {
+		node.Mounts = append(node.Mounts, localkube.Mount{
+			HostPath:      m.HostPath,
+			ContainerPath: m.ContainerPath,
+			ReadOnly:      m.ReadOnly,
+		})
+	}
+	return node
+}
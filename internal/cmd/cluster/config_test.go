@@ -0,0 +1,79 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/localkube"
+	"deployah.dev/deployah/internal/spec"
+)
+
+// TestNodeConfig converts labels, taints, port mappings, and mounts of a
+// cluster config node to localkube's form.
+func TestNodeConfig(t *testing.T) {
+	got := nodeConfig(localkube.NodeRoleWorker, spec.LocalClusterNode{
+		Labels:       map[string]string{"zone": "a"},
+		Taints:       []spec.LocalClusterTaint{{Key: "dedicated", Value: "batch", Effect: "NoSchedule"}},
+		PortMappings: []spec.LocalClusterPortMapping{{HostPort: 8080, ContainerPort: 30080, Protocol: "UDP"}},
+		Mounts:       []spec.LocalClusterMount{{HostPath: "/srv", ContainerPath: "/data", ReadOnly: true}},
+	})
+
+	assert.Equal(t, localkube.NodeConfig{
+		Role:         localkube.NodeRoleWorker,
+		Labels:       map[string]string{"zone": "a"},
+		Taints:       []localkube.Taint{{Key: "dedicated", Value: "batch", Effect: localkube.TaintNoSchedule}},
+		PortMappings: []localkube.PortMapping{{HostPort: 8080, ContainerPort: 30080, Protocol: localkube.ProtocolUDP}},
+		Mounts:       []localkube.Mount{{HostPath: "/srv", ContainerPath: "/data", ReadOnly: true}},
+	}, got)
+}
+
+// TestClusterCreateOptions returns no options without a config file.
+func TestClusterCreateOptions(t *testing.T) {
+	assert.Nil(t, clusterCreateOptions(nil))
+	assert.Len(t, clusterCreateOptions(&spec.LocalClusterConfig{
+		Workers: []spec.LocalClusterWorkerGroup{{Count: 2}},
+	}), 1)
+	assert.Len(t, clusterCreateOptions(&spec.LocalClusterConfig{
+		FeatureGates: map[string]bool{"InPlacePodVerticalScaling": true},
+	}), 2)
+}
+
+// TestRegistryMirrors merges the config file's mirrors with the flags, the
+// flags winning for the same registry, and rejects malformed flags.
+func TestRegistryMirrors(t *testing.T) {
+	cfg := &spec.LocalClusterConfig{RegistryMirrors: map[string]string{
+		"docker.io": "http://file-mirror:5000",
+		"quay.io":   "https://quay-cache.example.com",
+	}}
+
+	got, err := registryMirrors(cfg, []string{"docker.io=flag-mirror:5000", "ghcr.io=http://cache:5000"})
+	require.NoError(t, err)
+	assert.Equal(t, []localkube.RegistryMirror{
+		{Registry: "docker.io", Endpoint: "http://flag-mirror:5000"},
+		{Registry: "ghcr.io", Endpoint: "http://cache:5000"},
+		{Registry: "quay.io", Endpoint: "https://quay-cache.example.com"},
+	}, got)
+
+	got, err = registryMirrors(nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = registryMirrors(nil, []string{"docker.io"})
+	assert.ErrorContains(t, err, "--registry-mirror")
+}
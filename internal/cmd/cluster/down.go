@@ -25,7 +25,8 @@ import (
 
 // downOptions holds command-line flags for "cluster down".
 type downOptions struct {
-	Force bool `nabat:"force"`
+	Force    bool `nabat:"force"`
+	Registry bool `nabat:"registry"`
 }
 
 // registerDown attaches the "down" subcommand to the cluster group.
@@ -33,14 +34,20 @@ func registerDown(group *nabat.Command) {
 	group.MustCommand("down",
 		nabat.WithDescription("Delete the local cluster and stop the cloud provider"),
 		nabat.WithLongDescription("Stop the cloud-provider-kind container (if running) and delete the local cluster. "+
-			"This is destructive and removes all workloads running in the cluster."),
+			"This is destructive and removes all workloads running in the cluster.\n\n"+
+			"The local registry started by 'cluster up --registry' keeps running so the images pushed to it "+
+			"survive; pass --registry to remove it as well."),
 		nabat.WithFlag("force", false, nabat.WithUsage("Delete without confirmation")),
+		nabat.WithFlag("registry", false, nabat.WithUsage("Also remove the local registry and the images pushed to it")),
 		nabat.WithExample(`
 # Delete the local cluster (asks for confirmation)
 deployah cluster down
 
 # Delete without confirmation
-deployah cluster down --force`),
+deployah cluster down --force
+
+# Delete the cluster and the local registry
+deployah cluster down --registry`),
 		nabat.WithRun(runDown),
 	)
 }
@@ -60,7 +67,7 @@ func runDown(c *nabat.Context) error {
 	// Nothing to delete if the cluster isn't there; skip the prompt entirely.
 	if _, getErr := m.Get(c, clusterName); errors.Is(getErr, localkube.ErrNotFound) {
 		c.Info("No local cluster found", "hint", "run 'deployah cluster up' to create one")
-		return nil
+		return downRegistry(c, m, opts.Registry)
 	}
 
 	confirmed, confirmErr := c.Confirm(
@@ -105,5 +112,24 @@ func runDown(c *nabat.Context) error {
 	}
 
 	c.Success("Local cluster deleted", "cluster", clusterName)
+	return downRegistry(c, m, opts.Registry)
+}
+
+// downRegistry removes the local registry when remove is set and otherwise
+// says it was left running. It does nothing when no registry exists.
+func downRegistry(c *nabat.Context, m *localkube.Manager, remove bool) error {
+	if _, regErr := m.RegistryStatus(c); errors.Is(regErr, localkube.ErrNotFound) || errors.Is(regErr, localkube.ErrUnsupported) {
+		return nil
+	}
+	if !remove {
+		c.Info("Local registry left running", "address", m.RegistryAddress(), "hint", "pass --registry to remove it")
+		return nil
+	}
+	if spinErr := c.Spinner(func(_ *nabat.Spinner) error {
+		return m.StopRegistry(c)
+	}, nabat.WithTitle("Removing local registry...")); spinErr != nil {
+		return fmt.Errorf("remove local registry: %w", spinErr)
+	}
+	c.Success("Local registry removed", "address", m.RegistryAddress())
 	return nil
 }
@@ -0,0 +1,153 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/localkube"
+	"deployah.dev/deployah/internal/session"
+	"deployah.dev/deployah/internal/spec"
+)
+
+// loadOptions holds command-line arguments and flags for "cluster load".
+type loadOptions struct {
+	Images   []string `nabat:"images"`
+	FromSpec string   `nabat:"from-spec"`
+}
+
+// registerLoad attaches the "load" subcommand to the cluster group.
+func registerLoad(group *nabat.Command) {
+	group.MustCommand("load",
+		nabat.WithDescription("Load container images into the local cluster"),
+		nabat.WithLongDescription("Copy images into every node of the local cluster so pods start without a registry. "+
+			"Each argument is an image in the local Docker or Podman daemon, an image in a remote registry, or a "+
+			"Docker/OCI .tar archive. --from-spec loads every image the spec's active components and tasks use in an environment.\n\n"+
+			"An image referenced as :latest, without a tag, or by digest would be pulled again when a pod starts, so it "+
+			"is also tagged <repository>:dev-<id>; deploy that reference instead."),
+		nabat.WithArg("images", []string{}, nabat.WithUsage("Images or .tar archives to load")),
+		nabat.WithFlag("from-spec", "", nabat.WithUsage("Also load every image the spec uses in this environment")),
+		nabat.WithExample(`
+# Load an image built with docker build
+deployah cluster load myapp:dev
+
+# Load an image archive
+deployah cluster load ./myapp.tar
+
+# Load every image the spec deploys to the local environment
+deployah cluster load --from-spec local`),
+		nabat.WithRun(runLoad),
+	)
+}
+
+func runLoad(c *nabat.Context) error {
+	opts := &loadOptions{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+
+	images := slices.Clone(opts.Images)
+	if opts.FromSpec != "" {
+		sess := session.FromContext(c)
+		platform, err := sess.Platform()
+		if err != nil {
+			return fmt.Errorf("load platform file: %w", err)
+		}
+		manifest, err := spec.Load(c, sess.SpecPath(), opts.FromSpec, platform)
+		if err != nil {
+			return fmt.Errorf("load spec: %w", err)
+		}
+		fromSpec, err := specImages(manifest, opts.FromSpec)
+		if err != nil {
+			return err
+		}
+		images = append(images, fromSpec...)
+	}
+	slices.Sort(images)
+	images = slices.Compact(images)
+	if len(images) == 0 {
+		return errors.New("nothing to load: pass images or archives, or --from-spec <environment>")
+	}
+
+	m, err := newManager(c)
+	if err != nil {
+		return fmt.Errorf("init local cluster manager: %w", err)
+	}
+	defer closeManager(c, m)
+
+	if _, getErr := m.Get(c, clusterName); getErr != nil {
+		if errors.Is(getErr, localkube.ErrNotFound) {
+			return errors.New("no local cluster found; run 'deployah cluster up' to create one")
+		}
+		return fmt.Errorf("get local cluster: %w", getErr)
+	}
+
+	for _, image := range images {
+		var pinned string
+		if spinErr := c.Spinner(func(_ *nabat.Spinner) error {
+			var loadErr error
+			pinned, loadErr = m.LoadImagePinned(c, clusterName, image)
+			return loadErr
+		}, nabat.WithTitle("Loading "+image)); spinErr != nil {
+			return fmt.Errorf("load %s: %w", image, spinErr)
+		}
+		if pinned != image {
+			c.Success("Loaded "+image, "as", pinned)
+			c.Warn(fmt.Sprintf("Pods pull %s again when they start; set the image to %s to run the loaded copy", image, pinned))
+			continue
+		}
+		c.Success("Loaded " + image)
+	}
+	return nil
+}
+
+// specImages returns the images of the components, sidecars, init
+// containers, and tasks of m that deploy to environment, sorted and
+// without duplicates.
+func specImages(m *spec.Spec, environment string) ([]string, error) {
+	seen := map[string]struct{}{}
+	add := func(image string) {
+		if image != "" {
+			seen[image] = struct{}{}
+		}
+	}
+	for _, component := range m.Components {
+		if len(component.Environments) > 0 {
+			if _, ok := spec.MatchEnvKey(environment, component.Environments); !ok {
+				continue
+			}
+		}
+		add(component.Image)
+		for _, ctr := range component.Sidecars {
+			add(ctr.Image)
+		}
+		for _, ctr := range component.InitContainers {
+			add(ctr.Image)
+		}
+	}
+	tasks, err := spec.EffectiveTasks(m, environment, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, rt := range tasks {
+		add(rt.Task.Image)
+	}
+	return slices.Sorted(maps.Keys(seen)), nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+)
+
+// TestSpecImages verifies only components and tasks that deploy to the
+// environment contribute images, including sidecars and tasks that run
+// their parent's image.
+func TestSpecImages(t *testing.T) {
+	t.Parallel()
+
+	m := &spec.Spec{
+		Components: map[string]spec.Component{
+			"api": {
+				Image:    "myapp/api:dev",
+				Sidecars: map[string]spec.Container{"proxy": {Image: "envoyproxy/envoy:v1.31.0"}},
+			},
+			"web":   {Image: "myapp/web:dev", InitContainers: map[string]spec.Container{"assets": {Image: "myapp/api:dev"}}},
+			"audit": {Image: "myapp/audit:dev", Environments: []string{"production"}},
+		},
+		Tasks: map[string]spec.Task{
+			"migrate": {From: "api", Image: "myapp/migrate:dev", On: spec.TaskOnManual},
+			"report":  {Image: "myapp/report:dev", On: spec.TaskOnManual, Environments: []string{"production"}},
+		},
+	}
+
+	images, err := specImages(m, "review/pr-7")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"envoyproxy/envoy:v1.31.0",
+		"myapp/api:dev",
+		"myapp/migrate:dev",
+		"myapp/web:dev",
+	}, images)
+}
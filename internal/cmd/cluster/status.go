@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
@@ -29,6 +30,8 @@ import (
 	"nabat.dev/theme"
 
 	"deployah.dev/deployah/internal/cli"
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/k8s"
 	"deployah.dev/deployah/internal/localkube"
 
 	corev1 "k8s.io/api/core/v1"
@@ -54,6 +57,41 @@ type accessEntry struct {
 	Curl      string `json:"curl,omitempty" yaml:"curl,omitempty"`
 }
 
+// registryView reports the local registry started by "cluster up --registry".
+type registryView struct {
+	Address string `json:"address" yaml:"address"`
+	Status  string `json:"status" yaml:"status"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// mirrorView reports one registry mirror set with "cluster up
+// --registry-mirror" and whether the nodes still use and reach it.
+type mirrorView struct {
+	Registry string `json:"registry" yaml:"registry"`
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	Status   string `json:"status" yaml:"status"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// localCAView reports the local CA that signs selfSigned certificates.
+type localCAView struct {
+	Path      string `json:"path" yaml:"path"`
+	Subject   string `json:"subject" yaml:"subject"`
+	ExpiresAt string `json:"expiresAt" yaml:"expiresAt"`
+}
+
+// nodeView is one node of the cluster as the API server reports it.
+type nodeView struct {
+	Name    string `json:"name" yaml:"name"`
+	Role    string `json:"role" yaml:"role"`
+	Status  string `json:"status" yaml:"status"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Labels holds the node's labels minus the ones Kubernetes and Kind set
+	// on every node.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints []string          `json:"taints,omitempty" yaml:"taints,omitempty"`
+}
+
 // clusterStatusView is the structured representation of the cluster status,
 // used for JSON/YAML output and to drive the table view.
 type clusterStatusView struct {
@@ -63,19 +101,28 @@ type clusterStatusView struct {
 	Status               string         `json:"status" yaml:"status"`
 	Nodes                int            `json:"nodes" yaml:"nodes"`
 	Roles                map[string]int `json:"roles,omitempty" yaml:"roles,omitempty"`
+	NodeDetails          []nodeView     `json:"nodeDetails,omitempty" yaml:"nodeDetails,omitempty"`
 	Context              string         `json:"context" yaml:"context"`
 	Kubeconfig           string         `json:"kubeconfig" yaml:"kubeconfig"`
 	CloudProviderRunning bool           `json:"cloudProviderRunning" yaml:"cloudProviderRunning"`
+	Registry             *registryView  `json:"registry,omitempty" yaml:"registry,omitempty"`
+	RegistryMirrors      []mirrorView   `json:"registryMirrors,omitempty" yaml:"registryMirrors,omitempty"`
 	CreatedAt            string         `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
 	Access               []accessEntry  `json:"access,omitempty" yaml:"access,omitempty"`
+	LocalCA              *localCAView   `json:"localCA,omitempty" yaml:"localCA,omitempty"`
+	// Certificates lists the TLS certificates of selfSigned components in
+	// every namespace.
+	Certificates []cli.CertificateViewModel `json:"certificates,omitempty" yaml:"certificates,omitempty"`
 }
 
 // registerStatus attaches the "status" subcommand to the cluster group.
 func registerStatus(group *nabat.Command) {
 	group.MustCommand("status",
 		nabat.WithDescription("Show the local cluster status and access info"),
-		nabat.WithLongDescription("Show the local cluster's health, metadata, whether the cloud provider is running, "+
-			"and how to reach LoadBalancer Services and Ingresses (including suggested /etc/hosts entries)."),
+		nabat.WithLongDescription("Show the local cluster's health, metadata, each node's role, readiness, labels, and taints, whether the cloud provider is running, "+
+			"the health of the local registry when one was started, whether every node is configured with and reaches each registry mirror, and how to reach LoadBalancer Services and Ingresses (including suggested /etc/hosts entries).\n\n"+
+			"It also lists the certificates of components with selfSigned TLS and when they expire, and re-issues a certificate "+
+			"that expires within 30 days with your local CA (see 'deployah cluster trust')."),
 		nabat.WithSelectFlag("output", cli.OutputFormatTable, cli.OutputFormats, nabat.WithShort('o'), nabat.WithUsage("Output format")),
 		nabat.WithExample(`
 # Show the local cluster status
@@ -123,16 +170,24 @@ func runStatus(c *nabat.Context) error {
 		Context:              m.ContextName(clusterName),
 		CloudProviderRunning: m.CloudProviderRunning(c),
 	}
+	view.Registry = registryStatus(c, m)
+	view.RegistryMirrors = registryMirrorStatus(c, m)
 	if !cl.CreatedAt.IsZero() {
 		view.CreatedAt = cl.CreatedAt.Format("2006-01-02 15:04:05 MST")
 	}
 
-	// Fetch the kubeconfig path and use it to discover access endpoints. Both
-	// are best-effort: a stopped cluster still reports its lifecycle status.
+	// Fetch the kubeconfig path and use it to list nodes and discover access
+	// endpoints. All are best-effort: a stopped cluster still reports its
+	// lifecycle status.
 	if kc, kcErr := m.KubeConfig(c, clusterName); kcErr == nil {
 		view.Kubeconfig = kc.Path()
-		gwPorts := m.GatewayPorts(c, clusterName)
-		view.Access = gatherAccess(c, kc.Bytes(), gwPorts)
+		if clientset := newClientset(c, kc.Bytes()); clientset != nil {
+			view.NodeDetails = gatherNodes(c, clientset)
+			view.Access = gatherAccess(c, clientset, m.GatewayPorts(c, clusterName))
+			ca := cmdopts.ExistingLocalCA(c)
+			view.LocalCA = localCAStatus(ca)
+			view.Certificates = cmdopts.RenewCertificates(c, clientset, ca, "", nil)
+		}
 	} else {
 		c.Logger().Debug("kubeconfig unavailable", "err", kcErr)
 	}
@@ -148,23 +203,150 @@ func runStatus(c *nabat.Context) error {
 	}
 }
 
-// gatherAccess lists LoadBalancer Services and Ingresses in the cluster and
-// builds the externally reachable endpoints. gwPorts maps container port ->
-// host port from the envoy gateway; when set, URLs use 127.0.0.1:<hostPort>
-// instead of the container IP. Errors are non-fatal: an empty slice means
-// nothing reachable (or the cluster is unreachable).
-func gatherAccess(c *nabat.Context, kubeconfig []byte, gwPorts map[uint16]uint16) []accessEntry {
+// registryStatus probes the local registry. It returns nil when no registry
+// was started or the engine cannot run one.
+func registryStatus(c *nabat.Context, m *localkube.Manager) *registryView {
+	st, err := m.RegistryStatus(c)
+	if errors.Is(err, localkube.ErrNotFound) || errors.Is(err, localkube.ErrUnsupported) {
+		return nil
+	}
+	view := &registryView{Address: m.RegistryAddress(), Status: st.String()}
+	if err != nil {
+		view.Error = err.Error()
+	}
+	return view
+}
+
+// registryMirrorStatus checks the cluster's registry mirrors on its nodes.
+// It returns nil when the cluster has none or they cannot be checked.
+func registryMirrorStatus(c *nabat.Context, m *localkube.Manager) []mirrorView {
+	statuses, err := m.VerifyRegistryMirrors(c, clusterName)
+	if err != nil {
+		c.Logger().Debug("verify registry mirrors", "err", err)
+		return nil
+	}
+	return mirrorViews(statuses)
+}
+
+// mirrorViews converts mirror checks to their status view.
+func mirrorViews(statuses []localkube.MirrorStatus) []mirrorView {
+	if len(statuses) == 0 {
+		return nil
+	}
+	views := make([]mirrorView, 0, len(statuses))
+	for _, st := range statuses {
+		v := mirrorView{Registry: st.Registry, Endpoint: st.Endpoint, Status: "ok"}
+		if st.Err != nil {
+			v.Status = "failing"
+			v.Error = st.Err.Error()
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+// localCAStatus describes ca, or returns nil when there is none.
+func localCAStatus(ca *k8s.LocalCA) *localCAView {
+	if ca == nil {
+		return nil
+	}
+	return &localCAView{
+		Path:      ca.CertPath(),
+		Subject:   ca.Subject(),
+		ExpiresAt: ca.NotAfter().UTC().Format(time.RFC3339),
+	}
+}
+
+// newClientset builds a client for the local cluster from its kubeconfig.
+// It returns nil, after logging why, when the kubeconfig cannot be used.
+func newClientset(c *nabat.Context, kubeconfig []byte) kubernetes.Interface {
 	restCfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
 	if err != nil {
-		c.Logger().Debug("parse kubeconfig for access info", "err", err)
+		c.Logger().Debug("parse kubeconfig for status", "err", err)
 		return nil
 	}
 	clientset, err := kubernetes.NewForConfig(restCfg)
 	if err != nil {
-		c.Logger().Debug("build client for access info", "err", err)
+		c.Logger().Debug("build client for status", "err", err)
+		return nil
+	}
+	return clientset
+}
+
+// gatherNodes lists the cluster's nodes. Errors are non-fatal: nil means
+// the API server is unreachable.
+func gatherNodes(c *nabat.Context, clientset kubernetes.Interface) []nodeView {
+	list, err := clientset.CoreV1().Nodes().List(c, metav1.ListOptions{})
+	if err != nil {
+		c.Logger().Debug("list nodes", "err", err)
 		return nil
 	}
+	return nodeViews(list.Items)
+}
+
+// nodeViews converts nodes to their status view, sorted control plane
+// first, then by name.
+func nodeViews(nodes []corev1.Node) []nodeView {
+	views := make([]nodeView, 0, len(nodes))
+	for i := range nodes {
+		n := &nodes[i]
+		v := nodeView{
+			Name:    n.Name,
+			Role:    "worker",
+			Status:  "NotReady",
+			Version: n.Status.NodeInfo.KubeletVersion,
+		}
+		for key, value := range n.Labels {
+			if role, ok := strings.CutPrefix(key, nodeRoleLabelPrefix); ok {
+				v.Role = role
+				continue
+			}
+			if builtinNodeLabel(key) {
+				continue
+			}
+			if v.Labels == nil {
+				v.Labels = map[string]string{}
+			}
+			v.Labels[key] = value
+		}
+		for _, cond := range n.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+				v.Status = "Ready"
+			}
+		}
+		for _, t := range n.Spec.Taints {
+			v.Taints = append(v.Taints, t.ToString())
+		}
+		views = append(views, v)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if (views[i].Role == "control-plane") != (views[j].Role == "control-plane") {
+			return views[i].Role == "control-plane"
+		}
+		return views[i].Name < views[j].Name
+	})
+	return views
+}
 
+// nodeRoleLabelPrefix prefixes the label kubeadm sets to mark a node's role.
+const nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+
+// builtinNodeLabel reports whether key is a label the kubelet or Kind sets
+// on every node, which would only bury the labels from the cluster config.
+func builtinNodeLabel(key string) bool {
+	switch key {
+	case "kubernetes.io/arch", "kubernetes.io/os", "kubernetes.io/hostname":
+		return true
+	}
+	return strings.HasPrefix(key, "beta.kubernetes.io/") || strings.HasPrefix(key, "node.kubernetes.io/")
+}
+
+// gatherAccess lists LoadBalancer Services and Ingresses in the cluster and
+// builds the externally reachable endpoints. gwPorts maps container port ->
+// host port from the envoy gateway; when set, URLs use 127.0.0.1:<hostPort>
+// instead of the container IP. Errors are non-fatal: an empty slice means
+// nothing reachable (or the cluster is unreachable).
+func gatherAccess(c *nabat.Context, clientset kubernetes.Interface, gwPorts map[uint16]uint16) []accessEntry {
 	var entries []accessEntry
 
 	svcList, err := clientset.CoreV1().Services("").List(c, metav1.ListOptions{})
@@ -329,14 +511,64 @@ func renderStatusSummary(c *nabat.Context, view clusterStatusView) {
 		{Key: "Context", Value: view.Context},
 		{Key: "Cloud provider", Value: c.Badge(statusIcon(cloudProvider), cloudProvider)},
 	}
+	if r := view.Registry; r != nil {
+		value := c.Badge(statusIcon(r.Status), r.Status) + "  " + r.Address
+		if r.Error != "" {
+			value += "  " + c.Render(theme.TextMuted, r.Error)
+		}
+		fields = append(fields, nabat.Field{Key: "Registry", Value: value})
+	}
 	if view.CreatedAt != "" {
 		fields = append(fields, nabat.Field{Key: "Created", Value: view.CreatedAt})
 	}
 	if view.Kubeconfig != "" {
 		fields = append(fields, nabat.Field{Key: "Kubeconfig", Value: shortenHome(view.Kubeconfig)})
 	}
+	if view.LocalCA != nil {
+		fields = append(fields, nabat.Field{Key: "Local CA", Value: shortenHome(view.LocalCA.Path)})
+	}
 	c.Fields(fields, nabat.WithFieldKeyWidth(14)).Print()
 
+	if len(view.NodeDetails) > 0 {
+		c.Println("")
+		c.Printf("%s\n", c.Render(theme.TextTitle, "Nodes"))
+		nodeRows := make([][]string, 0, len(view.NodeDetails))
+		for _, n := range view.NodeDetails {
+			nodeRows = append(nodeRows, []string{n.Name, n.Role, n.Status, n.Version, labelsText(n.Labels), strings.Join(n.Taints, ", ")})
+		}
+		c.Table([]string{"NAME", "ROLE", "STATUS", "VERSION", "LABELS", "TAINTS"}, nodeRows, nabat.WithTableBorder(nabat.BorderRounded()))
+	}
+
+	if len(view.RegistryMirrors) > 0 {
+		c.Println("")
+		c.Printf("%s\n", c.Render(theme.TextTitle, "Registry mirrors"))
+		mirrorRows := make([][]string, 0, len(view.RegistryMirrors))
+		for _, mv := range view.RegistryMirrors {
+			mirrorRows = append(mirrorRows, []string{mv.Registry, mv.Endpoint, c.Badge(statusIcon(mv.Status), mv.Status), mv.Error})
+		}
+		c.Table([]string{"REGISTRY", "ENDPOINT", "STATUS", "ERROR"}, mirrorRows, nabat.WithTableBorder(nabat.BorderRounded()))
+	}
+
+	if len(view.Certificates) > 0 {
+		c.Println("")
+		c.Printf("%s\n", c.Render(theme.TextTitle, "TLS certificates"))
+		certRows := make([][]string, 0, len(view.Certificates))
+		untrusted := false
+		for _, cert := range view.Certificates {
+			expires := strings.SplitN(cert.ExpiresAt, "T", 2)[0]
+			if cert.Renewed {
+				expires += " (renewed)"
+			}
+			certRows = append(certRows, []string{cert.Host, cert.Namespace, expires, boolText(cert.Trusted, "local CA", "untrusted")})
+			untrusted = untrusted || !cert.Trusted
+		}
+		c.Table([]string{"HOST", "NAMESPACE", "EXPIRES", "ISSUER"}, certRows, nabat.WithTableBorder(nabat.BorderRounded()))
+		if untrusted {
+			c.Printf("%s\n", c.Render(theme.TextMuted,
+				"Redeploy to replace untrusted certificates with ones from the local CA, and run 'deployah cluster trust --install' once to trust it."))
+		}
+	}
+
 	if len(view.Access) == 0 {
 		return
 	}
@@ -380,6 +612,16 @@ func renderStatusSummary(c *nabat.Context, view clusterStatusView) {
 	}
 }
 
+// labelsText renders labels as sorted key=value pairs separated by commas.
+func labelsText(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
 // boolText returns trueText when b is true, otherwise falseText.
 func boolText(b bool, trueText, falseText string) string {
 	if b {
@@ -431,9 +673,9 @@ func pluralizeRole(role string, count int) string {
 // Deployah owns the word→icon mapping; Nabat does not interpret status strings.
 func statusIcon(status string) nabat.Icon {
 	switch status {
-	case "running":
+	case "running", "ok":
 		return nabat.IconSuccess
-	case "unhealthy":
+	case "unhealthy", "failing":
 		return nabat.IconWarning
 	case "stopped":
 		return nabat.IconError
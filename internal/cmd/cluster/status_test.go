@@ -15,12 +15,16 @@
 package cluster
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"deployah.dev/deployah/internal/localkube"
+
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // TestLoadBalancerAddress verifies that the first IP (or hostname) is returned
@@ -90,3 +94,57 @@ func TestHasPortMappedAccess(t *testing.T) {
 		{Address: "127.0.0.1:32769"},
 	}))
 }
+
+// TestNodeViews derives role and readiness, drops built-in labels, formats
+// taints, and sorts the control plane first.
+func TestNodeViews(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployah-worker", Labels: map[string]string{
+				"kubernetes.io/hostname": "deployah-worker",
+				"beta.kubernetes.io/os":  "linux",
+				"zone":                   "a",
+			}},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "batch", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployah-control-plane", Labels: map[string]string{
+				"node-role.kubernetes.io/control-plane":                   "",
+				"node.kubernetes.io/exclude-from-external-load-balancers": "",
+			}},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.35.0"},
+			},
+		},
+	}
+
+	got := nodeViews(nodes)
+
+	assert.Equal(t, []nodeView{
+		{Name: "deployah-control-plane", Role: "control-plane", Status: "Ready", Version: "v1.35.0"},
+		{Name: "deployah-worker", Role: "worker", Status: "NotReady", Labels: map[string]string{"zone": "a"}, Taints: []string{"dedicated=batch:NoSchedule"}},
+	}, got)
+}
+
+// TestLabelsText renders sorted key=value pairs.
+func TestLabelsText(t *testing.T) {
+	assert.Empty(t, labelsText(nil))
+	assert.Equal(t, "a=1, b=2", labelsText(map[string]string{"b": "2", "a": "1"}))
+}
+
+// TestMirrorViews marks each mirror ok or failing with the reason, and
+// reports nothing for a cluster without mirrors.
+func TestMirrorViews(t *testing.T) {
+	assert.Nil(t, mirrorViews(nil))
+
+	got := mirrorViews([]localkube.MirrorStatus{
+		{RegistryMirror: localkube.RegistryMirror{Registry: "docker.io", Endpoint: "http://mirror.local:5000"}},
+		{RegistryMirror: localkube.RegistryMirror{Registry: "ghcr.io", Endpoint: "http://cache:5000"}, Err: errors.New("unreachable")},
+	})
+
+	assert.Equal(t, []mirrorView{
+		{Registry: "docker.io", Endpoint: "http://mirror.local:5000", Status: "ok"},
+		{Registry: "ghcr.io", Endpoint: "http://cache:5000", Status: "failing", Error: "unreachable"},
+	}, got)
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/k8s"
+)
+
+// trustOptions holds command-line flags for "cluster trust".
+type trustOptions struct {
+	Install bool   `nabat:"install"`
+	File    string `nabat:"file"`
+}
+
+// trustStore is a Linux system trust store that reads extra roots from a
+// directory of PEM files.
+type trustStore struct {
+	// dir holds the extra roots; the store is present when it exists.
+	dir string
+	// file is the name the CA is installed under.
+	file string
+	// refresh rebuilds the store after the directory changed.
+	refresh []string
+}
+
+// trustStores lists the Linux trust stores in the order they are tried.
+var trustStores = []trustStore{
+	// Debian, Ubuntu, Alpine.
+	{dir: "/usr/local/share/ca-certificates", file: "deployah-local-ca.crt", refresh: []string{"update-ca-certificates"}},
+	// Fedora, RHEL, CentOS.
+	{dir: "/etc/pki/ca-trust/source/anchors", file: "deployah-local-ca.pem", refresh: []string{"update-ca-trust", "extract"}},
+	// Arch.
+	{dir: "/etc/ca-certificates/trust-source/anchors", file: "deployah-local-ca.crt", refresh: []string{"trust", "extract-compat"}},
+	// openSUSE.
+	{dir: "/usr/share/pki/trust/anchors", file: "deployah-local-ca.pem", refresh: []string{"update-ca-certificates"}},
+}
+
+// macOSSystemKeychain is the keychain "cluster trust --install" adds the CA
+// to on macOS.
+const macOSSystemKeychain = "/Library/Keychains/System.keychain"
+
+// registerTrust attaches the "trust" subcommand to the cluster group.
+func registerTrust(group *nabat.Command) {
+	group.MustCommand("trust",
+		nabat.WithDescription("Export or install the local CA that signs selfSigned certificates"),
+		nabat.WithLongDescription("Deployah signs the certificates of components with selfSigned TLS with a local CA that is "+
+			"created once per user and kept under the XDG data home (~/.local/share/deployah/ca on Linux). Trust it once "+
+			"and browsers and curl accept every local service.\n\n"+
+			"By default this prints the CA certificate (PEM) to stdout, e.g. for curl --cacert or a container image. "+
+			"Use --file to write it to a file instead.\n\n"+
+			"Use --install to add it to the system trust store: the CA directory of your Linux distribution, then its "+
+			"refresh command, or the System keychain on macOS. This runs sudo unless you are root. Firefox, and Chrome on "+
+			"Linux, keep their own store; import the exported file in their certificate settings."),
+		nabat.WithFlag("install", false, nabat.WithUsage("Install the CA into the system trust store (runs sudo)")),
+		nabat.WithFlag("file", "", nabat.WithUsage("Write the CA certificate to this file instead of stdout")),
+		nabat.WithExample(`
+# Trust local services system-wide
+deployah cluster trust --install
+
+# Trust them in one curl call only
+curl --cacert <(deployah cluster trust) https://web.127.0.0.1.nip.io
+
+# Export the CA to import it in a browser
+deployah cluster trust --file deployah-ca.pem`),
+		nabat.WithRun(runTrust),
+	)
+}
+
+func runTrust(c *nabat.Context) error {
+	opts := &trustOptions{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+
+	ca, err := k8s.EnsureLocalCA(k8s.DefaultLocalCADir())
+	if err != nil {
+		return err
+	}
+
+	if opts.File != "" {
+		if err = os.WriteFile(opts.File, ca.CertPEM(), 0o644); err != nil { // #nosec G306 -- a CA certificate is public
+			return fmt.Errorf("write local CA: %w", err)
+		}
+		c.Success("Local CA written", "file", opts.File)
+	}
+
+	if opts.Install {
+		location, installErr := installCA(c, ca)
+		if installErr != nil {
+			return installErr
+		}
+		c.Success("Local CA trusted", "name", ca.Subject(), "store", location)
+		return nil
+	}
+
+	if opts.File == "" {
+		c.Println(strings.TrimRight(string(ca.CertPEM()), "\n"))
+	}
+	return nil
+}
+
+// installCA adds ca to the system trust store and returns where it went.
+func installCA(c *nabat.Context, ca *k8s.LocalCA) (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		err := runPrivileged(c, nil, "security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", macOSSystemKeychain, ca.CertPath())
+		return macOSSystemKeychain, err
+	case "linux":
+		store, ok := detectTrustStore(func(dir string) bool {
+			info, err := os.Stat(dir)
+			return err == nil && info.IsDir()
+		})
+		if !ok {
+			return "", errors.New("no supported system trust store found; export the CA with --file and add it to your trust store manually")
+		}
+		path := filepath.Join(store.dir, store.file)
+		if err := runPrivileged(c, bytes.NewReader(ca.CertPEM()), "tee", path); err != nil {
+			return "", err
+		}
+		return path, runPrivileged(c, nil, store.refresh[0], store.refresh[1:]...)
+	default:
+		return "", fmt.Errorf("--install is not supported on %s; export the CA with --file and add it to your trust store manually", runtime.GOOS)
+	}
+}
+
+// detectTrustStore returns the first of trustStores whose directory exists.
+func detectTrustStore(dirExists func(string) bool) (trustStore, bool) {
+	for _, store := range trustStores {
+		if dirExists(store.dir) {
+			return store, true
+		}
+	}
+	return trustStore{}, false
+}
+
+// runPrivileged runs a command as root, through sudo unless deployah
+// already runs as root, with stdin as its input. Its output is dropped;
+// errors and sudo's password prompt go to stderr.
+func runPrivileged(c *nabat.Context, stdin io.Reader, name string, args ...string) error {
+	if os.Geteuid() != 0 {
+		args = append([]string{name}, args...)
+		name = "sudo"
+	}
+	cmd := exec.CommandContext(c, name, args...) // #nosec G204 -- fixed commands
+	cmd.Stdin = stdin
+	cmd.Stdout = io.Discard
+	cmd.Stderr = c.IO().ErrOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", strings.Join(cmd.Args, " "), err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDetectTrustStore picks the first trust store whose directory exists.
+func TestDetectTrustStore(t *testing.T) {
+	_, ok := detectTrustStore(func(string) bool { return false })
+	assert.False(t, ok)
+
+	store, ok := detectTrustStore(func(dir string) bool {
+		return dir == "/etc/pki/ca-trust/source/anchors" || dir == "/usr/share/pki/trust/anchors"
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "deployah-local-ca.pem", store.file)
+	assert.Equal(t, []string{"update-ca-trust", "extract"}, store.refresh)
+}
@@ -24,17 +24,24 @@ import (
 	"nabat.dev/nabat"
 	"sigs.k8s.io/yaml"
 
+	"deployah.dev/deployah/internal/cli"
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/localkube"
 	"deployah.dev/deployah/internal/spec"
 )
 
 // upOptions holds command-line flags for "cluster up".
 type upOptions struct {
-	NoCloudProvider   bool   `nabat:"no-cloud-provider"`
-	Attach            bool   `nabat:"attach"`
-	KubernetesVersion string `nabat:"kubernetes-version"`
-	Runtime           string `nabat:"runtime"`
-	SyncRegistryAuth  bool   `nabat:"sync-registry-auth"`
+	NoCloudProvider   bool     `nabat:"no-cloud-provider"`
+	Attach            bool     `nabat:"attach"`
+	KubernetesVersion string   `nabat:"kubernetes-version"`
+	Runtime           string   `nabat:"runtime"`
+	SyncRegistryAuth  bool     `nabat:"sync-registry-auth"`
+	Registry          bool     `nabat:"registry"`
+	Config            string   `nabat:"config"`
+	RegistryMirrors   []string `nabat:"registry-mirror"`
+	Output            string   `nabat:"output"`
 }
 
 // runtimeOptions are the accepted values for the --runtime flag.
@@ -49,12 +56,27 @@ func registerUp(group *nabat.Command) {
 			"returns immediately after starting the cloud provider in the background.\n\n"+
 			"Use --attach to stay in the foreground and stream the cloud provider logs; the container is stopped "+
 			"when you press Ctrl-C.\n\n"+
-			"Use --no-cloud-provider to only create the cluster without starting the cloud provider."),
+			"Use --no-cloud-provider to only create the cluster without starting the cloud provider.\n\n"+
+			"Use --registry to also start a local image registry at localhost:5001. The cluster pulls images pushed "+
+			"there, and the local environment sets ${REGISTRY} to its address, so a spec can use ${REGISTRY}/app:dev. "+
+			"The registry outlives the cluster; run this again after recreating the cluster to reconnect it.\n\n"+
+			"The cluster's nodes come from "+spec.DefaultLocalClusterPath+" in the current directory, or the file named by --config: "+
+			"worker groups, node labels and taints, host port mappings and mounts, the Kubernetes version, and feature gates. "+
+			"Without the file the cluster is a single node. The file is read only when the cluster is created; run "+
+			"'deployah cluster down' first to apply changes to an existing cluster.\n\n"+
+			"Use --registry-mirror registry=endpoint, or registryMirrors in the cluster config file, to make the nodes "+
+			"pull from a mirror such as a pull-through cache first, e.g. docker.io=mirror.local:5000. The endpoint "+
+			"must be reachable from the nodes and defaults to plain HTTP. Mirrors are applied on every run, also to an "+
+			"existing cluster, and kept when the cluster is recreated; 'deployah cluster status' checks them."),
 		nabat.WithFlag("no-cloud-provider", false, nabat.WithUsage("Only create the cluster; do not start the cloud provider")),
 		nabat.WithFlag("attach", false, nabat.WithUsage("Stay in the foreground and stream cloud provider logs (Ctrl-C stops the container)")),
 		nabat.WithFlag("kubernetes-version", "", nabat.WithUsage("Kubernetes version for the cluster (e.g. 1.31 or v1.31.2)")),
 		nabat.WithSelectFlag("runtime", "auto", runtimeOptions, nabat.WithUsage("Host container engine to use")),
 		nabat.WithFlag("sync-registry-auth", false, nabat.WithUsage("Copy host registry credentials into the cluster as a Kubernetes Secret and patch the default ServiceAccount to use them")),
+		nabat.WithFlag("registry", false, nabat.WithUsage("Start a local image registry at localhost:5001 that the cluster pulls from")),
+		nabat.WithFlag("registry-mirror", []string{}, nabat.WithUsage("Pull from a registry mirror first, as registry=endpoint (e.g. docker.io=mirror.local:5000); repeatable")),
+		nabat.WithFlag("config", "", nabat.WithUsage("Cluster config file declaring nodes, Kubernetes version, and feature gates (default: "+spec.DefaultLocalClusterPath+" when present)")),
+		nabat.WithSelectFlag("output", cli.OutputFormatText, cli.EventOutputFormats, nabat.WithUsage("Output format: text, or jsonl to stream progress events as JSON lines on stdout")),
 		nabat.WithExample(`
 # Bring up the local cluster with cloud provider in the background
 deployah cluster up
@@ -65,9 +87,22 @@ deployah cluster up --no-cloud-provider
 # Foreground mode: stream cloud provider logs, Ctrl-C stops the container
 deployah cluster up --attach
 
+# Start a local registry, then push images for ${REGISTRY}/app:dev
+deployah cluster up --registry
+docker push localhost:5001/app:dev
+
 # Pin the Kubernetes version and force a runtime
-deployah cluster up --kubernetes-version 1.31 --runtime podman`),
-		nabat.WithRun(runUp),
+deployah cluster up --kubernetes-version 1.31 --runtime podman
+
+# Pull Docker Hub images through a pull-through cache
+deployah cluster up --registry-mirror docker.io=mirror.local:5000
+
+# Create a multi-node cluster from a cluster config file
+deployah cluster up --config ci.cluster.yaml
+
+# Stream progress events, e.g. when a CI job creates the cluster
+deployah cluster up --output jsonl`),
+		nabat.WithRun(cmdopts.WithEventStream("cluster up", runUp)),
 	)
 }
 
@@ -82,9 +117,23 @@ func runUp(c *nabat.Context) error {
 		return err
 	}
 
+	clusterCfg, err := loadClusterConfig(opts.Config)
+	if err != nil {
+		return err
+	}
+	mirrors, err := registryMirrors(clusterCfg, opts.RegistryMirrors)
+	if err != nil {
+		return err
+	}
+
+	// --kubernetes-version wins over the cluster config file.
+	k8sVersion := opts.KubernetesVersion
+	if k8sVersion == "" && clusterCfg != nil {
+		k8sVersion = clusterCfg.KubernetesVersion
+	}
 	mgrOpts := []localkube.Option{localkube.WithRuntime(rt)}
-	if opts.KubernetesVersion != "" {
-		mgrOpts = append(mgrOpts, localkube.WithKubernetesVersion(opts.KubernetesVersion))
+	if k8sVersion != "" {
+		mgrOpts = append(mgrOpts, localkube.WithKubernetesVersion(k8sVersion))
 	}
 
 	m, err := newManager(c, mgrOpts...)
@@ -96,17 +145,23 @@ func runUp(c *nabat.Context) error {
 	// Probe current state so the output can tell the user whether this run
 	// actually created anything or the cluster was already there. Get returns
 	// ErrNotFound when the cluster is absent, so a nil error means it exists.
-	_, getErr := m.Get(c, clusterName)
+	existing, getErr := m.Get(c, clusterName)
 	clusterExisted := getErr == nil
+	if clusterExisted && clusterCfg != nil && existing.Nodes != clusterCfg.NodeCount() {
+		c.Warn(fmt.Sprintf("The cluster has %d nodes but the cluster config declares %d; run 'deployah cluster down' first to apply it",
+			existing.Nodes, clusterCfg.NodeCount()))
+	}
 
 	// Spinner delays animation until work runs past the default grace period,
 	// so a fast idempotent Create (cluster already exists) prints at most a
 	// static line and never probes the terminal.
 	const createTitle = "Creating local cluster"
+	events := eventlog.FromContext(c)
 	if spinErr := c.Spinner(func(sp *nabat.Spinner) error {
-		return m.Create(c, clusterName,
+		createOpts := append([]localkube.CreateOption{
 			localkube.WithCreateIfMissing(),
 			localkube.WithCreateEventHandler(func(e localkube.Event) {
+				emitStep(events, e)
 				if e.Status != localkube.StepStarted {
 					return
 				}
@@ -114,7 +169,11 @@ func runUp(c *nabat.Context) error {
 					sp.SetText(createTitle + ": " + lbl)
 				}
 			}),
-		)
+		}, clusterCreateOptions(clusterCfg)...)
+		if len(mirrors) > 0 {
+			createOpts = append(createOpts, localkube.WithRegistryMirrors(mirrors...))
+		}
+		return m.Create(c, clusterName, createOpts...)
 	}, nabat.WithTitle(createTitle)); spinErr != nil {
 		return fmt.Errorf("create local cluster: %w", spinErr)
 	}
@@ -128,7 +187,8 @@ func runUp(c *nabat.Context) error {
 	// - Absent:  create with local environment.
 	// - Exists, has local key: skip, print hint.
 	// - Exists, no local key: print the YAML block to add (do NOT mutate).
-	ensureLocalPlatformFile(c, spec.DefaultPlatformPath, localkube.DefaultIngressIP)
+	// - Exists, local lacks REGISTRY under --registry: print the variables block.
+	ensureLocalPlatformFile(c, spec.DefaultPlatformPath, localkube.DefaultIngressIP, m.RegistryAddress(), opts.Registry)
 
 	if opts.SyncRegistryAuth {
 		if syncErr := m.SyncRegistryAuth(c, clusterName, "default"); syncErr != nil {
@@ -153,6 +213,9 @@ func runUp(c *nabat.Context) error {
 		}
 		c.Info("Deploy:  deployah deploy <environment>  (set context: " + ctxName + " in your environment, or pass --context " + ctxName + ")")
 		c.Info(`kubectl: export KUBECONFIG="$(deployah cluster kubeconfig)"`)
+		if registryAddr != "" {
+			c.Info("Push:    docker push " + registryAddr + "/<image>  (use ${" + spec.LocalRegistryVariable + "}/<image> in the spec)")
+		}
 		if includeStop {
 			c.Info("Stop:    deployah cluster down")
 		}
@@ -163,6 +226,18 @@ func runUp(c *nabat.Context) error {
 	} else {
 		c.Success("Local cluster ready", "context", ctxName, "kubeconfig", kc.Path())
 	}
+	for _, mirror := range mirrors {
+		c.Info("Registry mirror configured", "registry", mirror.Registry, "endpoint", mirror.Endpoint)
+	}
+
+	if opts.Registry {
+		if spinErr := c.Spinner(func(_ *nabat.Spinner) error {
+			return m.StartRegistry(c, clusterName)
+		}, nabat.WithTitle("Starting local registry...")); spinErr != nil {
+			return fmt.Errorf("start local registry: %w", spinErr)
+		}
+		c.Success("Local registry running", "address", registryAddr)
+	}
 
 	if opts.NoCloudProvider {
 		c.Info("Cloud provider disabled; LoadBalancer and Ingress will not be reachable")
@@ -214,17 +289,21 @@ func runUp(c *nabat.Context) error {
 // to add when the file exists without a local key. It never mutates an
 // existing file to preserve hand-authored comments. ingressIP is the host IP
 // used for the nip.io base domain (from localkube.DefaultIngressIP).
-func ensureLocalPlatformFile(c *nabat.Context, path, ingressIP string) {
+// registryAddr is the local registry address the local env points
+// ${REGISTRY} at. With registry set (--registry), an existing local env
+// without the registry variable gets a hint too.
+func ensureLocalPlatformFile(c *nabat.Context, path, ingressIP, registryAddr string, registry bool) {
 	data, readErr := os.ReadFile(path) // #nosec G304
 	if readErr != nil {
 		// File absent: scaffold it.
-		created, scaffoldErr := spec.ScaffoldPlatformFile(path, ingressIP, []string{"local"})
+		created, scaffoldErr := spec.ScaffoldPlatformFile(path, ingressIP, registryAddr, []string{"local"})
 		if scaffoldErr != nil {
 			c.Warn(fmt.Sprintf("failed to create platform file: %v", scaffoldErr))
 			return
 		}
 		if created {
-			c.Info("Created " + path + " with local environment (kind-deployah, " + ingressIP + ".nip.io, selfSigned TLS)")
+			c.Info("Created " + path + " with local environment (kind-deployah, " + ingressIP + ".nip.io, selfSigned TLS, " +
+				spec.LocalRegistryVariable + "=" + registryAddr + ")")
 		}
 		return
 	}
@@ -236,13 +315,18 @@ func ensureLocalPlatformFile(c *nabat.Context, path, ingressIP string) {
 		return
 	}
 
-	if _, hasLocal := existing.Environments["local"]; hasLocal {
+	if local, hasLocal := existing.Environments["local"]; hasLocal {
+		if registry && local.Variables[spec.LocalRegistryVariable] == "" {
+			c.Info("Add the following to the local environment in " + path + " to use ${" + spec.LocalRegistryVariable + "} in your spec:")
+			cmdopts.Println(c, "variables:\n  "+spec.LocalRegistryVariable+": "+registryAddr+"\n")
+			return
+		}
 		c.Info(path + " already has a local environment; no changes made")
 		return
 	}
 
 	// File exists but lacks local key: print the block to add.
-	localEnv := spec.LocalPlatformEnvironment(ingressIP)
+	localEnv := spec.LocalPlatformEnvironment(ingressIP, registryAddr)
 	snippet := spec.PlatformConfig{
 		Environments: map[string]spec.PlatformEnvironment{"local": localEnv},
 	}
@@ -253,7 +337,7 @@ func ensureLocalPlatformFile(c *nabat.Context, path, ingressIP string) {
 	}
 
 	c.Info("Add the following to your " + path + " under environments:")
-	c.Println(string(snippetYAML))
+	cmdopts.Println(c, string(snippetYAML))
 }
 
 // parseRuntime converts the --runtime flag value to a localkube.Runtime.
@@ -0,0 +1,60 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdopts
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cli"
+	"deployah.dev/deployah/internal/k8s"
+)
+
+// ExistingLocalCA returns the user's local CA, or nil when none was created
+// yet or it cannot be read. Status commands use it so that looking at a
+// cluster never creates a CA.
+func ExistingLocalCA(c *nabat.Context) *k8s.LocalCA {
+	ca, err := k8s.LoadLocalCA(k8s.DefaultLocalCADir())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			c.Logger().Debug("could not read local CA", "err", err)
+		}
+		return nil
+	}
+	return ca
+}
+
+// RenewCertificates reports the selfSigned TLS certificates in namespace
+// (all namespaces when empty) that match selector, re-issuing with ca those
+// close to expiring. Like the rest of a status command it is best-effort:
+// a failure, e.g. for lack of RBAC on Secrets, is logged and yields what
+// was found.
+func RenewCertificates(c *nabat.Context, clientset kubernetes.Interface, ca *k8s.LocalCA, namespace string, selector labels.Selector) []cli.CertificateViewModel {
+	certs, err := k8s.RenewSelfSignedCerts(c, clientset, ca, namespace, selector)
+	if err != nil {
+		c.Logger().Debug("could not check TLS certificates", "namespace", namespace, "err", err)
+	}
+	for _, cert := range certs {
+		if cert.Renewed {
+			c.Info("Renewed TLS certificate", "host", cert.Host, "expires", cert.NotAfter.Format(time.DateOnly))
+		}
+	}
+	return cli.CertificatesToViewModel(certs)
+}
@@ -97,6 +97,7 @@ func HasExposeComponents(m *spec.Spec) bool {
 // when k8sErr is non-nil and a selfSigned component exists, rather than
 // rotating a live secret on a transient clientset failure; pass a nil
 // k8sClient with a nil k8sErr to force offline generation deliberately.
+// New certificates are issued by the user's local CA (see [LocalCA]).
 func MaterializeSelfSignedTLS(ctx context.Context, k8sClient kubernetes.Interface, k8sErr error, namespace string, resolved *spec.ResolvedSpec) error {
 	if resolved == nil {
 		return nil
@@ -104,5 +105,23 @@ func MaterializeSelfSignedTLS(ctx context.Context, k8sClient kubernetes.Interfac
 	if k8sErr != nil && k8s.HasSelfSignedComponents(resolved) {
 		return fmt.Errorf("kubernetes client required to materialize self-signed TLS certificates: %w", k8sErr)
 	}
-	return k8s.MaterializeSelfSignedTLS(ctx, k8sClient, namespace, resolved)
+	ca, err := LocalCA(resolved)
+	if err != nil {
+		return err
+	}
+	return k8s.MaterializeSelfSignedTLS(ctx, k8sClient, ca, namespace, resolved)
+}
+
+// LocalCA returns the user's local CA, creating it on first use, when
+// resolved has selfSigned components that need certificates. It returns
+// nil otherwise, so a spec without them never creates one.
+func LocalCA(resolved *spec.ResolvedSpec) (*k8s.LocalCA, error) {
+	if !k8s.HasSelfSignedComponents(resolved) {
+		return nil, nil //nolint:nilnil // no certificates to sign
+	}
+	ca, err := k8s.EnsureLocalCA(k8s.DefaultLocalCADir())
+	if err != nil {
+		return nil, fmt.Errorf("local CA for self-signed TLS: %w", err)
+	}
+	return ca, nil
 }
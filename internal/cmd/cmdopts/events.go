@@ -0,0 +1,76 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdopts
+
+import (
+	"fmt"
+	"io"
+
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cli"
+	"deployah.dev/deployah/internal/eventlog"
+)
+
+// eventOptions is the --output flag of a command wrapped by
+// [WithEventStream].
+type eventOptions struct {
+	Output string `nabat:"output"`
+}
+
+// WithEventStream wraps run so that --output jsonl streams the command's
+// progress as [eventlog] events on stdout. The stream travels in the
+// context for [eventlog.FromContext], and its last event reports the
+// error run returns. Without --output jsonl, run is called unchanged.
+func WithEventStream(command string, run func(*nabat.Context) error) func(*nabat.Context) error {
+	return func(c *nabat.Context) error {
+		var opts eventOptions
+		if err := c.Bind(&opts); err != nil {
+			return fmt.Errorf("binding options: %w", err)
+		}
+		if opts.Output != cli.OutputFormatJSONL {
+			return run(c)
+		}
+		stream := eventlog.New(c.IO().Out, command)
+		c.SetContext(eventlog.WithContext(c.Context(), stream))
+		err := run(c)
+		stream.Finish(err)
+		if writeErr := stream.Err(); writeErr != nil {
+			c.Logger().Debug("event stream write failed", "err", writeErr)
+		}
+		return err
+	}
+}
+
+// TextOut returns where a command prints human-readable output such as the
+// plan: stdout, or stderr while --output jsonl reserves stdout for events.
+// Use it instead of c.Printf in commands wrapped by [WithEventStream].
+func TextOut(c *nabat.Context) io.Writer {
+	if eventlog.FromContext(c) != nil {
+		return c.IO().ErrOut
+	}
+	return c.IO().Out
+}
+
+// Printf formats to [TextOut]. Like c.Printf, a write error is dropped.
+func Printf(c *nabat.Context, format string, a ...any) {
+	fmt.Fprintf(TextOut(c), format, a...) //nolint:errcheck // best-effort progress output
+}
+
+// Println prints a to [TextOut] followed by a newline. Like c.Println, a
+// write error is dropped.
+func Println(c *nabat.Context, a ...any) {
+	fmt.Fprintln(TextOut(c), a...) //nolint:errcheck // best-effort progress output
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdopts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/helm"
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/session"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// lockReleaseTimeout bounds releasing a lock after the command finished,
+// so an unreachable cluster does not hang the exit.
+const lockReleaseTimeout = 10 * time.Second
+
+// ciJobURLEnv lists the variables CI systems set to the running job's URL,
+// checked in order: an explicit DEPLOYAH_CI_JOB_URL, then GitLab, Jenkins,
+// CircleCI, and Buildkite.
+var ciJobURLEnv = []string{
+	"DEPLOYAH_CI_JOB_URL",
+	"CI_JOB_URL",
+	"BUILD_URL",
+	"CIRCLE_BUILD_URL",
+	"BUILDKITE_BUILD_URL",
+}
+
+// CIJobURL returns the URL of the CI job running this process, or an empty
+// string outside CI. GitHub Actions has no single variable for it, so its
+// run URL is built from GITHUB_SERVER_URL, GITHUB_REPOSITORY, and
+// GITHUB_RUN_ID.
+func CIJobURL(getenv func(string) string) string {
+	for _, key := range ciJobURLEnv {
+		if v := getenv(key); v != "" {
+			return v
+		}
+	}
+	server, repo, run := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"), getenv("GITHUB_RUN_ID")
+	if server != "" && repo != "" && run != "" {
+		return server + "/" + repo + "/actions/runs/" + run
+	}
+	return ""
+}
+
+// LockOwner identifies this process for a release lock taken by operation:
+// "user@host (pid N)" plus the CI job URL when running in CI.
+func LockOwner(operation string) k8s.LockOwner {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return k8s.LockOwner{
+		Holder:    fmt.Sprintf("%s@%s (pid %d)", name, host, os.Getpid()),
+		Operation: operation,
+		JobURL:    CIJobURL(os.Getenv),
+	}
+}
+
+// LockRelease takes the release lock for project and environment before
+// operation changes the release, and returns the func that releases it.
+// Two commands changing the same release at once fail fast here instead
+// of racing inside Helm. A namespace that does not exist yet holds no
+// release for operation to change, so it is not locked; deploys, which
+// create the namespace, use [LockDeploy].
+func LockRelease(c *nabat.Context, cluster *session.Cluster, project, environment, operation string) (unlock func(), err error) {
+	return lockRelease(c, cluster, project, environment, operation, func(cs kubernetes.Interface, release string) (*k8s.ReleaseLock, error) {
+		lock, err := k8s.AcquireReleaseLock(c, cs, cluster.Namespace(), release, LockOwner(operation), k8s.DefaultLockTTL)
+		if apierrors.IsNotFound(err) {
+			c.Logger().Debug("namespace does not exist; no release to lock", "namespace", cluster.Namespace())
+			return nil, nil //nolint:nilnil // nothing to lock
+		}
+		return lock, err
+	})
+}
+
+// LockDeploy takes the release lock for a deploy of project to
+// environment, like [LockRelease]. A first deploy creates the namespace,
+// with the platform file's labels and annotations, so the lock exists
+// before Helm runs and two first deploys cannot both proceed.
+func LockDeploy(c *nabat.Context, cluster *session.Cluster, project, environment string) (unlock func(), err error) {
+	var labels, annotations map[string]string
+	if ns := cluster.PlatformNamespace(); ns != nil {
+		labels, annotations = ns.Labels, ns.Annotations
+	}
+	return lockRelease(c, cluster, project, environment, "deploy", func(cs kubernetes.Interface, release string) (*k8s.ReleaseLock, error) {
+		lock, created, err := k8s.AcquireInstallLock(c, cs, cluster.Namespace(), release, LockOwner("deploy"), k8s.DefaultLockTTL, labels, annotations)
+		if created {
+			Printf(c, "Created namespace %s\n", cluster.Namespace())
+		}
+		return lock, err
+	})
+}
+
+// lockRelease takes a release lock with acquire, which returns a nil lock
+// when there is nothing to lock, and returns the func that releases it.
+func lockRelease(c *nabat.Context, cluster *session.Cluster, project, environment, operation string, acquire func(cs kubernetes.Interface, release string) (*k8s.ReleaseLock, error)) (unlock func(), err error) {
+	cs, err := cluster.Kubernetes()
+	if err != nil {
+		return nil, fmt.Errorf("lock release: kubernetes client: %w%s", err, ClusterHint(err))
+	}
+	release := helm.GenerateReleaseName(project, environment)
+	lock, err := acquire(cs, release)
+	if err != nil {
+		if errors.Is(err, k8s.ErrReleaseLocked) {
+			return nil, fmt.Errorf("%w\n\nHint: wait for it to finish, or run 'deployah unlock %s' if its holder is gone", err, environment)
+		}
+		return nil, fmt.Errorf("%w%s", err, ClusterHint(err))
+	}
+	if lock == nil {
+		return func() {}, nil
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c), lockReleaseTimeout)
+		defer cancel()
+		if releaseErr := lock.Release(ctx); releaseErr != nil {
+			c.Warn(fmt.Sprintf("could not release the lock on %s; it expires on its own: %v", release, releaseErr))
+		}
+	}, nil
+}
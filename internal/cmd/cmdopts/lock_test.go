@@ -0,0 +1,62 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdopts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCIJobURL picks the job URL from the CI system's variables.
+func TestCIJobURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "outside CI", env: map[string]string{}, want: ""},
+		{
+			name: "GitLab",
+			env:  map[string]string{"CI_JOB_URL": "https://gitlab.example.com/shop/-/jobs/42"},
+			want: "https://gitlab.example.com/shop/-/jobs/42",
+		},
+		{
+			name: "GitHub Actions",
+			env: map[string]string{
+				"GITHUB_SERVER_URL": "https://github.com",
+				"GITHUB_REPOSITORY": "acme/shop",
+				"GITHUB_RUN_ID":     "123",
+			},
+			want: "https://github.com/acme/shop/actions/runs/123",
+		},
+		{
+			name: "explicit override wins",
+			env: map[string]string{
+				"DEPLOYAH_CI_JOB_URL": "https://ci.example.com/run/7",
+				"BUILD_URL":           "https://jenkins.example.com/job/shop/7/",
+			},
+			want: "https://ci.example.com/run/7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, CIJobURL(func(k string) string { return tt.env[k] }))
+		})
+	}
+}
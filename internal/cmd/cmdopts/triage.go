@@ -0,0 +1,69 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdopts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/triage"
+)
+
+// triageTimeout bounds the pod listing and log fetches behind one report,
+// so diagnosing a failure cannot hang the command that already failed.
+const triageTimeout = 30 * time.Second
+
+// Diagnose prints a [triage.Report] for the pods matching selector after
+// operation failed with cause, and writes it to reportFile as JSON when
+// reportFile is set. warnings are the Warning events seen while waiting.
+// It is best-effort: when the pods cannot be inspected the report holds
+// only what the events show, and a report file that cannot be written is
+// a warning, so cause stays the error the command returns.
+func Diagnose(c *nabat.Context, k8sClient kubernetes.Interface, k8sErr error, namespace, selector, operation string, warnings []k8s.DeployEvent, cause error, reportFile string) {
+	var report *triage.Report
+	if k8sErr != nil {
+		c.Logger().Debug("triage: kubernetes client unavailable", "err", k8sErr)
+	} else {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c), triageTimeout)
+		defer cancel()
+		collected, err := triage.Collect(ctx, k8sClient, namespace, selector, warnings, triage.DefaultLogLines)
+		if err != nil {
+			c.Logger().Debug("triage: collect failed", "err", err)
+		}
+		report = collected
+	}
+	if report == nil {
+		report = triage.New(namespace, selector, warnings)
+	}
+	report.Operation = operation
+	report.Error = cause.Error()
+
+	if err := report.WriteText(c.IO().ErrOut); err != nil {
+		c.Logger().Debug("triage: print failed", "err", err)
+	}
+	if reportFile == "" {
+		return
+	}
+	if err := report.WriteFile(reportFile); err != nil {
+		c.Warn(fmt.Sprintf("Failure report not saved: %v", err))
+		return
+	}
+	c.Info("Failure report saved", "path", reportFile)
+}
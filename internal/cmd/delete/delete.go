@@ -11,6 +11,7 @@ import (
 
 	"deployah.dev/deployah/internal/cli"
 	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/helm"
 	"deployah.dev/deployah/internal/k8s"
 	"deployah.dev/deployah/internal/session"
@@ -68,7 +69,7 @@ func Register(app *nabat.App) {
 		nabat.WithFlag("yes", false, nabat.WithShort('y'), nabat.WithUsage("Skip confirmation prompt")),
 		nabat.WithFlag("dry-run", false, nabat.WithUsage("Simulate the deletion without actually removing the project")),
 		nabat.WithFlag("show-resources", false, nabat.WithUsage("Show detailed resources that would be deleted (implies --dry-run)")),
-		nabat.WithSelectFlag("output", cli.OutputFormatTree, cli.DeleteOutputFormats, nabat.WithShort('o'), nabat.WithUsage("Output format for dry-run preview")),
+		nabat.WithSelectFlag("output", cli.OutputFormatTree, cli.DeleteOutputFormats, nabat.WithShort('o'), nabat.WithUsage("Output format for the dry-run preview; jsonl streams a live delete's progress events as JSON lines instead")),
 		nabat.WithFlag("wait", false, nabat.WithUsage("Wait until all Kubernetes resources are fully deleted before returning (uses stable legacy polling; suitable for CI)")),
 		nabat.WithFlag("allow-missing-platform", false, nabat.WithUsage("Allow deletion to proceed even when no platform file is found (uses default kubeconfig context; requires --project and --context or a resolved kubeconfig)")),
 		nabat.WithExample(`
@@ -91,8 +92,11 @@ deployah delete my-app production --show-resources
 deployah delete my-app production --dry-run --output json
 
 # Wait until all resources are fully removed (useful in CI)
-deployah delete my-app production --wait`),
-		nabat.WithRun(runDelete),
+deployah delete my-app production --wait
+
+# Stream progress events for a CI wrapper
+deployah delete my-app production --yes --output jsonl`),
+		nabat.WithRun(cmdopts.WithEventStream("delete", runDelete)),
 	)
 }
 
@@ -106,6 +110,9 @@ func runDelete(c *nabat.Context) error {
 	if opts.ShowResources {
 		opts.DryRun = true
 	}
+	if opts.DryRun && opts.Output == cli.OutputFormatJSONL {
+		return errors.New("--output jsonl streams the events of a live delete; use --output json for the dry-run preview")
+	}
 
 	rt := session.FromContext(c)
 
@@ -127,7 +134,7 @@ func runDelete(c *nabat.Context) error {
 		}
 	}
 
-	cluster, err := rt.Target(c, opts.Environment)
+	cluster, err := rt.Target(c, opts.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -157,6 +164,7 @@ func runDelete(c *nabat.Context) error {
 
 	if nothingToDelete(release, jobs) {
 		c.Warn("Project not found, nothing to delete", "project", opts.Project, "environment", opts.Environment)
+		eventlog.FromContext(c).SetOutcome(eventlog.OutcomeUnchanged)
 		return nil
 	}
 
@@ -177,8 +185,18 @@ func runDelete(c *nabat.Context) error {
 	}
 	if !confirmed {
 		c.Info("Delete cancelled")
+		eventlog.FromContext(c).SetOutcome(eventlog.OutcomeCancelled)
 		return nil
 	}
+	if opts.Yes {
+		eventlog.FromContext(c).Emit(eventlog.TypeConfirmationSkipped, nil)
+	}
+
+	unlock, lockErr := cmdopts.LockRelease(c, cluster, opts.Project, opts.Environment, "delete")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer unlock()
 
 	if release != nil {
 		err = c.Spinner(
@@ -456,6 +474,15 @@ func extractDetail(kind, doc string) string {
 		if err := yaml.Unmarshal([]byte(doc), &obj); err == nil && len(obj.Spec.Rules) > 0 && obj.Spec.Rules[0].Host != "" {
 			return fmt.Sprintf("host: %s", obj.Spec.Rules[0].Host)
 		}
+	case "HTTPRoute":
+		var obj struct {
+			Spec struct {
+				Hostnames []string `yaml:"hostnames"`
+			} `yaml:"spec"`
+		}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err == nil && len(obj.Spec.Hostnames) > 0 {
+			return fmt.Sprintf("host: %s", obj.Spec.Hostnames[0])
+		}
 	case "Secret":
 		var obj struct {
 			Type string `yaml:"type"`
@@ -0,0 +1,191 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v4/pkg/release/common"
+	"k8s.io/client-go/kubernetes"
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/readiness"
+	"deployah.dev/deployah/internal/session"
+	"deployah.dev/deployah/internal/spec"
+
+	planengine "deployah.dev/deployah/internal/plan"
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+// readyPollInterval is a package var so unit tests can shrink the wait
+// between readiness polls.
+var readyPollInterval = pollInterval
+
+// autoRollback is what --rollback-on-failure needs to undo one apply: the
+// release it targets, the revision the apply creates, and the last
+// successful revision before it.
+type autoRollback struct {
+	project     string
+	environment string
+	release     string
+	namespace   string
+	// revision is the revision the apply creates.
+	revision int
+	// target is the last successful revision before the apply; nil on a
+	// first install, which has nothing to roll back to.
+	target *v1.Release
+}
+
+// rollbackOnFailure reports whether a deploy to opts.Environment rolls
+// back on failure: --rollback-on-failure or --no-rollback-on-failure when
+// set, otherwise the platform default for the environment.
+func rollbackOnFailure(opts *Options, platform *spec.PlatformConfig) bool {
+	switch {
+	case opts.NoRollbackOnFailure:
+		return false
+	case opts.RollbackOnFailure:
+		return true
+	}
+	return spec.PlatformEnvRollbackOnFailure(platform, opts.Environment)
+}
+
+// prepareAutoRollback looks up the revision a failed apply of revision
+// would roll back to. It returns nil when rollback on failure is off.
+func prepareAutoRollback(c *nabat.Context, helmClient session.HelmClient, project, environment, release, namespace string, revision int, enabled bool) (*autoRollback, error) {
+	if !enabled {
+		return nil, nil
+	}
+	target, _, err := planengine.LastSuccessfulRelease(c, helmClient, project, environment)
+	if err != nil {
+		return nil, fmt.Errorf("rollback on failure: %w%s", err, cmdopts.ClusterHint(err))
+	}
+	if target == nil {
+		c.Warn("No successful revision yet: a failed deploy is reported but cannot be rolled back.")
+	}
+	return &autoRollback{
+		project:     project,
+		environment: environment,
+		release:     release,
+		namespace:   namespace,
+		revision:    revision,
+		target:      target,
+	}, nil
+}
+
+// runApply runs apply inside [RunWatched]. A failure is diagnosed with
+// [cmdopts.Diagnose], which also writes reportFile when set. With rb nil
+// that is all it does. Otherwise, when apply fails (Helm timed out waiting
+// for resources, or a postDeploy task failed) or the release's pods are
+// not all ready by the end of timeout, the release is rolled back to
+// rb.target, after the diagnosis so it describes the failed revision, and
+// the returned error says why.
+func runApply(c *nabat.Context, helmClient session.HelmClient, k8sClient kubernetes.Interface, k8sErr error, namespace, release, title string, timeout time.Duration, rb *autoRollback, reportFile string, apply func() error) (*DeployWatcher, error) {
+	start := time.Now()
+	watcher, err := RunWatched(c, k8sClient, k8sErr, namespace, release, title, apply)
+	if err != nil {
+		err = fmt.Errorf("deploy failed: %w%s", err, cmdopts.ClusterHint(err))
+	}
+	if err == nil && rb != nil {
+		if k8sErr != nil {
+			c.Warn(fmt.Sprintf("Pod readiness cannot be checked (%v); only a failed apply triggers a rollback.", k8sErr))
+			return watcher, nil
+		}
+		err = waitReady(c, k8sClient, namespace, release, time.Until(start.Add(timeout)))
+	}
+	if err == nil {
+		return watcher, nil
+	}
+
+	var warnings []k8s.DeployEvent
+	if watcher != nil {
+		warnings = watcher.Warnings()
+	}
+	cmdopts.Diagnose(c, k8sClient, k8sErr, namespace, "app.kubernetes.io/instance="+release, "deploy", warnings, err, reportFile)
+	if rb == nil {
+		return watcher, err
+	}
+	return nil, rb.rollBack(c, helmClient, k8sClient, k8sErr, timeout, err)
+}
+
+// waitReady polls pod readiness for release until every pod is ready or
+// wait elapses, polling at least once. A release without pods, such as one
+// that only schedules tasks, has nothing to wait for. The error names the
+// components that did not become ready.
+func waitReady(ctx context.Context, k8sClient kubernetes.Interface, namespace, release string, wait time.Duration) error {
+	deadline := time.Now().Add(wait)
+	for {
+		statuses, err := readiness.Poll(ctx, k8sClient, namespace, release)
+		if err == nil && (len(statuses) == 0 || readiness.AllReady(statuses)) {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if err != nil {
+				return fmt.Errorf("pod readiness unknown at the end of the timeout: %w", err)
+			}
+			return fmt.Errorf("pods not ready within the timeout (%s)", readiness.Summary(statuses))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(readyPollInterval, remaining)):
+		}
+	}
+}
+
+// rollBack rolls the release back to rb.target after the apply failed with
+// cause, and returns cause together with what happened to the release. It
+// does not roll back when there is no successful revision, when Helm's own
+// rollback on a failed install or upgrade already restored one, when Helm
+// recorded no revision for the apply, which then left the release as it
+// was, or when the move would break the same workload rules deploy
+// enforces, such as shrinking volumes this deploy expanded.
+func (rb *autoRollback) rollBack(c *nabat.Context, helmClient session.HelmClient, k8sClient kubernetes.Interface, k8sErr error, timeout time.Duration, cause error) error {
+	if rb.target == nil {
+		return fmt.Errorf("%w; no successful revision to roll back to", cause)
+	}
+	history, err := helmClient.GetReleaseHistory(c, rb.project, rb.environment)
+	if err != nil {
+		return fmt.Errorf("%w; not rolled back: release history: %w%s", cause, err, cmdopts.ClusterHint(err))
+	}
+	releases := planengine.SortedReleases(history)
+	if len(releases) > 0 && releases[0].Version > rb.revision && deployedRevision(releases[0]) {
+		return fmt.Errorf("%w; Helm already rolled the release back (now revision %d)", cause, releases[0].Version)
+	}
+	applied, err := planengine.FindRevision(releases, rb.revision)
+	if err != nil {
+		return fmt.Errorf("%w; not rolled back: Helm recorded no revision %d, so the release was not changed", cause, rb.revision)
+	}
+	if guardErr := CheckReleaseTransition(rb.project, rb.environment, planengine.ChartValues(applied), planengine.ChartValues(rb.target)); guardErr != nil {
+		return fmt.Errorf("%w; not rolled back to revision %d, fix the release with another deploy: %w", cause, rb.target.Version, guardErr)
+	}
+
+	c.Warn(fmt.Sprintf("%v; rolling back to revision %d", cause, rb.target.Version))
+	title := fmt.Sprintf("Rolling back '%s' to revision %d...", rb.environment, rb.target.Version)
+	if _, rbErr := RunWatched(c, k8sClient, k8sErr, rb.namespace, rb.release, title, func() error {
+		return helmClient.RollbackRelease(c, rb.release, rb.target.Version, timeout)
+	}); rbErr != nil {
+		return fmt.Errorf("%w; rollback to revision %d failed: %w%s", cause, rb.target.Version, rbErr, cmdopts.ClusterHint(rbErr))
+	}
+	return fmt.Errorf("%w; rolled back to revision %d", cause, rb.target.Version)
+}
+
+func deployedRevision(rel *v1.Release) bool {
+	return rel.Info != nil && rel.Info.Status == common.StatusDeployed
+}
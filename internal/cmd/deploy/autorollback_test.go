@@ -0,0 +1,228 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v4/pkg/release/common"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"deployah.dev/deployah/internal/spec"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// revisionWithSize builds revision version of web-production with status
+// and the persistence size its deployah.resolved block records for "db".
+func revisionWithSize(version int, status common.Status, size string) *v1.Release {
+	return &v1.Release{
+		Name:    "web-production",
+		Version: version,
+		Info:    &v1.Info{Status: status},
+		Chart: &chart.Chart{Values: map[string]any{
+			"deployah": map[string]any{
+				"resolved": map[string]any{
+					"components": map[string]any{
+						"db": map[string]any{"workloadKind": "StatefulSet", "role": "service", "persistenceSize": size},
+					},
+				},
+			},
+		}},
+	}
+}
+
+func releasePod(name string, ready bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/instance":  "web-production",
+				"app.kubernetes.io/component": "web",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Ready: ready}},
+		},
+	}
+}
+
+// TestRollbackOnFailure covers both flags and the platform default they
+// override.
+func TestRollbackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	platform := &spec.PlatformConfig{Environments: map[string]spec.PlatformEnvironment{
+		"production": {RollbackOnFailure: true},
+		"staging":    {},
+	}}
+	assert.True(t, rollbackOnFailure(&Options{Environment: "production"}, platform))
+	assert.True(t, rollbackOnFailure(&Options{Environment: "staging", RollbackOnFailure: true}, platform))
+	assert.False(t, rollbackOnFailure(&Options{Environment: "staging"}, platform))
+	assert.False(t, rollbackOnFailure(&Options{Environment: "production"}, nil))
+	assert.False(t, rollbackOnFailure(&Options{Environment: "production", NoRollbackOnFailure: true}, platform))
+}
+
+// TestWaitReady covers ready pods, pods that stay unready past the
+// deadline, and a release without pods.
+func TestWaitReady(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pods    []*corev1.Pod
+		wantErr string
+	}{
+		{name: "all ready", pods: []*corev1.Pod{releasePod("web-1", true), releasePod("web-2", true)}},
+		{name: "not ready", pods: []*corev1.Pod{releasePod("web-1", true), releasePod("web-2", false)}, wantErr: "pods not ready within the timeout (web: 1/2)"},
+		{name: "no pods"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			k8sClient := fake.NewSimpleClientset()
+			for _, p := range tt.pods {
+				_, err := k8sClient.CoreV1().Pods("default").Create(t.Context(), p, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			err := waitReady(t.Context(), k8sClient, "default", "web-production", 0)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestRunApply_WithoutRollback returns the apply error unchanged apart from
+// the deploy failed prefix.
+func TestRunApply_WithoutRollback(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHelmClient{}
+	c := nabatContext(t)
+	_, err := runApply(c, stub, nil, assertNever{}, "default", "web-production", "Deploying...", 0, nil, "", func() error {
+		return errors.New("helm boom")
+	})
+	require.EqualError(t, err, "deploy failed: helm boom")
+	assert.Empty(t, stub.rollbacks)
+}
+
+// TestRunApply_RollsBackFailedApply rolls a failed upgrade back to the
+// last successful revision and reports both the cause and the rollback.
+func TestRunApply_RollsBackFailedApply(t *testing.T) {
+	t.Parallel()
+
+	target := revisionWithSize(3, common.StatusSuperseded, "10Gi")
+	stub := &stubHelmClient{history: []*v1.Release{
+		target,
+		revisionWithSize(4, common.StatusFailed, "10Gi"),
+	}}
+	rb := &autoRollback{project: "web", environment: "production", release: "web-production", namespace: "default", revision: 4, target: target}
+	c, _, _, stderr := nabatContextWithIO(t)
+
+	_, err := runApply(c, stub, nil, assertNever{}, "default", "web-production", "Deploying...", 0, rb, "", func() error {
+		return errors.New("post-upgrade hook migrate failed")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "post-upgrade hook migrate failed")
+	assert.Contains(t, err.Error(), "rolled back to revision 3")
+	assert.Equal(t, []int{3}, stub.rollbacks)
+	assert.Contains(t, stderr.String(), "rolling back to revision 3")
+}
+
+// TestAutoRollback_RollBack covers the cases where the release is left
+// alone, and a failed rollback.
+func TestAutoRollback_RollBack(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("pods not ready within the timeout (web: 0/1)")
+	tests := []struct {
+		name          string
+		target        *v1.Release
+		history       []*v1.Release
+		rollbackErr   error
+		wantRollbacks []int
+		wantErr       []string
+	}{
+		{
+			name:    "first install",
+			history: []*v1.Release{revisionWithSize(1, common.StatusDeployed, "10Gi")},
+			wantErr: []string{"no successful revision to roll back to"},
+		},
+		{
+			name:   "helm already rolled back",
+			target: revisionWithSize(3, common.StatusSuperseded, "10Gi"),
+			history: []*v1.Release{
+				revisionWithSize(3, common.StatusSuperseded, "10Gi"),
+				revisionWithSize(4, common.StatusFailed, "10Gi"),
+				revisionWithSize(5, common.StatusDeployed, "10Gi"),
+			},
+			wantErr: []string{"Helm already rolled the release back (now revision 5)"},
+		},
+		{
+			name:   "apply recorded no revision",
+			target: revisionWithSize(3, common.StatusDeployed, "10Gi"),
+			history: []*v1.Release{
+				revisionWithSize(3, common.StatusDeployed, "10Gi"),
+			},
+			wantErr: []string{"not rolled back: Helm recorded no revision 4"},
+		},
+		{
+			name:   "volumes expanded by this deploy",
+			target: revisionWithSize(3, common.StatusSuperseded, "10Gi"),
+			history: []*v1.Release{
+				revisionWithSize(3, common.StatusSuperseded, "10Gi"),
+				revisionWithSize(4, common.StatusDeployed, "20Gi"),
+			},
+			wantErr: []string{"not rolled back to revision 3", "persistence.size decrease 20Gi -> 10Gi"},
+		},
+		{
+			name:   "rollback fails",
+			target: revisionWithSize(3, common.StatusSuperseded, "10Gi"),
+			history: []*v1.Release{
+				revisionWithSize(3, common.StatusSuperseded, "10Gi"),
+				revisionWithSize(4, common.StatusDeployed, "10Gi"),
+			},
+			rollbackErr:   errors.New("timed out"),
+			wantRollbacks: []int{3},
+			wantErr:       []string{"rollback to revision 3 failed: timed out"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := &stubHelmClient{history: tt.history, rollbackErr: tt.rollbackErr}
+			rb := &autoRollback{project: "web", environment: "production", release: "web-production", namespace: "default", revision: 4, target: tt.target}
+			c := nabatContext(t)
+
+			err := rb.rollBack(c, stub, nil, assertNever{}, 0, cause)
+			require.ErrorIs(t, err, cause)
+			for _, want := range tt.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+			assert.Equal(t, tt.wantRollbacks, stub.rollbacks)
+		})
+	}
+}
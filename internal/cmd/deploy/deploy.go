@@ -1,19 +1,19 @@
 package deploy
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"os"
 	"slices"
 	"strings"
-	"sync"
 
 	"helm.sh/helm/v4/pkg/postrenderer"
 	"k8s.io/client-go/kubernetes"
 	"nabat.dev/nabat"
 
+	"deployah.dev/deployah/internal/cli"
 	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/extras"
 	"deployah.dev/deployah/internal/k8s"
 	"deployah.dev/deployah/internal/readiness"
@@ -33,6 +33,12 @@ type Options struct {
 	Yes                 bool   `nabat:"yes"`
 	Reapply             bool   `nabat:"reapply"`
 	CRDs                string `nabat:"crds"`
+	PlanFile            string `nabat:"plan-file"`
+	PlanHash            string `nabat:"plan-hash"`
+	RollbackOnFailure   bool   `nabat:"rollback-on-failure"`
+	NoRollbackOnFailure bool   `nabat:"no-rollback-on-failure"`
+	ReportFile          string `nabat:"report-file"`
+	Output              string `nabat:"output"`
 }
 
 // crdPolicies are the allowed values for --crds (same order as help text).
@@ -50,6 +56,13 @@ func Register(app *nabat.App) {
 		nabat.WithFlag("yes", false, nabat.WithShort('y'), nabat.WithUsage("Apply without an interactive confirmation prompt")),
 		nabat.WithFlag("reapply", false, nabat.WithUsage("Upgrade the release even when the plan shows no changes")),
 		nabat.WithSelectFlag("crds", string(extras.PolicyCreate), crdPolicies, nabat.WithUsage("CRD install policy: create (install if missing) or create-replace")),
+		nabat.WithFlag("plan-file", "", nabat.WithUsage("Apply a plan saved with 'deployah plan --out' exactly, instead of rendering the spec; refused if the release moved or its chart no longer renders the saved manifest")),
+		nabat.WithFlag("plan-hash", "", nabat.WithUsage("With --plan-file, refuse the file unless its sha256 is this hash, as printed by 'deployah plan --out'")),
+		nabat.WithFlag("rollback-on-failure", false, nabat.WithUsage("Roll back to the last successful revision when pods are not ready within --timeout or a postDeploy task fails (default: the platform environment's rollbackOnFailure)")),
+		nabat.WithFlag("no-rollback-on-failure", false, nabat.WithUsage("Do not roll back on failure, even when the platform environment sets rollbackOnFailure")),
+		nabat.WithFlag("report-file", "", nabat.WithUsage("When the deploy fails, also write the failure diagnosis (pod problems, recent logs, warning events) to this file as JSON")),
+		nabat.WithSelectFlag("output", cli.OutputFormatText, cli.EventOutputFormats, nabat.WithUsage("Output format: text, or jsonl to stream progress events as JSON lines on stdout (text output moves to stderr)")),
+		nabat.WithValidation(validateOptions),
 		nabat.WithExample(`
 # Deploy to production using the default spec path (./deployah.yaml)
 deployah deploy prod
@@ -66,9 +79,24 @@ deployah deploy prod --crds create-replace
 # Show resolution report before deploying
 deployah deploy prod --explain
 
+# Apply exactly the plan reviewed earlier
+deployah deploy prod --plan-file plan.dpy
+
+# Apply a plan file only if it is the one whose hash was reviewed
+deployah deploy prod --plan-file plan.dpy --plan-hash "$PLAN_HASH"
+
+# Roll back automatically if the new revision does not become ready
+deployah deploy prod --rollback-on-failure
+
+# Keep the failure diagnosis as a CI artifact
+deployah deploy prod --yes --report-file deploy-report.json
+
+# Stream progress events for a CI wrapper
+deployah deploy prod --yes --output jsonl
+
 # Preview what a deploy would change, without touching the cluster
 deployah plan prod --offline`),
-		nabat.WithRun(runDeploy),
+		nabat.WithRun(cmdopts.WithEventStream("deploy", runDeploy)),
 	)
 }
 
@@ -81,11 +109,61 @@ type deployPlan struct {
 	cleanup func()
 }
 
+// validateOptions rejects flags that need the spec, which a saved plan
+// does not render again, before runDeploy does any work.
+func validateOptions(c *nabat.Context) error {
+	opts := &Options{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+	if opts.PlanFile != "" && opts.Explain {
+		return errors.New("--explain cannot be used with --plan-file: a saved plan is not resolved again")
+	}
+	if opts.PlanHash != "" && opts.PlanFile == "" {
+		return errors.New("--plan-hash requires --plan-file")
+	}
+	if opts.PlanFile != "" && opts.ResizeVolumes {
+		return errors.New("--resize-volumes cannot be used with --plan-file; deploy without a plan file to resize volumes")
+	}
+	if opts.RollbackOnFailure && opts.NoRollbackOnFailure {
+		return errors.New("--rollback-on-failure cannot be used with --no-rollback-on-failure")
+	}
+	return nil
+}
+
+// Hooks customize a [Deploy] for commands built on top of it, such as
+// promote. The zero value deploys the spec as it is.
+type Hooks struct {
+	// Transform edits the loaded spec before it is resolved and rendered.
+	Transform func(*spec.Spec) error
+	// Done runs once the release is deployed or already matches the plan.
+	// It does not run when the deploy fails or is declined at the prompt.
+	Done func() error
+}
+
+// done runs h.Done when set.
+func (h Hooks) done() error {
+	if h.Done == nil {
+		return nil
+	}
+	return h.Done()
+}
+
 func runDeploy(c *nabat.Context) error {
 	opts := &Options{}
 	if err := c.Bind(opts); err != nil {
 		return fmt.Errorf("binding options: %w", err)
 	}
+	if opts.PlanFile != "" {
+		return runSavedPlan(c, opts)
+	}
+	return Deploy(c, opts, Hooks{})
+}
+
+// Deploy renders the spec for opts.Environment, shows the plan, and
+// applies it after confirmation, running hooks along the way. It is the
+// deploy command without a saved plan.
+func Deploy(c *nabat.Context, opts *Options, hooks Hooks) error {
 	c.Logger().Debug("starting deployment process")
 
 	sess := session.FromContext(c)
@@ -113,6 +191,12 @@ func runDeploy(c *nabat.Context) error {
 
 	c.Logger().Debug("spec loaded", "env", opts.Environment)
 
+	if hooks.Transform != nil {
+		if transformErr := hooks.Transform(manifest); transformErr != nil {
+			return transformErr
+		}
+	}
+
 	// Fail closed when any component uses expose and platform is absent.
 	if platform == nil && cmdopts.HasExposeComponents(manifest) {
 		return fmt.Errorf(
@@ -158,7 +242,7 @@ func runDeploy(c *nabat.Context) error {
 		return timeoutErr
 	}
 
-	cluster, err := sess.Target(c, opts.Environment)
+	cluster, err := sess.Target(c, manifest.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -209,13 +293,14 @@ func runDeploy(c *nabat.Context) error {
 	defer plan.cleanup()
 
 	if n := len(bundle.CRDs); n > 0 {
-		c.Printf("CRDs: %d from .deployah/crds/ (policy %s)\n", n, opts.CRDs)
+		cmdopts.Printf(c, "CRDs: %d from .deployah/crds/ (policy %s)\n", n, opts.CRDs)
 	}
 
 	textOpts := planengine.TextOptions{Mode: planengine.ModeCompact, Theme: c.Theme()}
-	if renderErr := planengine.RenderText(c.IO().Out, plan.diff, textOpts); renderErr != nil {
+	if renderErr := planengine.RenderText(cmdopts.TextOut(c), plan.diff, textOpts); renderErr != nil {
 		return fmt.Errorf("render plan: %w", renderErr)
 	}
+	emitPlan(c, plan.diff)
 
 	// Hostname guard: block FQDN changes unless --force-hostname-change.
 	// Runs after the plan diff is shown, so a block is never a surprise.
@@ -232,7 +317,7 @@ func runDeploy(c *nabat.Context) error {
 	if guardErr := checkWorkloadGuards(manifest, opts.Environment, prevResolved); guardErr != nil {
 		return guardErr
 	}
-	emitWorkloadWarnings(c, manifest, opts.Environment, prevResolved)
+	emitWorkloadWarnings(c, manifest, opts.Environment, resolvedSpec, prevResolved)
 
 	resizes := detectPersistenceResizes(manifest, opts.Environment, resolvedSpec, prevResolved)
 	if resizeFlagErr := requireResizeFlag(resizes, opts.ResizeVolumes); resizeFlagErr != nil {
@@ -241,7 +326,10 @@ func runDeploy(c *nabat.Context) error {
 
 	helmIdle := !plan.diff.HasChanges() && !opts.Reapply
 	if skipWhenIdle(helmIdle, len(bundle.CRDs)) {
-		return skipDeploy(c, k8sClient, k8sErr, plan)
+		if skipErr := skipDeploy(c, k8sClient, k8sErr, plan); skipErr != nil {
+			return skipErr
+		}
+		return hooks.done()
 	}
 
 	// Required-API check runs before confirmation: a missing CRD/API is a
@@ -277,14 +365,27 @@ func runDeploy(c *nabat.Context) error {
 		return confirmErr
 	}
 	if !proceed {
-		c.Println("Aborted.")
+		cmdopts.Println(c, "Aborted.")
+		eventlog.FromContext(c).SetOutcome(eventlog.OutcomeCancelled)
 		return nil
 	}
 
 	if helmIdle {
-		return applyCRDsOnly(c, sess, cluster, k8sClient, k8sErr, plan, bundle, opts)
+		if applyErr := applyCRDsOnly(c, sess, cluster, k8sClient, k8sErr, plan, bundle, opts); applyErr != nil {
+			return applyErr
+		}
+		return hooks.done()
+	}
+	// CRDs live outside the release, so only a Helm apply takes the lock.
+	unlock, lockErr := cmdopts.LockDeploy(c, cluster, manifest.Project, opts.Environment)
+	if lockErr != nil {
+		return lockErr
+	}
+	defer unlock()
+	if applyErr := applyDeploy(c, sess, cluster, helmClient, platform, manifest, opts, resolvedSpec, plan, k8sClient, k8sErr, bundle, postRenderer, resizes); applyErr != nil {
+		return applyErr
 	}
-	return applyDeploy(c, sess, cluster, helmClient, platform, manifest, opts, resolvedSpec, plan, k8sClient, k8sErr, bundle, postRenderer, resizes)
+	return hooks.done()
 }
 
 // skipWhenIdle reports whether deploy should exit without cluster writes:
@@ -305,9 +406,21 @@ func confirmApply(c *nabat.Context, opts *Options, prompt string) (proceed bool,
 	if confirmErr != nil {
 		return false, confirmErr
 	}
+	if opts.Yes {
+		eventlog.FromContext(c).Emit(eventlog.TypeConfirmationSkipped, nil)
+	}
 	return confirmed, nil
 }
 
+// emitPlan sends p to the event stream in the `deployah plan --output
+// json` format. It runs after the text plan is rendered, since the JSON
+// document masks secret values on p.
+func emitPlan(c *nabat.Context, p *planengine.Plan) {
+	if events := eventlog.FromContext(c); events != nil {
+		events.Emit(eventlog.TypePlanComputed, planengine.NewJSONDocument(p))
+	}
+}
+
 // computePlan renders the chart client-side and diffs it against the last
 // successful release. It never mutates the cluster or Helm's release history.
 // The caller must invoke deployPlan.cleanup when finished with the result.
@@ -325,6 +438,7 @@ func computePlan(c *nabat.Context, helmClient session.HelmClient, cluster *sessi
 // a real deploy.
 func skipDeploy(c *nabat.Context, k8sClient kubernetes.Interface, k8sErr error, plan *deployPlan) error {
 	c.Success(fmt.Sprintf("No changes. Release %s unchanged (revision %d).", plan.diff.Header.Release, plan.diff.Header.Revision))
+	eventlog.FromContext(c).SetOutcome(eventlog.OutcomeUnchanged)
 	return printReadiness(c, k8sClient, k8sErr, plan)
 }
 
@@ -372,7 +486,7 @@ func printReadiness(c *nabat.Context, k8sClient kubernetes.Interface, k8sErr err
 		return nil
 	}
 	if summary := readiness.Summary(statuses); summary != "" {
-		c.Println("Readiness: " + summary)
+		cmdopts.Println(c, "Readiness: "+summary)
 	}
 	return nil
 }
@@ -390,13 +504,19 @@ func applyBundleCRDs(c *nabat.Context, sess *session.Session, cluster *session.C
 	if crdErr != nil {
 		return stats, fmt.Errorf("apply CRDs: %w%s", crdErr, cmdopts.ClusterHint(crdErr))
 	}
+	eventlog.FromContext(c).Emit(eventlog.TypeCRDsApplied, eventlog.CRDsApplied{
+		Created:  stats.Created,
+		Replaced: stats.Replaced,
+		Ready:    stats.Ready,
+	})
 	return stats, nil
 }
 
 // applyDeploy re-renders and verifies determinism before the real Helm
 // install/upgrade. CRDs from the bundle are applied first. When resizes is
 // non-empty, PVC expansion (and StatefulSet orphan-delete when needed) run
-// before Helm.
+// before Helm. With rollback on failure, a release that does not become
+// ready is rolled back to the last successful revision; see [runApply].
 func applyDeploy(c *nabat.Context, sess *session.Session, cluster *session.Cluster, helmClient session.HelmClient, platform *spec.PlatformConfig, manifest *spec.Spec, opts *Options, resolved *spec.ResolvedSpec, plan *deployPlan, k8sClient kubernetes.Interface, k8sErr error, bundle *extras.Bundle, postRenderer postrenderer.PostRenderer, resizes []persistenceResize) error {
 	verify, verifyCleanup, err := helmClient.RenderManifests(c, manifest, opts.Environment, resolved, postRenderer)
 	if verifyCleanup != nil {
@@ -411,6 +531,24 @@ func applyDeploy(c *nabat.Context, sess *session.Session, cluster *session.Clust
 		return errors.New("rendered manifests changed between plan and apply; re-run 'deployah deploy' to see the current plan")
 	}
 
+	if ns := cluster.PlatformNamespace(); ns != nil {
+		if k8sErr != nil {
+			return fmt.Errorf("ensure namespace %s: kubernetes client unavailable: %w", cluster.Namespace(), k8sErr)
+		}
+		created, nsErr := k8s.EnsureNamespace(c, k8sClient, cluster.Namespace(), ns.Labels, ns.Annotations)
+		if nsErr != nil {
+			return fmt.Errorf("%w%s", nsErr, cmdopts.ClusterHint(nsErr))
+		}
+		if created {
+			cmdopts.Printf(c, "Created namespace %s\n", cluster.Namespace())
+		}
+	}
+
+	rb, rbErr := prepareAutoRollback(c, helmClient, manifest.Project, opts.Environment, plan.result.ReleaseName, cluster.Namespace(), plan.result.Revision, rollbackOnFailure(opts, platform))
+	if rbErr != nil {
+		return rbErr
+	}
+
 	crdStats, crdErr := applyBundleCRDs(c, sess, cluster, bundle, opts)
 	if crdErr != nil {
 		return crdErr
@@ -420,7 +558,7 @@ func applyDeploy(c *nabat.Context, sess *session.Session, cluster *session.Clust
 		if k8sErr != nil {
 			return fmt.Errorf("resize volumes: kubernetes client unavailable: %w", k8sErr)
 		}
-		c.Printf("Resizing volumes for %d component(s)...\n", len(resizes))
+		cmdopts.Printf(c, "Resizing volumes for %d component(s)...\n", len(resizes))
 		if resizeErr := resizeVolumes(c, k8sClient, cluster.Namespace(), plan.result.ReleaseName, resizes); resizeErr != nil {
 			return fmt.Errorf("%s: %w", resizeFailureHint(resizes), resizeErr)
 		}
@@ -442,39 +580,11 @@ func applyDeploy(c *nabat.Context, sess *session.Session, cluster *session.Clust
 
 	// k8sClient/k8sErr come from runDeploy's single cluster.Kubernetes()
 	// call; the required-API check already ran there, before confirmation.
-	var watcher *DeployWatcher
-	if k8sErr != nil {
-		// K8s client is best-effort: skip the event watcher rather than
-		// failing the whole deploy.
-		c.Logger().Debug("skipping deploy watcher: k8s client unavailable", "err", k8sErr)
-	} else {
-		watcher = NewDeployWatcher(k8sClient, cluster.Namespace(), plan.result.ReleaseName)
-	}
-
-	err = c.Status(func(st *nabat.Status) error {
-		var wg sync.WaitGroup
-		var cancel context.CancelFunc
-		if watcher != nil {
-			var watchCtx context.Context
-			watchCtx, cancel = context.WithCancel(c)
-			wg.Go(func() {
-				watcher.Run(watchCtx, st)
-			})
-		}
-		helmErr := helmClient.InstallApp(c, manifest, opts.Environment, false, resolved, postRenderer)
-		if cancel != nil {
-			cancel()
-		}
-		wg.Wait()
-		return helmErr
-	}, nabat.WithTitle(title))
+	watcher, err := runApply(c, helmClient, k8sClient, k8sErr, cluster.Namespace(), plan.result.ReleaseName, title, sess.Timeout(), rb, opts.ReportFile, func() error {
+		return helmClient.InstallApp(c, manifest, opts.Environment, false, resolved, postRenderer)
+	})
 	if err != nil {
-		if watcher != nil {
-			for _, w := range watcher.Warnings() {
-				c.Warn(fmt.Sprintf("[%s] %s: %s", w.Object, w.Reason, w.Message))
-			}
-		}
-		return fmt.Errorf("deploy failed: %w%s", err, cmdopts.ClusterHint(err))
+		return err
 	}
 
 	summary := buildSummaryMsg(watcher)
@@ -648,19 +758,19 @@ func warnContextMismatch(c *nabat.Context, kubeCtxOverride string, platform *spe
 
 // printExplain prints the resolution report before cluster checks.
 func printExplain(c *nabat.Context, resolved *spec.ResolvedSpec) {
-	c.Println("--- Resolution Report ---")
-	c.Println(fmt.Sprintf("Environment: %s", resolved.Env.Original))
+	cmdopts.Println(c, "--- Resolution Report ---")
+	cmdopts.Printf(c, "Environment: %s\n", resolved.Env.Original)
 	if resolved.KubeContext != "" {
-		c.Println(fmt.Sprintf("Context:     %s", resolved.KubeContext))
+		cmdopts.Printf(c, "Context:     %s\n", resolved.KubeContext)
 	}
 	for name, rc := range resolved.Components {
 		if rc.FQDN == "" {
 			continue
 		}
-		c.Println(fmt.Sprintf("  %s: hostname=%s tls=%s", name, rc.FQDN, rc.TLSMode))
+		cmdopts.Printf(c, "  %s: hostname=%s tls=%s\n", name, rc.FQDN, rc.TLSMode)
 	}
 	for _, w := range resolved.Warnings {
 		c.Warn(w)
 	}
-	c.Println("--- End Report ---")
+	cmdopts.Println(c, "--- End Report ---")
 }
@@ -52,7 +52,7 @@ func newClusterWithStub(t *testing.T, stub *stubHelmClient, k8sClient kubernetes
 			return k8sClient, nil
 		}),
 	)
-	cluster, err := sess.Target(t.Context(), "production")
+	cluster, err := sess.Target(t.Context(), "web", "production")
 	require.NoError(t, err)
 	return cluster
 }
@@ -368,7 +368,7 @@ func TestApplyBundleCRDs_RESTConfigError(t *testing.T) {
 			return stub, nil
 		}),
 	)
-	cluster, err := sess.Target(t.Context(), "production")
+	cluster, err := sess.Target(t.Context(), "web", "production")
 	require.NoError(t, err)
 	c := nabatContext(t)
 	opts := &Options{Environment: "production", CRDs: string(extras.PolicyCreate)}
@@ -445,7 +445,7 @@ func TestApplyDeploy_PropagatesCRDApplyError(t *testing.T) {
 			return stub, nil
 		}),
 	)
-	cluster, err := sess.Target(t.Context(), "production")
+	cluster, err := sess.Target(t.Context(), "web", "production")
 	require.NoError(t, err)
 	planned := &deployPlan{
 		diff:    &planengine.Plan{},
@@ -5,6 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 	"time"
@@ -27,9 +30,9 @@ import (
 	v1 "helm.sh/helm/v4/pkg/release/v1"
 )
 
-// stubHelmClient implements [session.HelmClient] for unit tests. Only
-// GetRelease and RenderManifests are wired; every other method panics if
-// called unexpectedly.
+// stubHelmClient implements [session.HelmClient] for unit tests. The
+// install, render, history, and rollback methods are wired; every other
+// method panics if called unexpectedly.
 type stubHelmClient struct {
 	release    *v1.Release
 	releaseErr error
@@ -43,6 +46,28 @@ type stubHelmClient struct {
 
 	installErr       error
 	installCallCount int
+
+	// chartInstalls records InstallChart calls: the files of the chart
+	// directory at call time, and the values passed.
+	chartInstalls []installedChart
+
+	// chartRender is returned by RenderChart; chartRenderErr, if set,
+	// instead.
+	chartRender    *render.RenderResult
+	chartRenderErr error
+
+	// history, when set, is returned by GetReleaseHistory instead of the
+	// single entry derived from release.
+	history []*v1.Release
+
+	// rollbacks records the revision of each RollbackRelease call.
+	rollbacks   []int
+	rollbackErr error
+}
+
+type installedChart struct {
+	files  map[string]string
+	values map[string]any
 }
 
 func (s *stubHelmClient) IsReachable() error { return nil }
@@ -52,6 +77,37 @@ func (s *stubHelmClient) InstallApp(context.Context, *spec.Spec, string, bool, *
 	return s.installErr
 }
 
+func (s *stubHelmClient) InstallChart(_ context.Context, _, _, _, chartPath string, values map[string]any, _ postrenderer.PostRenderer) error {
+	files := map[string]string{}
+	err := filepath.WalkDir(chartPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p) // #nosec G304 -- test chart directory
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(chartPath, p)
+		files[filepath.ToSlash(rel)] = string(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.chartInstalls = append(s.chartInstalls, installedChart{files: files, values: values})
+	return s.installErr
+}
+
+func (s *stubHelmClient) RenderChart(context.Context, string, string, string, string, map[string]any, postrenderer.PostRenderer) (*render.RenderResult, error) {
+	if s.chartRenderErr != nil {
+		return nil, s.chartRenderErr
+	}
+	if s.chartRender == nil {
+		panic("unexpected RenderChart call: no result configured")
+	}
+	return s.chartRender, nil
+}
+
 func (s *stubHelmClient) RenderManifests(context.Context, *spec.Spec, string, *spec.ResolvedSpec, postrenderer.PostRenderer) (*render.RenderResult, func(), error) {
 	if s.renderErr != nil {
 		return nil, func() {}, s.renderErr
@@ -83,13 +139,17 @@ func (s *stubHelmClient) ListReleases(context.Context, labels.Selector) ([]*v1.R
 	panic("unexpected ListReleases call")
 }
 
-// GetReleaseHistory derives a single-entry history from release/releaseErr,
-// defaulting Info to "deployed" when unset, so old GetRelease-based test
-// fixtures still work against LastSuccessfulRelease's status check.
+// GetReleaseHistory returns history when set. Otherwise it derives a
+// single-entry history from release/releaseErr, defaulting Info to
+// "deployed" when unset, so old GetRelease-based test fixtures still work
+// against LastSuccessfulRelease's status check.
 func (s *stubHelmClient) GetReleaseHistory(context.Context, string, string) ([]*v1.Release, error) {
 	if s.releaseErr != nil {
 		return nil, s.releaseErr
 	}
+	if s.history != nil {
+		return s.history, nil
+	}
 	if s.release == nil {
 		return nil, nil
 	}
@@ -102,8 +162,9 @@ func (s *stubHelmClient) GetReleaseHistory(context.Context, string, string) ([]*
 	return []*v1.Release{rel}, nil
 }
 
-func (s *stubHelmClient) RollbackRelease(context.Context, string, int, time.Duration) error {
-	panic("unexpected RollbackRelease call")
+func (s *stubHelmClient) RollbackRelease(_ context.Context, _ string, revision int, _ time.Duration) error {
+	s.rollbacks = append(s.rollbacks, revision)
+	return s.rollbackErr
 }
 
 var _ session.HelmClient = (*stubHelmClient)(nil)
@@ -64,90 +64,144 @@ func checkWorkloadGuards(
 		if !ok {
 			continue
 		}
+		errors = append(errors, workloadTransitionErrors(name, resolvedWorkloadShape(prev), componentWorkloadShape(component))...)
+	}
+	return workloadGuardError(manifest.Project, environment, errors)
+}
 
-		wantKind := "Deployment"
-		if component.Kind == spec.ComponentKindStateful {
-			wantKind = "StatefulSet"
-		}
-		prevKind, hasKind := prev["workloadKind"].(string)
-		if hasKind && prevKind != "" && prevKind != wantKind {
-			errors = append(errors, fmt.Sprintf(
-				"  %s: kind change %s -> %s is not supported; delete the release and redeploy",
-				name, prevKind, wantKind,
-			))
+// CheckReleaseTransition applies the same workload rules as deploy to a
+// move between two existing releases, e.g. a rollback from the current
+// revision to an older one. Both arguments are Helm chart values carrying
+// the deployah.resolved block; components present on only one side are
+// not checked, since Helm simply adds or removes them.
+func CheckReleaseTransition(project, environment string, currentValues, targetValues map[string]any) error {
+	current := previousResolvedComponents(currentValues)
+	target := previousResolvedComponents(targetValues)
+	var errors []string
+	for name, next := range target {
+		prev, ok := current[name]
+		if !ok {
+			continue
 		}
+		errors = append(errors, workloadTransitionErrors(name, resolvedWorkloadShape(prev), resolvedWorkloadShape(next))...)
+	}
+	return workloadGuardError(project, environment, errors)
+}
 
-		wantRole := string(component.Role)
-		if wantRole == "" {
-			wantRole = string(spec.ComponentRoleService)
-		}
-		// Missing or empty previous role means service (the only role before
-		// workers existed). Always compare so service -> worker upgrades are
-		// rejected even when the prior release omitted role from resolved values.
-		prevRole, hasRole := prev["role"].(string)
-		if !hasRole || prevRole == "" {
-			prevRole = string(spec.ComponentRoleService)
-		}
-		if prevRole != wantRole {
-			errors = append(errors, fmt.Sprintf(
-				"  %s: role change %s -> %s is not supported; delete the release and redeploy",
-				name, prevRole, wantRole,
-			))
-		}
+// workloadShape is the part of a component that [checkWorkloadGuards]
+// compares across releases.
+type workloadShape struct {
+	// kind is "Deployment" or "StatefulSet"; empty when a release predates
+	// the resolved workloadKind field.
+	kind            string
+	role            string
+	hasPersistence  bool
+	persistenceSize string
+}
 
-		prevSize, hasPrevSize := prev["persistenceSize"].(string)
-		prevHadPersistence := hasPrevSize && prevSize != ""
-		nowHasPersistence := component.Persistence != nil
-		// volumeClaimTemplates are immutable: adding or removing persistence
-		// on a StatefulSet (past or present) requires delete + redeploy.
-		wasOrWillBeStateful := wantKind == "StatefulSet" || prevKind == "StatefulSet"
-		if wasOrWillBeStateful && prevHadPersistence != nowHasPersistence {
-			switch {
-			case !prevHadPersistence && nowHasPersistence:
-				errors = append(errors, fmt.Sprintf(
-					"  %s: adding persistence to an existing StatefulSet is not supported; delete the release and redeploy",
-					name,
-				))
-			case prevHadPersistence && !nowHasPersistence:
-				errors = append(errors, fmt.Sprintf(
-					"  %s: removing persistence from an existing StatefulSet is not supported; delete the release and redeploy",
-					name,
-				))
-			}
-		}
+// componentWorkloadShape returns the shape a deploy of component would have.
+func componentWorkloadShape(component spec.Component) workloadShape {
+	shape := workloadShape{kind: "Deployment", role: string(component.Role)}
+	if component.Kind == spec.ComponentKindStateful {
+		shape.kind = "StatefulSet"
+	}
+	if shape.role == "" {
+		shape.role = string(spec.ComponentRoleService)
+	}
+	if component.Persistence != nil {
+		shape.hasPersistence = true
+		shape.persistenceSize = component.Persistence.Size
+	}
+	return shape
+}
 
-		if component.Persistence == nil || prevSize == "" {
-			continue
-		}
-		decreased, cmpErr := persistenceSizeDecreased(prevSize, component.Persistence.Size)
-		if cmpErr != nil {
-			errors = append(errors, fmt.Sprintf("  %s: %v", name, cmpErr))
-			continue
-		}
-		if decreased {
+// resolvedWorkloadShape reads a shape from one deployah.resolved.components
+// entry. A missing or empty role means service (the only role before
+// workers existed), so service -> worker changes are rejected even when the
+// prior release omitted role from resolved values.
+func resolvedWorkloadShape(resolved map[string]any) workloadShape {
+	kind, _ := resolved["workloadKind"].(string)
+	role, _ := resolved["role"].(string)
+	if role == "" {
+		role = string(spec.ComponentRoleService)
+	}
+	size, _ := resolved["persistenceSize"].(string)
+	return workloadShape{kind: kind, role: role, hasPersistence: size != "", persistenceSize: size}
+}
+
+// workloadTransitionErrors lists the reasons moving component name from
+// prev to next is rejected, one indented line each.
+func workloadTransitionErrors(name string, prev, next workloadShape) []string {
+	var errors []string
+	if prev.kind != "" && next.kind != "" && prev.kind != next.kind {
+		errors = append(errors, fmt.Sprintf(
+			"  %s: kind change %s -> %s is not supported; delete the release and redeploy",
+			name, prev.kind, next.kind,
+		))
+	}
+
+	if prev.role != next.role {
+		errors = append(errors, fmt.Sprintf(
+			"  %s: role change %s -> %s is not supported; delete the release and redeploy",
+			name, prev.role, next.role,
+		))
+	}
+
+	// volumeClaimTemplates are immutable: adding or removing persistence
+	// on a StatefulSet (past or present) requires delete + redeploy.
+	wasOrWillBeStateful := next.kind == "StatefulSet" || prev.kind == "StatefulSet"
+	if wasOrWillBeStateful && prev.hasPersistence != next.hasPersistence {
+		switch {
+		case !prev.hasPersistence && next.hasPersistence:
+			errors = append(errors, fmt.Sprintf(
+				"  %s: adding persistence to an existing StatefulSet is not supported; delete the release and redeploy",
+				name,
+			))
+		case prev.hasPersistence && !next.hasPersistence:
 			errors = append(errors, fmt.Sprintf(
-				"  %s: persistence.size decrease %s -> %s is not supported",
-				name, prevSize, component.Persistence.Size,
+				"  %s: removing persistence from an existing StatefulSet is not supported; delete the release and redeploy",
+				name,
 			))
 		}
 	}
 
+	if !next.hasPersistence || prev.persistenceSize == "" {
+		return errors
+	}
+	decreased, cmpErr := persistenceSizeDecreased(prev.persistenceSize, next.persistenceSize)
+	if cmpErr != nil {
+		return append(errors, fmt.Sprintf("  %s: %v", name, cmpErr))
+	}
+	if decreased {
+		errors = append(errors, fmt.Sprintf(
+			"  %s: persistence.size decrease %s -> %s is not supported",
+			name, prev.persistenceSize, next.persistenceSize,
+		))
+	}
+	return errors
+}
+
+// workloadGuardError joins guard violations into one sorted error, or
+// returns nil when there are none.
+func workloadGuardError(project, environment string, errors []string) error {
 	if len(errors) == 0 {
 		return nil
 	}
 	slices.Sort(errors)
 	return fmt.Errorf(
 		"workload change rejected for %s/%s:\n%s",
-		manifest.Project, environment, strings.Join(errors, "\n"),
+		project, environment, strings.Join(errors, "\n"),
 	)
 }
 
 // emitWorkloadWarnings prints non-fatal plan warnings for stateful/HPA,
-// multi-replica expose, and mountPath changes.
+// multi-replica expose, mountPath changes, and disruption budgets that
+// block every eviction. resolved supplies profile defaults and may be nil.
 func emitWorkloadWarnings(
 	c *nabat.Context,
 	manifest *spec.Spec,
 	environment string,
+	resolved *spec.ResolvedSpec,
 	prevResolved map[string]map[string]any,
 ) {
 	var warnings []string
@@ -180,6 +234,17 @@ func emitWorkloadWarnings(
 			}
 		}
 
+		var profile *spec.PlatformProfile
+		if resolved != nil {
+			profile = resolved.Components[name].MergedProfile
+		}
+		if d := spec.EffectiveDisruption(component, profile); d.BlocksEvictions(component.MinReplicas()) {
+			warnings = append(warnings, fmt.Sprintf(
+				"  %s: disruption %s with %d replicas allows no evictions; node drains will stall until the budget is relaxed",
+				name, d, component.MinReplicas(),
+			))
+		}
+
 		if component.Persistence != nil {
 			if prev, ok := prevResolved[name]; ok {
 				if prevMount, hasMount := prev["persistenceMountPath"].(string); hasMount &&
@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"deployah.dev/deployah/internal/spec"
 
@@ -151,6 +152,62 @@ func TestCheckWorkloadGuards_PersistenceRemoveRejected(t *testing.T) {
 	assert.Contains(t, err.Error(), "removing persistence")
 }
 
+func TestCheckReleaseTransition(t *testing.T) {
+	t.Parallel()
+	current := releaseWithResolved("db", map[string]any{
+		"workloadKind":    "StatefulSet",
+		"role":            "service",
+		"persistenceSize": "20Gi",
+	}).Chart.Values
+
+	tests := []struct {
+		name    string
+		target  map[string]any
+		wantErr string
+	}{
+		{
+			name:   "same shape",
+			target: map[string]any{"workloadKind": "StatefulSet", "role": "service", "persistenceSize": "20Gi"},
+		},
+		{
+			name:    "kind change",
+			target:  map[string]any{"workloadKind": "Deployment", "role": "service"},
+			wantErr: "kind change StatefulSet -> Deployment",
+		},
+		{
+			name:    "role change",
+			target:  map[string]any{"workloadKind": "StatefulSet", "role": "worker", "persistenceSize": "20Gi"},
+			wantErr: "role change service -> worker",
+		},
+		{
+			name:    "volume shrink",
+			target:  map[string]any{"workloadKind": "StatefulSet", "persistenceSize": "10Gi"},
+			wantErr: "persistence.size decrease 20Gi -> 10Gi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := CheckReleaseTransition("shop", "production", current, releaseWithResolved("db", tt.target).Chart.Values)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "workload change rejected for shop/production")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckReleaseTransition_ComponentOnOneSideIgnored(t *testing.T) {
+	t.Parallel()
+	current := releaseWithResolved("db", map[string]any{"workloadKind": "StatefulSet"}).Chart.Values
+	target := releaseWithResolved("web", map[string]any{"workloadKind": "Deployment"}).Chart.Values
+	require.NoError(t, CheckReleaseTransition("shop", "production", current, target))
+	require.NoError(t, CheckReleaseTransition("shop", "production", nil, target))
+}
+
 func TestPersistenceSizeDecreased(t *testing.T) {
 	t.Parallel()
 	decreased, err := persistenceSizeDecreased("20Gi", "10Gi")
@@ -194,7 +251,7 @@ func TestEmitWorkloadWarnings_HPAOnStatefulWithPersistence(t *testing.T) {
 			},
 		},
 	}
-	emitWorkloadWarnings(c, manifest, "dev", map[string]map[string]any{})
+	emitWorkloadWarnings(c, manifest, "dev", nil, map[string]map[string]any{})
 	assert.Contains(t, stderr.String(), "retains PVCs on scale-down")
 }
 
@@ -211,7 +268,7 @@ func TestEmitWorkloadWarnings_ExposeMultiReplicaStateful(t *testing.T) {
 			},
 		},
 	}
-	emitWorkloadWarnings(c, manifest, "dev", map[string]map[string]any{})
+	emitWorkloadWarnings(c, manifest, "dev", nil, map[string]map[string]any{})
 	assert.Contains(t, stderr.String(), "expose with replicas > 1")
 }
 
@@ -233,12 +290,48 @@ func TestEmitWorkloadWarnings_MountPathChange(t *testing.T) {
 			},
 		},
 	}
-	emitWorkloadWarnings(c, manifest, "dev", prev)
+	emitWorkloadWarnings(c, manifest, "dev", nil, prev)
 	assert.Contains(t, stderr.String(), "persistence.mountPath change")
 	assert.Contains(t, stderr.String(), "/old/data")
 	assert.Contains(t, stderr.String(), "/new/data")
 }
 
+func TestEmitWorkloadWarnings_DisruptionBlocksEvictions(t *testing.T) {
+	t.Parallel()
+	c, _, _, stderr := nabatContextWithIO(t)
+	replicas := 2
+	minAvailable := intstr.FromInt32(2)
+	manifest := &spec.Spec{
+		Components: map[string]spec.Component{
+			"api": {
+				Replicas:   &replicas,
+				Disruption: &spec.Disruption{MinAvailable: &minAvailable},
+			},
+		},
+	}
+	emitWorkloadWarnings(c, manifest, "dev", nil, map[string]map[string]any{})
+	assert.Contains(t, stderr.String(), "api: disruption minAvailable 2 with 2 replicas allows no evictions")
+}
+
+func TestEmitWorkloadWarnings_ProfileDisruptionBlocksEvictions(t *testing.T) {
+	t.Parallel()
+	c, _, _, stderr := nabatContextWithIO(t)
+	maxUnavailable := intstr.FromString("0%")
+	manifest := &spec.Spec{
+		Components: map[string]spec.Component{
+			"api": {Autoscaling: &spec.Autoscaling{Enabled: true, MinReplicas: 3, MaxReplicas: 6}},
+		},
+	}
+	resolved := &spec.ResolvedSpec{
+		Spec: manifest,
+		Components: map[string]spec.ResolvedComponent{
+			"api": {MergedProfile: &spec.PlatformProfile{Disruption: &spec.Disruption{MaxUnavailable: &maxUnavailable}}},
+		},
+	}
+	emitWorkloadWarnings(c, manifest, "dev", resolved, map[string]map[string]any{})
+	assert.Contains(t, stderr.String(), "api: disruption maxUnavailable 0% with 3 replicas")
+}
+
 func TestEmitWorkloadWarnings_NoWarningsWhenClean(t *testing.T) {
 	t.Parallel()
 	c, _, _, stderr := nabatContextWithIO(t)
@@ -247,7 +340,7 @@ func TestEmitWorkloadWarnings_NoWarningsWhenClean(t *testing.T) {
 			"web": {Kind: spec.ComponentKindStateless},
 		},
 	}
-	emitWorkloadWarnings(c, manifest, "dev", map[string]map[string]any{})
+	emitWorkloadWarnings(c, manifest, "dev", nil, map[string]map[string]any{})
 	assert.Empty(t, stderr.String())
 }
 
@@ -0,0 +1,342 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v4/pkg/postrenderer"
+	"k8s.io/client-go/kubernetes"
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/eventlog"
+	"deployah.dev/deployah/internal/extras"
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/session"
+
+	planengine "deployah.dev/deployah/internal/plan"
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+// runSavedPlan applies a plan file written by `deployah plan --out` instead
+// of rendering the spec: the chart, values, extras, and CRDs in the file
+// are installed as they are. The file is refused when it is not the one
+// --plan-hash names, was made for another environment, namespace, or
+// context, when the release has moved past the revision the plan was
+// computed against, or when its chart no longer renders its manifest.
+func runSavedPlan(c *nabat.Context, opts *Options) error {
+	sess := session.FromContext(c)
+
+	saved, hash, err := planengine.ReadSavedPlan(opts.PlanFile)
+	if err != nil {
+		return err
+	}
+	c.Logger().Debug("plan file loaded", "path", opts.PlanFile, "sha256", hash)
+	if opts.PlanHash != "" && !strings.EqualFold(opts.PlanHash, hash) {
+		return fmt.Errorf("plan file %s has sha256 %s, not the reviewed %s", opts.PlanFile, hash, opts.PlanHash)
+	}
+	if saved.Environment != opts.Environment {
+		return fmt.Errorf("plan file %s was made for environment %q, not %q", opts.PlanFile, saved.Environment, opts.Environment)
+	}
+
+	cluster, err := sess.Target(c, saved.Project, saved.Environment)
+	if err != nil {
+		return fmt.Errorf("target cluster: %w", err)
+	}
+	if targetErr := checkSavedPlanTarget(saved, cluster); targetErr != nil {
+		return targetErr
+	}
+
+	helmClient, err := cluster.Helm()
+	if err != nil {
+		return fmt.Errorf("helm client: %w%s", err, cmdopts.ClusterHint(err))
+	}
+	if reachErr := helmClient.IsReachable(); reachErr != nil {
+		return fmt.Errorf("%w%s", reachErr, cmdopts.ClusterHint(reachErr))
+	}
+	cmdopts.WarnContextFallback(c, cluster, opts.Environment)
+
+	if baseErr := checkSavedPlanBase(c, helmClient, saved); baseErr != nil {
+		return baseErr
+	}
+
+	diff, currentValues, err := savedPlanDiff(c, helmClient, cluster, saved)
+	if err != nil {
+		return err
+	}
+	if n := len(saved.CRDs); n > 0 {
+		cmdopts.Printf(c, "CRDs: %d from the plan file (policy %s)\n", n, opts.CRDs)
+	}
+	textOpts := planengine.TextOptions{Mode: planengine.ModeCompact, Theme: c.Theme()}
+	if renderErr := planengine.RenderText(cmdopts.TextOut(c), diff, textOpts); renderErr != nil {
+		return fmt.Errorf("render plan: %w", renderErr)
+	}
+	emitPlan(c, diff)
+
+	targetValues, err := saved.ChartValues()
+	if err != nil {
+		return err
+	}
+	if guardErr := checkSavedPlanHostnames(c, saved, currentValues, targetValues, opts.ForceHostnameChange); guardErr != nil {
+		return guardErr
+	}
+	if guardErr := CheckReleaseTransition(saved.Project, saved.Environment, currentValues, targetValues); guardErr != nil {
+		return guardErr
+	}
+
+	if skipWhenIdle(!diff.HasChanges() && !opts.Reapply, len(saved.CRDs)) {
+		c.Success(fmt.Sprintf("No changes. Release %s unchanged (revision %d).", diff.Header.Release, diff.Header.Revision))
+		eventlog.FromContext(c).SetOutcome(eventlog.OutcomeUnchanged)
+		return nil
+	}
+
+	proceed, confirmErr := confirmApply(c, opts, "Apply this plan?")
+	if confirmErr != nil {
+		return confirmErr
+	}
+	if !proceed {
+		cmdopts.Println(c, "Aborted.")
+		eventlog.FromContext(c).SetOutcome(eventlog.OutcomeCancelled)
+		return nil
+	}
+
+	unlock, lockErr := cmdopts.LockDeploy(c, cluster, saved.Project, saved.Environment)
+	if lockErr != nil {
+		return lockErr
+	}
+	defer unlock()
+	// The lock was taken after the base check above; check again so a
+	// deploy that landed in between is not overwritten.
+	if baseErr := checkSavedPlanBase(c, helmClient, saved); baseErr != nil {
+		return baseErr
+	}
+
+	k8sClient, k8sErr := cluster.Kubernetes()
+	if k8sErr != nil {
+		c.Logger().Debug("kubernetes client unavailable", "err", k8sErr)
+	}
+	return applySavedPlan(c, sess, cluster, helmClient, saved, opts, k8sClient, k8sErr)
+}
+
+// checkSavedPlanTarget refuses a plan whose namespace or kube context
+// differs from the one this invocation resolves, e.g. because --context
+// or --namespace was passed to only one of plan and deploy.
+func checkSavedPlanTarget(saved *planengine.SavedPlan, cluster *session.Cluster) error {
+	if saved.Namespace != cluster.Namespace() {
+		return fmt.Errorf("plan file targets namespace %q, but this deploy targets %q", saved.Namespace, cluster.Namespace())
+	}
+	if saved.Context != cluster.Context() {
+		return fmt.Errorf("plan file targets context %q, but this deploy targets %q", saved.Context, cluster.Context())
+	}
+	return nil
+}
+
+// checkSavedPlanBase refuses a stale plan: any new revision, including a
+// failed one, means the cluster is no longer in the state the plan was
+// reviewed against.
+func checkSavedPlanBase(c *nabat.Context, helmClient session.HelmClient, saved *planengine.SavedPlan) error {
+	latest, err := planengine.LatestRevision(c, helmClient, saved.Project, saved.Environment)
+	if err != nil {
+		return fmt.Errorf("%w%s", err, cmdopts.ClusterHint(err))
+	}
+	if latest != saved.BaseRevision {
+		return fmt.Errorf(
+			"release %s moved from revision %d to %d since the plan was made; run 'deployah plan --out' again",
+			saved.Release, saved.BaseRevision, latest,
+		)
+	}
+	return nil
+}
+
+// checkSavedPlanHostnames is the hostname guard for a saved plan. With no
+// resolved spec to hand, it compares the fqdn each component records in the
+// deployah.resolved block of the last successful release and of the plan.
+func checkSavedPlanHostnames(c *nabat.Context, saved *planengine.SavedPlan, currentValues, targetValues map[string]any, force bool) error {
+	current := previousResolvedComponents(currentValues)
+	var changed []string
+	for name, next := range previousResolvedComponents(targetValues) {
+		prevFQDN, _ := current[name]["fqdn"].(string)
+		nextFQDN, _ := next["fqdn"].(string)
+		if prevFQDN != "" && nextFQDN != "" && prevFQDN != nextFQDN {
+			changed = append(changed, fmt.Sprintf("  %s: %s -> %s", name, prevFQDN, nextFQDN))
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	slices.Sort(changed)
+	if force {
+		for _, line := range changed {
+			c.Warn(fmt.Sprintf("hostname change (may drop live traffic), continuing because --force-hostname-change is set:%s", line))
+		}
+		return nil
+	}
+	return fmt.Errorf(
+		"hostname change detected for %s/%s (pass --force-hostname-change to override):\n%s",
+		saved.Project, saved.Environment, strings.Join(changed, "\n"),
+	)
+}
+
+// savedPlanDiff diffs the saved manifest against the last successful
+// release, the same comparison `deployah plan` showed, and returns that
+// release's chart values for the workload guards.
+func savedPlanDiff(c *nabat.Context, helmClient session.HelmClient, cluster *session.Cluster, saved *planengine.SavedPlan) (*planengine.Plan, map[string]any, error) {
+	prevRelease, warning, err := planengine.LastSuccessfulRelease(c, helmClient, saved.Project, saved.Environment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("release history: %w%s", err, cmdopts.ClusterHint(err))
+	}
+
+	var previousManifest string
+	var previousHooks []*v1.Hook
+	var currentValues map[string]any
+	revision := 0
+	if prevRelease != nil {
+		previousManifest = prevRelease.Manifest
+		previousHooks = prevRelease.Hooks
+		revision = prevRelease.Version
+		if prevRelease.Chart != nil {
+			currentValues = prevRelease.Chart.Values
+		}
+	}
+
+	diff, err := planengine.ComputeDiff(previousManifest, saved.Manifest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compute diff: %w", err)
+	}
+	diff.HooksChanged = planengine.HooksChanged(previousHooks, saved.Hooks)
+	diff.Header = planengine.Header{
+		Project:      saved.Project,
+		Environment:  saved.Environment,
+		Release:      saved.Release,
+		Namespace:    saved.Namespace,
+		Context:      cluster.Context(),
+		Revision:     revision,
+		FreshInstall: prevRelease == nil,
+		Warning:      warning,
+	}
+	return diff, currentValues, nil
+}
+
+// applySavedPlan installs the saved chart. It mirrors [applyDeploy] minus
+// the re-render of the spec: the chart is restored to a temp dir and
+// checked against the saved manifest, the namespace is ensured, the saved
+// CRDs are applied, and the chart is installed with the saved values and
+// extras.
+func applySavedPlan(c *nabat.Context, sess *session.Session, cluster *session.Cluster, helmClient session.HelmClient, saved *planengine.SavedPlan, opts *Options, k8sClient kubernetes.Interface, k8sErr error) error {
+	bundle, err := savedBundle(saved)
+	if err != nil {
+		return err
+	}
+
+	chartDir, err := os.MkdirTemp("", "deployah-plan-chart-")
+	if err != nil {
+		return fmt.Errorf("create chart dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(chartDir) }()
+	if writeErr := saved.WriteChart(chartDir); writeErr != nil {
+		return fmt.Errorf("restore chart: %w", writeErr)
+	}
+
+	var postRenderer postrenderer.PostRenderer
+	if len(bundle.Manifests) > 0 {
+		postRenderer = &extras.PostRenderer{Manifests: bundle.Manifests}
+	}
+	if renderErr := checkSavedRender(c, helmClient, saved, opts.PlanFile, chartDir, postRenderer); renderErr != nil {
+		return renderErr
+	}
+
+	// The plan file carries no platform config; the rollbackOnFailure
+	// default comes from the platform file this invocation finds.
+	platform, err := sess.Platform()
+	if err != nil {
+		return fmt.Errorf("load platform file: %w", err)
+	}
+	rb, err := prepareAutoRollback(c, helmClient, saved.Project, saved.Environment, saved.Release, cluster.Namespace(), saved.BaseRevision+1, rollbackOnFailure(opts, platform))
+	if err != nil {
+		return err
+	}
+
+	if ns := cluster.PlatformNamespace(); ns != nil {
+		if k8sErr != nil {
+			return fmt.Errorf("ensure namespace %s: kubernetes client unavailable: %w", cluster.Namespace(), k8sErr)
+		}
+		created, nsErr := k8s.EnsureNamespace(c, k8sClient, cluster.Namespace(), ns.Labels, ns.Annotations)
+		if nsErr != nil {
+			return fmt.Errorf("%w%s", nsErr, cmdopts.ClusterHint(nsErr))
+		}
+		if created {
+			cmdopts.Printf(c, "Created namespace %s\n", cluster.Namespace())
+		}
+	}
+
+	crdStats, crdErr := applyBundleCRDs(c, sess, cluster, bundle, opts)
+	if crdErr != nil {
+		return crdErr
+	}
+
+	title := fmt.Sprintf("Applying plan to '%s'...", saved.Environment)
+	watcher, err := runApply(c, helmClient, k8sClient, k8sErr, cluster.Namespace(), saved.Release, title, sess.Timeout(), rb, opts.ReportFile, func() error {
+		return helmClient.InstallChart(c, saved.Project, saved.Environment, saved.APIVersion, chartDir, saved.Values, postRenderer)
+	})
+	if err != nil {
+		return err
+	}
+
+	summary := buildSummaryMsg(watcher)
+	c.Success("Deployed"+summary+crdApplySuffix(crdStats), "project", saved.Project, "environment", saved.Environment)
+	return nil
+}
+
+// checkSavedRender renders the restored chart with the saved values and
+// extras, and refuses the plan unless that yields the manifest and hooks
+// the diff showed. Deploy installs the chart and values, not the manifest,
+// so an edit to either would otherwise apply something nobody reviewed.
+func checkSavedRender(c *nabat.Context, helmClient session.HelmClient, saved *planengine.SavedPlan, planFile, chartDir string, postRenderer postrenderer.PostRenderer) error {
+	result, err := helmClient.RenderChart(c, saved.Project, saved.Environment, saved.APIVersion, chartDir, saved.Values, postRenderer)
+	if err != nil {
+		return fmt.Errorf("render plan file chart: %w%s", err, cmdopts.ClusterHint(err))
+	}
+	if result.Manifest != saved.Manifest || planengine.HooksChanged(saved.Hooks, result.Hooks) {
+		return fmt.Errorf(
+			"plan file %s: its chart and values no longer render the manifest it shows; run 'deployah plan --out' again",
+			planFile,
+		)
+	}
+	return nil
+}
+
+// savedBundle rebuilds the extras bundle stored in a plan file.
+func savedBundle(saved *planengine.SavedPlan) (*extras.Bundle, error) {
+	bundle := &extras.Bundle{}
+	for _, o := range saved.Extras {
+		obj, err := extras.ParseObject(o.Path, []byte(o.YAML))
+		if err != nil {
+			return nil, fmt.Errorf("plan file extras: %w", err)
+		}
+		bundle.Manifests = append(bundle.Manifests, obj)
+	}
+	for _, o := range saved.CRDs {
+		obj, err := extras.ParseObject(o.Path, []byte(o.YAML))
+		if err != nil {
+			return nil, fmt.Errorf("plan file CRDs: %w", err)
+		}
+		bundle.CRDs = append(bundle.CRDs, obj)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/extras"
+	"deployah.dev/deployah/internal/render"
+
+	planengine "deployah.dev/deployah/internal/plan"
+)
+
+func testSavedPlan() *planengine.SavedPlan {
+	return &planengine.SavedPlan{
+		Project:      "web",
+		Environment:  "production",
+		Release:      "web-production",
+		Namespace:    "default",
+		BaseRevision: 1,
+		Manifest:     deployFlowManifestV2,
+		Chart: map[string][]byte{
+			"Chart.yaml":          []byte("name: web\n"),
+			"values.yaml":         []byte("deployah:\n  resolved:\n    components:\n      web:\n        fqdn: web.example.com\n"),
+			"templates/web.yaml":  []byte("kind: Deployment\n"),
+			"templates/NOTES.txt": []byte("notes\n"),
+		},
+		Values: map[string]any{"secrets": map[string]any{"API_KEY": "s3cret"}},
+	}
+}
+
+// TestCheckSavedPlanBase refuses a plan once the release has a newer
+// revision than the one it was made against.
+func TestCheckSavedPlanBase(t *testing.T) {
+	t.Parallel()
+
+	c := nabatContext(t)
+	saved := testSavedPlan()
+
+	current := releaseWithResolvedFQDN("web", "web.example.com")
+	current.Version = 1
+	require.NoError(t, checkSavedPlanBase(c, &stubHelmClient{release: current}, saved))
+
+	moved := releaseWithResolvedFQDN("web", "web.example.com")
+	moved.Version = 2
+	err := checkSavedPlanBase(c, &stubHelmClient{release: moved}, saved)
+	require.ErrorContains(t, err, "moved from revision 1 to 2")
+}
+
+// TestCheckSavedPlanTarget refuses a plan made for another namespace.
+func TestCheckSavedPlanTarget(t *testing.T) {
+	t.Parallel()
+
+	cluster := newClusterWithStub(t, &stubHelmClient{}, nil)
+	saved := testSavedPlan()
+	require.NoError(t, checkSavedPlanTarget(saved, cluster))
+
+	saved.Namespace = "shop"
+	require.ErrorContains(t, checkSavedPlanTarget(saved, cluster), `targets namespace "shop"`)
+}
+
+// TestCheckSavedPlanHostnames blocks an FQDN change recorded in the plan
+// unless forced, in which case it warns.
+func TestCheckSavedPlanHostnames(t *testing.T) {
+	t.Parallel()
+
+	saved := testSavedPlan()
+	target, err := saved.ChartValues()
+	require.NoError(t, err)
+
+	c := nabatContext(t)
+	same := releaseWithResolvedFQDN("web", "web.example.com").Chart.Values
+	require.NoError(t, checkSavedPlanHostnames(c, saved, same, target, false))
+	require.NoError(t, checkSavedPlanHostnames(c, saved, nil, target, false), "first install has nothing to compare")
+
+	old := releaseWithResolvedFQDN("web", "old.example.com").Chart.Values
+	err = checkSavedPlanHostnames(c, saved, old, target, false)
+	require.ErrorContains(t, err, "web: old.example.com -> web.example.com")
+
+	c, _, _, stderr := nabatContextWithIO(t)
+	require.NoError(t, checkSavedPlanHostnames(c, saved, old, target, true))
+	assert.Contains(t, stderr.String(), "--force-hostname-change")
+}
+
+// TestApplySavedPlan_InstallsSavedChart restores the saved chart files and
+// installs them with the saved values, without rendering the spec.
+func TestApplySavedPlan_InstallsSavedChart(t *testing.T) {
+	t.Parallel()
+
+	saved := testSavedPlan()
+	stub := &stubHelmClient{chartRender: &render.RenderResult{Manifest: saved.Manifest}}
+	cluster := newClusterWithStub(t, stub, nil)
+	c, _, _, stderr := nabatContextWithIO(t)
+	opts := &Options{Environment: "production", CRDs: string(extras.PolicyCreate), PlanFile: "plan.dpy"}
+
+	require.NoError(t, applySavedPlan(c, cluster.Session, cluster, stub, saved, opts, nil, assertNever{}))
+	require.Len(t, stub.chartInstalls, 1)
+	assert.Zero(t, stub.renderCallCount, "a saved plan is never rendered again")
+	assert.Equal(t, "kind: Deployment\n", stub.chartInstalls[0].files["templates/web.yaml"])
+	assert.Len(t, stub.chartInstalls[0].files, len(saved.Chart))
+	assert.Equal(t, saved.Values, stub.chartInstalls[0].values)
+	assert.Contains(t, stderr.String(), "Deployed")
+}
+
+// TestApplySavedPlan_RefusesChangedRender refuses a plan file whose chart
+// and values render something other than the manifest it shows, before
+// anything is applied.
+func TestApplySavedPlan_RefusesChangedRender(t *testing.T) {
+	t.Parallel()
+
+	saved := testSavedPlan()
+	stub := &stubHelmClient{chartRender: &render.RenderResult{Manifest: deployFlowManifestV1}}
+	cluster := newClusterWithStub(t, stub, nil)
+	c := nabatContext(t)
+	opts := &Options{Environment: "production", CRDs: string(extras.PolicyCreate), PlanFile: "plan.dpy"}
+
+	err := applySavedPlan(c, cluster.Session, cluster, stub, saved, opts, nil, assertNever{})
+	require.ErrorContains(t, err, "plan file plan.dpy: its chart and values no longer render the manifest it shows")
+	assert.Empty(t, stub.chartInstalls)
+}
+
+// TestRunSavedPlan_PlanHash refuses a plan file other than the one whose
+// hash was passed.
+func TestRunSavedPlan_PlanHash(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "plan.dpy")
+	hash, err := planengine.WriteSavedPlan(path, testSavedPlan())
+	require.NoError(t, err)
+
+	opts := &Options{Environment: "production", PlanFile: path, PlanHash: strings.Repeat("0", len(hash))}
+	err = runSavedPlan(nabatContext(t), opts)
+	require.ErrorContains(t, err, "has sha256 "+hash+", not the reviewed 000")
+}
+
+// TestSavedBundle_RejectsBadYAML surfaces a corrupt extras entry with its
+// path.
+func TestSavedBundle_RejectsBadYAML(t *testing.T) {
+	t.Parallel()
+
+	saved := testSavedPlan()
+	saved.Extras = []planengine.SavedObject{{Path: ".deployah/manifests/cm.yaml", YAML: "kind: [\n"}}
+	_, err := savedBundle(saved)
+	require.ErrorContains(t, err, ".deployah/manifests/cm.yaml")
+}
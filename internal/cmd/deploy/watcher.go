@@ -19,12 +19,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
 	"k8s.io/client-go/kubernetes"
 	"nabat.dev/nabat"
 
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/k8s"
 	"deployah.dev/deployah/internal/readiness"
 
@@ -43,22 +45,37 @@ var (
 	finalRefreshTimeout = 10 * time.Second
 )
 
+// Job event reasons the Job controller reports when a hook task's Job
+// ends. Any reason in jobFailureReasons fails the Job.
+const jobCompletedReason = "Completed"
+
+var jobFailureReasons = map[string]bool{
+	"BackoffLimitExceeded":     true,
+	"DeadlineExceeded":         true,
+	"PodFailurePolicy":         true,
+	"MaxFailedIndexesExceeded": true,
+}
+
 // ComponentStatus summarizes pod readiness for one Deployah component at
 // the end of a deploy. Alias for [readiness.ComponentStatus].
 type ComponentStatus = readiness.ComponentStatus
 
 // DeployWatcher observes Kubernetes events and pod readiness during a Helm
-// deploy, feeding a live status view to a [nabat.Status]. Not safe for
-// concurrent use; call Run once, and read Warnings/Summary after Run returns.
+// deploy, feeding a live status view to a [nabat.Status]. With an event
+// stream it also reports hook task Jobs and pod readiness changes there.
+// Not safe for concurrent use; call Run once, and read Warnings/Summary
+// after Run returns.
 type DeployWatcher struct {
 	k8sClient   kubernetes.Interface
 	namespace   string
 	releaseName string
+	events      *eventlog.Stream
 
 	mu        sync.Mutex
 	warnings  []k8s.DeployEvent
 	summary   []ComponentStatus
 	pollStale bool // true when the last readiness poll failed
+	tasks     map[string]eventlog.Type
 }
 
 // NewDeployWatcher creates a watcher for the given release.
@@ -105,6 +122,7 @@ func (w *DeployWatcher) Run(ctx context.Context, st *nabat.Status) {
 				return
 			}
 			w.trackWarning(ev)
+			w.trackTask(ev)
 			w.pushRow(st, ev)
 			w.refreshPodStatus(ctx)
 			w.updateTitle(st)
@@ -116,6 +134,52 @@ func (w *DeployWatcher) Run(ctx context.Context, st *nabat.Status) {
 	}
 }
 
+// RunWatched runs apply inside a [nabat.Status] titled title while a
+// [DeployWatcher] for releaseName streams events and pod readiness into it.
+// The watcher is best-effort: when k8sErr is non-nil it is skipped and the
+// returned watcher is nil. When apply fails, the collected Warning events
+// are printed before its error is returned, so the cause is visible above
+// the error line.
+func RunWatched(
+	c *nabat.Context,
+	k8sClient kubernetes.Interface,
+	k8sErr error,
+	namespace, releaseName, title string,
+	apply func() error,
+) (*DeployWatcher, error) {
+	var watcher *DeployWatcher
+	if k8sErr != nil {
+		c.Logger().Debug("skipping deploy watcher: k8s client unavailable", "err", k8sErr)
+	} else {
+		watcher = NewDeployWatcher(k8sClient, namespace, releaseName)
+		watcher.events = eventlog.FromContext(c)
+	}
+
+	err := c.Status(func(st *nabat.Status) error {
+		var wg sync.WaitGroup
+		var cancel context.CancelFunc
+		if watcher != nil {
+			var watchCtx context.Context
+			watchCtx, cancel = context.WithCancel(c)
+			wg.Go(func() {
+				watcher.Run(watchCtx, st)
+			})
+		}
+		applyErr := apply()
+		if cancel != nil {
+			cancel()
+		}
+		wg.Wait()
+		return applyErr
+	}, nabat.WithTitle(title))
+	if err != nil && watcher != nil {
+		for _, w := range watcher.Warnings() {
+			c.Warn(fmt.Sprintf("[%s] %s: %s", w.Object, w.Reason, w.Message))
+		}
+	}
+	return watcher, err
+}
+
 // Warnings returns the Warning-type events collected during the deploy.
 // Call only after [DeployWatcher.Run] returns.
 func (w *DeployWatcher) Warnings() []k8s.DeployEvent {
@@ -149,6 +213,54 @@ func (w *DeployWatcher) trackWarning(ev k8s.DeployEvent) {
 	w.warnings = append(w.warnings, ev)
 }
 
+// trackTask reports the Job events of hook tasks to the event stream: the
+// Job controller's first SuccessfulCreate starts the task, and Completed or
+// a failure reason finishes it. Hook Jobs are named "<release>-<task>".
+func (w *DeployWatcher) trackTask(ev k8s.DeployEvent) {
+	if w.events == nil {
+		return
+	}
+	job, ok := strings.CutPrefix(ev.Object, "job/")
+	if !ok {
+		return
+	}
+	var typ eventlog.Type
+	var succeeded *bool
+	switch {
+	case ev.Reason == "SuccessfulCreate":
+		typ = eventlog.TypeTaskStarted
+	case ev.Reason == jobCompletedReason:
+		typ, succeeded = eventlog.TypeTaskFinished, new(true)
+	case jobFailureReasons[ev.Reason]:
+		typ, succeeded = eventlog.TypeTaskFinished, new(false)
+	default:
+		return
+	}
+
+	w.mu.Lock()
+	last := w.tasks[job]
+	if last == typ || last == eventlog.TypeTaskFinished {
+		w.mu.Unlock()
+		return
+	}
+	if w.tasks == nil {
+		w.tasks = map[string]eventlog.Type{}
+	}
+	w.tasks[job] = typ
+	w.mu.Unlock()
+
+	task := eventlog.Task{
+		Task:      strings.TrimPrefix(job, w.releaseName+"-"),
+		Job:       job,
+		Hook:      true,
+		Succeeded: succeeded,
+	}
+	if typ == eventlog.TypeTaskFinished {
+		task.Message = ev.Message
+	}
+	w.events.Emit(typ, task)
+}
+
 // pushRow adds or updates the Nabat status row for ev.
 func (w *DeployWatcher) pushRow(st *nabat.Status, ev k8s.DeployEvent) {
 	msg := ev.Message
@@ -257,7 +369,38 @@ func (w *DeployWatcher) refreshPodStatus(ctx context.Context) {
 	}
 
 	w.mu.Lock()
-	defer w.mu.Unlock()
+	changed := readinessChanges(w.summary, statuses)
 	w.summary = statuses
 	w.pollStale = false
+	w.mu.Unlock()
+	for _, s := range changed {
+		w.events.Emit(eventlog.TypePodReadiness, eventlog.PodReadiness{
+			Component: s.Name,
+			Ready:     s.ReadyPods,
+			Total:     s.TotalPods,
+		})
+	}
+}
+
+// readinessChanges returns the components of next whose ready or total
+// pod count differs from prev, then those in prev that have no pods left
+// in next, reported with zero counts.
+func readinessChanges(prev, next []ComponentStatus) []ComponentStatus {
+	before := make(map[string]ComponentStatus, len(prev))
+	for _, s := range prev {
+		before[s.Name] = s
+	}
+	var changed []ComponentStatus
+	for _, s := range next {
+		if old, ok := before[s.Name]; !ok || old != s {
+			changed = append(changed, s)
+		}
+		delete(before, s.Name)
+	}
+	for _, s := range prev {
+		if _, gone := before[s.Name]; gone {
+			changed = append(changed, ComponentStatus{Name: s.Name})
+		}
+	}
+	return changed
 }
@@ -15,7 +15,9 @@
 package deploy
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -28,6 +30,7 @@ import (
 	"nabat.dev/nabat"
 	"nabat.dev/nabat/nabattest"
 
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/k8s"
 
 	corev1 "k8s.io/api/core/v1"
@@ -393,3 +396,63 @@ func TestDeployWatcher_AllReady_EmptyIsFalse(t *testing.T) {
 	w := newWatcher()
 	assert.False(t, w.allReady())
 }
+
+// TestDeployWatcher_TrackTask_EmitsHookTaskEvents verifies that Job
+// events become one task_started and one task_finished per hook Job.
+func TestDeployWatcher_TrackTask_EmitsHookTaskEvents(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	w := newWatcher()
+	w.events = eventlog.New(&out, "deploy")
+
+	w.trackTask(makeDeployEvent("u1", corev1.EventTypeNormal, "SuccessfulCreate", "job/myapp-prod-migrate", "Created pod: myapp-prod-migrate-abc", 1))
+	w.trackTask(makeDeployEvent("u2", corev1.EventTypeNormal, "SuccessfulCreate", "job/myapp-prod-migrate", "Created pod: myapp-prod-migrate-def", 1))
+	w.trackTask(makeDeployEvent("u3", corev1.EventTypeNormal, "Scheduled", "pod/myapp-prod-migrate-abc", "scheduled", 1))
+	w.trackTask(makeDeployEvent("u4", corev1.EventTypeWarning, "BackoffLimitExceeded", "job/myapp-prod-migrate", "Job has reached the specified backoff limit", 1))
+	w.trackTask(makeDeployEvent("u5", corev1.EventTypeNormal, "Completed", "job/myapp-prod-migrate", "Job completed", 1))
+
+	var events []eventlog.Event
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var ev struct {
+			eventlog.Event
+			Data eventlog.Task `json:"data"`
+		}
+		require.NoError(t, dec.Decode(&ev))
+		ev.Event.Data = ev.Data
+		events = append(events, ev.Event)
+	}
+	require.Len(t, events, 2)
+	assert.Equal(t, eventlog.TypeTaskStarted, events[0].Type)
+	assert.Equal(t, eventlog.Task{Task: "migrate", Job: "myapp-prod-migrate", Hook: true}, events[0].Data)
+	assert.Equal(t, eventlog.TypeTaskFinished, events[1].Type)
+	assert.Equal(t, eventlog.Task{
+		Task:      "migrate",
+		Job:       "myapp-prod-migrate",
+		Hook:      true,
+		Succeeded: new(false),
+		Message:   "Job has reached the specified backoff limit",
+	}, events[1].Data)
+}
+
+// TestReadinessChanges verifies that only components whose counts moved
+// are reported, and that a component with no pods left reports 0/0.
+func TestReadinessChanges(t *testing.T) {
+	t.Parallel()
+	prev := []ComponentStatus{
+		{Name: "api", ReadyPods: 1, TotalPods: 2},
+		{Name: "worker", ReadyPods: 1, TotalPods: 1},
+		{Name: "old", ReadyPods: 1, TotalPods: 1},
+	}
+	next := []ComponentStatus{
+		{Name: "api", ReadyPods: 2, TotalPods: 2},
+		{Name: "worker", ReadyPods: 1, TotalPods: 1},
+		{Name: "web", ReadyPods: 0, TotalPods: 1},
+	}
+	assert.Equal(t, []ComponentStatus{
+		{Name: "api", ReadyPods: 2, TotalPods: 2},
+		{Name: "web", ReadyPods: 0, TotalPods: 1},
+		{Name: "old"},
+	}, readinessChanges(prev, next))
+	assert.Empty(t, readinessChanges(next, next))
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export implements the deployah export command.
+//
+// It renders an environment the way `deployah plan --offline` does and
+// writes the result to a directory for a GitOps controller to apply: the
+// composed umbrella chart with its resolved values, the fully rendered
+// manifests, or the chart wrapped in an Argo CD Application or a Flux
+// HelmRelease. Nothing is read from or written to the cluster.
+//
+// Register the command with [Register] on a [nabat.dev/nabat.App] instance.
+package export
@@ -0,0 +1,299 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/extras"
+	"deployah.dev/deployah/internal/helm"
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/session"
+	"deployah.dev/deployah/internal/spec"
+)
+
+const (
+	formatChart     = "chart"
+	formatManifests = "manifests"
+	formatArgoCD    = "argocd"
+	formatFlux      = "flux"
+)
+
+// formats lists the choices for --format, in help-text order.
+var formats = []string{formatChart, formatManifests, formatArgoCD, formatFlux}
+
+// Options holds command-line flags for export.
+type Options struct {
+	Environment string `nabat:"environment"`
+	Format      string `nabat:"format"`
+	Out         string `nabat:"out"`
+	RepoURL     string `nabat:"repo-url"`
+	RepoPath    string `nabat:"repo-path"`
+	Source      string `nabat:"source"`
+}
+
+// Register adds the export command to app.
+func Register(app *nabat.App) {
+	app.MustCommand("export",
+		nabat.WithDescription("Write an environment as a Helm chart or plain manifests for GitOps"),
+		nabat.WithLongDescription(`Render an environment without contacting the cluster and write the result to
+a directory, for a GitOps controller such as Argo CD or Flux to apply.
+
+  chart      the composed umbrella chart with its resolved values.yaml.
+             Components and tasks still take per-release overrides, e.g.
+             --set api.image.tag=1.2.4. CRDs go in crds/ and extra
+             manifests in extras/, both installed as-is.
+  manifests  the fully rendered YAML: manifests.yaml, plus hooks.yaml,
+             extras.yaml, and crds.yaml when there are any.
+  argocd     the chart in chart/ and an Argo CD Application for it.
+  flux       the chart in chart/ and a Flux HelmRelease for it.
+
+The chart directory must be empty or hold an earlier export. Its files are
+then overwritten and charts/, crds/, and extras/ are replaced.
+Components with provider-backed secrets (sops, age) cannot be exported,
+since their decrypted values would be written to disk; use secretRef.
+envFile values are written into the chart's values.yaml.
+`),
+		nabat.WithArg("environment", "", nabat.WithRequired(), nabat.WithUsage("Environment to export"), nabat.WithPrompt("Environment", "", nabat.WithHint("e.g. prod, staging"))),
+		nabat.WithSelectFlag("format", formatChart, formats, nabat.WithUsage("What to write")),
+		nabat.WithFlag("out", "", nabat.WithUsage("Directory to write to, created if missing (required)")),
+		nabat.WithFlag("repo-url", "", nabat.WithUsage("Git repository URL the Argo CD Application pulls from (required with --format argocd)")),
+		nabat.WithFlag("repo-path", "", nabat.WithUsage("Path of --out inside the Git repository (argocd and flux; defaults to --out)")),
+		nabat.WithFlag("source", "", nabat.WithUsage("Flux GitRepository holding the chart, as [namespace/]name (--format flux; defaults to flux-system)")),
+		nabat.WithValidation(validateOptions),
+		nabat.WithExample(`
+# Write the production chart for a GitOps repository
+deployah export production --out deploy/production
+
+# Write fully rendered manifests instead
+deployah export production --format manifests --out deploy/production
+
+# Chart plus an Argo CD Application pointing at it
+deployah export production --format argocd --out deploy/production \
+  --repo-url https://github.com/acme/gitops.git
+
+# Chart plus a Flux HelmRelease sourced from the flux-system GitRepository
+deployah export production --format flux --out deploy/production`),
+		nabat.WithRun(runExport),
+	)
+}
+
+// validateOptions rejects flags that do not apply to the chosen format,
+// before runExport does any work.
+func validateOptions(c *nabat.Context) error {
+	opts := &Options{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+
+	if opts.Out == "" {
+		return errors.New("--out is required")
+	}
+	if opts.Format == formatArgoCD && opts.RepoURL == "" {
+		return errors.New("--format argocd requires --repo-url")
+	}
+	if opts.RepoURL != "" && opts.Format != formatArgoCD {
+		return errors.New("--repo-url only applies to --format argocd")
+	}
+	if opts.Source != "" && opts.Format != formatFlux {
+		return errors.New("--source only applies to --format flux")
+	}
+	if opts.RepoPath != "" && opts.Format != formatArgoCD && opts.Format != formatFlux {
+		return errors.New("--repo-path only applies to --format argocd and flux")
+	}
+	if opts.Format == formatArgoCD || opts.Format == formatFlux {
+		if _, err := repoPath(opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repoPath returns the slash-separated path of the output directory inside
+// the Git repository: --repo-path when set, otherwise --out, which must
+// then be relative.
+func repoPath(opts *Options) (string, error) {
+	p, flag := opts.RepoPath, "--repo-path"
+	if p == "" {
+		p, flag = opts.Out, "--out"
+	}
+	if filepath.IsAbs(p) {
+		if flag == "--out" {
+			return "", errors.New("--out is an absolute path; set --repo-path to its path inside the Git repository")
+		}
+		return "", fmt.Errorf("%s %q must be relative to the repository root", flag, p)
+	}
+	p = path.Clean(filepath.ToSlash(p))
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("%s %q must stay inside the repository", flag, p)
+	}
+	return p, nil
+}
+
+func runExport(c *nabat.Context) error {
+	opts := &Options{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+	sess := session.FromContext(c)
+
+	// Prescan the raw (pre-envsubst) manifest for ${VAR} tokens, matching
+	// deploy's and plan's resolution behavior.
+	rawSpec, _, rawErr := spec.ParseManifest(sess.SpecPath())
+	if rawErr != nil {
+		return fmt.Errorf("parse manifest: %w", rawErr)
+	}
+	substReport := spec.PrescanSubstitutionReport(rawSpec)
+
+	platform, platformErr := sess.Platform()
+	if platformErr != nil {
+		return fmt.Errorf("load platform file: %w", platformErr)
+	}
+
+	manifest, err := spec.Load(c, sess.SpecPath(), opts.Environment, platform)
+	if err != nil {
+		return fmt.Errorf("load spec: %w", err)
+	}
+
+	if platform == nil && cmdopts.HasExposeComponents(manifest) {
+		return fmt.Errorf(
+			"one or more components use expose blocks but no platform file was found; "+
+				"create %s or set DEPLOYAH_PLATFORM_FILE, or pass --platform-file",
+			spec.DefaultPlatformPath,
+		)
+	}
+	if names := providerSecretComponents(manifest, opts.Environment); len(names) > 0 {
+		return fmt.Errorf(
+			"component %s uses provider-backed secrets, whose decrypted values export would write to %s; "+
+				"reference a cluster Secret with secretRef instead",
+			strings.Join(names, ", "), opts.Out,
+		)
+	}
+
+	var resolvedSpec *spec.ResolvedSpec
+	if platform != nil {
+		var report *spec.ResolutionReport
+		resolvedSpec, report, err = spec.Resolve(manifest, platform, spec.NormalizeEnv(opts.Environment), substReport)
+		if err != nil {
+			if report != nil && report.ErrorCode != "" {
+				return fmt.Errorf("resolution failed (%s): %w", report.ErrorCode, err)
+			}
+			return fmt.Errorf("resolution failed: %w", err)
+		}
+	}
+
+	cluster, err := sess.Target(c, manifest.Project, opts.Environment)
+	if err != nil {
+		return fmt.Errorf("target cluster: %w", err)
+	}
+	helmClient, err := cluster.Helm()
+	if err != nil {
+		return fmt.Errorf("helm client: %w", err)
+	}
+
+	// Like plan --offline, self-signed certificates are generated fresh
+	// rather than read from the cluster. They end up in the output.
+	if resolvedSpec != nil {
+		if k8s.HasSelfSignedComponents(resolvedSpec) {
+			c.Warn("self-signed TLS keys are written to the export; do not commit them unencrypted")
+		}
+		ca, caErr := cmdopts.LocalCA(resolvedSpec)
+		if caErr != nil {
+			return caErr
+		}
+		if tlsErr := k8s.MaterializeSelfSignedTLS(c, nil, ca, "", resolvedSpec); tlsErr != nil {
+			return fmt.Errorf("materialize self-signed TLS: %w", tlsErr)
+		}
+	}
+
+	bundle, err := extras.LoadFromSpec(sess.SpecPath(), manifest, platform, opts.Environment, cluster.Namespace(), nil)
+	if err != nil {
+		return fmt.Errorf("load extras: %w", err)
+	}
+
+	// Extras are written next to the chart output rather than post-rendered
+	// into it, so render without them and only run the collision check.
+	result, cleanup, err := helmClient.RenderOffline(c, manifest, opts.Environment, resolvedSpec, nil)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return fmt.Errorf("render manifests: %w", err)
+	}
+	if pr := bundle.PostRendererFor(); pr != nil {
+		if _, prErr := pr.Run(bytes.NewBufferString(result.Manifest)); prErr != nil {
+			return fmt.Errorf("extra manifests: %w", prErr)
+		}
+	}
+
+	// The prepared chart leaves envFile values out; the rendered manifests
+	// already hold them, but an exported chart has to carry them in its
+	// values.yaml. Provider-backed secrets were refused above, so no
+	// resolver is needed.
+	var secretValues map[string]any
+	if opts.Format != formatManifests {
+		secretValues, err = helm.SecretValues(c, manifest, opts.Environment, nil)
+		if err != nil {
+			return fmt.Errorf("read env files: %w", err)
+		}
+		if len(secretValues) > 0 {
+			c.Warn("envFile values are written to the export; do not commit them unencrypted")
+		}
+	}
+
+	switch opts.Format {
+	case formatManifests:
+		err = writeManifests(opts.Out, result, bundle)
+	case formatChart:
+		err = writeChart(opts.Out, result.ChartPath, bundle, secretValues)
+	default:
+		err = writeGitOps(opts, result, bundle, secretValues)
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", opts.Format, err)
+	}
+
+	c.Println(fmt.Sprintf("Exported environment '%s' as %s to %s (release %s in namespace %s).",
+		opts.Environment, opts.Format, opts.Out, result.ReleaseName, result.Namespace))
+	return nil
+}
+
+// providerSecretComponents returns the sorted names of the components
+// active in environment that decrypt secrets from local files.
+func providerSecretComponents(manifest *spec.Spec, environment string) []string {
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(manifest.Components)) {
+		component := manifest.Components[name]
+		if len(component.Environments) > 0 {
+			if _, ok := spec.MatchEnvKey(environment, component.Environments); !ok {
+				continue
+			}
+		}
+		if len(component.ProviderSecrets()) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,253 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"deployah.dev/deployah/internal/extras"
+	"deployah.dev/deployah/internal/render"
+	"deployah.dev/deployah/internal/spec"
+
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+func extraObject(kind, name, raw string) extras.Object {
+	return extras.Object{
+		Path: name + ".yaml",
+		Raw:  []byte(raw),
+		Obj: &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": name},
+		}},
+	}
+}
+
+// fakeChart writes a minimal prepared chart with a subchart per component
+// and returns its path.
+func fakeChart(t *testing.T, components ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	chartYAML := "name: shop\ndependencies:\n  - name: deployah\n    repository: file://charts/deployah\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(chartYAML), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("api:\n  image:\n    tag: \"1.0\"\n"), 0o600))
+	for _, name := range append([]string{"deployah"}, components...) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "charts", name), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "charts", name, "Chart.yaml"), []byte("name: "+name+"\n"), 0o600))
+	}
+	return dir
+}
+
+func TestWriteChart(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "production")
+	bundle := &extras.Bundle{
+		Manifests: []extras.Object{extraObject("ConfigMap", "banner", "kind: ConfigMap\ndata:\n  text: '{{ not a template }}'\n")},
+		CRDs:      []extras.Object{extraObject("CustomResourceDefinition", "widgets.acme.io", "kind: CustomResourceDefinition\n")},
+	}
+	require.NoError(t, writeChart(out, fakeChart(t), bundle, nil))
+
+	assert.FileExists(t, filepath.Join(out, "Chart.yaml"))
+	assert.FileExists(t, filepath.Join(out, "values.yaml"))
+	assert.FileExists(t, filepath.Join(out, "charts", "deployah", "Chart.yaml"))
+	assert.FileExists(t, filepath.Join(out, "crds", "001-customresourcedefinition-widgets.acme.io.yaml"))
+	extra, err := os.ReadFile(filepath.Join(out, "extras", "001-configmap-banner.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(extra), "{{ not a template }}")
+	tpl, err := os.ReadFile(filepath.Join(out, "templates", "deployah-extras.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(tpl), `.Files.Get $path`)
+
+	// Re-exporting without extras drops the stale ones.
+	require.NoError(t, writeChart(out, fakeChart(t), &extras.Bundle{}, nil))
+	assert.NoDirExists(t, filepath.Join(out, "extras"))
+	assert.NoDirExists(t, filepath.Join(out, "crds"))
+	assert.NoFileExists(t, filepath.Join(out, "templates", "deployah-extras.yaml"))
+}
+
+// TestWriteChart_removedComponent re-exports after a component was removed
+// and checks that its subchart does not linger for GitOps to deploy.
+func TestWriteChart_removedComponent(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "production")
+	require.NoError(t, writeChart(out, fakeChart(t, "api", "worker"), &extras.Bundle{}, nil))
+	assert.FileExists(t, filepath.Join(out, "charts", "worker", "Chart.yaml"))
+
+	require.NoError(t, writeChart(out, fakeChart(t, "api"), &extras.Bundle{}, nil))
+	assert.FileExists(t, filepath.Join(out, "charts", "api", "Chart.yaml"))
+	assert.FileExists(t, filepath.Join(out, "charts", "deployah", "Chart.yaml"))
+	assert.NoDirExists(t, filepath.Join(out, "charts", "worker"))
+}
+
+// TestWriteChart_foreignDirectory refuses to export into a directory that
+// holds files of its own, and leaves its charts/ directory alone.
+func TestWriteChart_foreignDirectory(t *testing.T) {
+	t.Parallel()
+
+	out := t.TempDir()
+	ownChart := filepath.Join(out, "charts", "app", "Chart.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(ownChart), 0o750))
+	require.NoError(t, os.WriteFile(ownChart, []byte("name: app\n"), 0o600))
+
+	err := writeChart(out, fakeChart(t, "api"), &extras.Bundle{}, nil)
+	require.ErrorContains(t, err, "holds no earlier export")
+	assert.FileExists(t, ownChart)
+	assert.NoFileExists(t, filepath.Join(out, "Chart.yaml"))
+}
+
+// TestWriteChart_secretValues checks that values kept out of the prepared
+// chart are merged into the exported values.yaml next to the existing ones.
+func TestWriteChart_secretValues(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "production")
+	secretValues := map[string]any{
+		"api": map[string]any{"secret": map[string]any{"data": map[string]any{"API_TOKEN": "czNjcmV0"}}},
+	}
+	require.NoError(t, writeChart(out, fakeChart(t, "api"), &extras.Bundle{}, secretValues))
+
+	data, err := os.ReadFile(filepath.Join(out, "values.yaml"))
+	require.NoError(t, err)
+	var values map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &values))
+	assert.Equal(t, map[string]any{
+		"api": map[string]any{
+			"image":  map[string]any{"tag": "1.0"},
+			"secret": map[string]any{"data": map[string]any{"API_TOKEN": "czNjcmV0"}},
+		},
+	}, values)
+}
+
+func TestWriteManifests(t *testing.T) {
+	t.Parallel()
+
+	out := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(out, "crds.yaml"), []byte("stale"), 0o600))
+	result := &render.RenderResult{
+		Manifest: "---\nkind: Deployment\n---\nkind: Service\n",
+		Hooks:    []*v1.Hook{{Manifest: "kind: Job\nmetadata:\n  name: migrate\n"}},
+	}
+	bundle := &extras.Bundle{
+		Manifests: []extras.Object{extraObject("ConfigMap", "banner", "kind: ConfigMap\n")},
+	}
+	require.NoError(t, writeManifests(out, result, bundle))
+
+	manifests, err := os.ReadFile(filepath.Join(out, "manifests.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Deployment\n---\nkind: Service\n", string(manifests))
+	hooks, err := os.ReadFile(filepath.Join(out, "hooks.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Job\nmetadata:\n  name: migrate\n", string(hooks))
+	assert.FileExists(t, filepath.Join(out, "extras.yaml"))
+	assert.NoFileExists(t, filepath.Join(out, "crds.yaml"), "a stale crds.yaml is removed")
+}
+
+func TestGitOpsWrappers(t *testing.T) {
+	t.Parallel()
+
+	app, err := yaml.Marshal(argoApplication("shop-production", "shop", "https://github.com/acme/gitops.git", "deploy/production/chart"))
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: shop-production
+  namespace: argocd
+spec:
+  destination:
+    namespace: shop
+    server: https://kubernetes.default.svc
+  project: default
+  source:
+    helm:
+      releaseName: shop-production
+    path: deploy/production/chart
+    repoURL: https://github.com/acme/gitops.git
+    targetRevision: HEAD
+  syncPolicy:
+    syncOptions:
+    - CreateNamespace=true
+`, string(app))
+
+	tests := []struct {
+		source        string
+		wantName      string
+		wantNamespace string
+	}{
+		{"", "flux-system", "flux-system"},
+		{"gitops", "gitops", "flux-system"},
+		{"infra/gitops", "gitops", "infra"},
+	}
+	for _, tt := range tests {
+		hr := fluxHelmRelease("shop-production", "shop", tt.source, "deploy/production/chart")
+		chart := hr["spec"].(map[string]any)["chart"].(map[string]any)["spec"].(map[string]any)
+		assert.Equal(t, "./deploy/production/chart", chart["chart"])
+		ref := chart["sourceRef"].(map[string]any)
+		assert.Equal(t, tt.wantName, ref["name"], tt.source)
+		assert.Equal(t, tt.wantNamespace, ref["namespace"], tt.source)
+		assert.Equal(t, tt.wantNamespace, hr["metadata"].(map[string]any)["namespace"], tt.source)
+	}
+}
+
+func TestRepoPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    Options
+		want    string
+		wantErr string
+	}{
+		{name: "from out", opts: Options{Out: "./deploy/production/"}, want: "deploy/production"},
+		{name: "explicit", opts: Options{Out: "/tmp/gitops/prod", RepoPath: "prod"}, want: "prod"},
+		{name: "absolute out", opts: Options{Out: "/tmp/gitops/prod"}, wantErr: "set --repo-path"},
+		{name: "escapes repo", opts: Options{Out: "../elsewhere"}, wantErr: "must stay inside the repository"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := repoPath(&tt.opts)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProviderSecretComponents(t *testing.T) {
+	t.Parallel()
+
+	sops := map[string]spec.SecretSource{"DB_PASSWORD": {Provider: spec.SecretProviderSOPS, File: "secrets.enc.yaml"}}
+	manifest := &spec.Spec{Components: map[string]spec.Component{
+		"api":     {Secrets: sops},
+		"web":     {Secrets: map[string]spec.SecretSource{"TOKEN": {SecretRef: &spec.SecretKeyRef{Name: "web", Key: "token"}}}},
+		"preview": {Secrets: sops, Environments: []string{"review"}},
+	}}
+
+	assert.Equal(t, []string{"api"}, providerSecretComponents(manifest, "production"))
+	assert.Equal(t, []string{"api", "preview"}, providerSecretComponents(manifest, "review/pr-7"))
+}
@@ -0,0 +1,363 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"deployah.dev/deployah/internal/extras"
+	"deployah.dev/deployah/internal/render"
+
+	chartutil "helm.sh/helm/v4/pkg/chart/common/util"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+)
+
+// extrasTemplate renders every file under the chart's extras/ directory
+// verbatim. .Files.Get returns the bytes without template evaluation, so
+// `{{ }}` in an extra manifest survives, as it does with the post-renderer
+// deploy uses.
+const extrasTemplate = `{{- range $path, $_ := .Files.Glob "extras/*.yaml" }}
+---
+{{ $.Files.Get $path }}
+{{- end }}
+`
+
+// libraryChartRepository is where the prepared chart's Chart.yaml finds
+// the deployah library chart. It marks a directory as an earlier export.
+const libraryChartRepository = "file://charts/deployah"
+
+const (
+	argoCDNamespace      = "argocd"
+	argoCDInClusterURL   = "https://kubernetes.default.svc"
+	fluxDefaultNamespace = "flux-system"
+	fluxInterval         = "10m"
+)
+
+// writeChart copies the prepared chart at chartPath into dir, merges
+// secretValues into its values.yaml, and adds the bundle's CRDs under
+// crds/, which Helm installs before the templates and never templates, and
+// its extra manifests under extras/ with a template that emits them as-is.
+// dir must be empty or hold an earlier export, whose generated directories
+// are cleared first: a subchart left in charts/ by a component removed
+// since then would otherwise still be deployed, with its default values.
+func writeChart(dir, chartPath string, bundle *extras.Bundle, secretValues map[string]any) error {
+	if err := clearChart(dir); err != nil {
+		return err
+	}
+	if err := copyTree(chartPath, dir); err != nil {
+		return err
+	}
+	if err := mergeValues(filepath.Join(dir, "values.yaml"), secretValues); err != nil {
+		return err
+	}
+
+	if err := writeObjects(filepath.Join(dir, "crds"), bundle.CRDs); err != nil {
+		return err
+	}
+	if len(bundle.Manifests) == 0 {
+		return nil
+	}
+	if err := writeObjects(filepath.Join(dir, "extras"), bundle.Manifests); err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, "templates", "deployah-extras.yaml"), []byte(extrasTemplate))
+}
+
+// clearChart removes what an earlier export generated in dir. It refuses a
+// dir that has files but no earlier export, such as a repository root with
+// its own charts/ directory, rather than delete anything it did not write.
+func clearChart(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(entries) == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", dir, err)
+	}
+	exported, err := earlierExport(dir)
+	if err != nil {
+		return err
+	}
+	if !exported {
+		return fmt.Errorf("%s is not empty and holds no earlier export; write to an empty or new directory", dir)
+	}
+
+	for _, sub := range []string{"charts", "crds", "extras"} {
+		if err := os.RemoveAll(filepath.Join(dir, sub)); err != nil {
+			return fmt.Errorf("clear %s: %w", sub, err)
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, "templates", "deployah-extras.yaml")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("clear extras template: %w", err)
+	}
+	return nil
+}
+
+// earlierExport reports whether dir holds a chart written by export: its
+// Chart.yaml depends on the deployah library chart under charts/.
+func earlierExport(dir string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "Chart.yaml")) // #nosec G304 -- Chart.yaml in the output directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read Chart.yaml: %w", err)
+	}
+	var meta chart.Metadata
+	if yaml.Unmarshal(data, &meta) != nil {
+		return false, nil
+	}
+	return slices.ContainsFunc(meta.Dependencies, func(d *chart.Dependency) bool {
+		return d != nil && d.Name == "deployah" && d.Repository == libraryChartRepository
+	}), nil
+}
+
+// writeManifests writes the rendered release to dir: manifests.yaml for
+// the chart's objects, and hooks.yaml, extras.yaml, and crds.yaml when
+// there are any. Helm hook annotations are kept on the hook objects, so
+// controllers that understand them (Argo CD does) still order them.
+func writeManifests(dir string, result *render.RenderResult, bundle *extras.Bundle) error {
+	hooks := make([]string, 0, len(result.Hooks))
+	for _, h := range result.Hooks {
+		if h == nil {
+			continue
+		}
+		hooks = append(hooks, h.Manifest)
+	}
+	extraDocs := make([]string, 0, len(bundle.Manifests))
+	for _, obj := range bundle.Manifests {
+		extraDocs = append(extraDocs, string(obj.Raw))
+	}
+	crdDocs := make([]string, 0, len(bundle.CRDs))
+	for _, obj := range bundle.CRDs {
+		crdDocs = append(crdDocs, string(obj.Raw))
+	}
+
+	for _, f := range []struct {
+		name string
+		docs []string
+	}{
+		{"manifests.yaml", []string{result.Manifest}},
+		{"hooks.yaml", hooks},
+		{"extras.yaml", extraDocs},
+		{"crds.yaml", crdDocs},
+	} {
+		p := filepath.Join(dir, f.name)
+		body := joinDocuments(f.docs)
+		if body == "" && f.name != "manifests.yaml" {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("remove stale %s: %w", f.name, err)
+			}
+			continue
+		}
+		if err := writeFile(p, []byte(body)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeGitOps writes the chart to dir/chart and the Argo CD Application or
+// Flux HelmRelease that installs it next to it.
+func writeGitOps(opts *Options, result *render.RenderResult, bundle *extras.Bundle, secretValues map[string]any) error {
+	if err := writeChart(filepath.Join(opts.Out, "chart"), result.ChartPath, bundle, secretValues); err != nil {
+		return err
+	}
+	repoDir, err := repoPath(opts)
+	if err != nil {
+		return err
+	}
+	chartDir := path.Join(repoDir, "chart")
+
+	var name string
+	var obj map[string]any
+	if opts.Format == formatArgoCD {
+		name, obj = "application.yaml", argoApplication(result.ReleaseName, result.Namespace, opts.RepoURL, chartDir)
+	} else {
+		name, obj = "helmrelease.yaml", fluxHelmRelease(result.ReleaseName, result.Namespace, opts.Source, chartDir)
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	return writeFile(filepath.Join(opts.Out, name), data)
+}
+
+// argoApplication returns an Argo CD Application that installs the chart at
+// chartDir of repoURL as release into namespace on the cluster Argo CD runs
+// in.
+func argoApplication(release, namespace, repoURL, chartDir string) map[string]any {
+	return map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]any{
+			"name":      release,
+			"namespace": argoCDNamespace,
+		},
+		"spec": map[string]any{
+			"project": "default",
+			"source": map[string]any{
+				"repoURL":        repoURL,
+				"path":           chartDir,
+				"targetRevision": "HEAD",
+				"helm": map[string]any{
+					"releaseName": release,
+				},
+			},
+			"destination": map[string]any{
+				"server":    argoCDInClusterURL,
+				"namespace": namespace,
+			},
+			"syncPolicy": map[string]any{
+				"syncOptions": []any{"CreateNamespace=true"},
+			},
+		},
+	}
+}
+
+// fluxHelmRelease returns a Flux HelmRelease that installs the chart at
+// chartDir of the GitRepository named by source ([namespace/]name) as
+// release into namespace. The HelmRelease lives next to its source.
+func fluxHelmRelease(release, namespace, source, chartDir string) map[string]any {
+	sourceNamespace, sourceName := fluxDefaultNamespace, source
+	if ns, name, ok := strings.Cut(source, "/"); ok {
+		sourceNamespace, sourceName = ns, name
+	}
+	if sourceName == "" {
+		sourceName = fluxDefaultNamespace
+	}
+	return map[string]any{
+		"apiVersion": "helm.toolkit.fluxcd.io/v2",
+		"kind":       "HelmRelease",
+		"metadata": map[string]any{
+			"name":      release,
+			"namespace": sourceNamespace,
+		},
+		"spec": map[string]any{
+			"interval":        fluxInterval,
+			"releaseName":     release,
+			"targetNamespace": namespace,
+			"install": map[string]any{
+				"createNamespace": true,
+			},
+			"chart": map[string]any{
+				"spec": map[string]any{
+					"chart": "./" + chartDir,
+					"sourceRef": map[string]any{
+						"kind":      "GitRepository",
+						"name":      sourceName,
+						"namespace": sourceNamespace,
+					},
+				},
+			},
+		},
+	}
+}
+
+// mergeValues merges values over the values file at p. The prepared chart
+// leaves out what [helm.SecretValues] returns, which deploy hands to Helm
+// separately; an exported chart is installed by a GitOps controller
+// without it, so it has to carry those values itself.
+func mergeValues(p string, values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+	data, err := os.ReadFile(p) // #nosec G304 -- values.yaml of the chart just written
+	if err != nil {
+		return fmt.Errorf("read values: %w", err)
+	}
+	base := map[string]any{}
+	if err := yaml.Unmarshal(data, &base); err != nil {
+		return fmt.Errorf("parse values: %w", err)
+	}
+	merged, err := yaml.Marshal(chartutil.CoalesceTables(values, base))
+	if err != nil {
+		return fmt.Errorf("encode values: %w", err)
+	}
+	return writeFile(p, merged)
+}
+
+// writeObjects writes each object to its own file in dir, numbered so a
+// lexical listing keeps the bundle's order.
+func writeObjects(dir string, objs []extras.Object) error {
+	for i, obj := range objs {
+		id := obj.Identity()
+		name := fmt.Sprintf("%03d-%s-%s.yaml", i+1, strings.ToLower(id.Kind), id.Name)
+		if err := writeFile(filepath.Join(dir, name), obj.Raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinDocuments joins YAML documents with "---" separators, skipping empty
+// ones. It returns "" when every document is empty.
+func joinDocuments(docs []string) string {
+	var b strings.Builder
+	for _, doc := range docs {
+		doc = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(doc), "---"))
+		if doc == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("---\n")
+		}
+		b.WriteString(doc)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// copyTree copies the files under src into dst, overwriting files that
+// already exist there and leaving any others alone.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o750)
+		}
+		data, err := os.ReadFile(p) // #nosec G304 -- path from WalkDir within the prepared chart
+		if err != nil {
+			return err
+		}
+		return writeFile(target, data)
+	})
+}
+
+func writeFile(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(p), err)
+	}
+	if err := os.WriteFile(p, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", p, err)
+	}
+	return nil
+}
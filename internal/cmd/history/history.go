@@ -0,0 +1,277 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cli"
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/helm"
+	"deployah.dev/deployah/internal/session"
+	"deployah.dev/deployah/internal/spec"
+
+	planengine "deployah.dev/deployah/internal/plan"
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+// Options holds command-line flags for history.
+type Options struct {
+	Project      string `nabat:"project"`
+	Environment  string `nabat:"environment"`
+	OutputFormat string `nabat:"output"`
+	Diff         string `nabat:"diff"`
+}
+
+// RevisionViewModel is one row of the history output.
+type RevisionViewModel struct {
+	Revision        int               `json:"revision" yaml:"revision"`
+	Status          string            `json:"status" yaml:"status"`
+	Deployed        string            `json:"deployed,omitempty" yaml:"deployed,omitempty"`
+	Age             string            `json:"age,omitempty" yaml:"age,omitempty"`
+	Images          map[string]string `json:"images,omitempty" yaml:"images,omitempty"`
+	DeployahVersion string            `json:"deployahVersion,omitempty" yaml:"deployahVersion,omitempty"`
+	Description     string            `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Register adds the history command to app.
+func Register(app *nabat.App) {
+	app.MustCommand("history",
+		nabat.WithDescription("Show the revision history of a project"),
+		nabat.WithLongDescription("List the Helm revisions of a project in an environment with their status, deploy time, image tags per component and the Deployah version that deployed them. With --diff, show what changed between two revisions instead."),
+		nabat.WithArg("project", "", nabat.WithRequired(), nabat.WithUsage("Project name to show history for"), nabat.WithPrompt("Project name", "", nabat.WithHint("e.g. my-app"))),
+		nabat.WithFlag("environment", "", nabat.WithShort('e'), nabat.WithUsage("Environment to show history for (required)")),
+		nabat.WithSelectFlag("output", cli.OutputFormatTable, cli.OutputFormats, nabat.WithShort('o'), nabat.WithUsage("Output format")),
+		nabat.WithFlag("diff", "", nabat.WithUsage("Show the diff between two revisions, as FROM..TO (e.g. 3..5)")),
+		nabat.WithExample(`
+# List the revisions of my-app in production
+deployah history my-app -e prod
+
+# Show what changed between revisions 3 and 5
+deployah history my-app -e prod --diff 3..5
+
+# Machine-readable history
+deployah history my-app -e prod -o json`),
+		nabat.WithRun(runHistory),
+	)
+}
+
+func runHistory(c *nabat.Context) error {
+	opts := &Options{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+	if opts.Environment == "" {
+		return errors.New("--environment is required")
+	}
+	var from, to int
+	if opts.Diff != "" {
+		var err error
+		if from, to, err = parseRevisionRange(opts.Diff); err != nil {
+			return err
+		}
+	}
+
+	rt := session.FromContext(c)
+	cluster, err := rt.Target(c, opts.Project, opts.Environment)
+	if err != nil {
+		return fmt.Errorf("target cluster: %w", err)
+	}
+	cmdopts.WarnContextFallback(c, cluster, opts.Environment)
+	helmClient, err := cluster.Helm()
+	if err != nil {
+		return fmt.Errorf("helm client: %w%s", err, cmdopts.ClusterHint(err))
+	}
+
+	history, err := helmClient.GetReleaseHistory(c, opts.Project, opts.Environment)
+	if err != nil {
+		if errors.Is(err, helm.ErrReleaseNotFound) {
+			return fmt.Errorf("no release found for project '%s' in environment '%s'\n\nHint: Use 'deployah list' to see all available projects and environments", opts.Project, opts.Environment)
+		}
+		return fmt.Errorf("release history: %w", err)
+	}
+	releases := planengine.SortedReleases(history)
+	if len(releases) == 0 {
+		return fmt.Errorf("no revisions found for project '%s' in environment '%s'", opts.Project, opts.Environment)
+	}
+
+	if opts.Diff != "" {
+		return renderDiff(c, opts, cluster.Context(), releases, from, to)
+	}
+
+	headers := []string{"REV", "STATUS", "DEPLOYED", "IMAGES", "DEPLOYAH"}
+	rows := make([][]string, 0, len(releases))
+	viewModels := make([]RevisionViewModel, 0, len(releases))
+	for _, rel := range releases {
+		vm := revisionToViewModel(rel)
+		deployed := vm.Age
+		if deployed == "" {
+			deployed = "-"
+		}
+		version := vm.DeployahVersion
+		if version == "" {
+			version = "-"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(vm.Revision),
+			fmt.Sprintf("● %s", vm.Status),
+			deployed,
+			formatImages(vm.Images),
+			version,
+		})
+		viewModels = append(viewModels, vm)
+	}
+	return cli.Render(c, opts.OutputFormat, headers, rows, viewModels)
+}
+
+// renderDiff prints the manifest diff from revision from to revision to in
+// the same formats as `deployah plan`.
+func renderDiff(c *nabat.Context, opts *Options, kubeContext string, releases []*v1.Release, from, to int) error {
+	fromRel, err := planengine.FindRevision(releases, from)
+	if err != nil {
+		return err
+	}
+	toRel, err := planengine.FindRevision(releases, to)
+	if err != nil {
+		return err
+	}
+
+	p, err := planengine.ComputeDiff(fromRel.Manifest, toRel.Manifest)
+	if err != nil {
+		return fmt.Errorf("compute diff: %w", err)
+	}
+	p.HooksChanged = planengine.HooksChanged(fromRel.Hooks, toRel.Hooks)
+	p.Header = planengine.Header{
+		Project:     opts.Project,
+		Environment: opts.Environment,
+		Release:     toRel.Name,
+		Namespace:   toRel.Namespace,
+		Context:     kubeContext,
+		Revision:    fromRel.Version,
+	}
+	// Revisions are stored as rendered, so Secret values would otherwise be
+	// printed in the clear.
+	planengine.ApplyMasking(p)
+
+	switch opts.OutputFormat {
+	case cli.OutputFormatJSON:
+		var buf bytes.Buffer
+		if err := planengine.RenderJSON(&buf, p); err != nil {
+			return fmt.Errorf("render json: %w", err)
+		}
+		return c.FprintHighlight(c.IO().Out, strings.TrimRight(buf.String(), "\n"), "json")
+	case cli.OutputFormatYAML:
+		return c.YAML(planengine.NewJSONDocument(p))
+	default:
+		c.Printf("Changes from revision %d (%s) to revision %d (%s).\n\n",
+			fromRel.Version, planengine.ReleaseStatus(fromRel), toRel.Version, planengine.ReleaseStatus(toRel))
+		textOpts := planengine.TextOptions{Mode: planengine.ModeCompact, Theme: c.Theme()}
+		if err := planengine.RenderText(c.IO().Out, p, textOpts); err != nil {
+			return fmt.Errorf("render plan: %w", err)
+		}
+		return nil
+	}
+}
+
+// parseRevisionRange parses a --diff value of the form FROM..TO.
+func parseRevisionRange(s string) (from, to int, err error) {
+	left, right, ok := strings.Cut(s, "..")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --diff %q: expected FROM..TO (e.g. 3..5)", s)
+	}
+	from, fromErr := strconv.Atoi(strings.TrimSpace(left))
+	to, toErr := strconv.Atoi(strings.TrimSpace(right))
+	if fromErr != nil || toErr != nil || from <= 0 || to <= 0 {
+		return 0, 0, fmt.Errorf("invalid --diff %q: revisions must be positive numbers", s)
+	}
+	if from == to {
+		return 0, 0, fmt.Errorf("invalid --diff %q: revisions must differ", s)
+	}
+	return from, to, nil
+}
+
+func revisionToViewModel(rel *v1.Release) RevisionViewModel {
+	vm := RevisionViewModel{
+		Revision:        rel.Version,
+		Status:          planengine.ReleaseStatus(rel),
+		Images:          componentImages(rel),
+		DeployahVersion: rel.Labels[spec.LabelCLIVersion],
+	}
+	if rel.Info != nil {
+		if !rel.Info.LastDeployed.IsZero() {
+			vm.Deployed = rel.Info.LastDeployed.UTC().Format(time.RFC3339)
+			vm.Age = humanize.Time(rel.Info.LastDeployed)
+		}
+		vm.Description = rel.Info.Description
+	}
+	return vm
+}
+
+// componentImages returns the image tag (or digest) of each component in the
+// revision's chart values, keyed by component name. Component values are the
+// top-level maps carrying an image repository.
+func componentImages(rel *v1.Release) map[string]string {
+	if rel.Chart == nil {
+		return nil
+	}
+	images := map[string]string{}
+	for name, raw := range rel.Chart.Values {
+		component, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		image, ok := component["image"].(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, hasRepo := image["repository"]; !hasRepo {
+			continue
+		}
+		ref := "latest"
+		if digest, _ := image["digest"].(string); digest != "" {
+			ref = digest
+		} else if tag, _ := image["tag"].(string); tag != "" {
+			ref = tag
+		}
+		images[name] = ref
+	}
+	if len(images) == 0 {
+		return nil
+	}
+	return images
+}
+
+// formatImages renders images as "name=tag" pairs in component order.
+func formatImages(images map[string]string) string {
+	if len(images) == 0 {
+		return "-"
+	}
+	names := slices.Sorted(maps.Keys(images))
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+images[name])
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/release/common"
+
+	"deployah.dev/deployah/internal/spec"
+
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+func TestParseRevisionRange(t *testing.T) {
+	t.Parallel()
+
+	from, to, err := parseRevisionRange("3..5")
+	require.NoError(t, err)
+	assert.Equal(t, 3, from)
+	assert.Equal(t, 5, to)
+
+	// Diffing backwards is allowed: it shows what a rollback would undo.
+	from, to, err = parseRevisionRange("5..3")
+	require.NoError(t, err)
+	assert.Equal(t, 5, from)
+	assert.Equal(t, 3, to)
+
+	for _, bad := range []string{"3", "3-5", "a..5", "0..2", "2..2", "..4"} {
+		_, _, err := parseRevisionRange(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestRevisionToViewModel(t *testing.T) {
+	t.Parallel()
+
+	rel := &v1.Release{
+		Version: 4,
+		Info:    &v1.Info{Status: common.StatusDeployed, Description: "Upgrade complete"},
+		Labels:  map[string]string{spec.LabelCLIVersion: "v0.9.0"},
+		Chart: &chart.Chart{Values: map[string]any{
+			"api":    map[string]any{"image": map[string]any{"repository": "ghcr.io/acme/api", "tag": "1.4.2"}},
+			"worker": map[string]any{"image": map[string]any{"repository": "ghcr.io/acme/worker", "digest": "sha256:abc"}},
+			"web":    map[string]any{"image": map[string]any{"repository": "ghcr.io/acme/web"}},
+			"deployah": map[string]any{
+				"resolved": map[string]any{"components": map[string]any{}},
+			},
+		}},
+	}
+
+	vm := revisionToViewModel(rel)
+	assert.Equal(t, 4, vm.Revision)
+	assert.Equal(t, "deployed", vm.Status)
+	assert.Equal(t, "v0.9.0", vm.DeployahVersion)
+	assert.Equal(t, "Upgrade complete", vm.Description)
+	assert.Equal(t, map[string]string{"api": "1.4.2", "worker": "sha256:abc", "web": "latest"}, vm.Images)
+	assert.Equal(t, "api=1.4.2, web=latest, worker=sha256:abc", formatImages(vm.Images))
+}
+
+// TestRevisionToViewModel_PreVersionLabel verifies revisions deployed before
+// the CLI version label existed render without one.
+func TestRevisionToViewModel_PreVersionLabel(t *testing.T) {
+	t.Parallel()
+
+	vm := revisionToViewModel(&v1.Release{Version: 1})
+	assert.Equal(t, "unknown", vm.Status)
+	assert.Empty(t, vm.DeployahVersion)
+	assert.Nil(t, vm.Images)
+	assert.Equal(t, "-", formatImages(vm.Images))
+}
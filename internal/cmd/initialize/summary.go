@@ -62,7 +62,9 @@ func showSummaryAndSave(c *nabat.Context, config *ProjectConfig) (saved bool, er
 	platformFile := platformPath(config)
 	envNames := slices.Sorted(slices.Values(config.EnvironmentNames))
 
-	created, added, platformErr := spec.EnsurePlatformEnvironments(platformFile, localkube.DefaultIngressIP, envNames)
+	// init starts no registry; `deployah cluster up --registry` prints the
+	// variable to add for one.
+	created, added, platformErr := spec.EnsurePlatformEnvironments(platformFile, localkube.DefaultIngressIP, "", envNames)
 	if platformErr != nil {
 		return false, fmt.Errorf("saved spec but failed to update platform file: %w", platformErr)
 	}
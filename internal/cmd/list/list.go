@@ -50,7 +50,7 @@ func runList(c *nabat.Context) error {
 	}
 
 	rt := session.FromContext(c)
-	cluster, err := rt.Target(c, opts.Environment)
+	cluster, err := rt.Target(c, opts.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"text/template"
 	"time"
@@ -44,7 +45,7 @@ func Register(app *nabat.App) {
 		nabat.WithLongDescription("View logs from pods associated with a deployed project. This command connects to Kubernetes to stream logs from the pods."),
 		nabat.WithArg("project", "", nabat.WithRequired(), nabat.WithUsage("Project name to view logs for"), nabat.WithPrompt("Project name", "", nabat.WithHint("e.g. my-app"))),
 		nabat.WithFlag("no-follow", false, nabat.WithUsage("Do not follow log output")),
-		nabat.WithFlag("container", "", nabat.WithUsage("Container name (if pod has multiple containers)")),
+		nabat.WithFlag("container", "", nabat.WithUsage("Container name: the component, or a sidecar or init container by its spec name")),
 		nabat.WithFlag("since", 48*time.Hour, nabat.WithUsage("Show logs since duration (e.g., 10s, 1m, 1h)")),
 		nabat.WithFlag("tail", int64(-1), nabat.WithUsage("Number of lines to show from the end of the logs (-1 shows all)")),
 		nabat.WithFlag("timestamps", false, nabat.WithUsage("Include timestamps in log output")),
@@ -65,6 +66,9 @@ deployah logs myproject --component=api
 # Only "prod" environment pods
 deployah logs myproject --environment=prod
 
+# Only the "log-shipper" sidecar of the "api" component
+deployah logs myproject --component=api --container=log-shipper
+
 # Custom log format
 deployah logs myproject --template="{{.Message}}"
 
@@ -96,7 +100,7 @@ func runLogs(c *nabat.Context) error {
 
 	rt := session.FromContext(c)
 
-	cluster, err := rt.Target(c, opts.Environment)
+	cluster, err := rt.Target(c, opts.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -118,6 +122,18 @@ func runLogs(c *nabat.Context) error {
 		return fmt.Errorf("parse label selector: %w", err)
 	}
 
+	// --resource selects pods outside the project selector, so only a
+	// project query can check the container name up front.
+	if opts.Container != "" && opts.Resource == "" {
+		containers, initContainers, listErr := k8sClient.ListContainerNames(c, labelSelectorStr)
+		if listErr != nil {
+			return fmt.Errorf("list containers: %w", listErr)
+		}
+		if checkErr := checkContainer(opts.Container, containers, initContainers); checkErr != nil {
+			return checkErr
+		}
+	}
+
 	containerStates, err := logContainerStates()
 	if err != nil {
 		return err
@@ -157,7 +173,7 @@ func runLogs(c *nabat.Context) error {
 		Namespaces:          []string{cluster.Namespace()},
 		AllNamespaces:       false,
 		EphemeralContainers: false,
-		InitContainers:      false,
+		InitContainers:      opts.Container != "",
 		Timestamps:          opts.Timestamps,
 		Location:            loc,
 		Since:               opts.Since,
@@ -198,6 +214,22 @@ func runLogs(c *nabat.Context) error {
 	return nil
 }
 
+// checkContainer reports an unknown --container name along with the
+// names that exist. Before any pod exists there is nothing to check.
+func checkContainer(name string, containers, initContainers []string) error {
+	if len(containers) == 0 && len(initContainers) == 0 {
+		return nil
+	}
+	if slices.Contains(containers, name) || slices.Contains(initContainers, name) {
+		return nil
+	}
+	available := slices.Clone(containers)
+	for _, init := range initContainers {
+		available = append(available, init+" (init)")
+	}
+	return fmt.Errorf("container %q not found (available: %s)", name, strings.Join(available, ", "))
+}
+
 func logContainerStates() ([]stern.ContainerState, error) {
 	running, err := stern.NewContainerState(stern.RUNNING)
 	if err != nil {
@@ -69,3 +69,18 @@ func TestRunLogs_FlagValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckContainer(t *testing.T) {
+	t.Parallel()
+
+	containers := []string{"api", "log-shipper"}
+	inits := []string{"migrate"}
+
+	require.NoError(t, checkContainer("log-shipper", containers, inits))
+	require.NoError(t, checkContainer("migrate", containers, inits))
+	require.NoError(t, checkContainer("anything", nil, nil), "no pods yet")
+
+	err := checkContainer("shipper", containers, inits)
+	require.Error(t, err)
+	assert.Equal(t, `container "shipper" not found (available: api, log-shipper, migrate (init))`, err.Error())
+}
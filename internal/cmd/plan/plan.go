@@ -26,6 +26,7 @@ import (
 	"deployah.dev/deployah/internal/drift"
 	"deployah.dev/deployah/internal/extras"
 	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/render"
 	"deployah.dev/deployah/internal/session"
 	"deployah.dev/deployah/internal/spec"
 
@@ -50,6 +51,7 @@ type Options struct {
 	YAML             bool   `nabat:"yaml"`
 	OutputFormat     string `nabat:"output"`
 	DetailedExitCode bool   `nabat:"detailed-exitcode"`
+	Out              string `nabat:"out"`
 }
 
 // Register adds the plan command to app.
@@ -65,6 +67,7 @@ func Register(app *nabat.App) {
 		nabat.WithFlag("yaml", false, nabat.WithUsage("Show changed fields as YAML blocks instead of a single line")),
 		nabat.WithSelectFlag("output", outputFormatText, outputFormats, nabat.WithUsage("Output format")),
 		nabat.WithFlag("detailed-exitcode", false, nabat.WithUsage("Exit 2 when the plan has pending changes, 0 when it does not, 1 on error (for CI)")),
+		nabat.WithFlag("out", "", nabat.WithUsage("Save the plan to a file that 'deployah deploy --plan-file' applies exactly (contains decrypted secrets)")),
 		nabat.WithValidation(validateOptions),
 		nabat.WithExample(`
 # Preview what a deploy would change
@@ -77,7 +80,11 @@ deployah plan production --offline
 deployah plan production --output json
 
 # Gate a CI job on exit code 2 (pending changes) vs. 0 (no changes)
-deployah plan production --detailed-exitcode`),
+deployah plan production --detailed-exitcode
+
+# Save the reviewed plan and apply exactly that later
+deployah plan production --out plan.dpy
+deployah deploy production --plan-file plan.dpy`),
 		nabat.WithRun(runPlan),
 	)
 }
@@ -105,6 +112,9 @@ func validateOptions(c *nabat.Context) error {
 	if opts.Drift && opts.Offline {
 		return errors.New("--drift requires cluster access; it cannot be used with --offline")
 	}
+	if opts.Out != "" && opts.Offline {
+		return errors.New("--out records the live release revision; it cannot be used with --offline")
+	}
 	return nil
 }
 
@@ -165,7 +175,7 @@ func runPlan(c *nabat.Context) error {
 // resource count instead of a diff: there is no reachable release history to
 // compare against.
 func runOffline(c *nabat.Context, sess *session.Session, platform *spec.PlatformConfig, manifest *spec.Spec, opts *Options, resolvedSpec *spec.ResolvedSpec) error {
-	cluster, err := sess.Target(c, opts.Environment)
+	cluster, err := sess.Target(c, manifest.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -179,7 +189,11 @@ func runOffline(c *nabat.Context, sess *session.Session, platform *spec.Platform
 	// deliberate offline generation, not cmdopts.MaterializeSelfSignedTLS's
 	// fail-closed path for an online command that couldn't build a client.
 	if resolvedSpec != nil {
-		if tlsErr := k8s.MaterializeSelfSignedTLS(c, nil, "", resolvedSpec); tlsErr != nil {
+		ca, caErr := cmdopts.LocalCA(resolvedSpec)
+		if caErr != nil {
+			return caErr
+		}
+		if tlsErr := k8s.MaterializeSelfSignedTLS(c, nil, ca, "", resolvedSpec); tlsErr != nil {
 			return fmt.Errorf("materialize self-signed TLS: %w", tlsErr)
 		}
 	}
@@ -214,7 +228,7 @@ func runOffline(c *nabat.Context, sess *session.Session, platform *spec.Platform
 // runOnline renders the chart, diffs it against the last successful
 // release, and displays the resulting plan.
 func runOnline(c *nabat.Context, sess *session.Session, platform *spec.PlatformConfig, manifest *spec.Spec, opts *Options, resolvedSpec *spec.ResolvedSpec) error {
-	cluster, err := sess.Target(c, opts.Environment)
+	cluster, err := sess.Target(c, manifest.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -277,9 +291,48 @@ func runOnline(c *nabat.Context, sess *session.Session, platform *spec.PlatformC
 		}
 	}
 
+	if opts.Out != "" {
+		if saveErr := savePlan(c, helmClient, manifest, p, result, bundle, opts); saveErr != nil {
+			return fmt.Errorf("save plan: %w", saveErr)
+		}
+	}
+
 	return outputPlan(c, p, opts)
 }
 
+// savePlan writes the plan, the render behind it, and the extras bundle to
+// opts.Out, and records the file's hash on p so the output shows it. The
+// base revision is read from the release history after rendering, so a
+// deploy landing in between makes the file stale rather than wrong.
+func savePlan(c *nabat.Context, helmClient session.HelmClient, manifest *spec.Spec, p *planengine.Plan, result *render.RenderResult, bundle *extras.Bundle, opts *Options) error {
+	base, err := planengine.LatestRevision(c, helmClient, manifest.Project, opts.Environment)
+	if err != nil {
+		return err
+	}
+	saved, err := planengine.NewSavedPlan(p, result, manifest.APIVersion, base)
+	if err != nil {
+		return err
+	}
+	saved.Extras = savedObjects(bundle.Manifests)
+	saved.CRDs = savedObjects(bundle.CRDs)
+
+	hash, err := planengine.WriteSavedPlan(opts.Out, saved)
+	if err != nil {
+		return err
+	}
+	p.Hash = hash
+	c.Info(fmt.Sprintf("Saved plan to %s (sha256 %s). It contains decrypted secrets; keep it private.", opts.Out, hash))
+	return nil
+}
+
+func savedObjects(objs []extras.Object) []planengine.SavedObject {
+	out := make([]planengine.SavedObject, 0, len(objs))
+	for _, o := range objs {
+		out = append(out, planengine.SavedObject{Path: o.Path, YAML: string(o.Raw)})
+	}
+	return out
+}
+
 // checkDrift runs `--drift` detection against the resolved cluster and
 // records the outcome directly on p (DriftChecked, Drift, DriftIncomplete),
 // so it takes effect no matter which renderer outputPlan picks. On a fresh
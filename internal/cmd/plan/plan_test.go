@@ -86,6 +86,14 @@ func (s *stubHelmClient) InstallApp(context.Context, *spec.Spec, string, bool, *
 	panic("unexpected InstallApp call")
 }
 
+func (s *stubHelmClient) InstallChart(context.Context, string, string, string, string, map[string]any, postrenderer.PostRenderer) error {
+	panic("unexpected InstallChart call")
+}
+
+func (s *stubHelmClient) RenderChart(context.Context, string, string, string, string, map[string]any, postrenderer.PostRenderer) (*render.RenderResult, error) {
+	panic("unexpected RenderChart call")
+}
+
 func (s *stubHelmClient) DeleteRelease(context.Context, string, string, bool) error {
 	panic("unexpected DeleteRelease call")
 }
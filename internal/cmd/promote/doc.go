@@ -0,0 +1,21 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package promote implements the deployah promote command, which deploys
+// the images running in one environment to another. It reads the image
+// references from the source release's Helm values, pins them (by digest
+// when the running pods report one) into the target environment's render,
+// and hands over to `deployah deploy` for the plan, confirmation, and
+// apply.
+package promote
@@ -0,0 +1,129 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promote
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/spec"
+
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+// imageRef is one image as the chart values record it.
+type imageRef struct {
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// String renders the reference the way a spec writes it, preferring the
+// digest: "ghcr.io/acme/api@sha256:..." or "ghcr.io/acme/api:1.4.0".
+func (r imageRef) String() string {
+	switch {
+	case r.Digest != "":
+		return r.Repository + "@" + r.Digest
+	case r.Tag != "":
+		return r.Repository + ":" + r.Tag
+	}
+	return r.Repository
+}
+
+// releaseImages returns the image of every component and task in rel,
+// keyed by name. Chart values hold each one under its own top-level key.
+func releaseImages(rel *v1.Release) map[string]imageRef {
+	images := map[string]imageRef{}
+	if rel == nil || rel.Chart == nil {
+		return images
+	}
+	for name, raw := range rel.Chart.Values {
+		values, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		image, ok := values["image"].(map[string]any)
+		if !ok {
+			continue
+		}
+		repository, _ := image["repository"].(string)
+		if repository == "" {
+			continue
+		}
+		tag, _ := image["tag"].(string)
+		digest, _ := image["digest"].(string)
+		images[name] = imageRef{Repository: repository, Tag: tag, Digest: digest}
+	}
+	return images
+}
+
+// resolveDigests fills in the digest of each component image that the
+// values only record by tag, from the pods running that image in
+// environment. A tag can move after the source deployed it; the digest
+// cannot. Images whose pods report no single digest keep their tag.
+func resolveDigests(ctx context.Context, client *k8s.Client, project, environment string, images map[string]imageRef, components []string) error {
+	for _, name := range components {
+		ref, ok := images[name]
+		if !ok || ref.Digest != "" {
+			continue
+		}
+		selector, err := k8s.BuildSelector(project, name, environment)
+		if err != nil {
+			return err
+		}
+		digest, err := client.RunningImageDigest(ctx, selector, name, ref.String())
+		if err != nil {
+			return err
+		}
+		if digest != "" {
+			ref.Digest = digest
+			images[name] = ref
+		}
+	}
+	return nil
+}
+
+// pinImages points each component, and each task that runs its own
+// image, at the image the source release runs. It returns the references
+// it set, keyed by name, and the components the source release does not
+// run, which keep the image in the spec.
+func pinImages(m *spec.Spec, images map[string]imageRef) (pinned map[string]string, missing []string) {
+	pinned = map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(m.Components)) {
+		ref, ok := images[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		component := m.Components[name]
+		component.Image = ref.String()
+		m.Components[name] = component
+		pinned[name] = component.Image
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Tasks)) {
+		task := m.Tasks[name]
+		ref, ok := images[name]
+		if !ok || strings.TrimSpace(task.Image) == "" {
+			continue
+		}
+		task.Image = ref.String()
+		m.Tasks[name] = task
+		pinned[name] = task.Image
+	}
+	return pinned, missing
+}
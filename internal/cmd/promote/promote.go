@@ -0,0 +1,217 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promote
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cli"
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/cmd/deploy"
+	"deployah.dev/deployah/internal/extras"
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/session"
+	"deployah.dev/deployah/internal/spec"
+
+	planengine "deployah.dev/deployah/internal/plan"
+)
+
+// Options holds command-line flags for promote.
+type Options struct {
+	From                string `nabat:"from"`
+	To                  string `nabat:"to"`
+	Yes                 bool   `nabat:"yes"`
+	WriteSpec           bool   `nabat:"write-spec"`
+	RollbackOnFailure   bool   `nabat:"rollback-on-failure"`
+	NoRollbackOnFailure bool   `nabat:"no-rollback-on-failure"`
+	ReportFile          string `nabat:"report-file"`
+	Output              string `nabat:"output"`
+}
+
+// Register adds the promote command to app.
+func Register(app *nabat.App) {
+	app.MustCommand("promote",
+		nabat.WithDescription("Deploy the images running in one environment to another"),
+		nabat.WithLongDescription("Deploy the project to the target environment with the exact images its release runs in the source environment, pinned by digest when the source pods report one. Everything else comes from the spec for the target environment. Shows what would change and asks for confirmation before applying, unless --yes is set. The platform file's promoteFrom lists which environments the target accepts."),
+		nabat.WithArg("from", "", nabat.WithRequired(), nabat.WithUsage("Environment to promote from"), nabat.WithPrompt("Promote from", "", nabat.WithHint("e.g. staging"))),
+		nabat.WithArg("to", "", nabat.WithRequired(), nabat.WithUsage("Environment to promote to"), nabat.WithPrompt("Promote to", "", nabat.WithHint("e.g. prod"))),
+		nabat.WithFlag("yes", false, nabat.WithShort('y'), nabat.WithUsage("Apply without an interactive confirmation prompt")),
+		nabat.WithFlag("write-spec", false, nabat.WithUsage("After a successful promote, write the promoted images to environments.<to>.components in the spec file")),
+		nabat.WithFlag("rollback-on-failure", false, nabat.WithUsage("Roll back to the last successful revision when pods are not ready within --timeout or a postDeploy task fails (default: the platform environment's rollbackOnFailure)")),
+		nabat.WithFlag("no-rollback-on-failure", false, nabat.WithUsage("Do not roll back on failure, even when the platform environment sets rollbackOnFailure")),
+		nabat.WithFlag("report-file", "", nabat.WithUsage("When the deploy fails, also write the failure diagnosis (pod problems, recent logs, warning events) to this file as JSON")),
+		nabat.WithSelectFlag("output", cli.OutputFormatText, cli.EventOutputFormats, nabat.WithUsage("Output format: text, or jsonl to stream progress events as JSON lines on stdout (text output moves to stderr)")),
+		nabat.WithExample(`
+# Deploy what runs in staging to production
+deployah promote staging prod
+
+# Promote and record the pinned images in deployah.yaml
+deployah promote staging prod --write-spec
+
+# Promote without an interactive confirmation prompt (e.g. in CI)
+deployah promote staging prod --yes`),
+		nabat.WithRun(cmdopts.WithEventStream("promote", runPromote)),
+	)
+}
+
+func runPromote(c *nabat.Context) error {
+	opts := &Options{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+	if opts.RollbackOnFailure && opts.NoRollbackOnFailure {
+		return errors.New("--rollback-on-failure cannot be used with --no-rollback-on-failure")
+	}
+
+	sess := session.FromContext(c)
+	platform, err := sess.Platform()
+	if err != nil {
+		return fmt.Errorf("load platform file: %w", err)
+	}
+	if checkErr := spec.CheckPromotion(platform, opts.From, opts.To); checkErr != nil {
+		return checkErr
+	}
+
+	// Only the project name is needed here; deploy loads the spec for the
+	// target environment itself.
+	rawSpec, _, err := spec.ParseManifest(sess.SpecPath())
+	if err != nil {
+		return fmt.Errorf("parse manifest: %w", err)
+	}
+	project := rawSpec.Project
+
+	images, err := sourceImages(c, sess, project, opts.From, slices.Collect(maps.Keys(rawSpec.Components)))
+	if err != nil {
+		return err
+	}
+
+	var pinned map[string]string
+	hooks := deploy.Hooks{
+		Transform: func(m *spec.Spec) error {
+			var missing []string
+			pinned, missing = pinImages(m, images)
+			if len(pinned) == 0 {
+				return fmt.Errorf("the %s release of %s runs none of the spec's components or tasks", opts.From, project)
+			}
+			printPinned(c, opts.From, opts.To, pinned)
+			if len(missing) > 0 {
+				c.Warn(fmt.Sprintf("Not running in %s, keeping the spec's image: %s", opts.From, strings.Join(missing, ", ")))
+			}
+			return nil
+		},
+	}
+	if opts.WriteSpec {
+		hooks.Done = func() error {
+			return writeSpec(c, sess.SpecPath(), opts.To, rawSpec, pinned)
+		}
+	}
+
+	return deploy.Deploy(c, &deploy.Options{
+		Environment:         opts.To,
+		Yes:                 opts.Yes,
+		CRDs:                string(extras.PolicyCreate),
+		RollbackOnFailure:   opts.RollbackOnFailure,
+		NoRollbackOnFailure: opts.NoRollbackOnFailure,
+		ReportFile:          opts.ReportFile,
+		Output:              opts.Output,
+	}, hooks)
+}
+
+// sourceImages reads the images of the last successful release of project
+// in environment from, with component digests filled in from its pods.
+func sourceImages(c *nabat.Context, sess *session.Session, project, from string, components []string) (map[string]imageRef, error) {
+	cluster, err := sess.Target(c, project, from)
+	if err != nil {
+		return nil, fmt.Errorf("target cluster: %w", err)
+	}
+	helmClient, err := cluster.Helm()
+	if err != nil {
+		return nil, fmt.Errorf("helm client: %w%s", err, cmdopts.ClusterHint(err))
+	}
+	if reachErr := helmClient.IsReachable(); reachErr != nil {
+		return nil, fmt.Errorf("%w%s", reachErr, cmdopts.ClusterHint(reachErr))
+	}
+	cmdopts.WarnContextFallback(c, cluster, from)
+
+	rel, warning, err := planengine.LastSuccessfulRelease(c, helmClient, project, from)
+	if err != nil {
+		return nil, fmt.Errorf("%s release: %w", from, err)
+	}
+	if rel == nil {
+		return nil, fmt.Errorf("no successful release of project %q in environment %q to promote", project, from)
+	}
+	if warning != "" {
+		c.Warn(fmt.Sprintf("%s: %s", from, warning))
+	}
+	images := releaseImages(rel)
+	if len(images) == 0 {
+		return nil, errors.New("the source release records no images")
+	}
+
+	clientset, k8sErr := cluster.Kubernetes()
+	if k8sErr != nil {
+		c.Logger().Debug("kubernetes client unavailable; promoting tags as recorded", "err", k8sErr)
+		return images, nil
+	}
+	if digestErr := resolveDigests(c, k8s.NewClient(clientset, cluster.Namespace()), project, from, images, components); digestErr != nil {
+		c.Logger().Debug("pod image digests unavailable; promoting tags as recorded", "err", digestErr)
+	}
+	c.Logger().Debug("source release images", "env", from, "revision", rel.Version, "count", len(images))
+	return images, nil
+}
+
+// printPinned lists the image each component and task is promoted with.
+func printPinned(c *nabat.Context, from, to string, pinned map[string]string) {
+	cmdopts.Printf(c, "Promoting images from %s to %s:\n", from, to)
+	names := slices.Sorted(maps.Keys(pinned))
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		cmdopts.Printf(c, "  %-*s  %s\n", width, name, pinned[name])
+	}
+	cmdopts.Println(c)
+}
+
+// writeSpec records the promoted component images as overrides of
+// environment to in the spec file. Task images have no environment
+// override, so they are reported instead.
+func writeSpec(c *nabat.Context, path, to string, rawSpec *spec.Spec, pinned map[string]string) error {
+	components := map[string]string{}
+	var tasks []string
+	for name, image := range pinned {
+		if _, ok := rawSpec.Components[name]; ok {
+			components[name] = image
+			continue
+		}
+		tasks = append(tasks, name)
+	}
+	if err := spec.WritePromotedImages(path, to, components); err != nil {
+		return fmt.Errorf("write promoted images: %w", err)
+	}
+	c.Success("Wrote promoted images to "+path, "environment", to, "components", len(components))
+	if len(tasks) > 0 {
+		slices.Sort(tasks)
+		c.Warn("Task images have no per-environment override and were not written: " + strings.Join(tasks, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+
+	"deployah.dev/deployah/internal/spec"
+
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+const digest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+
+func TestReleaseImages(t *testing.T) {
+	t.Parallel()
+
+	rel := &v1.Release{Chart: &chart.Chart{Values: map[string]any{
+		"api":     map[string]any{"image": map[string]any{"repository": "ghcr.io/acme/api", "tag": "1.4.0"}},
+		"migrate": map[string]any{"image": map[string]any{"repository": "ghcr.io/acme/migrate", "digest": digest}},
+		"global":  map[string]any{"labels": map[string]any{}},
+		"broken":  map[string]any{"image": map[string]any{"tag": "1"}},
+	}}}
+
+	images := releaseImages(rel)
+	assert.Equal(t, map[string]imageRef{
+		"api":     {Repository: "ghcr.io/acme/api", Tag: "1.4.0"},
+		"migrate": {Repository: "ghcr.io/acme/migrate", Digest: digest},
+	}, images)
+	assert.Equal(t, "ghcr.io/acme/api:1.4.0", images["api"].String())
+	assert.Equal(t, "ghcr.io/acme/migrate@"+digest, images["migrate"].String())
+	assert.Empty(t, releaseImages(nil))
+}
+
+func TestPinImages(t *testing.T) {
+	t.Parallel()
+
+	m := &spec.Spec{
+		Components: map[string]spec.Component{
+			"api":    {Image: "ghcr.io/acme/api:${TAG}"},
+			"worker": {Image: "ghcr.io/acme/worker:${TAG}"},
+		},
+		Tasks: map[string]spec.Task{
+			"migrate": {Image: "ghcr.io/acme/migrate:${TAG}"},
+			"seed":    {From: "api"},
+		},
+	}
+	images := map[string]imageRef{
+		"api":     {Repository: "ghcr.io/acme/api", Tag: "1.4.0", Digest: digest},
+		"migrate": {Repository: "ghcr.io/acme/migrate", Tag: "1.4.0"},
+	}
+
+	pinned, missing := pinImages(m, images)
+	assert.Equal(t, map[string]string{
+		"api":     "ghcr.io/acme/api@" + digest,
+		"migrate": "ghcr.io/acme/migrate:1.4.0",
+	}, pinned)
+	assert.Equal(t, []string{"worker"}, missing)
+	assert.Equal(t, "ghcr.io/acme/api@"+digest, m.Components["api"].Image)
+	assert.Equal(t, "ghcr.io/acme/worker:${TAG}", m.Components["worker"].Image)
+	assert.Equal(t, "ghcr.io/acme/migrate:1.4.0", m.Tasks["migrate"].Image)
+	assert.Empty(t, m.Tasks["seed"].Image, "tasks running the parent image follow the component")
+}
@@ -45,8 +45,10 @@ func Register(app *nabat.App) {
 
 resolve is offline: it never contacts a Kubernetes cluster. It loads the
 platform file (deployah.platform.yaml) when present and performs full
-resolution, including FQDN construction and TLS mode selection. When the
-platform file is absent the output is partial and includes PLATFORM_NOT_FOUND.
+resolution, including FQDN construction, TLS mode selection, the Gateway
+an HTTPRoute attaches to on gateway-mode domains, and the
+connectivity matrix that NetworkPolicies enforce. When the platform file is
+absent the output is partial and includes PLATFORM_NOT_FOUND.
 
 With --environments it instead lists every environment from the spec and
 platform files: where each is registered, its context (or the kubeconfig
@@ -134,6 +136,9 @@ func outputText(c *nabat.Context, resolved *spec.ResolvedSpec, report *spec.Reso
 	if resolved.KubeContext != "" {
 		c.Println(fmt.Sprintf("Context:     %s", resolved.KubeContext))
 	}
+	if resolved.Namespace != "" {
+		c.Println(fmt.Sprintf("Namespace:   %s", resolved.Namespace))
+	}
 
 	// Sort components for deterministic output.
 	names := make([]string, 0, len(resolved.Components))
@@ -145,13 +150,18 @@ func outputText(c *nabat.Context, resolved *spec.ResolvedSpec, report *spec.Reso
 	c.Println("\nComponents:")
 	for _, name := range names {
 		rc := resolved.Components[name]
-		if rc.FQDN == "" && len(rc.Profiles) == 0 {
+		componentSecrets := resolvedSecrets(resolved, name)
+		overrides := resolvedOverrides(resolved, name)
+		if rc.FQDN == "" && len(rc.Profiles) == 0 && len(componentSecrets) == 0 && len(overrides) == 0 {
 			continue
 		}
 		c.Println(fmt.Sprintf("  %s:", name))
 		if rc.FQDN != "" {
 			c.Println(fmt.Sprintf("    hostname: %s", rc.FQDN))
 		}
+		if rc.GatewayRef != nil {
+			c.Println(fmt.Sprintf("    gateway: %s", rc.GatewayRef))
+		}
 		if rc.TLSMode != "" {
 			c.Println(fmt.Sprintf("    tls.mode: %s", rc.TLSMode))
 		}
@@ -170,6 +180,19 @@ func outputText(c *nabat.Context, resolved *spec.ResolvedSpec, report *spec.Reso
 		if rc.StorageClass != "" {
 			c.Println(fmt.Sprintf("    storageClass: %s", rc.StorageClass))
 		}
+		for _, env := range slices.Sorted(maps.Keys(componentSecrets)) {
+			c.Println(fmt.Sprintf("    secret %s: %s", env, componentSecrets[env]))
+		}
+		for _, f := range overrides {
+			c.Println(fmt.Sprintf("    %s: %s (from %s)", f.Path, f.Value, f.Source))
+		}
+	}
+
+	if lines := connectivityLines(resolved); len(lines) > 0 {
+		c.Println("\nConnectivity:")
+		for _, line := range lines {
+			c.Println(line)
+		}
 	}
 
 	if len(report.Warnings) > 0 {
@@ -187,6 +210,7 @@ func outputText(c *nabat.Context, resolved *spec.ResolvedSpec, report *spec.Reso
 type jsonResolveOutput struct {
 	Environment  string                   `json:"environment"`
 	Context      string                   `json:"context,omitempty"`
+	Namespace    string                   `json:"namespace,omitempty"`
 	Components   map[string]jsonComponent `json:"components"`
 	Warnings     []string                 `json:"warnings,omitempty"`
 	ErrorCode    string                   `json:"error_code,omitempty"`
@@ -194,13 +218,122 @@ type jsonResolveOutput struct {
 }
 
 type jsonComponent struct {
-	FQDN          string                `json:"fqdn,omitempty"`
-	TLSMode       string                `json:"tls_mode,omitempty"`
-	TLSIssuer     string                `json:"tls_issuer,omitempty"`
-	TLSSecretName string                `json:"tls_secret_name,omitempty"`
-	Profiles      []string              `json:"profiles,omitempty"`
-	MergedProfile *spec.PlatformProfile `json:"merged_profile,omitempty"`
-	StorageClass  string                `json:"storage_class,omitempty"`
+	FQDN          string                 `json:"fqdn,omitempty"`
+	TLSMode       string                 `json:"tls_mode,omitempty"`
+	TLSIssuer     string                 `json:"tls_issuer,omitempty"`
+	TLSSecretName string                 `json:"tls_secret_name,omitempty"`
+	GatewayRef    *spec.GatewayRef       `json:"gateway_ref,omitempty"`
+	Profiles      []string               `json:"profiles,omitempty"`
+	MergedProfile *spec.PlatformProfile  `json:"merged_profile,omitempty"`
+	StorageClass  string                 `json:"storage_class,omitempty"`
+	Secrets       map[string]jsonSecret  `json:"secrets,omitempty"`
+	Network       *spec.ComponentNetwork `json:"network,omitempty"`
+	Overrides     []jsonOverride         `json:"overrides,omitempty"`
+}
+
+// jsonOverride is one field set by the environment's components block.
+type jsonOverride struct {
+	Path   string `json:"path"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// redactedSecretValue stands in for every secret value in resolve output.
+// resolve never decrypts anything; the field makes the redaction explicit
+// for scripts that read the JSON.
+const redactedSecretValue = "(redacted)"
+
+// jsonSecret is one entry of a component's secrets block: where the value
+// comes from, never the value itself.
+type jsonSecret struct {
+	Source string `json:"source"`
+	Value  string `json:"value"`
+}
+
+// resolvedSecrets returns the secrets block of component name in the
+// resolved spec, or nil when it has none.
+func resolvedSecrets(resolved *spec.ResolvedSpec, name string) map[string]spec.SecretSource {
+	if resolved.Spec == nil {
+		return nil
+	}
+	return resolved.Spec.Components[name].Secrets
+}
+
+// resolvedOverrides returns the fields of component name set by the
+// environment's components block, in path order.
+func resolvedOverrides(resolved *spec.ResolvedSpec, name string) []spec.ResolvedField {
+	if resolved.Spec == nil {
+		return nil
+	}
+	var out []spec.ResolvedField
+	for _, f := range resolved.Spec.Overrides {
+		if f.Component == name {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// jsonSecrets describes secrets for JSON output with every value redacted.
+func jsonSecrets(secrets map[string]spec.SecretSource) map[string]jsonSecret {
+	if len(secrets) == 0 {
+		return nil
+	}
+	out := make(map[string]jsonSecret, len(secrets))
+	for env, src := range secrets {
+		out[env] = jsonSecret{Source: src.String(), Value: redactedSecretValue}
+	}
+	return out
+}
+
+// jsonOverrides converts override provenance for JSON output.
+func jsonOverrides(fields []spec.ResolvedField) []jsonOverride {
+	if len(fields) == 0 {
+		return nil
+	}
+	out := make([]jsonOverride, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, jsonOverride{Path: f.Path, Value: f.Value, Source: f.Source})
+	}
+	return out
+}
+
+// connectivityLines renders the connectivity matrix for text output: per
+// component, whether a NetworkPolicy isolates it, who may connect in, and
+// what it may connect out to. Open components list their declared
+// connections too, marked as not enforced.
+func connectivityLines(resolved *spec.ResolvedSpec) []string {
+	var lines []string
+	for _, name := range slices.Sorted(maps.Keys(resolved.Components)) {
+		n := resolved.Components[name].Network
+		if n == nil {
+			continue
+		}
+		mode := "default-deny"
+		if !n.DefaultDeny {
+			mode = "open, not enforced"
+		}
+		lines = append(lines, fmt.Sprintf("  %s (%s):", name, mode))
+		lines = append(lines, "    in:  "+joinRules(n.Ingress, n.DefaultDeny))
+		lines = append(lines, "    out: "+joinRules(n.Egress, n.DefaultDeny))
+	}
+	return lines
+}
+
+// joinRules lists rules by their other end. An empty list means nothing
+// when the component is isolated and anything when it is open.
+func joinRules(rules []spec.NetworkRule, defaultDeny bool) string {
+	if len(rules) == 0 {
+		if defaultDeny {
+			return "(none)"
+		}
+		return "(any)"
+	}
+	parts := make([]string, 0, len(rules))
+	for _, r := range rules {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, ", ")
 }
 
 // printMergedProfile writes key merged profile fields for text output.
@@ -239,10 +372,32 @@ func printMergedProfile(c *nabat.Context, p *spec.PlatformProfile) {
 		if p.MaxResources.Memory != nil && !p.MaxResources.Memory.IsZero() {
 			parts = append(parts, "memory="+p.MaxResources.Memory.String())
 		}
+		if l := p.MaxResources.Limits; l != nil {
+			if l.CPU != nil && !l.CPU.IsZero() {
+				parts = append(parts, "limits.cpu="+l.CPU.String())
+			}
+			if l.Memory != nil && !l.Memory.IsZero() {
+				parts = append(parts, "limits.memory="+l.Memory.String())
+			}
+		}
 		if len(parts) > 0 {
 			c.Println(fmt.Sprintf("    maxResources: %s", strings.Join(parts, ", ")))
 		}
 	}
+	if l := p.ResourceLimits; l != nil {
+		parts := make([]string, 0, 3)
+		for _, f := range []struct {
+			name   string
+			policy spec.LimitPolicy
+		}{{"cpu", l.CPU}, {"memory", l.Memory}, {"ephemeralStorage", l.EphemeralStorage}} {
+			if f.policy != "" {
+				parts = append(parts, fmt.Sprintf("%s=%s", f.name, f.policy))
+			}
+		}
+		if len(parts) > 0 {
+			c.Println(fmt.Sprintf("    resourceLimits: %s", strings.Join(parts, ", ")))
+		}
+	}
 }
 
 // envOverviewRow is one environment in the --environments overview.
@@ -254,6 +409,7 @@ type envOverviewRow struct {
 	Deployable      bool     `json:"deployable"`
 	Context         string   `json:"context,omitempty"`
 	ContextFallback string   `json:"context_fallback,omitempty"`
+	Namespace       string   `json:"namespace,omitempty"`
 	Domains         []string `json:"domains,omitempty"`
 	Overrides       []string `json:"overrides,omitempty"`
 }
@@ -286,6 +442,9 @@ func buildEnvironmentOverview(rawSpec *spec.Spec, platform *spec.PlatformConfig,
 			if pe.Context == "" {
 				row.ContextFallback = currentCtx
 			}
+			if pe.Namespace != nil {
+				row.Namespace = pe.Namespace.Name
+			}
 			row.Domains = slices.Sorted(maps.Keys(pe.Domains))
 		} else {
 			row.Source = "spec-only"
@@ -347,6 +506,9 @@ func runEnvironmentsOverview(c *nabat.Context, sess *session.Session, output str
 		default:
 			c.Println("    context:    (none — deploys follow the current kubeconfig context)")
 		}
+		if r.Namespace != "" {
+			c.Println("    namespace:  " + r.Namespace)
+		}
 		if len(r.Domains) > 0 {
 			c.Println("    domains:    " + strings.Join(r.Domains, ", "))
 		}
@@ -362,6 +524,7 @@ func outputJSON(c *nabat.Context, resolved *spec.ResolvedSpec, report *spec.Reso
 	out := jsonResolveOutput{
 		Environment:  report.Env.Original,
 		Context:      resolved.KubeContext,
+		Namespace:    resolved.Namespace,
 		Components:   make(map[string]jsonComponent),
 		Warnings:     report.Warnings,
 		ErrorCode:    report.ErrorCode,
@@ -374,9 +537,13 @@ func outputJSON(c *nabat.Context, resolved *spec.ResolvedSpec, report *spec.Reso
 			TLSMode:       string(rc.TLSMode),
 			TLSIssuer:     rc.TLSIssuer,
 			TLSSecretName: rc.TLSSecretName,
+			GatewayRef:    rc.GatewayRef,
 			Profiles:      rc.Profiles,
 			MergedProfile: rc.MergedProfile,
 			StorageClass:  rc.StorageClass,
+			Secrets:       jsonSecrets(resolvedSecrets(resolved, name)),
+			Network:       rc.Network,
+			Overrides:     jsonOverrides(resolvedOverrides(resolved, name)),
 		}
 	}
 
@@ -27,7 +27,7 @@ func TestBuildEnvironmentOverview(t *testing.T) {
 				Context: "kind-deployah",
 				Domains: map[string]spec.PlatformDomain{"public": {BaseDomain: "127.0.0.1.nip.io"}},
 			},
-			"production": {},
+			"production": {Namespace: &spec.PlatformNamespace{Name: "{project}-{env}"}},
 		},
 	}
 
@@ -47,6 +47,7 @@ func TestBuildEnvironmentOverview(t *testing.T) {
 	assert.True(t, production.Deployable)
 	assert.Empty(t, production.Context)
 	assert.Equal(t, "minikube", production.ContextFallback)
+	assert.Equal(t, "{project}-{env}", production.Namespace)
 	assert.Equal(t, []string{"variables"}, production.Overrides)
 
 	assert.Equal(t, "qa", qa.Name)
@@ -70,3 +71,67 @@ func TestBuildEnvironmentOverview_NoPlatformFile(t *testing.T) {
 	assert.True(t, rows[0].Deployable)
 	assert.Equal(t, "minikube", rows[0].ContextFallback)
 }
+
+// TestResolvedOverrides lists only the named component's overridden fields.
+func TestResolvedOverrides(t *testing.T) {
+	t.Parallel()
+
+	resolved := &spec.ResolvedSpec{Spec: &spec.Spec{Overrides: []spec.ResolvedField{
+		{Component: "api", Path: "replicas", Value: "3", Source: "spec environments.production.components.api.replicas"},
+		{Component: "worker", Path: "replicas", Value: "2", Source: "spec environments.production.components.worker.replicas"},
+	}}}
+
+	got := jsonOverrides(resolvedOverrides(resolved, "api"))
+	assert.Equal(t, []jsonOverride{
+		{Path: "replicas", Value: "3", Source: "spec environments.production.components.api.replicas"},
+	}, got)
+	assert.Nil(t, jsonOverrides(resolvedOverrides(resolved, "web")))
+}
+
+// TestJSONSecrets verifies secrets are listed by source with every value
+// redacted.
+func TestJSONSecrets(t *testing.T) {
+	t.Parallel()
+
+	got := jsonSecrets(map[string]spec.SecretSource{
+		"DATABASE_PASSWORD": {SecretRef: &spec.SecretKeyRef{Name: "db-credentials", Key: "password"}},
+		"STRIPE_KEY":        {Provider: spec.SecretProviderSOPS, File: "secrets/prod.enc.yaml", Key: "stripe.key"},
+	})
+	assert.Equal(t, map[string]jsonSecret{
+		"DATABASE_PASSWORD": {Source: "secretRef db-credentials/password", Value: redactedSecretValue},
+		"STRIPE_KEY":        {Source: "sops secrets/prod.enc.yaml#stripe.key", Value: redactedSecretValue},
+	}, got)
+	assert.Nil(t, jsonSecrets(nil))
+}
+
+// TestConnectivityLines verifies the connectivity matrix lists isolated
+// and open components with their allowed connections.
+func TestConnectivityLines(t *testing.T) {
+	t.Parallel()
+
+	resolved := &spec.ResolvedSpec{
+		Components: map[string]spec.ResolvedComponent{
+			"api": {Network: &spec.ComponentNetwork{
+				DefaultDeny: true,
+				Ingress: []spec.NetworkRule{
+					{Kind: spec.NetworkEndpointComponent, Name: "web"},
+					{Kind: spec.NetworkEndpointMonitoring, Port: "http"},
+				},
+				Egress: []spec.NetworkRule{{Kind: spec.NetworkEndpointDNS}},
+			}},
+			"web": {Network: &spec.ComponentNetwork{
+				Egress: []spec.NetworkRule{{Kind: spec.NetworkEndpointComponent, Name: "api"}},
+			}},
+			"worker": {},
+		},
+	}
+	assert.Equal(t, []string{
+		"  api (default-deny):",
+		"    in:  component web, monitoring (port http)",
+		"    out: dns",
+		"  web (open, not enforced):",
+		"    in:  (any)",
+		"    out: component api",
+	}, connectivityLines(resolved))
+	assert.Empty(t, connectivityLines(&spec.ResolvedSpec{}))
+}
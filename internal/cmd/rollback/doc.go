@@ -0,0 +1,20 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollback implements the deployah rollback command, which moves a
+// release back to an earlier Helm revision. It shows the plan-style diff
+// between the current and target manifests, applies the same workload
+// guards as `deployah deploy`, and reports readiness with the deploy
+// watcher.
+package rollback
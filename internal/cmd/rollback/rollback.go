@@ -0,0 +1,203 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollback
+
+import (
+	"errors"
+	"fmt"
+
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/cmd/deploy"
+	"deployah.dev/deployah/internal/helm"
+	"deployah.dev/deployah/internal/readiness"
+	"deployah.dev/deployah/internal/session"
+	"deployah.dev/deployah/internal/spec"
+
+	planengine "deployah.dev/deployah/internal/plan"
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+// Options holds command-line flags for rollback.
+type Options struct {
+	Environment string `nabat:"environment"`
+	ToRevision  int    `nabat:"to-revision"`
+	Yes         bool   `nabat:"yes"`
+}
+
+// Register adds the rollback command to app.
+func Register(app *nabat.App) {
+	app.MustCommand("rollback",
+		nabat.WithDescription("Roll a project back to an earlier revision"),
+		nabat.WithLongDescription("Roll the project in the spec back to an earlier Helm revision in an environment. Defaults to the last successful revision before the current one. Shows what would change and asks for confirmation before applying, unless --yes is set."),
+		nabat.WithArg("environment", "", nabat.WithRequired(), nabat.WithUsage("Environment to roll back"), nabat.WithPrompt("Environment", "", nabat.WithHint("e.g. prod, staging"))),
+		nabat.WithFlag("to-revision", 0, nabat.WithUsage("Revision to roll back to (default: the last successful revision before the current one)")),
+		nabat.WithFlag("yes", false, nabat.WithShort('y'), nabat.WithUsage("Apply without an interactive confirmation prompt")),
+		nabat.WithExample(`
+# Roll production back to the last successful revision
+deployah rollback prod
+
+# Roll back to a specific revision (see 'deployah status' for the current one)
+deployah rollback prod --to-revision 3
+
+# Roll back without an interactive confirmation prompt (e.g. in CI)
+deployah rollback prod --yes`),
+		nabat.WithRun(runRollback),
+	)
+}
+
+func runRollback(c *nabat.Context) error {
+	opts := &Options{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+	if opts.ToRevision < 0 {
+		return errors.New("--to-revision must be a positive revision number")
+	}
+
+	sess := session.FromContext(c)
+
+	// Only the project name is needed: the target manifest comes from the
+	// release history, not from re-rendering the spec.
+	rawSpec, _, err := spec.ParseManifest(sess.SpecPath())
+	if err != nil {
+		return fmt.Errorf("parse manifest: %w", err)
+	}
+	project := rawSpec.Project
+
+	cluster, err := sess.Target(c, project, opts.Environment)
+	if err != nil {
+		return fmt.Errorf("target cluster: %w", err)
+	}
+	helmClient, err := cluster.Helm()
+	if err != nil {
+		return fmt.Errorf("helm client: %w%s", err, cmdopts.ClusterHint(err))
+	}
+	if reachErr := helmClient.IsReachable(); reachErr != nil {
+		return fmt.Errorf("%w%s", reachErr, cmdopts.ClusterHint(reachErr))
+	}
+	cmdopts.WarnContextFallback(c, cluster, opts.Environment)
+
+	history, err := helmClient.GetReleaseHistory(c, project, opts.Environment)
+	if err != nil {
+		if errors.Is(err, helm.ErrReleaseNotFound) {
+			return fmt.Errorf("no release found for project %q in environment %q", project, opts.Environment)
+		}
+		return fmt.Errorf("release history: %w", err)
+	}
+	current, target, err := pickRevisions(history, opts.ToRevision)
+	if err != nil {
+		return err
+	}
+
+	// Same hard rules as deploy: Helm would happily roll a StatefulSet back
+	// to a Deployment, or shrink a volume claim, and leave the release
+	// broken halfway.
+	if guardErr := deploy.CheckReleaseTransition(project, opts.Environment, planengine.ChartValues(current), planengine.ChartValues(target)); guardErr != nil {
+		return guardErr
+	}
+
+	diff, err := planengine.ComputeDiff(current.Manifest, target.Manifest)
+	if err != nil {
+		return fmt.Errorf("compute diff: %w", err)
+	}
+	diff.HooksChanged = planengine.HooksChanged(current.Hooks, target.Hooks)
+	diff.Header = planengine.Header{
+		Project:     project,
+		Environment: opts.Environment,
+		Release:     current.Name,
+		Namespace:   current.Namespace,
+		Context:     cluster.Context(),
+		Revision:    current.Version,
+	}
+	c.Printf("Rolling back from revision %d (%s) to revision %d (%s).\n\n",
+		current.Version, planengine.ReleaseStatus(current), target.Version, planengine.ReleaseStatus(target))
+	textOpts := planengine.TextOptions{Mode: planengine.ModeCompact, Theme: c.Theme()}
+	if renderErr := planengine.RenderText(c.IO().Out, diff, textOpts); renderErr != nil {
+		return fmt.Errorf("render plan: %w", renderErr)
+	}
+
+	confirmed, err := c.Confirm(
+		fmt.Sprintf("Roll back '%s' in '%s' to revision %d?", project, opts.Environment, target.Version),
+		nabat.WithYes(opts.Yes),
+		nabat.WithBypassHint("--yes"),
+	)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		c.Println("Aborted.")
+		return nil
+	}
+
+	unlock, lockErr := cmdopts.LockRelease(c, cluster, project, opts.Environment, "rollback")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer unlock()
+
+	k8sClient, k8sErr := cluster.Kubernetes()
+	if k8sErr != nil {
+		c.Logger().Debug("kubernetes client unavailable", "err", k8sErr)
+	}
+	title := fmt.Sprintf("Rolling back '%s' to revision %d...", opts.Environment, target.Version)
+	watcher, err := deploy.RunWatched(c, k8sClient, k8sErr, cluster.Namespace(), current.Name, title, func() error {
+		return helmClient.RollbackRelease(c, current.Name, target.Version, sess.Timeout())
+	})
+	if err != nil {
+		return fmt.Errorf("rollback failed: %w%s", err, cmdopts.ClusterHint(err))
+	}
+
+	summary := ""
+	if watcher != nil {
+		if s := readiness.Summary(watcher.Summary()); s != "" {
+			summary = " (" + s + ")"
+		}
+	}
+	c.Success("Rolled back"+summary, "project", project, "environment", opts.Environment, "revision", target.Version)
+	return nil
+}
+
+// pickRevisions returns the newest revision in history (the one being rolled
+// back from) and the revision to roll back to. With toRevision zero the
+// target is the newest successful revision older than current, which is
+// what [planengine.LastSuccessfulRelease] returns whenever the current
+// revision itself failed.
+func pickRevisions(history []*v1.Release, toRevision int) (current, target *v1.Release, err error) {
+	releases := planengine.SortedReleases(history)
+	if len(releases) == 0 {
+		return nil, nil, errors.New("release has no history to roll back to")
+	}
+	current = releases[0]
+
+	if toRevision > 0 {
+		if toRevision == current.Version {
+			return nil, nil, fmt.Errorf("revision %d is already the current revision", toRevision)
+		}
+		target, err = planengine.FindRevision(releases, toRevision)
+		if err != nil {
+			return nil, nil, err
+		}
+		return current, target, nil
+	}
+
+	for _, rel := range releases[1:] {
+		if planengine.Successful(rel) {
+			return current, rel, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no successful revision older than %d to roll back to; pass --to-revision to pick one", current.Version)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v4/pkg/release/common"
+
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+func rel(version int, status common.Status) *v1.Release {
+	return &v1.Release{Name: "shop-production", Version: version, Info: &v1.Info{Status: status}}
+}
+
+func TestPickRevisions_DefaultSkipsHealthyCurrent(t *testing.T) {
+	t.Parallel()
+	// Unordered on purpose: storage drivers do not guarantee list order.
+	history := []*v1.Release{
+		rel(2, common.StatusSuperseded),
+		rel(4, common.StatusDeployed),
+		rel(3, common.StatusFailed),
+		rel(1, common.StatusSuperseded),
+	}
+	current, target, err := pickRevisions(history, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 4, current.Version)
+	assert.Equal(t, 2, target.Version)
+}
+
+func TestPickRevisions_DefaultAfterFailedDeploy(t *testing.T) {
+	t.Parallel()
+	history := []*v1.Release{
+		rel(1, common.StatusSuperseded),
+		rel(2, common.StatusDeployed),
+		rel(3, common.StatusFailed),
+	}
+	current, target, err := pickRevisions(history, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, current.Version)
+	assert.Equal(t, 2, target.Version)
+}
+
+func TestPickRevisions_ExplicitRevision(t *testing.T) {
+	t.Parallel()
+	history := []*v1.Release{
+		rel(1, common.StatusSuperseded),
+		rel(2, common.StatusSuperseded),
+		rel(3, common.StatusDeployed),
+	}
+	_, target, err := pickRevisions(history, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, target.Version)
+
+	_, _, err = pickRevisions(history, 3)
+	require.ErrorContains(t, err, "already the current revision")
+
+	_, _, err = pickRevisions(history, 9)
+	require.ErrorContains(t, err, "available revisions: 3, 2, 1")
+}
+
+func TestPickRevisions_NothingToRollBackTo(t *testing.T) {
+	t.Parallel()
+	_, _, err := pickRevisions([]*v1.Release{rel(1, common.StatusDeployed)}, 0)
+	require.ErrorContains(t, err, "no successful revision older than 1")
+
+	_, _, err = pickRevisions(nil, 0)
+	require.Error(t, err)
+}
@@ -31,13 +31,18 @@ import (
 	"deployah.dev/deployah/internal/cmd/cmdopts"
 	"deployah.dev/deployah/internal/cmd/delete"
 	"deployah.dev/deployah/internal/cmd/deploy"
+	"deployah.dev/deployah/internal/cmd/export"
+	"deployah.dev/deployah/internal/cmd/history"
 	"deployah.dev/deployah/internal/cmd/initialize"
 	"deployah.dev/deployah/internal/cmd/list"
 	"deployah.dev/deployah/internal/cmd/logs"
+	"deployah.dev/deployah/internal/cmd/promote"
 	"deployah.dev/deployah/internal/cmd/resolve"
+	"deployah.dev/deployah/internal/cmd/rollback"
 	"deployah.dev/deployah/internal/cmd/run"
 	"deployah.dev/deployah/internal/cmd/shell"
 	"deployah.dev/deployah/internal/cmd/status"
+	"deployah.dev/deployah/internal/cmd/unlock"
 	"deployah.dev/deployah/internal/cmd/validate"
 	"deployah.dev/deployah/internal/plan"
 	"deployah.dev/deployah/internal/session"
@@ -65,7 +70,7 @@ func NewApp(opts ...nabat.Option) *nabat.App {
 		nabat.WithFlag("debug", false, nabat.WithShort('d'), nabat.WithUsage("Enable debug mode (verbose logging and keep temporary files)"), nabat.WithPersistent()),
 		nabat.WithFlag("spec", spec.DefaultSpecPath, nabat.WithShort('s'), nabat.WithUsage("Path to the Deployah spec file (YAML or JSON)"), nabat.WithPersistent()),
 		nabat.WithFlag("platform-file", "", nabat.WithUsage("Path to the platform config file (overrides DEPLOYAH_PLATFORM_FILE and the default same-directory lookup)"), nabat.WithPersistent()),
-		nabat.WithFlag("namespace", "", nabat.WithShort('n'), nabat.WithUsage("Kubernetes namespace to use for Deployah operations (defaults to current context namespace)"), nabat.WithPersistent()),
+		nabat.WithFlag("namespace", "", nabat.WithShort('n'), nabat.WithUsage("Kubernetes namespace to use for Deployah operations (overrides the platform file's environment namespace; defaults to \"default\")"), nabat.WithPersistent()),
 		nabat.WithFlag("kubeconfig", "", nabat.WithShort('k'), nabat.WithUsage("Path to the kubeconfig file to use (defaults to standard kubeconfig resolution)"), nabat.WithPersistent()),
 		nabat.WithFlag("context", "", nabat.WithUsage("Kubernetes context to use (overrides the current context and any environment 'context' field)"), nabat.WithPersistent()),
 		nabat.WithFlag("timeout", session.DefaultTimeout, nabat.WithShort('t'), nabat.WithUsage("Timeout for Deployah operations (install/upgrade, list, status, logs, delete, run)"), nabat.WithPersistent()),
@@ -104,6 +109,7 @@ func NewApp(opts ...nabat.Option) *nabat.App {
 			session.WithSpecPath(opts.Spec),
 			session.WithDebug(opts.Debug),
 			session.WithTimeout(opts.Timeout),
+			session.WithCLIVersion(version),
 		}
 		if opts.PlatformFile != "" {
 			rtOpts = append(rtOpts, session.WithPlatformFile(opts.PlatformFile))
@@ -123,14 +129,19 @@ func NewApp(opts ...nabat.Option) *nabat.App {
 	cluster.Register(app)
 	delete.Register(app)
 	deploy.Register(app)
+	export.Register(app)
+	history.Register(app)
 	initialize.Register(app)
 	list.Register(app)
 	logs.Register(app)
 	planCmd.Register(app)
+	promote.Register(app)
 	resolve.Register(app)
+	rollback.Register(app)
 	run.Register(app)
 	shell.Register(app)
 	status.Register(app)
+	unlock.Register(app)
 	validate.Register(app)
 
 	return app
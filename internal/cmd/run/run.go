@@ -21,10 +21,13 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"nabat.dev/nabat"
 
+	"deployah.dev/deployah/internal/cli"
 	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/k8s"
 	"deployah.dev/deployah/internal/session"
 	"deployah.dev/deployah/internal/spec"
+	"deployah.dev/deployah/internal/triage"
 
 	batchv1 "k8s.io/api/batch/v1"
 )
@@ -37,19 +40,23 @@ type Options struct {
 	Count       int    `nabat:"count"`
 	Parallelism int    `nabat:"parallelism"`
 	Yes         bool   `nabat:"yes"`
+	ReportFile  string `nabat:"report-file"`
+	Output      string `nabat:"output"`
 }
 
 // Register adds the run command to app.
 func Register(app *nabat.App) {
 	app.MustCommand("run",
 		nabat.WithDescription("Run a spec task as a one-off Job"),
-		nabat.WithLongDescription("Create a Kubernetes Job for a task from the spec. Works for preDeploy, postDeploy, and manual tasks. Runs only the named task; tasks listed in its after field are not run. Waits for completion unless --detach is set."),
+		nabat.WithLongDescription("Create a Kubernetes Job for a task from the spec. Works for preDeploy, postDeploy, manual, and schedule tasks; a scheduled task is started from the CronJob the last deploy created. Runs only the named task; tasks listed in its after field are not run. Waits for completion unless --detach is set."),
 		nabat.WithArg("task", "", nabat.WithRequired(), nabat.WithUsage("Task name to run"), nabat.WithPrompt("Task", "", nabat.WithHint("e.g. migrate, backfill"))),
 		nabat.WithArg("environment", "", nabat.WithRequired(), nabat.WithUsage("Environment to run in"), nabat.WithPrompt("Environment", "", nabat.WithHint("e.g. prod, staging"))),
 		nabat.WithFlag("detach", false, nabat.WithUsage("Return after creating the Job without waiting for completion")),
 		nabat.WithFlag("count", 0, nabat.WithUsage("Override fanout count for this run")),
 		nabat.WithFlag("parallelism", 0, nabat.WithUsage("Override how many copies may run at once")),
 		nabat.WithFlag("yes", false, nabat.WithShort('y'), nabat.WithUsage("Run without an interactive confirmation prompt")),
+		nabat.WithFlag("report-file", "", nabat.WithUsage("When the Job fails, also write the failure diagnosis (pod problems, recent logs, warning events) to this file as JSON")),
+		nabat.WithSelectFlag("output", cli.OutputFormatText, cli.EventOutputFormats, nabat.WithUsage("Output format: text, or jsonl to stream progress events as JSON lines on stdout")),
 		nabat.WithExample(`
 # Run a manual backfill and wait for it to finish
 deployah run backfill production
@@ -57,9 +64,18 @@ deployah run backfill production
 # Run without waiting
 deployah run backfill production --detach
 
+# Keep the failure diagnosis as a CI artifact
+deployah run migrate production --yes --report-file migrate-report.json
+
+# Stream progress events for a CI wrapper
+deployah run migrate production --yes --output jsonl
+
 # Override fanout for this run
-deployah run backfill production --count 4 --parallelism 2`),
-		nabat.WithRun(runTask),
+deployah run backfill production --count 4 --parallelism 2
+
+# Run a scheduled task now instead of waiting for its schedule
+deployah run nightly-report production`),
+		nabat.WithRun(cmdopts.WithEventStream("run", runTask)),
 	)
 }
 
@@ -113,10 +129,14 @@ func runTask(c *nabat.Context) error {
 	}
 	if !confirmed {
 		c.Info("Run cancelled")
+		eventlog.FromContext(c).SetOutcome(eventlog.OutcomeCancelled)
 		return nil
 	}
+	if opts.Yes {
+		eventlog.FromContext(c).Emit(eventlog.TypeConfirmationSkipped, nil)
+	}
 
-	cluster, err := sess.Target(c, opts.Environment)
+	cluster, err := sess.Target(c, manifest.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -127,6 +147,25 @@ func runTask(c *nabat.Context) error {
 		return fmt.Errorf("kubernetes client: %w", err)
 	}
 
+	// preDeploy and postDeploy tasks also run inside deploys, where two
+	// copies of a migration must not overlap, so they share the release
+	// lock. The lock is held until the Job finishes unless --detach is set.
+	if rt.Task.On == spec.TaskOnPreDeploy || rt.Task.On == spec.TaskOnPostDeploy {
+		unlock, lockErr := cmdopts.LockRelease(c, cluster, manifest.Project, opts.Environment, "run "+opts.Task)
+		if lockErr != nil {
+			return lockErr
+		}
+		defer unlock()
+	}
+
+	if rt.Task.On == spec.TaskOnSchedule {
+		job, cronErr := scheduledTaskJob(c, cs, cluster.Namespace(), manifest.Project, opts)
+		if cronErr != nil {
+			return cronErr
+		}
+		return executeRun(c, cs, sess.Timeout(), job, opts)
+	}
+
 	job, err := k8s.BuildTaskJob(k8s.TaskJobOptions{
 		Project:     manifest.Project,
 		Environment: opts.Environment,
@@ -141,30 +180,66 @@ func runTask(c *nabat.Context) error {
 		return fmt.Errorf("build job for %s: %w", opts.Task, err)
 	}
 
-	return executeRun(c, cs, sess.Timeout(), job, opts.Detach)
+	return executeRun(c, cs, sess.Timeout(), job, opts)
 }
 
-func executeRun(c *nabat.Context, cs kubernetes.Interface, timeout time.Duration, job *batchv1.Job, detach bool) error {
+// executeRun creates job and, unless opts.Detach is set, waits for it. A
+// failed Job is diagnosed from its pods and the Warning events seen while
+// waiting; see [cmdopts.Diagnose].
+func executeRun(c *nabat.Context, cs kubernetes.Interface, timeout time.Duration, job *batchv1.Job, opts *Options) error {
 	created, err := k8s.CreateTaskJob(c, cs, job)
 	if err != nil {
 		return err
 	}
 	c.Success("Created Job", "name", created.Name, "namespace", created.Namespace)
+	events := eventlog.FromContext(c)
+	task := eventlog.Task{Task: opts.Task, Job: created.Name}
+	events.Emit(eventlog.TypeTaskStarted, task)
 
-	if detach {
+	if opts.Detach {
 		c.Info("Detached; the Job continues in the cluster")
 		return nil
 	}
 
+	// The Job's pods are named after it, so its name is the event prefix.
+	recorder, recErr := triage.Record(c, cs, created.Namespace, created.Name)
+	if recErr != nil {
+		c.Logger().Debug("event watch unavailable", "err", recErr)
+	}
 	waitCtx, cancel := context.WithTimeout(c, timeout)
 	defer cancel()
-	if waitErr := k8s.WaitForJob(waitCtx, cs, created.Namespace, created.Name); waitErr != nil {
+	waitErr := k8s.WaitForJob(waitCtx, cs, created.Namespace, created.Name)
+	var warnings []k8s.DeployEvent
+	if recorder != nil {
+		warnings = recorder.Stop()
+	}
+	if waitErr != nil {
+		task.Succeeded, task.Message = new(false), waitErr.Error()
+		events.Emit(eventlog.TypeTaskFinished, task)
+		cmdopts.Diagnose(c, cs, nil, created.Namespace, batchv1.JobNameLabel+"="+created.Name, "run "+opts.Task, warnings, waitErr, opts.ReportFile)
 		return waitErr
 	}
+	task.Succeeded = new(true)
+	events.Emit(eventlog.TypeTaskFinished, task)
 	c.Success("Job completed", "name", created.Name)
 	return nil
 }
 
+// scheduledTaskJob builds a one-off Job from the CronJob deploy rendered for
+// a scheduled task, so an ad hoc run uses exactly what the schedule would
+// run rather than the local spec.
+func scheduledTaskJob(ctx context.Context, cs kubernetes.Interface, namespace, project string, opts *Options) (*batchv1.Job, error) {
+	cronJob, err := k8s.FindTaskCronJob(ctx, cs, namespace, project, opts.Environment, opts.Task)
+	if err != nil {
+		return nil, err
+	}
+	job, err := k8s.JobFromCronJob(cronJob, opts.Count, opts.Parallelism)
+	if err != nil {
+		return nil, fmt.Errorf("build job for %s: %w", opts.Task, err)
+	}
+	return job, nil
+}
+
 func resolveRunTask(manifest *spec.Spec, platform *spec.PlatformConfig, environment, name string) (spec.ResolvedTask, error) {
 	envIdentity := spec.NormalizeEnv(environment)
 	if platform != nil {
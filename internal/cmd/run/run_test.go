@@ -14,7 +14,12 @@
 package run
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,8 +30,10 @@ import (
 	"nabat.dev/nabat"
 	"nabat.dev/nabat/nabattest"
 
+	"deployah.dev/deployah/internal/eventlog"
 	"deployah.dev/deployah/internal/k8s"
 	"deployah.dev/deployah/internal/spec"
+	"deployah.dev/deployah/internal/triage"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -183,7 +190,7 @@ func TestExecuteRun_DetachAndWait(t *testing.T) {
 		cs := fake.NewSimpleClientset()
 		job := mustBuildJob(t, opts, "shop-dev-backfill-detach")
 		c := nabatContext(t)
-		require.NoError(t, executeRun(c, cs, time.Minute, job, true))
+		require.NoError(t, executeRun(c, cs, time.Minute, job, &Options{Task: "backfill", Detach: true}))
 		got, err := cs.BatchV1().Jobs("default").Get(t.Context(), "shop-dev-backfill-detach", metav1.GetOptions{})
 		require.NoError(t, err)
 		assert.Equal(t, "shop-dev-backfill-detach", got.Name)
@@ -197,7 +204,7 @@ func TestExecuteRun_DetachAndWait(t *testing.T) {
 		})
 		job := mustBuildJob(t, opts, "shop-dev-backfill-wait")
 		c := nabatContext(t)
-		require.NoError(t, executeRun(c, cs, time.Minute, job, false))
+		require.NoError(t, executeRun(c, cs, time.Minute, job, &Options{Task: "backfill"}))
 	})
 
 	t.Run("wait fails when the job fails", func(t *testing.T) {
@@ -211,10 +218,46 @@ func TestExecuteRun_DetachAndWait(t *testing.T) {
 			}}
 		})
 		job := mustBuildJob(t, opts, "shop-dev-backfill-fail")
+		reportFile := filepath.Join(t.TempDir(), "report.json")
 		c := nabatContext(t)
-		err := executeRun(c, cs, time.Minute, job, false)
+		err := executeRun(c, cs, time.Minute, job, &Options{Task: "backfill", ReportFile: reportFile})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "backoff limit exceeded")
+
+		data, readErr := os.ReadFile(reportFile) // #nosec G304 -- test temp file
+		require.NoError(t, readErr)
+		var report triage.Report
+		require.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, "run backfill", report.Operation)
+		assert.Equal(t, "batch.kubernetes.io/job-name=shop-dev-backfill-fail", report.Selector)
+		assert.Contains(t, report.Error, "backoff limit exceeded")
+		assert.True(t, report.PodsInspected)
+	})
+
+	t.Run("streams task events", func(t *testing.T) {
+		t.Parallel()
+		cs := fake.NewSimpleClientset()
+		jobGetWithStatus(cs, func(job *batchv1.Job) {
+			job.Status.Succeeded = 1
+		})
+		job := mustBuildJob(t, opts, "shop-dev-backfill-events")
+		c := nabatContext(t)
+		var out bytes.Buffer
+		c.SetContext(eventlog.WithContext(c.Context(), eventlog.New(&out, "run")))
+		require.NoError(t, executeRun(c, cs, time.Minute, job, &Options{Task: "backfill"}))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2)
+		var started, finished struct {
+			Type eventlog.Type `json:"type"`
+			Data eventlog.Task `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &started))
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &finished))
+		assert.Equal(t, eventlog.TypeTaskStarted, started.Type)
+		assert.Equal(t, eventlog.Task{Task: "backfill", Job: "shop-dev-backfill-events"}, started.Data)
+		assert.Equal(t, eventlog.TypeTaskFinished, finished.Type)
+		assert.Equal(t, new(true), finished.Data.Succeeded)
 	})
 
 	t.Run("create error", func(t *testing.T) {
@@ -225,12 +268,49 @@ func TestExecuteRun_DetachAndWait(t *testing.T) {
 		})
 		job := mustBuildJob(t, opts, "shop-dev-backfill-create")
 		c := nabatContext(t)
-		err := executeRun(c, cs, time.Minute, job, true)
+		err := executeRun(c, cs, time.Minute, job, &Options{Task: "backfill", Detach: true})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "quota exceeded")
 	})
 }
 
+// TestScheduledTaskJob verifies a scheduled task runs from the CronJob in
+// the cluster, with --count and --parallelism applied on top.
+func TestScheduledTaskJob(t *testing.T) {
+	t.Parallel()
+
+	cronJob := &batchv1.CronJob{
+		Name:      "shop-prod-report",
+		Namespace: "default",
+		Labels: map[string]string{
+			spec.LabelProject:     "shop",
+			spec.LabelComponent:   "report",
+			spec.LabelEnvironment: "prod",
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 2 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Completions: new(int32(1)),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "report", Image: "ghcr.io/acme/shop:1.2.3"}}},
+					},
+				},
+			},
+		},
+	}
+	cs := fake.NewSimpleClientset(cronJob)
+
+	job, err := scheduledTaskJob(t.Context(), cs, "default", "shop", &Options{Task: "report", Environment: "prod", Count: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "shop-prod-report-manual-", job.GenerateName)
+	assert.Equal(t, int32(2), *job.Spec.Completions)
+	assert.Equal(t, "ghcr.io/acme/shop:1.2.3", job.Spec.Template.Spec.Containers[0].Image)
+
+	_, err = scheduledTaskJob(t.Context(), cs, "default", "shop", &Options{Task: "report", Environment: "staging"})
+	require.ErrorContains(t, err, "no CronJob found for task report")
+}
+
 func mustBuildJob(t *testing.T, opts k8s.TaskJobOptions, name string) *batchv1.Job {
 	t.Helper()
 	job, err := k8s.BuildTaskJob(opts)
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"slices"
+	"strings"
 	"sync"
 	"syscall"
 
@@ -150,7 +151,7 @@ func (e *ShellExecutor) Execute(opts ExecuteOptions) error {
 
 	pod := pods[0]
 
-	containerName, err := e.selectContainer(pod.Containers, componentName, opts.Container)
+	containerName, err := e.selectContainer(pod, componentName, opts.Container)
 	if err != nil {
 		return fmt.Errorf("failed to select container: %w", err)
 	}
@@ -229,15 +230,13 @@ func (e *ShellExecutor) selectEnvironment(projectName, componentName, environmen
 	return selectEnvironmentInteractively(e.ctx, environments, projectName, componentName)
 }
 
-// selectContainer selects a container from the list
-func (e *ShellExecutor) selectContainer(containers []string, componentName, containerName string) (string, error) {
+// selectContainer selects a container from the pod. Without a name it
+// prefers the component's main container, which is named after the
+// component; sidecars are reached by their spec name.
+func (e *ShellExecutor) selectContainer(pod k8s.PodInfo, componentName, containerName string) (string, error) {
+	containers := pod.Containers
 	if containerName != "" {
-		for _, container := range containers {
-			if container == containerName {
-				return container, nil
-			}
-		}
-		return "", fmt.Errorf("container '%s' not found in pod", containerName)
+		return findContainer(pod, containerName)
 	}
 
 	if len(containers) == 1 {
@@ -253,6 +252,19 @@ func (e *ShellExecutor) selectContainer(containers []string, componentName, cont
 	return selectContainerInteractively(e.ctx, containers, componentName)
 }
 
+// findContainer returns name when it is a running container of pod. Init
+// containers have exited by the time the pod runs, so they are reported
+// separately.
+func findContainer(pod k8s.PodInfo, name string) (string, error) {
+	if slices.Contains(pod.Containers, name) {
+		return name, nil
+	}
+	if slices.Contains(pod.InitContainers, name) {
+		return "", fmt.Errorf("container '%s' is an init container and has already exited; use deployah logs --container %s", name, name)
+	}
+	return "", fmt.Errorf("container '%s' not found in pod (available: %s)", name, strings.Join(pod.Containers, ", "))
+}
+
 // detectAvailableShells detects which shells are available in the container
 func (e *ShellExecutor) detectAvailableShells(podName, containerName string) ([]string, error) {
 	availableShells := []string{}
@@ -25,6 +25,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/tools/remotecommand"
+
+	"deployah.dev/deployah/internal/k8s"
 )
 
 // TestShellQuote verifies POSIX-safe quoting via shellQuote.
@@ -237,3 +239,26 @@ func TestStartTerminalResizeWatch_SIGWINCHUpdatesSize(t *testing.T) {
 	assert.Equal(t, uint16(120), resized.Width)
 	assert.Equal(t, uint16(40), resized.Height)
 }
+
+// TestFindContainer verifies --container lookups by spec name.
+func TestFindContainer(t *testing.T) {
+	t.Parallel()
+
+	pod := k8s.PodInfo{
+		Name:           "shop-dev-api-0",
+		Containers:     []string{"api", "log-shipper"},
+		InitContainers: []string{"migrate"},
+	}
+
+	got, err := findContainer(pod, "log-shipper")
+	require.NoError(t, err)
+	assert.Equal(t, "log-shipper", got)
+
+	_, err = findContainer(pod, "migrate")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "init container")
+
+	_, err = findContainer(pod, "shipper")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: api, log-shipper")
+}
@@ -27,7 +27,7 @@ func Register(app *nabat.App) {
 		nabat.WithLongDescription("Connect to an interactive shell in a container of a deployed project."),
 		nabat.WithArg("project", "", nabat.WithRequired(), nabat.WithUsage("Project name to connect to"), nabat.WithPrompt("Project name", "", nabat.WithHint("e.g. my-app"))),
 		nabat.WithFlag("component", "", nabat.WithUsage("Component name (e.g., api, web, worker)")),
-		nabat.WithFlag("container", "", nabat.WithUsage("Container name (if pod has multiple containers)")),
+		nabat.WithFlag("container", "", nabat.WithUsage("Container name: the component, or a sidecar by its spec name")),
 		nabat.WithFlag("shell", "", nabat.WithUsage("Preferred shell (bash, zsh, sh, ash, dash, fish)")),
 		nabat.WithFlag("command", "", nabat.WithUsage("Command to execute (default: shell)")),
 		nabat.WithFlag("workdir", "", nabat.WithUsage("Working directory in container")),
@@ -40,7 +40,7 @@ deployah shell myproject
 deployah shell myproject --component=api
 
 # Connect to specific container
-deployah shell myproject --container=app
+deployah shell myproject --component=api --container=log-shipper
 
 # Use specific shell
 deployah shell myproject --shell=zsh
@@ -65,7 +65,7 @@ func runShell(c *nabat.Context) error {
 
 	rt := session.FromContext(c)
 
-	cluster, err := rt.Target(c, opts.Environment)
+	cluster, err := rt.Target(c, opts.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
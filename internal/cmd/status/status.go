@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
+	"k8s.io/client-go/kubernetes"
 	"nabat.dev/nabat"
 
 	"deployah.dev/deployah/internal/cli"
@@ -25,7 +27,9 @@ type Options struct {
 func Register(app *nabat.App) {
 	app.MustCommand("status",
 		nabat.WithDescription("Display the status of a project"),
-		nabat.WithLongDescription("Display detailed status information about a deployed project, including its current state, revision, and resources."),
+		nabat.WithLongDescription("Display detailed status information about a deployed project, including its current state, revision, and resources.\n\n"+
+			"For components with selfSigned TLS it also shows when their certificates expire, and re-issues a certificate "+
+			"that expires within 30 days with your local CA (see 'deployah cluster trust')."),
 		nabat.WithArg("project", "", nabat.WithRequired(), nabat.WithUsage("Project name to show status for"), nabat.WithPrompt("Project name", "", nabat.WithHint("e.g. my-app"))),
 		nabat.WithSelectFlag("output", cli.OutputFormatTable, cli.OutputFormats, nabat.WithShort('o'), nabat.WithUsage("Output format")),
 		nabat.WithFlag("environment", "", nabat.WithShort('e'), nabat.WithUsage("Environment to display status for")),
@@ -50,7 +54,7 @@ func runStatus(c *nabat.Context) error {
 	}
 
 	rt := session.FromContext(c)
-	cluster, err := rt.Target(c, opts.Environment)
+	cluster, err := rt.Target(c, opts.Project, opts.Environment)
 	if err != nil {
 		return fmt.Errorf("target cluster: %w", err)
 	}
@@ -83,8 +87,8 @@ func runStatus(c *nabat.Context) error {
 	})
 
 	var k8sClient *k8s.Client
+	clientset, k8sErr := cluster.Kubernetes()
 	if opts.Detailed {
-		clientset, k8sErr := cluster.Kubernetes()
 		if k8sErr != nil {
 			return fmt.Errorf("k8s client: %w", k8sErr)
 		}
@@ -96,8 +100,14 @@ func runStatus(c *nabat.Context) error {
 		headers = append(headers, "PODS", "READY")
 	}
 
+	var ca *k8s.LocalCA
+	if k8sErr == nil {
+		ca = cmdopts.ExistingLocalCA(c)
+	}
+
 	rows := make([][]string, 0, len(releases))
 	viewModels := make([]cli.ReleaseViewModel, 0, len(releases))
+	anyLocked, anyCerts := false, false
 
 	for _, rel := range releases {
 		var vm cli.ReleaseViewModel
@@ -106,6 +116,12 @@ func runStatus(c *nabat.Context) error {
 		} else {
 			vm = cli.ReleaseToViewModel(rel)
 		}
+		if k8sErr == nil {
+			vm.Lock = releaseLock(c, clientset, rel.Namespace, rel.Name)
+			anyLocked = anyLocked || vm.Lock != nil
+			vm.Certificates = releaseCertificates(c, clientset, ca, rel.Namespace, vm)
+			anyCerts = anyCerts || len(vm.Certificates) > 0
+		}
 
 		row := []string{
 			vm.Project,
@@ -127,5 +143,44 @@ func runStatus(c *nabat.Context) error {
 		viewModels = append(viewModels, vm)
 	}
 
+	// The LOCK column only appears while some release is locked, so the
+	// usual table stays as narrow as before.
+	if anyLocked {
+		headers = append(headers, "LOCK")
+		for i := range rows {
+			rows[i] = append(rows[i], cli.LockSummary(viewModels[i].Lock))
+		}
+	}
+	// Likewise, the CERT column only appears for selfSigned TLS.
+	if anyCerts {
+		headers = append(headers, "CERT")
+		for i := range rows {
+			rows[i] = append(rows[i], cli.CertificateSummary(viewModels[i].Certificates))
+		}
+	}
+
 	return cli.Render(c, opts.OutputFormat, headers, rows, viewModels)
 }
+
+// releaseCertificates returns the selfSigned TLS certificates of the
+// release vm describes, renewing those close to expiring.
+func releaseCertificates(c *nabat.Context, clientset kubernetes.Interface, ca *k8s.LocalCA, namespace string, vm cli.ReleaseViewModel) []cli.CertificateViewModel {
+	selector, err := k8s.BuildLabelSelector(vm.Project, vm.Environment)
+	if err != nil {
+		c.Logger().Debug("could not select TLS certificates", "release", vm.Release, "err", err)
+		return nil
+	}
+	return cmdopts.RenewCertificates(c, clientset, ca, namespace, selector)
+}
+
+// releaseLock returns the lock on a release for display. Status is
+// read-only and best-effort here: a lock that cannot be read, e.g. for
+// lack of RBAC on Leases, is logged and shown as absent.
+func releaseLock(c *nabat.Context, clientset kubernetes.Interface, namespace, release string) *cli.LockViewModel {
+	lock, err := k8s.GetReleaseLock(c, clientset, namespace, release)
+	if err != nil {
+		c.Logger().Debug("could not read release lock", "release", release, "err", err)
+		return nil
+	}
+	return cli.LockToViewModel(lock, time.Now())
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unlock
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"nabat.dev/nabat"
+
+	"deployah.dev/deployah/internal/cmd/cmdopts"
+	"deployah.dev/deployah/internal/helm"
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/session"
+	"deployah.dev/deployah/internal/spec"
+)
+
+// Options holds command-line flags for unlock.
+type Options struct {
+	Environment string `nabat:"environment"`
+	Project     string `nabat:"project"`
+	Yes         bool   `nabat:"yes"`
+}
+
+// Register adds the unlock command to app.
+func Register(app *nabat.App) {
+	app.MustCommand("unlock",
+		nabat.WithDescription("Break the lock on a release"),
+		nabat.WithLongDescription("Break the release lock that deploy, delete, rollback, and run of preDeploy and postDeploy tasks hold while they change a release. Use it when the holder is gone and you do not want to wait for the lock to expire. Shows the holder and asks for confirmation, unless --yes is set. The holder's operation, if it is still running, is not stopped."),
+		nabat.WithArg("environment", "", nabat.WithRequired(), nabat.WithUsage("Environment whose release to unlock"), nabat.WithPrompt("Environment", "", nabat.WithHint("e.g. prod, staging"))),
+		nabat.WithFlag("project", "", nabat.WithUsage("Project name (default: the project in the spec)")),
+		nabat.WithFlag("yes", false, nabat.WithShort('y'), nabat.WithUsage("Break the lock without an interactive confirmation prompt")),
+		nabat.WithExample(`
+# Break the lock on production after a CI job was killed mid-deploy
+deployah unlock prod
+
+# Unlock without a spec file at hand
+deployah unlock prod --project my-app`),
+		nabat.WithRun(runUnlock),
+	)
+}
+
+func runUnlock(c *nabat.Context) error {
+	opts := &Options{}
+	if err := c.Bind(opts); err != nil {
+		return fmt.Errorf("binding options: %w", err)
+	}
+	sess := session.FromContext(c)
+
+	project := opts.Project
+	if project == "" {
+		rawSpec, _, err := spec.ParseManifest(sess.SpecPath())
+		if err != nil {
+			return fmt.Errorf("parse manifest (or pass --project): %w", err)
+		}
+		project = rawSpec.Project
+	}
+
+	cluster, err := sess.Target(c, project, opts.Environment)
+	if err != nil {
+		return fmt.Errorf("target cluster: %w", err)
+	}
+	cmdopts.WarnContextFallback(c, cluster, opts.Environment)
+	cs, err := cluster.Kubernetes()
+	if err != nil {
+		return fmt.Errorf("kubernetes client: %w%s", err, cmdopts.ClusterHint(err))
+	}
+
+	release := helm.GenerateReleaseName(project, opts.Environment)
+	lock, err := k8s.GetReleaseLock(c, cs, cluster.Namespace(), release)
+	if err != nil {
+		return fmt.Errorf("%w%s", err, cmdopts.ClusterHint(err))
+	}
+	if lock == nil {
+		c.Info("Release is not locked", "release", release, "namespace", cluster.Namespace())
+		return nil
+	}
+
+	c.Println(lockDescription(lock, time.Now()))
+	confirmed, err := c.Confirm(
+		fmt.Sprintf("Break the lock on %s held by %s?", release, lock.Holder),
+		nabat.WithAffirmative("Yes, break it"),
+		nabat.WithNegative("No, cancel"),
+		nabat.WithYes(opts.Yes),
+		nabat.WithBypassHint("--yes"),
+	)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		c.Info("Unlock cancelled")
+		return nil
+	}
+
+	if err := k8s.BreakReleaseLock(c, cs, cluster.Namespace(), lock); err != nil {
+		if errors.Is(err, k8s.ErrLockChanged) {
+			return fmt.Errorf("%w; run unlock again to see the current holder", err)
+		}
+		return fmt.Errorf("%w%s", err, cmdopts.ClusterHint(err))
+	}
+	c.Success("Unlocked", "release", release, "namespace", cluster.Namespace())
+	return nil
+}
+
+// lockDescription renders the lock for the confirmation screen, noting
+// when it has already expired.
+func lockDescription(lock *k8s.LockInfo, now time.Time) string {
+	desc := fmt.Sprintf("Release %s is locked by %s.", lock.Release, lock.String())
+	if lock.Expired(now) {
+		desc += " The lock has expired; the next deploy would take it over anyway."
+	}
+	return desc
+}
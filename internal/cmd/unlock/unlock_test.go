@@ -0,0 +1,44 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unlock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"deployah.dev/deployah/internal/k8s"
+)
+
+// TestLockDescription shows the holder and flags an expired lock.
+func TestLockDescription(t *testing.T) {
+	t.Parallel()
+
+	acquired := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	lock := &k8s.LockInfo{
+		Release:    "web-production",
+		LockOwner:  k8s.LockOwner{Holder: "ci@runner-7 (pid 42)", Operation: "deploy", JobURL: "https://ci.example.com/jobs/9"},
+		AcquiredAt: acquired,
+		ExpiresAt:  acquired.Add(k8s.DefaultLockTTL),
+	}
+
+	live := lockDescription(lock, acquired.Add(time.Minute))
+	assert.Contains(t, live, "web-production is locked by ci@runner-7 (pid 42) (deploy)")
+	assert.Contains(t, live, "https://ci.example.com/jobs/9")
+	assert.NotContains(t, live, "expired")
+
+	assert.Contains(t, lockDescription(lock, acquired.Add(time.Hour)), "has expired")
+}
@@ -0,0 +1,21 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventlog writes the machine-readable event stream that deploy,
+// run, delete, and cluster up print with --output jsonl: one JSON object
+// per line, each carrying a format version, a type, a timestamp, and a
+// type-specific data object. A [Stream] travels in the command's context,
+// so code deep in a command emits events without extra parameters, and a
+// nil Stream discards them.
+package eventlog
@@ -0,0 +1,207 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// formatVersion is the schema version stamped on every event. Bump the
+// minor version when a type or field is added, the major version when one
+// is removed or changes meaning, and document the change in
+// docs/automation.md.
+const formatVersion = "1.0"
+
+// Type names an event in the stream.
+type Type string
+
+const (
+	// TypePlanComputed carries the plan a deploy is about to apply, in the
+	// `deployah plan --output json` format.
+	TypePlanComputed Type = "plan_computed"
+	// TypeConfirmationSkipped is emitted when --yes skips the prompt.
+	TypeConfirmationSkipped Type = "confirmation_skipped"
+	// TypeCRDsApplied carries [CRDsApplied] after .deployah/crds/ applied.
+	TypeCRDsApplied Type = "crds_applied"
+	// TypeTaskStarted carries [Task] when a hook or run Job starts.
+	TypeTaskStarted Type = "task_started"
+	// TypeTaskFinished carries [Task] when a hook or run Job completes or
+	// fails.
+	TypeTaskFinished Type = "task_finished"
+	// TypePodReadiness carries [PodReadiness] when a component's ready or
+	// total pod count changes during a deploy.
+	TypePodReadiness Type = "pod_readiness"
+	// TypeClusterStep carries [ClusterStep] for each step of cluster up.
+	TypeClusterStep Type = "cluster_step"
+	// TypeResult carries [Result]. It is always the last event.
+	TypeResult Type = "result"
+)
+
+// Outcome is how a command ended, reported by [TypeResult].
+type Outcome string
+
+const (
+	// OutcomeSucceeded means the command did what it was asked.
+	OutcomeSucceeded Outcome = "succeeded"
+	// OutcomeUnchanged means there was nothing to apply.
+	OutcomeUnchanged Outcome = "unchanged"
+	// OutcomeCancelled means the confirmation prompt was declined.
+	OutcomeCancelled Outcome = "cancelled"
+	// OutcomeFailed means the command returned an error.
+	OutcomeFailed Outcome = "failed"
+)
+
+// Event is one line of the stream.
+type Event struct {
+	FormatVersion string    `json:"format_version"`
+	Type          Type      `json:"type"`
+	Time          time.Time `json:"time"`
+	Command       string    `json:"command"`
+	Data          any       `json:"data,omitempty"`
+}
+
+// CRDsApplied is the data of [TypeCRDsApplied].
+type CRDsApplied struct {
+	Created  int `json:"created"`
+	Replaced int `json:"replaced"`
+	Ready    int `json:"ready"`
+}
+
+// Task is the data of [TypeTaskStarted] and [TypeTaskFinished]. Hook is
+// true for a preDeploy or postDeploy task Helm runs during a deploy.
+// Succeeded and Message are set on [TypeTaskFinished] only.
+type Task struct {
+	Task      string `json:"task"`
+	Job       string `json:"job"`
+	Hook      bool   `json:"hook"`
+	Succeeded *bool  `json:"succeeded,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// PodReadiness is the data of [TypePodReadiness].
+type PodReadiness struct {
+	Component string `json:"component"`
+	Ready     int    `json:"ready"`
+	Total     int    `json:"total"`
+}
+
+// ClusterStep is the data of [TypeClusterStep]. Status is "started",
+// "completed", or "failed".
+type ClusterStep struct {
+	Step   string `json:"step"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Result is the data of [TypeResult]. Error is the command's error message
+// when Outcome is [OutcomeFailed].
+type Result struct {
+	Outcome Outcome `json:"outcome"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Stream writes events to w, one JSON object per line. It is safe for
+// concurrent use, and every method is a no-op on a nil Stream.
+type Stream struct {
+	command string
+	now     func() time.Time
+
+	mu      sync.Mutex
+	enc     *json.Encoder
+	outcome Outcome
+	err     error
+}
+
+// New returns a Stream writing events for command (e.g. "deploy") to w.
+func New(w io.Writer, command string) *Stream {
+	return &Stream{command: command, now: time.Now, enc: json.NewEncoder(w)}
+}
+
+// Emit writes one event. data is marshaled as the event's data object and
+// may be nil. Write errors are kept for [Stream.Err] rather than returned,
+// so a broken pipe never fails the operation being reported on.
+func (s *Stream) Emit(typ Type, data any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.err = s.enc.Encode(Event{
+		FormatVersion: formatVersion,
+		Type:          typ,
+		Time:          s.now().UTC(),
+		Command:       s.command,
+		Data:          data,
+	})
+}
+
+// SetOutcome records how a command that returns no error ended, for
+// [Stream.Finish]. Without it a nil error reports [OutcomeSucceeded].
+func (s *Stream) SetOutcome(o Outcome) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outcome = o
+}
+
+// Finish emits the [TypeResult] event for a command that returned err.
+func (s *Stream) Finish(err error) {
+	if s == nil {
+		return
+	}
+	result := Result{Outcome: OutcomeSucceeded}
+	s.mu.Lock()
+	if s.outcome != "" {
+		result.Outcome = s.outcome
+	}
+	s.mu.Unlock()
+	if err != nil {
+		result = Result{Outcome: OutcomeFailed, Error: err.Error()}
+	}
+	s.Emit(TypeResult, result)
+}
+
+// Err returns the first error writing the stream, if any.
+func (s *Stream) Err() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+type streamKey struct{}
+
+// WithContext returns a new context carrying s.
+func WithContext(ctx context.Context, s *Stream) context.Context {
+	return context.WithValue(ctx, streamKey{}, s)
+}
+
+// FromContext returns the Stream in ctx, or nil when the command was not
+// run with --output jsonl.
+func FromContext(ctx context.Context) *Stream {
+	s, _ := ctx.Value(streamKey{}).(*Stream)
+	return s
+}
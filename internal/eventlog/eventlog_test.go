@@ -0,0 +1,125 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeLines parses each line of out as one event.
+func decodeLines(t *testing.T, out *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var events []map[string]any
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var ev map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev), "line: %s", scanner.Text())
+		events = append(events, ev)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestStream_Emit(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	s := New(&out, "deploy")
+	s.now = func() time.Time { return time.Date(2026, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600)) }
+
+	s.Emit(TypeConfirmationSkipped, nil)
+	s.Emit(TypeCRDsApplied, CRDsApplied{Created: 1, Ready: 2})
+
+	events := decodeLines(t, &out)
+	require.Len(t, events, 2)
+	assert.Equal(t, map[string]any{
+		"format_version": formatVersion,
+		"type":           "confirmation_skipped",
+		"time":           "2026-05-01T10:00:00Z",
+		"command":        "deploy",
+	}, events[0])
+	assert.Equal(t, map[string]any{"created": 1.0, "replaced": 0.0, "ready": 2.0}, events[1]["data"])
+	assert.NoError(t, s.Err())
+}
+
+func TestStream_Finish(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		outcome Outcome
+		err     error
+		want    map[string]any
+	}{
+		{name: "success", want: map[string]any{"outcome": "succeeded"}},
+		{name: "recorded outcome", outcome: OutcomeCancelled, want: map[string]any{"outcome": "cancelled"}},
+		{name: "error wins", outcome: OutcomeUnchanged, err: errors.New("boom"), want: map[string]any{"outcome": "failed", "error": "boom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			s := New(&out, "run")
+			if tt.outcome != "" {
+				s.SetOutcome(tt.outcome)
+			}
+			s.Finish(tt.err)
+
+			events := decodeLines(t, &out)
+			require.Len(t, events, 1)
+			assert.Equal(t, "result", events[0]["type"])
+			assert.Equal(t, tt.want, events[0]["data"])
+		})
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestStream_KeepsFirstWriteError(t *testing.T) {
+	t.Parallel()
+	s := New(failingWriter{}, "delete")
+	s.Emit(TypeConfirmationSkipped, nil)
+	s.Finish(nil)
+	assert.EqualError(t, s.Err(), "broken pipe")
+}
+
+func TestStream_NilIsNoOp(t *testing.T) {
+	t.Parallel()
+	var s *Stream
+	assert.NotPanics(t, func() {
+		s.Emit(TypeResult, nil)
+		s.SetOutcome(OutcomeFailed)
+		s.Finish(errors.New("boom"))
+	})
+	assert.NoError(t, s.Err())
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, FromContext(context.Background()))
+
+	s := New(&bytes.Buffer{}, "deploy")
+	assert.Same(t, s, FromContext(WithContext(context.Background(), s)))
+}
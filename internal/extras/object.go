@@ -53,6 +53,17 @@ type Object struct {
 	Obj  *unstructured.Unstructured
 }
 
+// ParseObject rebuilds an Object from the Raw bytes of one loaded earlier,
+// such as a bundle stored in a saved plan. path is the file the object was
+// originally loaded from.
+func ParseObject(path string, raw []byte) (Object, error) {
+	obj := &unstructured.Unstructured{}
+	if err := sigsyaml.Unmarshal(raw, &obj.Object); err != nil {
+		return Object{}, fmt.Errorf("%s: parse YAML: %w", path, err)
+	}
+	return Object{Path: path, Raw: raw, Obj: obj}, nil
+}
+
 // Identity returns the object's identity, using an empty namespace for
 // cluster-scoped resources that have none set.
 func (o *Object) Identity() Identity {
@@ -62,6 +62,12 @@ func NewChartCache(ttl time.Duration) *ChartCache {
 // covers only the target-environment subset and ensures platform file changes
 // invalidate the cache. encoding/json sorts map keys deterministically since
 // Go 1.12, so the serialization is stable.
+//
+// The content of the configFile mounted into each active component is
+// hashed too: it lives outside the spec, so editing it must not reuse a
+// chart rendered from the old content. Of an envFile only the entry names
+// are hashed. Its values, like decrypted secrets, never reach the prepared
+// chart (see [SecretValues]), so nothing derived from them is cached.
 func (c *ChartCache) GenerateKey(manifest *spec.Spec, environment string, resolved *spec.ResolvedSpec) (string, error) {
 	var inputBytes []byte
 	var err error
@@ -74,6 +80,16 @@ func (c *ChartCache) GenerateKey(manifest *spec.Spec, environment string, resolv
 		return "", fmt.Errorf("failed to marshal spec for hashing: %w", err)
 	}
 
+	filesSpec := manifest
+	if resolved != nil && resolved.Spec != nil {
+		filesSpec = resolved.Spec
+	}
+	runtimeBytes, err := runtimeFilesHashInput(filesSpec, environment)
+	if err != nil {
+		return "", err
+	}
+	inputBytes = append(inputBytes, runtimeBytes...)
+
 	chartHash, err := c.embeddedChartHash()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate embedded chart hash: %w", err)
@@ -86,6 +102,40 @@ func (c *ChartCache) GenerateKey(manifest *spec.Spec, environment string, resolv
 	return hex.EncodeToString(finalHash[:]), nil
 }
 
+// runtimeFilesHashInput returns the serialized [spec.RuntimeFiles] of every
+// component active in environment, with envFile values blanked, for
+// [ChartCache.GenerateKey].
+func runtimeFilesHashInput(manifest *spec.Spec, environment string) ([]byte, error) {
+	if manifest == nil {
+		return nil, nil
+	}
+	files := make(map[string]*spec.RuntimeFiles)
+	for name, component := range manifest.Components {
+		if !componentActiveInEnvironment(component, environment) {
+			continue
+		}
+		rf, err := spec.LoadRuntimeFiles(manifest, environment, component)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", name, err)
+		}
+		if rf.Empty() {
+			continue
+		}
+		for k := range rf.Env {
+			rf.Env[k] = ""
+		}
+		files[name] = rf
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	out, err := json.Marshal(files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal runtime files for hashing: %w", err)
+	}
+	return out, nil
+}
+
 // embeddedChartHash returns a hash of the embedded chart templates, computed
 // once per [ChartCache] instance so cache keys invalidate when the base
 // Deployah chart changes.
@@ -256,3 +256,30 @@ func TestGenerateKey_ResolvedSpecContentInvalidates(t *testing.T) {
 	assert.Equal(t, keyA, keySameResolved,
 		"GenerateKey hashes resolved when non-nil, not the separate manifest parameter")
 }
+
+// TestGenerateKey_RuntimeFileContentInvalidates verifies that editing a
+// mounted configFile changes the cache key even though the spec is unchanged.
+func TestGenerateKey_RuntimeFileContentInvalidates(t *testing.T) {
+	t.Parallel()
+	cache := NewChartCache(time.Hour)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("level: info\n"), 0o600))
+
+	web := serviceComponent()
+	web.ConfigFile = configPath
+	m := &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "cache-key",
+		Components: map[string]spec.Component{"web": web},
+	}
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+
+	keyA, err := cache.GenerateKey(m, "production", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("level: debug\n"), 0o600))
+	keyB, err := cache.GenerateKey(m, "production", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, keyA, keyB, "configFile content must be part of the cache key")
+}
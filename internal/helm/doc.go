@@ -18,4 +18,7 @@
 // [PrepareChart] renders templates and values for an environment into a
 // caller-supplied [ChartCache]. [Client] wraps Helm v4 actions with
 // Deployah-specific release naming, labels, and a per-client [ChartCache].
+// envFile entries and provider-backed secrets are read and decrypted by
+// [SecretValues] at install or render time and passed to Helm as values,
+// outside the cached chart.
 package helm
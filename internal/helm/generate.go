@@ -18,12 +18,15 @@ import (
 
 	"github.com/distribution/reference"
 	"go.yaml.in/yaml/v3"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"deployah.dev/deployah/internal/spec"
 
 	sprig "github.com/Masterminds/sprig/v3"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ChartTemplateFS embeds the chart directory. Underscore-prefixed templates
@@ -135,7 +138,7 @@ func PrepareChart(ctx context.Context, manifest *spec.Spec, desiredEnvironment s
 	// Resolve the sub-chart names once, before creating anything on disk, so
 	// Chart.yaml and the sub-chart directories below cannot disagree.
 	componentNames := activeComponentNames(manifest, desiredEnvironment)
-	taskNames, err := hookTaskNames(manifest, desiredEnvironment, resolved)
+	taskNames, err := chartTaskNames(manifest, desiredEnvironment, resolved)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve task sub-chart names: %w", err)
 	}
@@ -355,13 +358,19 @@ func MapSpecToChartValues(m *spec.Spec, desiredEnvironment string, resolved *spe
 			},
 		}
 
-		// TODO: Implement handling for component envFile
-		//   Exclude Deployah-specific environment variables (those prefixed with DPY_VAR_) and provide the remaining variables to the component
-
-		// TODO: Implement component configFile -- deep-merge config.yaml <
-		// config.<env>.yaml < config.<component>.yaml < config.<component>.<env>.yaml.
+		runtimeFiles, err := spec.LoadRuntimeFiles(m, desiredEnvironment, component)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", componentName, err)
+		}
+		applyRuntimeFiles(componentValues, runtimeFiles)
 
-		// TODO: Add support for component env, The user can specify the environment variables for the component e.g. NODE_ENV=roduction
+		// Component env is inlined onto the container the same way task env
+		// is onto a Job, so a changed value shows up in the plan diff and
+		// rolls the pods on deploy.
+		if len(component.Env) > 0 {
+			componentValues["envVars"] = maps.Clone(component.Env)
+		}
+		applySecrets(componentValues, component)
 
 		image := ""
 		tag := ""
@@ -370,26 +379,16 @@ func MapSpecToChartValues(m *spec.Spec, desiredEnvironment string, resolved *spe
 			image, tag = parseContainerImage(component.Image)
 		}
 
-		resources := map[string]any{}
-
-		// Map spec-level resolved resources (defaults/presets applied already by spec package).
-		// Only set the requests keys the spec actually provides: a field left
-		// unset is genuinely absent, not an empty-string request.
-		requests := map[string]any{}
-		if component.Resources.CPU != nil && !component.Resources.CPU.IsZero() {
-			requests["cpu"] = component.Resources.CPU.String()
-		}
-		if component.Resources.Memory != nil && !component.Resources.Memory.IsZero() {
-			requests["memory"] = component.Resources.Memory.String()
-		}
-		if component.Resources.EphemeralStorage != nil && !component.Resources.EphemeralStorage.IsZero() {
-			requests["ephemeral-storage"] = component.Resources.EphemeralStorage.String()
-		}
-		if len(requests) > 0 {
-			resources["requests"] = requests
+		var mergedProfile *spec.PlatformProfile
+		if resolved != nil {
+			if rc, ok := resolved.Components[componentName]; ok {
+				mergedProfile = rc.MergedProfile
+			}
 		}
 
-		componentValues["resources"] = resources
+		// Map spec-level resolved resources (defaults/presets applied already
+		// by spec package); the profile's limit policy shapes the limits.
+		componentValues["resources"] = resourceValues(component.Resources, mergedProfile)
 
 		workloadKind := "Deployment"
 		if component.Kind == spec.ComponentKindStateful {
@@ -454,6 +453,7 @@ func MapSpecToChartValues(m *spec.Spec, desiredEnvironment string, resolved *spe
 
 		if component.Expose != nil {
 			ingressVals := map[string]any{"enabled": true}
+			var routeVals map[string]any
 			if resolved != nil {
 				if rc, ok := resolved.Components[componentName]; ok && rc.FQDN != "" {
 					ingressVals["hostname"] = rc.FQDN
@@ -481,13 +481,20 @@ func MapSpecToChartValues(m *spec.Spec, desiredEnvironment string, resolved *spe
 					default:
 						ingressVals["tls"] = false
 					}
+					if rc.GatewayRef != nil {
+						routeVals = httpRouteValues(rc, ingressVals["secrets"])
+					}
 					resolvedComponents[componentName] = map[string]any{
 						"fqdn":    rc.FQDN,
 						"tlsMode": string(rc.TLSMode),
 					}
 				}
 			}
-			componentValues["ingress"] = ingressVals
+			if routeVals != nil {
+				componentValues["httpRoute"] = routeVals
+			} else {
+				componentValues["ingress"] = ingressVals
+			}
 		}
 
 		if component.Autoscaling != nil && component.Autoscaling.Enabled {
@@ -529,12 +536,6 @@ func MapSpecToChartValues(m *spec.Spec, desiredEnvironment string, resolved *spe
 			return nil, fmt.Errorf("component %s: shutdownTimeout: %w", componentName, err)
 		}
 
-		var mergedProfile *spec.PlatformProfile
-		if resolved != nil {
-			if rc, ok := resolved.Components[componentName]; ok {
-				mergedProfile = rc.MergedProfile
-			}
-		}
 		if err := applyMetricsValues(componentValues, component, mergedProfile); err != nil {
 			return nil, fmt.Errorf("component %s: metrics: %w", componentName, err)
 		}
@@ -575,6 +576,16 @@ func MapSpecToChartValues(m *spec.Spec, desiredEnvironment string, resolved *spe
 			}
 		}
 
+		if err := applyContainers(componentValues, component, mergedProfile); err != nil {
+			return nil, fmt.Errorf("component %s: %w", componentName, err)
+		}
+		applyDisruption(componentValues, component, mergedProfile)
+		if resolved != nil {
+			if err := applyNetworkPolicy(componentValues, resolved.Components[componentName].Network); err != nil {
+				return nil, fmt.Errorf("component %s: %w", componentName, err)
+			}
+		}
+
 		values[componentName] = componentValues
 	}
 
@@ -977,6 +988,346 @@ func buildAutoscalingValues(a *spec.Autoscaling) map[string]any {
 	return v
 }
 
+// runtimeFilesObjectName is the chart template for the per-component
+// ConfigMap and Secret name, rendered by common.tplvalues.render.
+const runtimeFilesObjectName = `{{ include "common.names.fullname" . }}`
+
+// applyRuntimeFiles wires a component's envFile entries into the chart Secret
+// (loaded with envFrom) and its config files into the chart ConfigMap
+// (mounted at configMap.mountPath). The workload templates hash both into
+// checksum pod annotations, so a content change rolls the pods. Like a
+// provider-backed secret, an envFile only switches on envFrom here: its
+// values are read by [SecretValues] and passed to Helm directly, so they
+// never land in values.yaml or the [ChartCache].
+func applyRuntimeFiles(componentValues map[string]any, files *spec.RuntimeFiles) {
+	if files.Empty() {
+		return
+	}
+	if len(files.Env) > 0 {
+		componentValues["envVarsSecret"] = runtimeFilesObjectName
+	}
+	if len(files.Config) > 0 {
+		data := make(map[string]any, len(files.Config))
+		for k, v := range files.Config {
+			data[k] = escapeTemplateDelims(v)
+		}
+		componentValues["configMap"] = map[string]any{
+			"mounted": true,
+			"data":    data,
+		}
+	}
+}
+
+// applySecrets wires a component's secrets block into the chart. A cluster
+// reference becomes a valueFrom.secretKeyRef env entry. A provider-backed
+// secret only switches on envFrom for the chart Secret here: its value is
+// decrypted at install time by [SecretValues] and passed to Helm directly,
+// so plaintext never lands in values.yaml or the [ChartCache].
+func applySecrets(componentValues map[string]any, component spec.Component) {
+	envVars := make(map[string]any, len(component.Env)+len(component.Secrets))
+	for k, v := range component.Env {
+		envVars[k] = v
+	}
+	hasRefs := false
+	for name, src := range component.Secrets {
+		if !src.IsClusterRef() {
+			continue
+		}
+		hasRefs = true
+		envVars[name] = map[string]any{
+			"valueFrom": map[string]any{
+				"secretKeyRef": map[string]any{
+					"name": src.SecretRef.Name,
+					"key":  src.SecretRef.Key,
+				},
+			},
+		}
+	}
+	if hasRefs {
+		componentValues["envVars"] = envVars
+	}
+	if len(component.ProviderSecrets()) > 0 {
+		componentValues["envVarsSecret"] = runtimeFilesObjectName
+	}
+}
+
+// applyDisruption enables the chart PodDisruptionBudget when the component
+// or its profile sets a disruption budget and the component runs more than
+// one pod. Only the field that is set is written, so a zero count reaches
+// the template instead of reading as unset.
+func applyDisruption(componentValues map[string]any, component spec.Component, profile *spec.PlatformProfile) {
+	d := spec.EffectiveDisruption(component, profile)
+	if d == nil {
+		return
+	}
+	pdb := map[string]any{"create": true}
+	if d.MinAvailable != nil {
+		pdb["minAvailable"] = intOrStringValue(*d.MinAvailable)
+	}
+	if d.MaxUnavailable != nil {
+		pdb["maxUnavailable"] = intOrStringValue(*d.MaxUnavailable)
+	}
+	componentValues["pdb"] = pdb
+}
+
+func intOrStringValue(v intstr.IntOrString) any {
+	if v.Type == intstr.Int {
+		return int(v.IntVal)
+	}
+	return v.StrVal
+}
+
+// httpRouteValues builds the chart httpRoute block for a component exposed
+// on a gateway-mode domain. The route itself carries no TLS: the Gateway
+// listener terminates it. So that every TLS mode leaves a certificate for
+// the listener to reference, the chart still creates the self-signed
+// secrets, and certManager mode requests a Certificate for the FQDN in
+// place of the Ingress annotation cert-manager would otherwise act on.
+func httpRouteValues(rc spec.ResolvedComponent, secrets any) map[string]any {
+	parentRef := map[string]any{"name": rc.GatewayRef.Name}
+	if rc.GatewayRef.Namespace != "" {
+		parentRef["namespace"] = rc.GatewayRef.Namespace
+	}
+	if rc.GatewayRef.SectionName != "" {
+		parentRef["sectionName"] = rc.GatewayRef.SectionName
+	}
+	route := map[string]any{
+		"enabled":    true,
+		"hostname":   rc.FQDN,
+		"parentRefs": []map[string]any{parentRef},
+	}
+	if secrets != nil {
+		route["secrets"] = secrets
+	}
+	if rc.TLSMode == spec.TLSModeCertManager {
+		route["certificate"] = map[string]any{
+			"issuer":     rc.TLSIssuer,
+			"secretName": rc.FQDN + "-tls",
+		}
+	}
+	return route
+}
+
+// applyNetworkPolicy enables the chart NetworkPolicy for a component whose
+// profile sets networkPolicy.defaultDeny, with one rule per connection
+// [spec.Resolve] allowed. The policy isolates both directions, so an empty
+// rule list denies everything but what the chart adds.
+func applyNetworkPolicy(componentValues map[string]any, network *spec.ComponentNetwork) error {
+	if network == nil || !network.DefaultDeny {
+		return nil
+	}
+	ingress := make([]networkingv1.NetworkPolicyIngressRule, 0, len(network.Ingress))
+	for _, r := range network.Ingress {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  networkPolicyPeers(r.Peer),
+			Ports: networkPolicyPorts(r),
+		})
+	}
+	egress := make([]networkingv1.NetworkPolicyEgressRule, 0, len(network.Egress))
+	for _, r := range network.Egress {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To:    networkPolicyPeers(r.Peer),
+			Ports: networkPolicyPorts(r),
+		})
+	}
+	ingressVals, err := toValuesSlice(ingress)
+	if err != nil {
+		return fmt.Errorf("networkPolicy ingress: %w", err)
+	}
+	egressVals, err := toValuesSlice(egress)
+	if err != nil {
+		return fmt.Errorf("networkPolicy egress: %w", err)
+	}
+	componentValues["networkPolicy"] = map[string]any{
+		"enabled": true,
+		"ingress": ingressVals,
+		"egress":  egressVals,
+	}
+	return nil
+}
+
+// networkPolicyPeers maps a spec peer to NetworkPolicy peers: one ipBlock
+// per CIDR, or a single namespace and pod selector. A peer without
+// namespace fields but with pod labels stays in the release namespace. An
+// empty peer returns nil, which NetworkPolicy reads as any source or
+// destination, including traffic from outside the cluster.
+func networkPolicyPeers(p spec.NetworkPeer) []networkingv1.NetworkPolicyPeer {
+	if p.Namespace == "" && len(p.NamespaceLabels) == 0 && len(p.PodLabels) == 0 && len(p.CIDRs) == 0 {
+		return nil
+	}
+	if len(p.CIDRs) > 0 {
+		peers := make([]networkingv1.NetworkPolicyPeer, 0, len(p.CIDRs))
+		for _, cidr := range p.CIDRs {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+		}
+		return peers
+	}
+	peer := networkingv1.NetworkPolicyPeer{}
+	nsLabels := maps.Clone(p.NamespaceLabels)
+	if p.Namespace != "" {
+		if nsLabels == nil {
+			nsLabels = map[string]string{}
+		}
+		nsLabels[corev1.LabelMetadataName] = p.Namespace
+	}
+	if nsLabels != nil {
+		peer.NamespaceSelector = &metav1.LabelSelector{MatchLabels: nsLabels}
+	}
+	if len(p.PodLabels) > 0 {
+		peer.PodSelector = &metav1.LabelSelector{MatchLabels: maps.Clone(p.PodLabels)}
+	}
+	return []networkingv1.NetworkPolicyPeer{peer}
+}
+
+// networkPolicyPorts limits a rule to its named container port, or to port
+// 53 over UDP and TCP for DNS. Nil allows every port.
+func networkPolicyPorts(r spec.NetworkRule) []networkingv1.NetworkPolicyPort {
+	if r.Kind == spec.NetworkEndpointDNS {
+		dns := intstr.FromInt32(53)
+		return []networkingv1.NetworkPolicyPort{
+			{Protocol: new(corev1.ProtocolUDP), Port: &dns},
+			{Protocol: new(corev1.ProtocolTCP), Port: &dns},
+		}
+	}
+	if r.Port == "" {
+		return nil
+	}
+	port := intstr.FromString(r.Port)
+	return []networkingv1.NetworkPolicyPort{{Protocol: new(corev1.ProtocolTCP), Port: &port}}
+}
+
+// resourceValues maps spec resources to a chart resources block. Only the
+// requests and limits the spec actually provides are set: a field left
+// unset is genuinely absent, not an empty-string request. Limits go
+// through the profile's resourceLimits policy first.
+func resourceValues(r spec.Resources, profile *spec.PlatformProfile) map[string]any {
+	resources := map[string]any{}
+	if requests := quantityValues(r.CPU, r.Memory, r.EphemeralStorage); len(requests) > 0 {
+		resources["requests"] = requests
+	}
+	if l := r.EffectiveLimits(profile); l != nil {
+		if limits := quantityValues(l.CPU, l.Memory, l.EphemeralStorage); len(limits) > 0 {
+			resources["limits"] = limits
+		}
+	}
+	return resources
+}
+
+func quantityValues(cpu, memory, ephemeralStorage *resource.Quantity) map[string]any {
+	out := map[string]any{}
+	if cpu != nil && !cpu.IsZero() {
+		out["cpu"] = cpu.String()
+	}
+	if memory != nil && !memory.IsZero() {
+		out["memory"] = memory.String()
+	}
+	if ephemeralStorage != nil && !ephemeralStorage.IsZero() {
+		out["ephemeral-storage"] = ephemeralStorage.String()
+	}
+	return out
+}
+
+// applyContainers renders a component's sidecars and init containers as
+// full container objects, adds an emptyDir for each shared volume, and
+// mounts shared volumes into the main container. The profile
+// containerSecurityContext applies to every extra container, like the main
+// one. The chart passes these values through tpl, so user strings are
+// escaped.
+func applyContainers(componentValues map[string]any, component spec.Component, profile *spec.PlatformProfile) error {
+	var securityContext map[string]any
+	if profile != nil && profile.ContainerSecurityContext != nil {
+		csc, err := toValuesMap(profile.ContainerSecurityContext)
+		if err != nil {
+			return fmt.Errorf("containerSecurityContext: %w", err)
+		}
+		securityContext = csc
+	}
+	if names := component.SidecarNames(); len(names) > 0 {
+		sidecars := make([]any, 0, len(names))
+		for _, name := range names {
+			sidecars = append(sidecars, containerValues(name, component.Sidecars[name], securityContext, profile))
+		}
+		componentValues["sidecars"] = sidecars
+	}
+	if names := component.InitContainerNames(); len(names) > 0 {
+		initContainers := make([]any, 0, len(names))
+		for _, name := range names {
+			initContainers = append(initContainers, containerValues(name, component.InitContainers[name], securityContext, profile))
+		}
+		componentValues["initContainers"] = initContainers
+	}
+	if shared := component.SharedVolumes(); len(shared) > 0 {
+		volumes := make([]any, 0, len(shared))
+		for _, name := range shared {
+			volumes = append(volumes, map[string]any{"name": name, "emptyDir": map[string]any{}})
+		}
+		componentValues["extraVolumes"] = volumes
+	}
+	if len(component.VolumeMounts) > 0 {
+		componentValues["extraVolumeMounts"] = volumeMountValues(component.VolumeMounts)
+	}
+	return nil
+}
+
+// containerValues builds one Kubernetes container object for the chart's
+// sidecars or initContainers list.
+func containerValues(name string, ctr spec.Container, securityContext map[string]any, profile *spec.PlatformProfile) map[string]any {
+	out := map[string]any{
+		"name":  name,
+		"image": ctr.Image,
+	}
+	if len(ctr.Command) > 0 {
+		out["command"] = escapeTemplateDelimsAll(ctr.Command)
+	}
+	if len(ctr.Args) > 0 {
+		out["args"] = escapeTemplateDelimsAll(ctr.Args)
+	}
+	if len(ctr.Env) > 0 {
+		env := make([]any, 0, len(ctr.Env))
+		for _, k := range slices.Sorted(maps.Keys(ctr.Env)) {
+			env = append(env, map[string]any{"name": k, "value": escapeTemplateDelims(ctr.Env[k])})
+		}
+		out["env"] = env
+	}
+	if resources := resourceValues(ctr.Resources, profile); len(resources) > 0 {
+		out["resources"] = resources
+	}
+	if len(ctr.VolumeMounts) > 0 {
+		out["volumeMounts"] = volumeMountValues(ctr.VolumeMounts)
+	}
+	if securityContext != nil {
+		out["securityContext"] = maps.Clone(securityContext)
+	}
+	return out
+}
+
+func volumeMountValues(mounts []spec.VolumeMount) []any {
+	out := make([]any, 0, len(mounts))
+	for _, m := range mounts {
+		mount := map[string]any{"name": m.Name, "mountPath": m.MountPath}
+		if m.ReadOnly {
+			mount["readOnly"] = true
+		}
+		out = append(out, mount)
+	}
+	return out
+}
+
+func escapeTemplateDelimsAll(in []string) []string {
+	out := make([]string, len(in))
+	for i, s := range in {
+		out[i] = escapeTemplateDelims(s)
+	}
+	return out
+}
+
+// escapeTemplateDelims makes s survive common.tplvalues.render unchanged:
+// every "{{" becomes an action that prints "{{", so user config containing
+// template syntax is mounted verbatim instead of being evaluated.
+func escapeTemplateDelims(s string) string {
+	return strings.ReplaceAll(s, "{{", `{{"{{"}}`)
+}
+
 // applyMergedProfile writes resolved profile fields into component Helm values.
 func applyMergedProfile(componentValues map[string]any, profile *spec.PlatformProfile) error {
 	if profile == nil {
@@ -0,0 +1,128 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func containersManifest() *spec.Spec {
+	return &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "shop",
+		Environments: map[string]spec.Environment{
+			"production": {},
+		},
+		Components: map[string]spec.Component{
+			"api": {
+				Role:         spec.ComponentRoleService,
+				Image:        "ghcr.io/acme/api:1.0.0",
+				Port:         8080,
+				VolumeMounts: []spec.VolumeMount{{Name: "logs", MountPath: "/var/log/app"}},
+				Sidecars: map[string]spec.Container{
+					"log-shipper": {
+						Image:        "fluent/fluent-bit:3.1",
+						Args:         []string{"--tag={{.Name}}"},
+						Env:          map[string]string{"LOG_LEVEL": "info"},
+						VolumeMounts: []spec.VolumeMount{{Name: "logs", MountPath: "/logs", ReadOnly: true}},
+					},
+				},
+				InitContainers: map[string]spec.Container{
+					"migrate":      {Image: "ghcr.io/acme/api:1.0.0", Command: []string{"./migrate", "up"}},
+					"fetch-config": {Image: "busybox:1.36", ResourcePreset: spec.ResourcePresetMicro},
+				},
+			},
+		},
+	}
+}
+
+// TestMapSpecToChartValues_Containers maps sidecars, init containers, and
+// shared volumes, with the profile container security context on each.
+func TestMapSpecToChartValues_Containers(t *testing.T) {
+	t.Parallel()
+
+	m := containersManifest()
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+	resolved := &spec.ResolvedSpec{
+		Spec: m,
+		Env:  spec.NormalizeEnv("production"),
+		Components: map[string]spec.ResolvedComponent{
+			"api": {
+				MergedProfile: &spec.PlatformProfile{
+					ContainerSecurityContext: &corev1.SecurityContext{
+						ReadOnlyRootFilesystem: new(true),
+					},
+				},
+			},
+		},
+	}
+
+	vals, err := MapSpecToChartValues(m, "production", resolved)
+	require.NoError(t, err)
+	api := mustNestedMap(t, vals, "api")
+
+	sidecars, ok := api["sidecars"].([]any)
+	require.True(t, ok)
+	require.Len(t, sidecars, 1)
+	shipper, ok := sidecars[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "log-shipper", shipper["name"])
+	assert.Equal(t, "fluent/fluent-bit:3.1", shipper["image"])
+	assert.Equal(t, []string{`--tag={{"{{"}}.Name}}`}, shipper["args"])
+	assert.Equal(t, []any{map[string]any{"name": "LOG_LEVEL", "value": "info"}}, shipper["env"])
+	assert.Equal(t, []any{map[string]any{"name": "logs", "mountPath": "/logs", "readOnly": true}}, shipper["volumeMounts"])
+	nano := spec.ResourcePresetMappings[spec.ResourcePresetNano]["requests"]
+	assert.Equal(t, nano.CPU.String(), mustNestedMap(t, mustNestedMap(t, shipper, "resources"), "requests")["cpu"])
+	assert.Equal(t, map[string]any{"readOnlyRootFilesystem": true}, shipper["securityContext"])
+
+	initContainers, ok := api["initContainers"].([]any)
+	require.True(t, ok)
+	require.Len(t, initContainers, 2)
+	first, ok := initContainers[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "fetch-config", first["name"], "init containers run in name order")
+
+	assert.Equal(t, []any{map[string]any{"name": "logs", "emptyDir": map[string]any{}}}, api["extraVolumes"])
+	assert.Equal(t, []any{map[string]any{"name": "logs", "mountPath": "/var/log/app"}}, api["extraVolumeMounts"])
+}
+
+// TestRenderOffline_Containers renders sidecars and init containers into
+// the Deployment pod template.
+func TestRenderOffline_Containers(t *testing.T) {
+	t.Parallel()
+
+	deployments := renderedObjects[appsv1.Deployment](t, renderManifest(t, containersManifest(), nil), "Deployment")
+	require.Len(t, deployments, 1)
+	pod := deployments[0].Spec.Template.Spec
+
+	names := make([]string, 0, len(pod.Containers))
+	for _, c := range pod.Containers {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"api", "log-shipper"}, names)
+	require.Len(t, pod.InitContainers, 2)
+	assert.Equal(t, "migrate", pod.InitContainers[1].Name)
+	assert.Equal(t, []string{"./migrate", "up"}, pod.InitContainers[1].Command)
+	assert.Equal(t, []string{"--tag={{.Name}}"}, pod.Containers[1].Args)
+	assert.Contains(t, pod.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "logs", MountPath: "/var/log/app"})
+	assert.Contains(t, pod.Volumes, corev1.Volume{Name: "logs", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+}
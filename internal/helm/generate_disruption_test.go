@@ -0,0 +1,97 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func disruptionManifest() *spec.Spec {
+	half := intstr.FromString("50%")
+	one := intstr.FromInt32(1)
+	return &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "shop",
+		Environments: map[string]spec.Environment{
+			"production": {},
+		},
+		Components: map[string]spec.Component{
+			"api": {
+				Role:       spec.ComponentRoleService,
+				Image:      "ghcr.io/acme/api:1.0.0",
+				Port:       8080,
+				Replicas:   new(3),
+				Disruption: &spec.Disruption{MinAvailable: &half},
+			},
+			"worker": {
+				Role:       spec.ComponentRoleWorker,
+				Image:      "ghcr.io/acme/worker:1.0.0",
+				Replicas:   new(1),
+				Disruption: &spec.Disruption{MaxUnavailable: &one},
+			},
+		},
+	}
+}
+
+// TestMapSpecToChartValues_Disruption enables the PodDisruptionBudget only
+// for components running more than one pod, and falls back to the profile
+// default when the component sets none.
+func TestMapSpecToChartValues_Disruption(t *testing.T) {
+	t.Parallel()
+
+	m := disruptionManifest()
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+
+	vals, err := MapSpecToChartValues(m, "production", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"create": true, "minAvailable": "50%"}, mustNestedMap(t, vals, "api")["pdb"])
+	assert.NotContains(t, mustNestedMap(t, vals, "worker"), "pdb", "single replica gets no budget")
+
+	api := m.Components["api"]
+	api.Disruption = nil
+	m.Components["api"] = api
+	zero := intstr.FromInt32(0)
+	resolved := &spec.ResolvedSpec{
+		Spec: m,
+		Env:  spec.NormalizeEnv("production"),
+		Components: map[string]spec.ResolvedComponent{
+			"api": {MergedProfile: &spec.PlatformProfile{Disruption: &spec.Disruption{MaxUnavailable: &zero}}},
+		},
+	}
+	vals, err = MapSpecToChartValues(m, "production", resolved)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"create": true, "maxUnavailable": 0}, mustNestedMap(t, vals, "api")["pdb"])
+}
+
+// TestRenderOffline_Disruption renders a PodDisruptionBudget selecting the
+// component's pods.
+func TestRenderOffline_Disruption(t *testing.T) {
+	t.Parallel()
+
+	pdbs := renderedObjects[policyv1.PodDisruptionBudget](t, renderManifest(t, disruptionManifest(), nil), "PodDisruptionBudget")
+	require.Len(t, pdbs, 1)
+	require.NotNil(t, pdbs[0].Spec.MinAvailable)
+	assert.Equal(t, "50%", pdbs[0].Spec.MinAvailable.String())
+	assert.Nil(t, pdbs[0].Spec.MaxUnavailable)
+	require.NotNil(t, pdbs[0].Spec.Selector)
+	assert.Equal(t, "api", pdbs[0].Spec.Selector.MatchLabels["app.kubernetes.io/name"])
+}
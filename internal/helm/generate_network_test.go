@@ -0,0 +1,131 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func networkPolicyManifest() (*spec.Spec, *spec.ResolvedSpec) {
+	m := &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "shop",
+		Environments: map[string]spec.Environment{
+			"production": {},
+		},
+		Components: map[string]spec.Component{
+			"api": {
+				Role:  spec.ComponentRoleService,
+				Image: "ghcr.io/acme/api:1.0.0",
+				Port:  8080,
+			},
+			"web": {
+				Role:  spec.ComponentRoleService,
+				Image: "ghcr.io/acme/web:1.0.0",
+				Port:  3000,
+			},
+		},
+	}
+	webPods := spec.NetworkPeer{PodLabels: map[string]string{
+		spec.LabelProject:     "shop",
+		spec.LabelComponent:   "web",
+		spec.LabelEnvironment: "production",
+	}}
+	resolved := &spec.ResolvedSpec{
+		Spec: m,
+		Env:  spec.NormalizeEnv("production"),
+		Components: map[string]spec.ResolvedComponent{
+			"api": {Network: &spec.ComponentNetwork{
+				DefaultDeny: true,
+				Ingress: []spec.NetworkRule{
+					{Kind: spec.NetworkEndpointComponent, Name: "web", Peer: webPods},
+					{Kind: spec.NetworkEndpointMonitoring, Peer: spec.NetworkPeer{Namespace: "monitoring"}, Port: "http"},
+				},
+				Egress: []spec.NetworkRule{
+					{Kind: spec.NetworkEndpointPeer, Name: "postgres", Peer: spec.NetworkPeer{CIDRs: []string{"10.20.0.0/16"}}},
+					{Kind: spec.NetworkEndpointDNS, Peer: spec.NetworkPeer{Namespace: "kube-system"}},
+				},
+			}},
+			"web": {Network: &spec.ComponentNetwork{
+				Egress: []spec.NetworkRule{{Kind: spec.NetworkEndpointComponent, Name: "api"}},
+			}},
+		},
+	}
+	return m, resolved
+}
+
+// TestMapSpecToChartValues_NetworkPolicy maps resolved connectivity to
+// NetworkPolicy rules, and renders none for components left open.
+func TestMapSpecToChartValues_NetworkPolicy(t *testing.T) {
+	t.Parallel()
+
+	m, resolved := networkPolicyManifest()
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+
+	vals, err := MapSpecToChartValues(m, "production", resolved)
+	require.NoError(t, err)
+	assert.NotContains(t, mustNestedMap(t, vals, "web"), "networkPolicy", "open component gets no policy")
+
+	np := mustNestedMap(t, mustNestedMap(t, vals, "api"), "networkPolicy")
+	assert.Equal(t, true, np["enabled"])
+	assert.Equal(t, []any{
+		map[string]any{"from": []any{map[string]any{"podSelector": map[string]any{"matchLabels": map[string]any{
+			spec.LabelProject:     "shop",
+			spec.LabelComponent:   "web",
+			spec.LabelEnvironment: "production",
+		}}}}},
+		map[string]any{
+			"from": []any{map[string]any{"namespaceSelector": map[string]any{"matchLabels": map[string]any{
+				corev1.LabelMetadataName: "monitoring",
+			}}}},
+			"ports": []any{map[string]any{"protocol": "TCP", "port": "http"}},
+		},
+	}, np["ingress"])
+	assert.Equal(t, []any{
+		map[string]any{"to": []any{map[string]any{"ipBlock": map[string]any{"cidr": "10.20.0.0/16"}}}},
+		map[string]any{
+			"to": []any{map[string]any{"namespaceSelector": map[string]any{"matchLabels": map[string]any{
+				corev1.LabelMetadataName: "kube-system",
+			}}}},
+			"ports": []any{
+				map[string]any{"protocol": "UDP", "port": 53},
+				map[string]any{"protocol": "TCP", "port": 53},
+			},
+		},
+	}, np["egress"])
+}
+
+// TestRenderOffline_NetworkPolicy renders a NetworkPolicy isolating the
+// component's pods in both directions.
+func TestRenderOffline_NetworkPolicy(t *testing.T) {
+	t.Parallel()
+
+	m, resolved := networkPolicyManifest()
+	policies := renderedObjects[networkingv1.NetworkPolicy](t, renderManifest(t, m, resolved), "NetworkPolicy")
+	require.Len(t, policies, 1)
+	np := policies[0]
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, np.Spec.PolicyTypes)
+	assert.Equal(t, "api", np.Spec.PodSelector.MatchLabels["app.kubernetes.io/name"])
+	require.Len(t, np.Spec.Ingress, 2)
+	require.Len(t, np.Spec.Egress, 2)
+	assert.Equal(t, "10.20.0.0/16", np.Spec.Egress[0].To[0].IPBlock.CIDR)
+}
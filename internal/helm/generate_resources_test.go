@@ -0,0 +1,112 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func resourcesManifest() *spec.Spec {
+	return &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "shop",
+		Environments: map[string]spec.Environment{
+			"production": {},
+		},
+		Components: map[string]spec.Component{
+			"api": {
+				Role:           spec.ComponentRoleService,
+				Image:          "ghcr.io/acme/api:1.0.0",
+				Port:           8080,
+				ResourcePreset: spec.ResourcePresetMicro,
+			},
+			"worker": {
+				Role:  spec.ComponentRoleWorker,
+				Image: "ghcr.io/acme/worker:1.0.0",
+				Resources: spec.Resources{
+					CPU:    spec.MustQuantity("250m"),
+					Memory: spec.MustQuantity("512Mi"),
+					Limits: &spec.ResourceLimits{Memory: spec.MustQuantity("1Gi")},
+				},
+			},
+		},
+	}
+}
+
+// TestMapSpecToChartValues_ResourceLimits maps preset and explicit limits,
+// and shapes them with the profile's resourceLimits policy.
+func TestMapSpecToChartValues_ResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	m := resourcesManifest()
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+
+	vals, err := MapSpecToChartValues(m, "production", nil)
+	require.NoError(t, err)
+	micro := spec.ResourcePresetMappings[spec.ResourcePresetMicro]["limits"]
+	apiLimits := mustNestedMap(t, mustNestedMap(t, mustNestedMap(t, vals, "api"), "resources"), "limits")
+	assert.Equal(t, micro.CPU.String(), apiLimits["cpu"])
+	assert.Equal(t, micro.Memory.String(), apiLimits["memory"])
+	workerLimits := mustNestedMap(t, mustNestedMap(t, mustNestedMap(t, vals, "worker"), "resources"), "limits")
+	assert.Equal(t, map[string]any{"memory": "1Gi"}, workerLimits)
+
+	resolved := &spec.ResolvedSpec{
+		Spec: m,
+		Env:  spec.NormalizeEnv("production"),
+		Components: map[string]spec.ResolvedComponent{
+			"api": {
+				MergedProfile: &spec.PlatformProfile{
+					ResourceLimits: &spec.ProfileResourceLimits{
+						CPU:              spec.LimitPolicyNone,
+						Memory:           spec.LimitPolicyRequest,
+						EphemeralStorage: spec.LimitPolicyNone,
+					},
+				},
+			},
+		},
+	}
+	vals, err = MapSpecToChartValues(m, "production", resolved)
+	require.NoError(t, err)
+	requests := spec.ResourcePresetMappings[spec.ResourcePresetMicro]["requests"]
+	apiLimits = mustNestedMap(t, mustNestedMap(t, mustNestedMap(t, vals, "api"), "resources"), "limits")
+	assert.Equal(t, map[string]any{"memory": requests.Memory.String()}, apiLimits)
+}
+
+// TestRenderOffline_ResourceLimits renders limits onto the main container.
+func TestRenderOffline_ResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	manifest := renderManifest(t, resourcesManifest(), nil)
+
+	var worker *appsv1.Deployment
+	for _, d := range renderedObjects[appsv1.Deployment](t, manifest, "Deployment") {
+		if d.Spec.Selector != nil && d.Spec.Selector.MatchLabels["app.kubernetes.io/name"] == "worker" {
+			worker = &d
+			break
+		}
+	}
+	require.NotNil(t, worker)
+	res := worker.Spec.Template.Spec.Containers[0].Resources
+	assert.True(t, spec.MustQuantity("1Gi").Equal(res.Limits[corev1.ResourceMemory]))
+	assert.NotContains(t, res.Limits, corev1.ResourceCPU)
+	assert.True(t, spec.MustQuantity("250m").Equal(res.Requests[corev1.ResourceCPU]))
+}
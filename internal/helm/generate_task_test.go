@@ -202,7 +202,7 @@ func removeChartDirs(tb testing.TB, cache *ChartCache, m *spec.Spec, environment
 	}
 }
 
-func TestHookTasksForChart(t *testing.T) {
+func TestChartTasks(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -258,7 +258,7 @@ func TestHookTasksForChart(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, err := hookTasksForChart(tt.spec, tt.environment, nil)
+			got, err := chartTasks(tt.spec, tt.environment, nil)
 			require.NoError(t, err)
 			weights := make(map[string]int, len(got))
 			for name, rt := range got {
@@ -269,6 +269,47 @@ func TestHookTasksForChart(t *testing.T) {
 	}
 }
 
+func TestMapSpecToChartValues_ScheduledTask(t *testing.T) {
+	t.Parallel()
+
+	keep := 5
+	m := taskSpec()
+	m.Tasks["report"] = spec.Task{
+		From:                       "api",
+		On:                         spec.TaskOnSchedule,
+		Command:                    []string{"report"},
+		Schedule:                   "0 2 * * *",
+		TimeZone:                   "Europe/Berlin",
+		SuccessfulJobsHistoryLimit: &keep,
+	}
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+
+	vals, err := MapSpecToChartValues(m, "dev", nil)
+	require.NoError(t, err)
+
+	report := mustNestedMap(t, vals, "report")
+	job := mustNestedMap(t, report, "job")
+	assert.Equal(t, false, job["enabled"], "a scheduled task must not render a hook Job")
+	assert.Empty(t, job["hook"])
+	cron := mustNestedMap(t, report, "cronjob")
+	assert.Equal(t, true, cron["enabled"])
+	assert.Equal(t, "0 2 * * *", cron["schedule"])
+	assert.Equal(t, "Europe/Berlin", cron["timeZone"])
+	assert.Equal(t, string(spec.DefaultConcurrencyPolicy), cron["concurrencyPolicy"])
+	assert.Equal(t, 5, cron["successfulJobsHistoryLimit"])
+	_, hasFailedLimit := cron["failedJobsHistoryLimit"]
+	assert.False(t, hasFailedLimit, "unset history limits fall back to the chart default")
+	// Inherited from the parent component like a hook task.
+	assert.Equal(t, map[string]string{"DATABASE_URL": "postgres://db", "LOG": "info"}, report["envVars"])
+
+	migrateCron := mustNestedMap(t, mustNestedMap(t, vals, "migrate"), "cronjob")
+	assert.Equal(t, false, migrateCron["enabled"])
+
+	resolvedTasks := mustNestedMap(t, mustNestedMap(t, mustNestedMap(t, vals, "deployah"), "resolved"), "tasks")
+	resolvedReport := mustNestedMap(t, resolvedTasks, "report")
+	assert.Equal(t, "0 2 * * *", resolvedReport["schedule"])
+}
+
 func TestMapTaskToChartValues_DigestArgsEphemeralTTL(t *testing.T) {
 	t.Parallel()
 
@@ -314,7 +355,7 @@ func TestMapTaskToChartValues_DigestArgsEphemeralTTL(t *testing.T) {
 	assert.Equal(t, 300, job["activeDeadlineSeconds"])
 }
 
-func TestHookTasksForChart_Cycle(t *testing.T) {
+func TestChartTasks_Cycle(t *testing.T) {
 	t.Parallel()
 
 	m := &spec.Spec{
@@ -327,7 +368,7 @@ func TestHookTasksForChart_Cycle(t *testing.T) {
 			"b": {From: "api", On: spec.TaskOnPreDeploy, After: []string{"a"}, Command: []string{"true"}},
 		},
 	}
-	_, err := hookTasksForChart(m, "dev", nil)
+	_, err := chartTasks(m, "dev", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "cycle")
 }
@@ -1,10 +1,15 @@
 package helm
 
 import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
 
 	"deployah.dev/deployah/internal/spec"
 
@@ -30,6 +35,51 @@ func mustNestedMap(t *testing.T, parent map[string]any, key string) map[string]a
 	return m
 }
 
+// renderManifest fills in m's defaults and renders its production
+// environment offline, returning the rendered manifest.
+func renderManifest(t *testing.T, m *spec.Spec, resolved *spec.ResolvedSpec) string {
+	t.Helper()
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+	client, err := NewClient(WithNamespace("default"))
+	require.NoError(t, err)
+	result, cleanup, err := client.RenderOffline(t.Context(), m, "production", resolved, nil)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	return result.Manifest
+}
+
+// renderedObjectsByKind splits a rendered manifest into its objects,
+// grouped by kind in manifest order.
+func renderedObjectsByKind(t *testing.T, manifest string) map[string][]map[string]any {
+	t.Helper()
+	kinds := map[string][]map[string]any{}
+	for doc := range strings.SplitSeq(manifest, "\n---\n") {
+		var obj map[string]any
+		if yaml.Unmarshal([]byte(doc), &obj) != nil {
+			continue
+		}
+		if kind, ok := obj["kind"].(string); ok {
+			kinds[kind] = append(kinds[kind], obj)
+		}
+	}
+	return kinds
+}
+
+// renderedObjects decodes the objects of kind in a rendered manifest into
+// typed objects.
+func renderedObjects[T any](t *testing.T, manifest, kind string) []T {
+	t.Helper()
+	var objs []T
+	for _, obj := range renderedObjectsByKind(t, manifest)[kind] {
+		data, err := yaml.Marshal(obj)
+		require.NoError(t, err)
+		var typed T
+		require.NoError(t, yaml.Unmarshal(data, &typed))
+		objs = append(objs, typed)
+	}
+	return objs
+}
+
 // TestBuildProbeValues_ZeroConfig verifies zero-config service probes are TCP.
 func TestBuildProbeValues_ZeroConfig(t *testing.T) {
 	t.Parallel()
@@ -807,6 +857,115 @@ func TestMapSpecToChartValues_Replicas(t *testing.T) {
 	assert.Equal(t, "Deployment", web["workloadKind"])
 }
 
+// TestMapSpecToChartValues_ComponentEnv verifies component env reaches the
+// chart's envVars for both workload kinds and is omitted when unset.
+func TestMapSpecToChartValues_ComponentEnv(t *testing.T) {
+	t.Parallel()
+
+	m := &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "shop",
+		Environments: map[string]spec.Environment{
+			"production": {},
+		},
+		Components: map[string]spec.Component{
+			"web": {
+				Role:  spec.ComponentRoleService,
+				Image: "nginx:1.0.0",
+				Port:  80,
+				Env:   map[string]string{"LOG_LEVEL": "info", "NODE_ENV": "production"},
+			},
+			"db": {
+				Role:  spec.ComponentRoleService,
+				Kind:  spec.ComponentKindStateful,
+				Image: "postgres:16",
+				Port:  5432,
+				Env:   map[string]string{"PGDATA": "/var/lib/postgresql/data/pgdata"},
+			},
+			"cache": {
+				Role:  spec.ComponentRoleService,
+				Image: "redis:7",
+				Port:  6379,
+			},
+		},
+	}
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+
+	vals, err := MapSpecToChartValues(m, "production", nil)
+	require.NoError(t, err)
+
+	web := mustNestedMap(t, vals, "web")
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "info", "NODE_ENV": "production"}, web["envVars"])
+
+	db := mustNestedMap(t, vals, "db")
+	assert.Equal(t, "StatefulSet", db["workloadKind"])
+	assert.Equal(t, map[string]string{"PGDATA": "/var/lib/postgresql/data/pgdata"}, db["envVars"])
+
+	cache := mustNestedMap(t, vals, "cache")
+	assert.NotContains(t, cache, "envVars")
+
+	// The chart values hold a copy: mutating the spec after mapping must
+	// not leak into values already handed to Helm.
+	m.Components["web"].Env["LOG_LEVEL"] = "debug"
+	assert.Equal(t, "info", web["envVars"].(map[string]string)["LOG_LEVEL"])
+}
+
+// TestMapSpecToChartValues_RuntimeFiles verifies envFile entries reach the
+// chart Secret via envFrom and configFile content reaches the mounted
+// ConfigMap, with template delimiters escaped.
+func TestMapSpecToChartValues_RuntimeFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env.production")
+	require.NoError(t, os.WriteFile(envFile, []byte("DPY_VAR_TAG=1.0.0\nAPI_TOKEN=s3cret\n"), 0o600))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("greeting: \"{{ .Name }}\"\n"), 0o600))
+
+	web := serviceComponent()
+	web.ConfigFile = configFile
+	m := &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "shop",
+		Environments: map[string]spec.Environment{
+			"production": {EnvFile: envFile},
+		},
+		Components: map[string]spec.Component{
+			"web":   web,
+			"cache": {Role: spec.ComponentRoleService, Image: "redis:7", Port: 6379, Environments: []string{"staging"}},
+		},
+	}
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+
+	vals, err := MapSpecToChartValues(m, "production", nil)
+	require.NoError(t, err)
+
+	webVals := mustNestedMap(t, vals, "web")
+	assert.Equal(t, runtimeFilesObjectName, webVals["envVarsSecret"])
+	// envFile values reach Helm through SecretValues, not values.yaml.
+	assert.NotContains(t, webVals, "secret")
+
+	secretVals, err := SecretValues(t.Context(), m, "production", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"web": map[string]any{"secret": map[string]any{"data": map[string]any{
+			"API_TOKEN": base64.StdEncoding.EncodeToString([]byte("s3cret")),
+		}}},
+	}, secretVals)
+
+	configMap := mustNestedMap(t, webVals, "configMap")
+	assert.Equal(t, true, configMap["mounted"])
+	configData := mustNestedMap(t, configMap, "data")
+	assert.Equal(t, "greeting: \"{{\"{{\"}} .Name }}\"\n", configData["config.yaml"])
+
+	// A declared file that is missing fails the render instead of
+	// deploying without it.
+	m.Environments["production"] = spec.Environment{EnvFile: filepath.Join(dir, "missing.env")}
+	_, err = MapSpecToChartValues(m, "production", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "component web")
+}
+
 // TestParseContainerImage verifies repository/tag/digest extraction across
 // bare names, tagged references, digest references, and malformed input.
 func TestParseContainerImage(t *testing.T) {
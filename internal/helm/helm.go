@@ -20,9 +20,12 @@ import (
 	"helm.sh/helm/v4/pkg/storage/driver"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
 
+	"deployah.dev/deployah/internal/secrets"
 	"deployah.dev/deployah/internal/spec"
 
+	chart "helm.sh/helm/v4/pkg/chart/v2"
 	v1 "helm.sh/helm/v4/pkg/release/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
@@ -40,8 +43,9 @@ var (
 	ErrReleaseAlreadyExists = errors.New("release already exists")
 	// ErrReleasePending is returned when a Helm release has an operation in progress.
 	//
-	// Only [Client.InstallApp] produces this sentinel, via a typed check of the
-	// newest revision's pending status (Status.IsPending) before upgrade.
+	// Only [Client.InstallApp] and [Client.InstallChart] produce this
+	// sentinel, via a typed check of the newest revision's pending status
+	// (Status.IsPending) before upgrade.
 	// [Client.wrapHelmError] does not classify Helm's plain pending messages, so
 	// other action paths (and a rare race after the pre-check) surface those as
 	// generic helm failures. Callers may match with [errors.Is].
@@ -59,7 +63,9 @@ type Client struct {
 	extraKubeconfigPaths []string
 	storageDriver        string
 	debug                bool
+	cliVersion           string
 	chartCache           *ChartCache
+	secretResolver       *secrets.Resolver
 }
 
 // Option is a functional option for configuring the Helm client
@@ -118,6 +124,22 @@ func WithDebug(keep bool) Option {
 	}
 }
 
+// WithCLIVersion sets the Deployah version recorded on each installed or
+// upgraded revision, so `deployah history` can show which release made it.
+func WithCLIVersion(version string) Option {
+	return func(c *Client) {
+		c.cliVersion = version
+	}
+}
+
+// WithSecretResolver sets the resolver that decrypts provider-backed
+// component secrets at render time. Defaults to [secrets.NewResolver].
+func WithSecretResolver(resolver *secrets.Resolver) Option {
+	return func(c *Client) {
+		c.secretResolver = resolver
+	}
+}
+
 // WithChartCache sets the prepared-chart cache used by this client.
 // cache must be non-nil; [NewClient] rejects a nil cache.
 func WithChartCache(cache *ChartCache) Option {
@@ -143,6 +165,9 @@ func NewClient(opts ...Option) (*Client, error) {
 	if c.chartCache == nil {
 		return nil, errors.New("chart cache is required")
 	}
+	if c.secretResolver == nil {
+		c.secretResolver = secrets.NewResolver()
+	}
 
 	settings := cli.New()
 
@@ -229,30 +254,20 @@ func (c *Client) InstallApp(ctx context.Context, manifest *spec.Spec, environmen
 		return err
 	}
 
-	labels := map[string]string{
-		"deployah.dev/project":     manifest.Project,
-		"deployah.dev/environment": environment,
-		"deployah.dev/managed-by":  "deployah",
-		"deployah.dev/version":     manifest.APIVersion,
-	}
-
 	releaseName := GenerateReleaseName(manifest.Project, environment)
 
 	// Decide install vs upgrade (and reject pending) before preparing the
 	// chart so a stuck pending release fails without chart work.
-	// History.Max is ignored by Helm (see [Client.GetReleaseHistory]), so
-	// sort by Version after a successful lookup rather than trusting order.
-	history := action.NewHistory(c.config)
-	histRels, histErr := history.Run(releaseName)
-	upgradeExisting := histErr == nil
-	if upgradeExisting {
-		rels, convErr := releaserListToV1(histRels)
-		if convErr != nil {
-			return fmt.Errorf("failed to convert release history: %w", convErr)
-		}
-		if newest := newestRelease(rels); newest != nil && newest.Info != nil && newest.Info.Status.IsPending() {
-			return fmt.Errorf("release '%s': %w", releaseName, ErrReleasePending)
-		}
+	upgradeExisting, err := c.checkReleaseState(releaseName)
+	if err != nil {
+		return err
+	}
+
+	// Decrypted secrets go to Helm as install values, never into the
+	// prepared (and cached) chart; see [SecretValues].
+	values, err := SecretValues(ctx, manifest, environment, c.secretResolver)
+	if err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
 	}
 
 	chartPath, err := PrepareChart(ctx, manifest, environment, resolved, c.chartCache)
@@ -269,15 +284,77 @@ func (c *Client) InstallApp(ctx context.Context, manifest *spec.Spec, environmen
 		}()
 	}
 
-	// Values stay empty: the chart's own values.yaml, written by
-	// [PrepareChart], already carries the mapped spec data.
-	values := map[string]any{}
+	ch, err := loader.Load(chartPath)
+	if err != nil {
+		return fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	labels := c.releaseLabels(manifest.Project, environment, manifest.APIVersion)
+	return c.installOrUpgrade(ctx, releaseName, upgradeExisting, ch, values, labels, postRenderer)
+}
 
+// InstallChart installs or upgrades the release for project/environment
+// from an already prepared chart directory and its values, instead of
+// generating the chart from a spec. It is how `deploy --plan-file` applies
+// the chart a saved plan captured. apiVersion is the spec apiVersion the
+// chart was generated from, recorded in the release labels like
+// [Client.InstallApp] does. The caller owns chartPath.
+func (c *Client) InstallChart(ctx context.Context, project, environment, apiVersion, chartPath string, values map[string]any, postRenderer postrenderer.PostRenderer) error {
+	releaseName := GenerateReleaseName(project, environment)
+	upgradeExisting, err := c.checkReleaseState(releaseName)
+	if err != nil {
+		return err
+	}
 	ch, err := loader.Load(chartPath)
 	if err != nil {
 		return fmt.Errorf("failed to load chart: %w", err)
 	}
+	labels := c.releaseLabels(project, environment, apiVersion)
+	return c.installOrUpgrade(ctx, releaseName, upgradeExisting, ch, values, labels, postRenderer)
+}
+
+// checkReleaseState reports whether releaseName already has history, so
+// the next apply is an upgrade, and rejects a release whose newest revision
+// is still pending with [ErrReleasePending]. History.Max is ignored by Helm
+// (see [Client.GetReleaseHistory]), so it sorts by Version after a
+// successful lookup rather than trusting order.
+func (c *Client) checkReleaseState(releaseName string) (upgradeExisting bool, err error) {
+	history := action.NewHistory(c.config)
+	histRels, histErr := history.Run(releaseName)
+	if histErr != nil {
+		return false, nil
+	}
+	rels, convErr := releaserListToV1(histRels)
+	if convErr != nil {
+		return false, fmt.Errorf("failed to convert release history: %w", convErr)
+	}
+	if newest := newestRelease(rels); newest != nil && newest.Info != nil && newest.Info.Status.IsPending() {
+		return false, fmt.Errorf("release '%s': %w", releaseName, ErrReleasePending)
+	}
+	return true, nil
+}
+
+// releaseLabels returns the Helm release labels Deployah records on every
+// revision it installs.
+func (c *Client) releaseLabels(project, environment, apiVersion string) map[string]string {
+	labels := map[string]string{
+		"deployah.dev/project":     project,
+		"deployah.dev/environment": environment,
+		"deployah.dev/managed-by":  "deployah",
+		"deployah.dev/version":     apiVersion,
+	}
+	// Build metadata such as "+dirty" is not a valid label value; skip the
+	// label rather than fail the deploy over it.
+	if c.cliVersion != "" && len(validation.IsValidLabelValue(c.cliVersion)) == 0 {
+		labels[spec.LabelCLIVersion] = c.cliVersion
+	}
+	return labels
+}
 
+// installOrUpgrade runs the Helm install (when upgradeExisting is false) or
+// upgrade of ch with values, waiting for resources and rolling back on
+// failure.
+func (c *Client) installOrUpgrade(ctx context.Context, releaseName string, upgradeExisting bool, ch *chart.Chart, values map[string]any, labels map[string]string, postRenderer postrenderer.PostRenderer) error {
 	if !upgradeExisting {
 		// Not found -> install. For other history errors, proceed with install
 		// attempt as well.
@@ -305,8 +382,7 @@ func (c *Client) InstallApp(ctx context.Context, manifest *spec.Spec, environmen
 	upgrade.WaitStrategy = kube.StatusWatcherStrategy
 	upgrade.Labels = labels
 	upgrade.PostRenderer = postRenderer
-	_, err = upgrade.RunWithContext(ctx, releaseName, ch, values)
-	if err != nil {
+	if _, err := upgrade.RunWithContext(ctx, releaseName, ch, values); err != nil {
 		return c.wrapHelmError("upgrade", releaseName, err)
 	}
 	return nil
@@ -49,12 +49,15 @@ const offlineMonitorAPIVersion = "monitoring.coreos.com/v1"
 func (c *Client) RenderManifests(ctx context.Context, manifest *spec.Spec, environment string, resolved *spec.ResolvedSpec, postRenderer postrenderer.PostRenderer) (result *render.RenderResult, cleanup func(), err error) {
 	releaseName := GenerateReleaseName(manifest.Project, environment)
 
-	ch, chartPath, cleanup, err := c.prepareAndLoadChart(ctx, manifest, environment, resolved)
+	values, labels, err := c.renderInputs(ctx, manifest, environment)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	values, labels := renderInputs(manifest, environment)
+	ch, chartPath, cleanup, err := c.prepareAndLoadChart(ctx, manifest, environment, resolved)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	history := action.NewHistory(c.config)
 	history.Max = 1
@@ -71,6 +74,7 @@ func (c *Client) RenderManifests(ctx context.Context, manifest *spec.Spec, envir
 		return nil, nil, err
 	}
 	result.ChartPath = chartPath
+	result.Values = values
 	return result, cleanup, nil
 }
 
@@ -84,12 +88,15 @@ func (c *Client) RenderManifests(ctx context.Context, manifest *spec.Spec, envir
 func (c *Client) RenderOffline(ctx context.Context, manifest *spec.Spec, environment string, resolved *spec.ResolvedSpec, postRenderer postrenderer.PostRenderer) (result *render.RenderResult, cleanup func(), err error) {
 	releaseName := GenerateReleaseName(manifest.Project, environment)
 
-	ch, chartPath, cleanup, err := c.prepareAndLoadChart(ctx, manifest, environment, resolved)
+	values, labels, err := c.renderInputs(ctx, manifest, environment)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	values, labels := renderInputs(manifest, environment)
+	ch, chartPath, cleanup, err := c.prepareAndLoadChart(ctx, manifest, environment, resolved)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	result, err = c.renderInstall(ctx, releaseName, ch, values, labels, postRenderer)
 	if err != nil {
@@ -97,9 +104,30 @@ func (c *Client) RenderOffline(ctx context.Context, manifest *spec.Spec, environ
 		return nil, nil, err
 	}
 	result.ChartPath = chartPath
+	result.Values = values
 	return result, cleanup, nil
 }
 
+// RenderChart renders an already prepared chart directory with values,
+// the way [Client.InstallChart] would apply it, without mutating the
+// cluster or Helm's release history. Deploy renders a saved plan's chart
+// with it to check the chart still produces the manifest that was reviewed.
+func (c *Client) RenderChart(ctx context.Context, project, environment, apiVersion, chartPath string, values map[string]any, postRenderer postrenderer.PostRenderer) (*render.RenderResult, error) {
+	releaseName := GenerateReleaseName(project, environment)
+	ch, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+	labels := c.releaseLabels(project, environment, apiVersion)
+
+	history := action.NewHistory(c.config)
+	history.Max = 1
+	if _, histErr := history.Run(releaseName); histErr != nil {
+		return c.renderInstall(ctx, releaseName, ch, values, labels, postRenderer)
+	}
+	return c.renderUpgrade(ctx, releaseName, ch, values, labels, postRenderer)
+}
+
 // prepareAndLoadChart generates (or fetches from cache) the Helm chart for
 // manifest/environment and loads it, returning a cleanup func for the
 // generated chart directory. It is the common first step shared by
@@ -130,18 +158,22 @@ func (c *Client) prepareAndLoadChart(ctx context.Context, manifest *spec.Spec, e
 }
 
 // renderInputs builds the Helm values and labels shared by every render
-// path (install, upgrade, offline). Values are empty: the chart's own
-// values.yaml, written by [PrepareChart], already carries the mapped spec
-// data. This mirrors InstallApp.
-func renderInputs(manifest *spec.Spec, environment string) (values map[string]any, labels map[string]string) {
-	values = map[string]any{}
+// path (install, upgrade, offline). Values hold only the decrypted secrets
+// from [SecretValues]: the chart's own values.yaml, written by
+// [PrepareChart], already carries the rest of the mapped spec data. This
+// mirrors InstallApp.
+func (c *Client) renderInputs(ctx context.Context, manifest *spec.Spec, environment string) (values map[string]any, labels map[string]string, err error) {
+	values, err = SecretValues(ctx, manifest, environment, c.secretResolver)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
 	labels = map[string]string{
 		"deployah.dev/project":     manifest.Project,
 		"deployah.dev/environment": environment,
 		"deployah.dev/managed-by":  "deployah",
 		"deployah.dev/version":     manifest.APIVersion,
 	}
-	return values, labels
+	return values, labels, nil
 }
 
 // restoreConfigForDryRun snapshots the Configuration fields a client-side dry
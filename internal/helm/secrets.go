@@ -0,0 +1,70 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+
+	"deployah.dev/deployah/internal/secrets"
+	"deployah.dev/deployah/internal/spec"
+)
+
+// SecretValues reads the envFile entries and decrypts the provider-backed
+// secrets of every component active in environment, and returns them as
+// Helm values shaped like the chart values [MapSpecToChartValues] builds
+// (component -> secret.data), for Helm to merge over the prepared chart's
+// values.yaml. A decrypted secret wins over an envFile entry with the same
+// name. The result is empty when no active component has any.
+//
+// These values are handed to Helm on each install, upgrade, or render and
+// are never written into the prepared chart, so the [ChartCache] only ever
+// holds the references from the spec.
+func SecretValues(ctx context.Context, manifest *spec.Spec, environment string, resolver *secrets.Resolver) (map[string]any, error) {
+	values := map[string]any{}
+	for _, name := range slices.Sorted(maps.Keys(manifest.Components)) {
+		component := manifest.Components[name]
+		if !componentActiveInEnvironment(component, environment) {
+			continue
+		}
+		files, err := spec.LoadRuntimeFiles(manifest, environment, component)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", name, err)
+		}
+		resolvedSecrets, err := resolver.ResolveComponent(ctx, component)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", name, err)
+		}
+		if len(files.Env) == 0 && len(resolvedSecrets) == 0 {
+			continue
+		}
+		// Base64 data, not stringData: the chart renders secret.data
+		// through tpl, and encoded content can never contain "{{".
+		data := make(map[string]any, len(files.Env)+len(resolvedSecrets))
+		for k, v := range files.Env {
+			data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+		}
+		for k, v := range resolvedSecrets {
+			data[k] = base64.StdEncoding.EncodeToString([]byte(v))
+		}
+		values[name] = map[string]any{
+			"secret": map[string]any{"data": data},
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/secrets"
+	"deployah.dev/deployah/internal/spec"
+)
+
+// fakeSOPSResolver returns a resolver whose sops provider serves a fixed
+// decrypted document instead of running the CLI.
+func fakeSOPSResolver(doc string) *secrets.Resolver {
+	return secrets.NewResolver(secrets.WithProvider(spec.SecretProviderSOPS,
+		secrets.ProviderFunc(func(context.Context, string) ([]byte, error) {
+			return []byte(doc), nil
+		})))
+}
+
+// secretsManifest returns a spec whose web component mixes a plain env
+// entry, a cluster secretRef, and a sops-backed secret.
+func secretsManifest(t *testing.T) *spec.Spec {
+	t.Helper()
+	web := serviceComponent()
+	web.Env = map[string]string{"LOG_LEVEL": "info"}
+	web.Secrets = map[string]spec.SecretSource{
+		"DATABASE_PASSWORD": {SecretRef: &spec.SecretKeyRef{Name: "db-credentials", Key: "password"}},
+		"STRIPE_KEY":        {Provider: spec.SecretProviderSOPS, File: "secrets/prod.enc.yaml", Key: "stripe.key"},
+	}
+	m := &spec.Spec{
+		APIVersion: spec.CurrentManifestVersion,
+		Project:    "shop",
+		Components: map[string]spec.Component{
+			"web":   web,
+			"admin": {Role: spec.ComponentRoleService, Image: "admin:1", Port: 8080, Environments: []string{"staging"}},
+		},
+	}
+	require.NoError(t, spec.FillSpecWithDefaults(m, spec.CurrentManifestVersion))
+	return m
+}
+
+// TestMapSpecToChartValues_Secrets verifies a cluster secretRef becomes a
+// secretKeyRef env entry and a provider secret only enables envFrom: no
+// value, decrypted or not, reaches the chart values.
+func TestMapSpecToChartValues_Secrets(t *testing.T) {
+	t.Parallel()
+
+	vals, err := MapSpecToChartValues(secretsManifest(t), "production", nil)
+	require.NoError(t, err)
+
+	web := mustNestedMap(t, vals, "web")
+	assert.Equal(t, map[string]any{
+		"LOG_LEVEL": "info",
+		"DATABASE_PASSWORD": map[string]any{
+			"valueFrom": map[string]any{
+				"secretKeyRef": map[string]any{"name": "db-credentials", "key": "password"},
+			},
+		},
+	}, web["envVars"])
+	assert.Equal(t, runtimeFilesObjectName, web["envVarsSecret"])
+	assert.NotContains(t, web, "secret")
+}
+
+// TestSecretValues verifies decrypted values are shaped as chart Secret data
+// for active components only.
+func TestSecretValues(t *testing.T) {
+	t.Parallel()
+
+	vals, err := SecretValues(t.Context(), secretsManifest(t), "production", fakeSOPSResolver("stripe:\n  key: sk_live_123\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"web": map[string]any{
+			"secret": map[string]any{
+				"data": map[string]any{"STRIPE_KEY": base64.StdEncoding.EncodeToString([]byte("sk_live_123"))},
+			},
+		},
+	}, vals)
+
+	failing := secrets.NewResolver(secrets.WithProvider(spec.SecretProviderSOPS,
+		secrets.ProviderFunc(func(context.Context, string) ([]byte, error) {
+			return nil, os.ErrNotExist
+		})))
+	_, err = SecretValues(t.Context(), secretsManifest(t), "production", failing)
+	require.ErrorContains(t, err, "component web")
+}
+
+// TestRenderOffline_SecretsNotCached verifies a decrypted secret reaches the
+// rendered Secret but never the prepared chart kept by the cache.
+func TestRenderOffline_SecretsNotCached(t *testing.T) {
+	t.Parallel()
+
+	cache := NewChartCache(0)
+	client, err := NewClient(
+		WithNamespace("default"),
+		WithChartCache(cache),
+		WithSecretResolver(fakeSOPSResolver("stripe:\n  key: sk_live_123\n")),
+	)
+	require.NoError(t, err)
+
+	manifest := secretsManifest(t)
+	result, cleanup, err := client.RenderOffline(t.Context(), manifest, "production", nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("sk_live_123"))
+	assert.Contains(t, result.Manifest, "STRIPE_KEY: "+encoded)
+
+	key, err := cache.GenerateKey(manifest, "production", nil)
+	require.NoError(t, err)
+	cachedPath, found := cache.get(key)
+	require.True(t, found)
+	t.Cleanup(func() { removeChartDir(t, cachedPath) })
+
+	valuesYAML, err := os.ReadFile(filepath.Join(cachedPath, "values.yaml")) // #nosec G304 -- test temp dir
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(string(valuesYAML), "sk_live_123") || strings.Contains(string(valuesYAML), encoded),
+		"cached values.yaml must not hold the decrypted secret")
+}
+
+// TestRenderOffline_EnvFileNotCached verifies an envFile value reaches the
+// rendered Secret but never the prepared chart kept by the cache.
+func TestRenderOffline_EnvFileNotCached(t *testing.T) {
+	t.Parallel()
+
+	envFile := filepath.Join(t.TempDir(), ".env.production")
+	require.NoError(t, os.WriteFile(envFile, []byte("API_TOKEN=s3cret\n"), 0o600))
+
+	cache := NewChartCache(0)
+	client, err := NewClient(WithNamespace("default"), WithChartCache(cache))
+	require.NoError(t, err)
+
+	manifest := &spec.Spec{
+		APIVersion:   spec.CurrentManifestVersion,
+		Project:      "shop",
+		Environments: map[string]spec.Environment{"production": {EnvFile: envFile}},
+		Components:   map[string]spec.Component{"web": serviceComponent()},
+	}
+	require.NoError(t, spec.FillSpecWithDefaults(manifest, spec.CurrentManifestVersion))
+
+	result, cleanup, err := client.RenderOffline(t.Context(), manifest, "production", nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("s3cret"))
+	assert.Contains(t, result.Manifest, "API_TOKEN: "+encoded)
+
+	key, err := cache.GenerateKey(manifest, "production", nil)
+	require.NoError(t, err)
+	cachedPath, found := cache.get(key)
+	require.True(t, found)
+	t.Cleanup(func() { removeChartDir(t, cachedPath) })
+
+	valuesYAML, err := os.ReadFile(filepath.Join(cachedPath, "values.yaml")) // #nosec G304 -- test temp dir
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(string(valuesYAML), "s3cret") || strings.Contains(string(valuesYAML), encoded),
+		"cached values.yaml must not hold the envFile value")
+}
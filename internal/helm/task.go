@@ -26,7 +26,7 @@ import (
 const hookDeletePolicy = "before-hook-creation,hook-succeeded"
 
 // createTaskSubCharts creates a sub-chart directory for each name in
-// taskNames, as returned by [hookTaskNames]. Manual tasks and tasks from
+// taskNames, as returned by [chartTaskNames]. Manual tasks and tasks from
 // other environments are absent from that list and get no subchart.
 func createTaskSubCharts(chartDir string, taskNames []string) error {
 	chartsDir := filepath.Join(chartDir, "charts")
@@ -46,43 +46,53 @@ func createTaskSubCharts(chartDir string, taskNames []string) error {
 		if err := os.MkdirAll(templatesDir, 0o750); err != nil {
 			return fmt.Errorf("failed to create templates directory for task %s: %w", name, err)
 		}
-		if err := createTaskJobTemplate(templatesDir); err != nil {
-			return fmt.Errorf("failed to create job.yaml template for task %s: %w", name, err)
+		if err := createTaskJobTemplates(templatesDir); err != nil {
+			return fmt.Errorf("failed to create templates for task %s: %w", name, err)
 		}
 	}
 	return nil
 }
 
-func createTaskJobTemplate(templatesDir string) error {
-	body := `{{- include "deployah.job" . -}}`
-	return os.WriteFile(filepath.Join(templatesDir, "job.yaml"), []byte(body), 0o600)
+// createTaskJobTemplates writes both the Job and the CronJob template; the
+// task's job.enabled and cronjob.enabled values decide which one renders.
+func createTaskJobTemplates(templatesDir string) error {
+	templates := map[string]string{
+		"job.yaml":     `{{- include "deployah.job" . -}}`,
+		"cronjob.yaml": `{{- include "deployah.cronjob" . -}}`,
+	}
+	for file, body := range templates {
+		if err := os.WriteFile(filepath.Join(templatesDir, file), []byte(body), 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// hookTaskNames returns the sorted names of the hook tasks that get a
-// sub-chart in this environment.
-func hookTaskNames(m *spec.Spec, desiredEnvironment string, resolved *spec.ResolvedSpec) ([]string, error) {
-	hooks, err := hookTasksForChart(m, desiredEnvironment, resolved)
+// chartTaskNames returns the sorted names of the hook and scheduled tasks
+// that get a sub-chart in this environment.
+func chartTaskNames(m *spec.Spec, desiredEnvironment string, resolved *spec.ResolvedSpec) ([]string, error) {
+	tasks, err := chartTasks(m, desiredEnvironment, resolved)
 	if err != nil {
 		return nil, err
 	}
-	names := make([]string, 0, len(hooks))
-	for name := range hooks {
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
 		names = append(names, name)
 	}
 	slices.Sort(names)
 	return names, nil
 }
 
-// hookTasksForChart returns merged hook tasks that belong in this
+// chartTasks returns merged hook and scheduled tasks that belong in this
 // environment. Manual tasks are omitted.
-func hookTasksForChart(m *spec.Spec, desiredEnvironment string, resolved *spec.ResolvedSpec) (map[string]spec.ResolvedTask, error) {
+func chartTasks(m *spec.Spec, desiredEnvironment string, resolved *spec.ResolvedSpec) (map[string]spec.ResolvedTask, error) {
 	all, err := spec.EffectiveTasks(m, desiredEnvironment, resolved)
 	if err != nil {
 		return nil, err
 	}
 	out := make(map[string]spec.ResolvedTask, len(all))
 	for name, rt := range all {
-		if rt.Task.On.IsHook() {
+		if rt.Task.On.InChart() {
 			out[name] = rt
 		}
 	}
@@ -91,21 +101,25 @@ func hookTasksForChart(m *spec.Spec, desiredEnvironment string, resolved *spec.R
 
 func applyTaskChartValues(values map[string]any, m *spec.Spec, desiredEnvironment string, resolved *spec.ResolvedSpec) (map[string]any, error) {
 	resolvedTasks := make(map[string]any)
-	hooks, err := hookTasksForChart(m, desiredEnvironment, resolved)
+	tasks, err := chartTasks(m, desiredEnvironment, resolved)
 	if err != nil {
 		return nil, err
 	}
-	for name, rt := range hooks {
+	for name, rt := range tasks {
 		taskValues, mapErr := mapTaskToChartValues(m, name, rt, desiredEnvironment)
 		if mapErr != nil {
 			return nil, fmt.Errorf("task %s: %w", name, mapErr)
 		}
 		values[name] = taskValues
-		resolvedTasks[name] = map[string]any{
+		resolvedTask := map[string]any{
 			"on":         string(rt.Task.On),
 			"hookWeight": rt.HookWeight,
 			"timeout":    rt.Task.Timeout,
 		}
+		if rt.Task.On == spec.TaskOnSchedule {
+			resolvedTask["schedule"] = rt.Task.Schedule
+		}
+		resolvedTasks[name] = resolvedTask
 	}
 	return resolvedTasks, nil
 }
@@ -117,21 +131,6 @@ func mapTaskToChartValues(m *spec.Spec, name string, rt spec.ResolvedTask, desir
 	}
 	image, tag := parseContainerImage(fields.Image)
 
-	requests := map[string]any{}
-	if fields.Resources.CPU != nil && !fields.Resources.CPU.IsZero() {
-		requests["cpu"] = fields.Resources.CPU.String()
-	}
-	if fields.Resources.Memory != nil && !fields.Resources.Memory.IsZero() {
-		requests["memory"] = fields.Resources.Memory.String()
-	}
-	if fields.Resources.EphemeralStorage != nil && !fields.Resources.EphemeralStorage.IsZero() {
-		requests["ephemeral-storage"] = fields.Resources.EphemeralStorage.String()
-	}
-	resources := map[string]any{}
-	if len(requests) > 0 {
-		resources["requests"] = requests
-	}
-
 	imageValues := map[string]any{"repository": image}
 	if tag != "" {
 		if strings.HasPrefix(tag, "sha256:") {
@@ -142,7 +141,7 @@ func mapTaskToChartValues(m *spec.Spec, name string, rt spec.ResolvedTask, desir
 	}
 
 	job := map[string]any{
-		"enabled":          true,
+		"enabled":          rt.Task.On.IsHook(),
 		"hook":             rt.Task.HelmHookEvents(),
 		"hookWeight":       rt.HookWeight,
 		"hookDeletePolicy": hookDeletePolicy,
@@ -168,8 +167,9 @@ func mapTaskToChartValues(m *spec.Spec, name string, rt spec.ResolvedTask, desir
 			spec.AnnotationProject: m.Project,
 		},
 		"image":     imageValues,
-		"resources": resources,
+		"resources": resourceValues(fields.Resources, rt.MergedProfile),
 		"job":       job,
+		"cronjob":   cronJobValues(rt.Task),
 		"service": map[string]any{
 			"enabled": false,
 		},
@@ -188,3 +188,32 @@ func mapTaskToChartValues(m *spec.Spec, name string, rt spec.ResolvedTask, desir
 	}
 	return values, nil
 }
+
+// cronJobValues returns the cronjob block of a task's chart values. Only
+// scheduled tasks enable it; the pod comes from the job block through the
+// shared deployah.job.spec template.
+func cronJobValues(task spec.Task) map[string]any {
+	if task.On != spec.TaskOnSchedule {
+		return map[string]any{"enabled": false}
+	}
+	policy := task.ConcurrencyPolicy
+	if policy == "" {
+		policy = spec.DefaultConcurrencyPolicy
+	}
+	values := map[string]any{
+		"enabled":           true,
+		"schedule":          task.Schedule,
+		"concurrencyPolicy": string(policy),
+		"suspend":           task.Suspend,
+	}
+	if task.TimeZone != "" {
+		values["timeZone"] = task.TimeZone
+	}
+	if task.SuccessfulJobsHistoryLimit != nil {
+		values["successfulJobsHistoryLimit"] = *task.SuccessfulJobsHistoryLimit
+	}
+	if task.FailedJobsHistoryLimit != nil {
+		values["failedJobsHistoryLimit"] = *task.FailedJobsHistoryLimit
+	}
+	return values
+}
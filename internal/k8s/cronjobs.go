@@ -0,0 +1,124 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+
+	"k8s.io/client-go/kubernetes"
+
+	"deployah.dev/deployah/internal/spec"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cronJobInstantiateAnnotation marks a Job created by hand from a CronJob,
+// matching `kubectl create job --from=cronjob/...`.
+const cronJobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+
+// FindTaskCronJob returns the CronJob a release rendered for the scheduled
+// task named task. It matches on the project, environment, and component
+// labels the chart puts on every task resource, so the caller does not need
+// to know the release's naming scheme.
+func FindTaskCronJob(ctx context.Context, cs kubernetes.Interface, namespace, project, environment, task string) (*batchv1.CronJob, error) {
+	sb, err := NewSelectorBuilder().WithProject(project)
+	if err != nil {
+		return nil, fmt.Errorf("build cronjob selector: %w", err)
+	}
+	if sb, err = sb.WithEnvironment(environment); err != nil {
+		return nil, fmt.Errorf("build cronjob selector: %w", err)
+	}
+	if sb, err = sb.WithComponent(task); err != nil {
+		return nil, fmt.Errorf("build cronjob selector: %w", err)
+	}
+	list, err := cs.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: sb.Build(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list cronjobs: %w", err)
+	}
+	switch len(list.Items) {
+	case 0:
+		return nil, fmt.Errorf("no CronJob found for task %s in namespace %s; deploy the environment first", task, namespace)
+	case 1:
+		return &list.Items[0], nil
+	default:
+		return nil, fmt.Errorf("found %d CronJobs for task %s in namespace %s; expected one", len(list.Items), task, namespace)
+	}
+}
+
+// JobFromCronJob builds a one-off Job from cronJob's jobTemplate, the way
+// `kubectl create job --from=cronjob/...` does. The Job is owned by the
+// CronJob so it is cleaned up with the release. count and parallelism
+// override the template's completions and parallelism when greater than 0;
+// parallelism is clamped to the resulting completions.
+func JobFromCronJob(cronJob *batchv1.CronJob, count, parallelism int) (*batchv1.Job, error) {
+	tmpl := cronJob.Spec.JobTemplate
+	jobSpec := *tmpl.Spec.DeepCopy()
+	if count > 0 {
+		c32, err := toInt32("count", count)
+		if err != nil {
+			return nil, err
+		}
+		jobSpec.Completions = &c32
+	}
+	if parallelism > 0 {
+		p32, err := toInt32("parallelism", parallelism)
+		if err != nil {
+			return nil, err
+		}
+		jobSpec.Parallelism = &p32
+	}
+	if jobSpec.Completions != nil && jobSpec.Parallelism != nil && *jobSpec.Parallelism > *jobSpec.Completions {
+		jobSpec.Parallelism = new(*jobSpec.Completions)
+	}
+
+	labels := maps.Clone(tmpl.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	maps.Copy(labels, map[string]string{
+		spec.LabelProject:     cronJob.Labels[spec.LabelProject],
+		spec.LabelComponent:   cronJob.Labels[spec.LabelComponent],
+		spec.LabelEnvironment: cronJob.Labels[spec.LabelEnvironment],
+	})
+	annotations := maps.Clone(tmpl.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[cronJobInstantiateAnnotation] = "manual"
+
+	job := &batchv1.Job{
+		GenerateName: jobGenerateName(cronJob.Name, "manual"),
+		Namespace:    cronJob.Namespace,
+		Labels:       labels,
+		Annotations:  annotations,
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+		},
+		Spec: jobSpec,
+	}
+	return job, nil
+}
+
+func toInt32(name string, n int) (int32, error) {
+	if n < 0 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("%s %d is outside the int32 range", name, n)
+	}
+	return int32(n), nil
+}
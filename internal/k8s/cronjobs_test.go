@@ -0,0 +1,97 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"deployah.dev/deployah/internal/spec"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func taskCronJob(name, project, environment, task string) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		Name:      name,
+		Namespace: "default",
+		UID:       "cron-uid",
+		Labels: map[string]string{
+			spec.LabelProject:     project,
+			spec.LabelComponent:   task,
+			spec.LabelEnvironment: environment,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 2 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				Labels: map[string]string{"app.kubernetes.io/name": task},
+				Spec: batchv1.JobSpec{
+					Completions: new(int32(4)),
+					Parallelism: new(int32(2)),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: task, Image: "ghcr.io/acme/shop:1.2.3"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFindTaskCronJob(t *testing.T) {
+	t.Parallel()
+
+	cs := fake.NewSimpleClientset(
+		taskCronJob("shop-review-pr-42-report", "shop", "review-pr-42", "report"),
+		taskCronJob("shop-review-pr-42-cleanup", "shop", "review-pr-42", "cleanup"),
+		taskCronJob("other-review-pr-42-report", "other", "review-pr-42", "report"),
+	)
+	got, err := FindTaskCronJob(t.Context(), cs, "default", "shop", "review/pr-42", "report")
+	require.NoError(t, err)
+	assert.Equal(t, "shop-review-pr-42-report", got.Name)
+
+	_, err = FindTaskCronJob(t.Context(), cs, "default", "shop", "review/pr-42", "missing")
+	require.ErrorContains(t, err, "deploy the environment first")
+}
+
+func TestJobFromCronJob(t *testing.T) {
+	t.Parallel()
+
+	cronJob := taskCronJob("shop-prod-report", "shop", "prod", "report")
+	job, err := JobFromCronJob(cronJob, 0, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop-prod-report-manual-", job.GenerateName)
+	assert.Equal(t, "default", job.Namespace)
+	assert.Equal(t, "manual", job.Annotations[cronJobInstantiateAnnotation])
+	assert.Equal(t, "report", job.Labels[spec.LabelComponent])
+	assert.Equal(t, "report", job.Labels["app.kubernetes.io/name"])
+	require.Len(t, job.OwnerReferences, 1)
+	assert.Equal(t, "CronJob", job.OwnerReferences[0].Kind)
+	assert.Equal(t, int32(4), *job.Spec.Completions)
+	assert.Equal(t, int32(2), *job.Spec.Parallelism)
+
+	// Overrides apply, with parallelism clamped to completions; the
+	// CronJob's template is left untouched.
+	job, err = JobFromCronJob(cronJob, 1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), *job.Spec.Completions)
+	assert.Equal(t, int32(1), *job.Spec.Parallelism)
+	assert.Equal(t, int32(4), *cronJob.Spec.JobTemplate.Spec.Completions)
+}
@@ -21,6 +21,7 @@ import (
 	"slices"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 
@@ -87,9 +88,13 @@ func BuildTaskJob(opts TaskJobOptions) (*batchv1.Job, error) {
 	if len(fields.Args) > 0 {
 		container.Args = fields.Args
 	}
-	if req := resourceList(fields.Resources); len(req) > 0 {
+	res := fields.Resources
+	if req := resourceList(res.CPU, res.Memory, res.EphemeralStorage); len(req) > 0 {
 		container.Resources.Requests = req
 	}
+	if l := res.EffectiveLimits(opts.Profile); l != nil {
+		container.Resources.Limits = resourceList(l.CPU, l.Memory, l.EphemeralStorage)
+	}
 
 	podLabels := map[string]string{
 		spec.LabelProject:     opts.Project,
@@ -169,16 +174,16 @@ func jobGenerateName(release, task string) string {
 	return prefix
 }
 
-func resourceList(res spec.Resources) corev1.ResourceList {
+func resourceList(cpu, memory, ephemeralStorage *resource.Quantity) corev1.ResourceList {
 	out := corev1.ResourceList{}
-	if res.CPU != nil && !res.CPU.IsZero() {
-		out[corev1.ResourceCPU] = *res.CPU
+	if cpu != nil && !cpu.IsZero() {
+		out[corev1.ResourceCPU] = *cpu
 	}
-	if res.Memory != nil && !res.Memory.IsZero() {
-		out[corev1.ResourceMemory] = *res.Memory
+	if memory != nil && !memory.IsZero() {
+		out[corev1.ResourceMemory] = *memory
 	}
-	if res.EphemeralStorage != nil && !res.EphemeralStorage.IsZero() {
-		out[corev1.ResourceEphemeralStorage] = *res.EphemeralStorage
+	if ephemeralStorage != nil && !ephemeralStorage.IsZero() {
+		out[corev1.ResourceEphemeralStorage] = *ephemeralStorage
 	}
 	return out
 }
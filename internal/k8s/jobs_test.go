@@ -163,6 +163,38 @@ func TestBuildTaskJob_EphemeralStorageArgsAndTTL(t *testing.T) {
 	assert.Equal(t, int32(30), *job.Spec.TTLSecondsAfterFinished)
 }
 
+func TestBuildTaskJob_LimitsFollowProfilePolicy(t *testing.T) {
+	t.Parallel()
+
+	job, err := BuildTaskJob(TaskJobOptions{
+		Project:     "shop",
+		Environment: "dev",
+		Namespace:   "default",
+		TaskName:    "migrate",
+		Task: spec.Task{
+			Image: "busybox:1.36",
+			Resources: spec.Resources{
+				CPU:    spec.MustQuantity("100m"),
+				Memory: spec.MustQuantity("128Mi"),
+				Limits: &spec.ResourceLimits{
+					CPU:    spec.MustQuantity("500m"),
+					Memory: spec.MustQuantity("256Mi"),
+				},
+			},
+		},
+		Profile: &spec.PlatformProfile{
+			ResourceLimits: &spec.ProfileResourceLimits{
+				CPU:    spec.LimitPolicyNone,
+				Memory: spec.LimitPolicyRequest,
+			},
+		},
+	})
+	require.NoError(t, err)
+	limits := job.Spec.Template.Spec.Containers[0].Resources.Limits
+	assert.NotContains(t, limits, corev1.ResourceCPU)
+	assert.True(t, spec.MustQuantity("128Mi").Equal(limits[corev1.ResourceMemory]))
+}
+
 func TestCreateTaskJob_Error(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,244 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/google/renameio/v2"
+)
+
+const (
+	// localCACertFile and localCAKeyFile name the CA's files in its directory.
+	localCACertFile = "rootCA.pem"
+	localCAKeyFile  = "rootCA-key.pem"
+
+	// localCAMaxAge is the validity window of the local CA itself.
+	localCAMaxAge = 10 * 365 * 24 * time.Hour
+
+	// localCACertMaxAge is the validity window of a certificate the local
+	// CA issues: 825 days is the longest macOS and iOS accept for a TLS
+	// server certificate, even from a locally trusted root.
+	localCACertMaxAge = 825 * 24 * time.Hour
+)
+
+// LocalCA is the per-user root certificate authority that signs the
+// certificates of selfSigned components. Trusting it once, see
+// "deployah cluster trust", makes browsers and curl accept every local
+// service.
+type LocalCA struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+	dir     string
+}
+
+// DefaultLocalCADir returns the directory the local CA is kept in, under
+// the XDG data home.
+func DefaultLocalCADir() string {
+	return filepath.Join(xdg.DataHome, "deployah", "ca")
+}
+
+// LoadLocalCA reads the local CA from dir. The error matches
+// [os.ErrNotExist] when no CA was created there yet.
+func LoadLocalCA(dir string) (*LocalCA, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, localCACertFile)) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("read local CA: %w", err)
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, localCAKeyFile)) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("read local CA key: %w", err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse local CA in %s: %w", dir, err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse local CA in %s: %w", dir, err)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok || !cert.IsCA {
+		return nil, fmt.Errorf("parse local CA in %s: not a CA certificate and key", dir)
+	}
+	return &LocalCA{cert: cert, key: key, certPEM: certPEM, dir: dir}, nil
+}
+
+// EnsureLocalCA returns the local CA in dir, creating it on first use.
+// Concurrent first uses agree on one CA: each builds its own in a
+// temporary directory, and only the first to rename it into place wins.
+func EnsureLocalCA(dir string) (*LocalCA, error) {
+	ca, err := LoadLocalCA(dir)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return ca, err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dir), 0o700); err != nil {
+		return nil, fmt.Errorf("create local CA directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".ca-")
+	if err != nil {
+		return nil, fmt.Errorf("create local CA directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err = writeLocalCA(tmp); err != nil {
+		return nil, err
+	}
+	// A leftover empty directory would make the rename fail; a populated
+	// one means another process won the race.
+	_ = os.Remove(dir)
+	if renameErr := os.Rename(tmp, dir); renameErr != nil {
+		if _, statErr := os.Stat(filepath.Join(dir, localCACertFile)); statErr != nil {
+			return nil, fmt.Errorf("create local CA: %w", renameErr)
+		}
+	}
+	return LoadLocalCA(dir)
+}
+
+// writeLocalCA generates a new CA and writes its certificate and key to dir.
+func writeLocalCA(dir string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generate local CA key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+	name := "Deployah local CA"
+	if user, host := os.Getenv("USER"), hostname(); user != "" {
+		name += " " + user + "@" + host
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Deployah local CA"}, CommonName: name},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(localCAMaxAge),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("create local CA certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("encode local CA key: %w", err)
+	}
+
+	if err = renameio.WriteFile(filepath.Join(dir, localCAKeyFile), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return fmt.Errorf("write local CA key: %w", err)
+	}
+	if err = renameio.WriteFile(filepath.Join(dir, localCACertFile), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		return fmt.Errorf("write local CA: %w", err)
+	}
+	return nil
+}
+
+// CertPEM returns the CA certificate, the part to trust.
+func (ca *LocalCA) CertPEM() []byte { return ca.certPEM }
+
+// CertPath returns the path of the CA certificate file.
+func (ca *LocalCA) CertPath() string { return filepath.Join(ca.dir, localCACertFile) }
+
+// Subject returns the CA's common name, which trust stores list it by.
+func (ca *LocalCA) Subject() string { return ca.cert.Subject.CommonName }
+
+// NotAfter returns when the CA expires.
+func (ca *LocalCA) NotAfter() time.Time { return ca.cert.NotAfter }
+
+// IssueCert issues a PEM cert/key pair for host, a DNS name or an IP
+// address, signed by the CA.
+func (ca *LocalCA) IssueCert(host string) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate key for %s: %w", host, err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	notAfter := now.Add(localCACertMaxAge)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"Deployah local development"}, CommonName: host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		tmpl.IPAddresses = []net.IP{ip}
+	} else {
+		tmpl.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign certificate for %s: %w", host, err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode key for %s: %w", host, err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// Signed reports whether the CA issued leaf.
+func (ca *LocalCA) Signed(leaf *x509.Certificate) bool {
+	return leaf.CheckSignatureFrom(ca.cert) == nil
+}
+
+// randomSerial returns a random 128-bit certificate serial number.
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("generate certificate serial: %w", err)
+	}
+	return serial, nil
+}
+
+// hostname returns the host name, or "localhost" when it is unknown.
+func hostname() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "localhost"
+	}
+	return host
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnsureLocalCA creates the CA once and loads the same one afterwards.
+func TestEnsureLocalCA(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "ca")
+
+	_, err := LoadLocalCA(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	ca, err := EnsureLocalCA(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, localCACertFile), ca.CertPath())
+
+	again, err := EnsureLocalCA(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ca.CertPEM(), again.CertPEM())
+
+	info, err := os.Stat(filepath.Join(dir, localCAKeyFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+// TestLocalCA_IssueCert issues a server certificate that verifies against
+// the CA for its host.
+func TestLocalCA_IssueCert(t *testing.T) {
+	t.Parallel()
+	ca, err := EnsureLocalCA(t.TempDir())
+	require.NoError(t, err)
+
+	for _, host := range []string{tlsTestFQDN, "127.0.0.1"} {
+		certPEM, keyPEM, issueErr := ca.IssueCert(host)
+		require.NoError(t, issueErr)
+		_, pairErr := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, pairErr)
+
+		roots := x509.NewCertPool()
+		require.True(t, roots.AppendCertsFromPEM(ca.CertPEM()))
+		leaf := leafOf(t, certPEM)
+		_, verifyErr := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+		require.NoError(t, verifyErr, "host %s", host)
+		assert.True(t, ca.Signed(leaf))
+	}
+
+	other, err := EnsureLocalCA(t.TempDir())
+	require.NoError(t, err)
+	certPEM, _, err := other.IssueCert(tlsTestFQDN)
+	require.NoError(t, err)
+	assert.False(t, ca.Signed(leafOf(t, certPEM)))
+}
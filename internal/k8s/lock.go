@@ -0,0 +1,360 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"deployah.dev/deployah/internal/spec"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// lockLeasePrefix prefixes the name of the Lease that locks a release.
+	lockLeasePrefix = "deployah-lock-"
+	// AnnotationLockOperation records the command holding a release lock.
+	AnnotationLockOperation = spec.LabelPrefix + "/lock-operation"
+	// AnnotationLockJobURL records the CI job holding a release lock.
+	AnnotationLockJobURL = spec.LabelPrefix + "/lock-job-url"
+	// DefaultLockTTL is how long a release lock outlives its last renewal.
+	// A holder renews at a third of it, so a lock left by a killed process
+	// expires within this window.
+	DefaultLockTTL = 2 * time.Minute
+)
+
+// ErrReleaseLocked is matched by the error [AcquireReleaseLock] returns
+// when another holder owns a live lock on the release.
+var ErrReleaseLocked = errors.New("release is locked")
+
+// ErrLockChanged is matched by the error [BreakReleaseLock] returns when
+// the lock was released and taken again since it was read.
+var ErrLockChanged = errors.New("lock changed hands")
+
+// LockOwner identifies who asks for a release lock.
+type LockOwner struct {
+	// Holder is a human-readable identity, such as "alice@laptop".
+	Holder string
+	// Operation is the command taking the lock: deploy, delete, rollback,
+	// or run.
+	Operation string
+	// JobURL links the CI job holding the lock; empty outside CI.
+	JobURL string
+}
+
+// LockInfo describes a release lock as recorded in its Lease.
+type LockInfo struct {
+	LockOwner
+	// Release is the Helm release the lock guards.
+	Release string
+	// AcquiredAt is when the current holder took the lock.
+	AcquiredAt time.Time
+	// ExpiresAt is when the lock lapses unless the holder renews it.
+	ExpiresAt time.Time
+	// UID and ResourceVersion identify the Lease as read, so
+	// [BreakReleaseLock] deletes only the lock that was shown.
+	UID             types.UID
+	ResourceVersion string
+}
+
+// Expired reports whether the lock has lapsed at now, so another holder
+// may take it over.
+func (l *LockInfo) Expired(now time.Time) bool {
+	return !l.ExpiresAt.IsZero() && now.After(l.ExpiresAt)
+}
+
+// String describes the holder for error messages and prompts, e.g.
+// "alice@laptop (deploy) since 12:04:05 UTC, expires 12:06:05 UTC, job
+// https://ci.example.com/123".
+func (l *LockInfo) String() string {
+	const layout = "2006-01-02 15:04:05 MST"
+	s := l.Holder
+	if l.Operation != "" {
+		s += " (" + l.Operation + ")"
+	}
+	if !l.AcquiredAt.IsZero() {
+		s += " since " + l.AcquiredAt.Format(layout)
+	}
+	if !l.ExpiresAt.IsZero() {
+		s += ", expires " + l.ExpiresAt.Format(layout)
+	}
+	if l.JobURL != "" {
+		s += ", job " + l.JobURL
+	}
+	return s
+}
+
+// LockedError is returned by [AcquireReleaseLock] when another holder owns
+// a live lock. It matches [ErrReleaseLocked] with errors.Is.
+type LockedError struct {
+	Lock LockInfo
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("release %s is locked by %s", e.Lock.Release, e.Lock.String())
+}
+
+// Is reports whether target is [ErrReleaseLocked].
+func (e *LockedError) Is(target error) bool {
+	return target == ErrReleaseLocked
+}
+
+// ReleaseLock is a held release lock. It renews itself in the background
+// until [ReleaseLock.Release] is called.
+type ReleaseLock struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	holder    string
+	ttl       time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// LockLeaseName returns the name of the Lease that locks release.
+func LockLeaseName(release string) string {
+	return lockLeasePrefix + release
+}
+
+// AcquireReleaseLock takes the lock on release by creating its Lease in
+// namespace, or by taking over a Lease whose holder let it expire. It
+// fails with a [*LockedError] while another holder's lock is live. The
+// lock expires ttl after its last renewal; the returned lock renews it
+// until released.
+func AcquireReleaseLock(ctx context.Context, client kubernetes.Interface, namespace, release string, owner LockOwner, ttl time.Duration) (*ReleaseLock, error) {
+	leases := client.CoordinationV1().Leases(namespace)
+	name := LockLeaseName(release)
+	now := metav1.NewMicroTime(time.Now())
+	seconds := int32(ttl / time.Second)
+
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				spec.LabelManagedBy: spec.ManagedByValue,
+			},
+		},
+	}
+	setLockOwner(lease, owner, now, seconds)
+	_, err := leases.Create(ctx, lease, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := leases.Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			return nil, fmt.Errorf("read lock %s/%s: %w", namespace, name, getErr)
+		}
+		info := lockInfoFromLease(release, existing)
+		if !info.Expired(now.Time) {
+			return nil, &LockedError{Lock: *info}
+		}
+		// Update carries the resourceVersion just read, so of two callers
+		// taking over the same expired lock only one succeeds.
+		setLockOwner(existing, owner, now, seconds)
+		_, err = leases.Update(ctx, existing, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			return nil, fmt.Errorf("release %s: %w: another holder took over the expired lock", release, ErrReleaseLocked)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("acquire lock %s/%s: %w", namespace, name, err)
+	}
+
+	renewCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	l := &ReleaseLock{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		holder:    owner.Holder,
+		ttl:       ttl,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go l.renew(renewCtx)
+	return l, nil
+}
+
+// AcquireInstallLock is [AcquireReleaseLock] for a command that installs
+// the release and may run before its namespace exists, such as a first
+// deploy. A missing namespace is created as [EnsureNamespace] does, with
+// labels and annotations, and the lock taken in it, so two first deploys
+// contend for the same Lease. created reports whether this call created
+// the namespace.
+func AcquireInstallLock(ctx context.Context, client kubernetes.Interface, namespace, release string, owner LockOwner, ttl time.Duration, labels, annotations map[string]string) (lock *ReleaseLock, created bool, err error) {
+	lock, err = AcquireReleaseLock(ctx, client, namespace, release, owner, ttl)
+	if !apierrors.IsNotFound(err) {
+		return lock, false, err
+	}
+	created, err = EnsureNamespace(ctx, client, namespace, labels, annotations)
+	if err != nil {
+		return nil, false, err
+	}
+	lock, err = AcquireReleaseLock(ctx, client, namespace, release, owner, ttl)
+	return lock, created, err
+}
+
+// renew pushes the lock's expiry forward every third of its TTL until ctx
+// is canceled. It stops early if the Lease was broken or taken over.
+func (l *ReleaseLock) renew(ctx context.Context) {
+	defer close(l.done)
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		leases := l.client.CoordinationV1().Leases(l.namespace)
+		lease, err := leases.Get(ctx, l.name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return
+			}
+			continue
+		}
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.holder {
+			return
+		}
+		lease.Spec.RenewTime = new(metav1.NewMicroTime(time.Now()))
+		// A failed renewal is retried on the next tick; the lock only
+		// lapses if renewals fail for the whole TTL.
+		_, _ = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	}
+}
+
+// Release stops renewing the lock and deletes its Lease, unless it was
+// broken or taken over in the meantime. It is safe to call more than once.
+func (l *ReleaseLock) Release(ctx context.Context) error {
+	var err error
+	l.once.Do(func() {
+		l.cancel()
+		<-l.done
+		leases := l.client.CoordinationV1().Leases(l.namespace)
+		lease, getErr := leases.Get(ctx, l.name, metav1.GetOptions{})
+		if apierrors.IsNotFound(getErr) {
+			return
+		}
+		if getErr != nil {
+			err = fmt.Errorf("release lock %s/%s: %w", l.namespace, l.name, getErr)
+			return
+		}
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.holder {
+			return
+		}
+		delErr := leases.Delete(ctx, l.name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &lease.UID, ResourceVersion: &lease.ResourceVersion},
+		})
+		if delErr != nil && !apierrors.IsNotFound(delErr) && !apierrors.IsConflict(delErr) {
+			err = fmt.Errorf("release lock %s/%s: %w", l.namespace, l.name, delErr)
+		}
+	})
+	return err
+}
+
+// GetReleaseLock returns the lock on release, or nil when it is not
+// locked. An expired lock is still returned; check [LockInfo.Expired].
+func GetReleaseLock(ctx context.Context, client kubernetes.Interface, namespace, release string) (*LockInfo, error) {
+	lease, err := client.CoordinationV1().Leases(namespace).Get(ctx, LockLeaseName(release), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read lock for %s: %w", release, err)
+	}
+	return lockInfoFromLease(release, lease), nil
+}
+
+// BreakReleaseLock deletes lock, as read by [GetReleaseLock]. The holder's
+// next renewal notices and stops; its operation keeps running. The delete
+// is conditional on the Lease read, like [ReleaseLock.Release], so a lock
+// released and taken by someone else since is left alone and the error
+// matches [ErrLockChanged]. A renewal by the same holder is not a change.
+func BreakReleaseLock(ctx context.Context, client kubernetes.Interface, namespace string, lock *LockInfo) error {
+	leases := client.CoordinationV1().Leases(namespace)
+	name := LockLeaseName(lock.Release)
+	uid, resourceVersion := lock.UID, lock.ResourceVersion
+	for {
+		err := leases.Delete(ctx, name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &uid, ResourceVersion: &resourceVersion},
+		})
+		if err == nil || apierrors.IsNotFound(err) {
+			return nil
+		}
+		if !apierrors.IsConflict(err) {
+			return fmt.Errorf("break lock for %s: %w", lock.Release, err)
+		}
+		lease, getErr := leases.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(getErr) {
+			return nil
+		}
+		if getErr != nil {
+			return fmt.Errorf("break lock for %s: %w", lock.Release, getErr)
+		}
+		current := lockInfoFromLease(lock.Release, lease)
+		if current.UID != lock.UID || current.Holder != lock.Holder || !current.AcquiredAt.Equal(lock.AcquiredAt) {
+			return fmt.Errorf("break lock for %s: %w: now held by %s", lock.Release, ErrLockChanged, current.String())
+		}
+		resourceVersion = current.ResourceVersion
+	}
+}
+
+func setLockOwner(lease *coordinationv1.Lease, owner LockOwner, now metav1.MicroTime, seconds int32) {
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[AnnotationLockOperation] = owner.Operation
+	if owner.JobURL != "" {
+		lease.Annotations[AnnotationLockJobURL] = owner.JobURL
+	} else {
+		delete(lease.Annotations, AnnotationLockJobURL)
+	}
+	lease.Spec.HolderIdentity = new(owner.Holder)
+	lease.Spec.LeaseDurationSeconds = new(seconds)
+	lease.Spec.AcquireTime = new(now)
+	lease.Spec.RenewTime = new(now)
+}
+
+func lockInfoFromLease(release string, lease *coordinationv1.Lease) *LockInfo {
+	info := &LockInfo{
+		Release: release,
+		LockOwner: LockOwner{
+			Operation: lease.Annotations[AnnotationLockOperation],
+			JobURL:    lease.Annotations[AnnotationLockJobURL],
+		},
+		UID:             lease.UID,
+		ResourceVersion: lease.ResourceVersion,
+	}
+	if lease.Spec.HolderIdentity != nil {
+		info.Holder = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.AcquireTime != nil {
+		info.AcquiredAt = lease.Spec.AcquireTime.Time
+	}
+	if lease.Spec.RenewTime != nil && lease.Spec.LeaseDurationSeconds != nil {
+		info.ExpiresAt = lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	}
+	return info
+}
@@ -0,0 +1,273 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"deployah.dev/deployah/internal/spec"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	lockAlice = LockOwner{Holder: "alice@laptop (pid 1)", Operation: "deploy", JobURL: "https://ci.example.com/jobs/1"}
+	lockBob   = LockOwner{Holder: "bob@runner (pid 2)", Operation: "rollback"}
+)
+
+// TestAcquireReleaseLock_Contended refuses a second holder while the first
+// holds the lock, and reports who holds it.
+func TestAcquireReleaseLock_Contended(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewClientset()
+	lock, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockAlice, DefaultLockTTL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lock.Release(t.Context()) })
+
+	_, err = AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockBob, DefaultLockTTL)
+	require.ErrorIs(t, err, ErrReleaseLocked)
+	var locked *LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, lockAlice, locked.Lock.LockOwner)
+	assert.Contains(t, err.Error(), "web-production is locked by alice@laptop (pid 1) (deploy)")
+	assert.Contains(t, err.Error(), "https://ci.example.com/jobs/1")
+
+	info, err := GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.False(t, info.Expired(time.Now()))
+	assert.WithinDuration(t, time.Now().Add(DefaultLockTTL), info.ExpiresAt, 5*time.Second)
+}
+
+// TestReleaseLock_Release deletes the Lease so the next holder can take
+// the lock.
+func TestReleaseLock_Release(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewClientset()
+	lock, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockAlice, DefaultLockTTL)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release(t.Context()))
+	require.NoError(t, lock.Release(t.Context()), "second release is a no-op")
+
+	info, err := GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+	assert.Nil(t, info)
+
+	next, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockBob, DefaultLockTTL)
+	require.NoError(t, err)
+	require.NoError(t, next.Release(t.Context()))
+}
+
+// TestAcquireReleaseLock_TakesOverExpired lets a new holder take a lock
+// whose holder stopped renewing it.
+func TestAcquireReleaseLock_TakesOverExpired(t *testing.T) {
+	t.Parallel()
+
+	stale := metav1.NewMicroTime(time.Now().Add(-time.Hour))
+	client := fake.NewClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: LockLeaseName("web-production"), Namespace: "shop"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       new("crashed@runner (pid 3)"),
+			LeaseDurationSeconds: new(int32(120)),
+			AcquireTime:          &stale,
+			RenewTime:            &stale,
+		},
+	})
+
+	lock, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockBob, DefaultLockTTL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lock.Release(t.Context()) })
+
+	info, err := GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+	assert.Equal(t, lockBob.Holder, info.Holder)
+	assert.Equal(t, "rollback", info.Operation)
+	assert.Empty(t, info.JobURL)
+}
+
+// TestBreakReleaseLock removes the lock that was read, and is a no-op when
+// the release is no longer locked.
+func TestBreakReleaseLock(t *testing.T) {
+	t.Parallel()
+
+	client := versionedLeaseClient()
+	lock, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockAlice, DefaultLockTTL)
+	require.NoError(t, err)
+	info, err := GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+
+	require.NoError(t, BreakReleaseLock(t.Context(), client, "shop", info))
+	require.NoError(t, BreakReleaseLock(t.Context(), client, "shop", info))
+	require.NoError(t, lock.Release(t.Context()), "releasing a broken lock is not an error")
+
+	info, err = GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+// TestBreakReleaseLock_Renewed still breaks a lock its holder renewed after
+// it was read.
+func TestBreakReleaseLock_Renewed(t *testing.T) {
+	t.Parallel()
+
+	client := versionedLeaseClient()
+	lock, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockAlice, DefaultLockTTL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lock.Release(t.Context()) })
+	info, err := GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+
+	leases := client.CoordinationV1().Leases("shop")
+	lease, err := leases.Get(t.Context(), LockLeaseName("web-production"), metav1.GetOptions{})
+	require.NoError(t, err)
+	lease.Spec.RenewTime = new(metav1.NewMicroTime(time.Now()))
+	_, err = leases.Update(t.Context(), lease, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, BreakReleaseLock(t.Context(), client, "shop", info))
+	info, err = GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+// TestBreakReleaseLock_ChangedHands leaves alone a lock that was released
+// and taken by someone else after it was read.
+func TestBreakReleaseLock_ChangedHands(t *testing.T) {
+	t.Parallel()
+
+	client := versionedLeaseClient()
+	lock, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockAlice, DefaultLockTTL)
+	require.NoError(t, err)
+	info, err := GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+	require.NoError(t, lock.Release(t.Context()))
+	lock, err = AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockBob, DefaultLockTTL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lock.Release(t.Context()) })
+
+	err = BreakReleaseLock(t.Context(), client, "shop", info)
+	require.ErrorIs(t, err, ErrLockChanged)
+	assert.Contains(t, err.Error(), "now held by bob@runner (pid 2)")
+
+	current, err := GetReleaseLock(t.Context(), client, "shop", "web-production")
+	require.NoError(t, err)
+	require.NotNil(t, current)
+	assert.Equal(t, lockBob, current.LockOwner)
+}
+
+// versionedLeaseClient returns a fake clientset that, like the API server,
+// stamps Leases with a UID and a resourceVersion on every write and
+// enforces delete preconditions.
+func versionedLeaseClient() *fake.Clientset {
+	client := fake.NewClientset()
+	var version atomic.Int64
+	stamp := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lease := action.(interface{ GetObject() runtime.Object }).GetObject().(*coordinationv1.Lease)
+		n := version.Add(1)
+		if action.GetVerb() == "create" {
+			lease.UID = types.UID(fmt.Sprintf("lease-%d", n))
+		}
+		lease.ResourceVersion = strconv.FormatInt(n, 10)
+		return false, nil, nil
+	}
+	client.PrependReactor("create", "leases", stamp)
+	client.PrependReactor("update", "leases", stamp)
+	client.PrependReactor("delete", "leases", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		del := action.(k8stesting.DeleteAction)
+		pre := del.GetDeleteOptions().Preconditions
+		if pre == nil {
+			return false, nil, nil
+		}
+		// The tracker, not the client: reactors run under the client's lock.
+		obj, err := client.Tracker().Get(coordinationv1.SchemeGroupVersion.WithResource("leases"), action.GetNamespace(), del.GetName())
+		if err != nil {
+			return false, nil, nil
+		}
+		lease := obj.(*coordinationv1.Lease)
+		if (pre.UID != nil && *pre.UID != lease.UID) || (pre.ResourceVersion != nil && *pre.ResourceVersion != lease.ResourceVersion) {
+			return true, nil, apierrors.NewConflict(coordinationv1.Resource("leases"), del.GetName(), errors.New("precondition failed"))
+		}
+		return false, nil, nil
+	})
+	return client
+}
+
+// namespacedLeaseClient returns a fake clientset that, like the API
+// server, refuses to create a Lease in a namespace that does not exist.
+func namespacedLeaseClient() *fake.Clientset {
+	client := fake.NewClientset()
+	client.PrependReactor("create", "leases", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		// The tracker, not the client: reactors run under the client's lock.
+		_, err := client.Tracker().Get(corev1.SchemeGroupVersion.WithResource("namespaces"), "", action.GetNamespace())
+		return err != nil, nil, err
+	})
+	return client
+}
+
+// TestAcquireInstallLock_FirstDeploys has two first deploys race for a
+// release whose namespace does not exist yet: one creates the namespace
+// and both contend for the same Lease, so exactly one holds the lock.
+func TestAcquireInstallLock_FirstDeploys(t *testing.T) {
+	t.Parallel()
+
+	client := namespacedLeaseClient()
+	_, err := AcquireReleaseLock(t.Context(), client, "shop", "web-production", lockAlice, DefaultLockTTL)
+	require.True(t, apierrors.IsNotFound(err), "no namespace, no lock: %v", err)
+
+	owners := []LockOwner{lockAlice, {Holder: "bob@runner (pid 2)", Operation: "deploy"}}
+	locks := make([]*ReleaseLock, len(owners))
+	errs := make([]error, len(owners))
+	var wg sync.WaitGroup
+	for i, owner := range owners {
+		wg.Go(func() {
+			locks[i], _, errs[i] = AcquireInstallLock(t.Context(), client, "shop", "web-production", owner, DefaultLockTTL,
+				map[string]string{"team": "payments"}, nil)
+		})
+	}
+	wg.Wait()
+
+	held := 0
+	for i, lockErr := range errs {
+		if lockErr == nil {
+			held++
+			require.NoError(t, locks[i].Release(t.Context()))
+			continue
+		}
+		require.ErrorIs(t, lockErr, ErrReleaseLocked)
+	}
+	assert.Equal(t, 1, held, "exactly one first deploy holds the lock")
+
+	ns, err := client.CoreV1().Namespaces().Get(t.Context(), "shop", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", spec.LabelManagedBy: spec.ManagedByValue}, ns.Labels)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"k8s.io/client-go/kubernetes"
+
+	"deployah.dev/deployah/internal/spec"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnsureNamespace creates namespace with the given labels and annotations
+// when it does not exist, marking it managed by Deployah. On an existing
+// namespace each given label and annotation is set, overwriting a
+// different value under the same key; keys not given are left alone.
+// created reports whether the namespace was created.
+func EnsureNamespace(ctx context.Context, client kubernetes.Interface, namespace string, labels, annotations map[string]string) (created bool, err error) {
+	ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		nsLabels := maps.Clone(labels)
+		if nsLabels == nil {
+			nsLabels = map[string]string{}
+		}
+		nsLabels[spec.LabelManagedBy] = spec.ManagedByValue
+		_, err = client.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        namespace,
+				Labels:      nsLabels,
+				Annotations: annotations,
+			},
+		}, metav1.CreateOptions{})
+		if err == nil {
+			return true, nil
+		}
+		if !apierrors.IsAlreadyExists(err) {
+			return false, fmt.Errorf("create namespace %s: %w", namespace, err)
+		}
+		// A concurrent deploy created it first; fall through to merge
+		// metadata into theirs.
+		ns, err = client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	}
+	if err != nil {
+		return false, fmt.Errorf("get namespace %s: %w", namespace, err)
+	}
+
+	changed := setEntries(&ns.Labels, labels)
+	changed = setEntries(&ns.Annotations, annotations) || changed
+	if !changed {
+		return false, nil
+	}
+	if _, err := client.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{}); err != nil {
+		return false, fmt.Errorf("update namespace %s metadata: %w", namespace, err)
+	}
+	return false, nil
+}
+
+// setEntries copies want into *dst, allocating it when nil and overwriting
+// entries with a different value, and reports whether any entry was added
+// or changed.
+func setEntries(dst *map[string]string, want map[string]string) bool {
+	changed := false
+	for k, v := range want {
+		if cur, ok := (*dst)[k]; ok && cur == v {
+			continue
+		}
+		if *dst == nil {
+			*dst = make(map[string]string, len(want))
+		}
+		(*dst)[k] = v
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"deployah.dev/deployah/internal/spec"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestEnsureNamespace_Creates creates a missing namespace with the platform
+// labels and annotations plus the managed-by label.
+func TestEnsureNamespace_Creates(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewClientset()
+	created, err := EnsureNamespace(t.Context(), client, "shop-production",
+		map[string]string{"team": "payments"}, map[string]string{"owner": "payments@example.com"})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	ns, err := client.CoreV1().Namespaces().Get(t.Context(), "shop-production", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", spec.LabelManagedBy: spec.ManagedByValue}, ns.Labels)
+	assert.Equal(t, map[string]string{"owner": "payments@example.com"}, ns.Annotations)
+}
+
+// TestEnsureNamespace_MergesIntoExisting adds platform metadata to an
+// existing namespace without dropping what others set, and leaves a
+// namespace that already matches untouched.
+func TestEnsureNamespace_MergesIntoExisting(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "shop-production",
+		Labels: map[string]string{"team": "old", "istio-injection": "enabled"},
+	}})
+	created, err := EnsureNamespace(t.Context(), client, "shop-production", map[string]string{"team": "payments"}, nil)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	ns, err := client.CoreV1().Namespaces().Get(t.Context(), "shop-production", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "istio-injection": "enabled"}, ns.Labels)
+
+	client.ClearActions()
+	_, err = EnsureNamespace(t.Context(), client, "shop-production", map[string]string{"team": "payments"}, nil)
+	require.NoError(t, err)
+	for _, action := range client.Actions() {
+		assert.Equal(t, "get", action.GetVerb(), "matching namespace must not be updated")
+	}
+}
+
+// TestEnsureNamespace_OverwritesChangedValues sets a label and an
+// annotation whose value changed in the platform file, and keeps the
+// entries it does not manage.
+func TestEnsureNamespace_OverwritesChangedValues(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "shop-production",
+		Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "privileged", "istio-injection": "enabled"},
+		Annotations: map[string]string{"owner": "platform-team", "note": "kept"},
+	}})
+	created, err := EnsureNamespace(t.Context(), client, "shop-production",
+		map[string]string{"pod-security.kubernetes.io/enforce": "baseline"}, map[string]string{"owner": "payments@example.com"})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	ns, err := client.CoreV1().Namespaces().Get(t.Context(), "shop-production", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod-security.kubernetes.io/enforce": "baseline", "istio-injection": "enabled"}, ns.Labels)
+	assert.Equal(t, map[string]string{"owner": "payments@example.com", "note": "kept"}, ns.Annotations)
+}
@@ -17,7 +17,10 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/distribution/reference"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 
@@ -44,17 +47,7 @@ func (c *Client) GetRunningPods(ctx context.Context, project, component, environ
 	// Convert to PodInfo
 	podInfos := make([]PodInfo, 0, len(pods.Items))
 	for _, pod := range pods.Items {
-		containers := make([]string, 0, len(pod.Spec.Containers))
-		for _, container := range pod.Spec.Containers {
-			containers = append(containers, container.Name)
-		}
-
-		podInfos = append(podInfos, PodInfo{
-			Name:       pod.Name,
-			Namespace:  pod.Namespace,
-			Containers: containers,
-			Status:     string(pod.Status.Phase),
-		})
+		podInfos = append(podInfos, newPodInfo(&pod))
 	}
 
 	return podInfos, nil
@@ -67,17 +60,97 @@ func (c *Client) GetPodInfo(ctx context.Context, podName string) (*PodInfo, erro
 		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
 	}
 
+	info := newPodInfo(pod)
+	return &info, nil
+}
+
+// ListContainerNames returns the distinct container and init container
+// names across pods matching selector, in any phase, each sorted.
+func (c *Client) ListContainerNames(ctx context.Context, selector string) (containers, initContainers []string, err error) {
+	pods, err := c.k8sClient.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		info := newPodInfo(&pod)
+		containers = append(containers, info.Containers...)
+		initContainers = append(initContainers, info.InitContainers...)
+	}
+	slices.Sort(containers)
+	slices.Sort(initContainers)
+	return slices.Compact(containers), slices.Compact(initContainers), nil
+}
+
+// RunningImageDigest returns the digest, such as "sha256:ab12...", of
+// image, a "repository:tag" reference, as container runs it in the running
+// pods matching selector. Only containers whose spec or reported image is
+// image count, so pods of an older or newer rollout are ignored. It
+// returns "" when no such container reports a registry digest or they
+// disagree, as they do when the tag moved between pulls. Images loaded
+// straight into a node have no registry digest.
+func (c *Client) RunningImageDigest(ctx context.Context, selector, container, image string) (string, error) {
+	pods, err := c.k8sClient.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", err)
+	}
+	digest := ""
+	for _, pod := range pods.Items {
+		specImage := ""
+		for _, ctr := range pod.Spec.Containers {
+			if ctr.Name == container {
+				specImage = ctr.Image
+			}
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != container || (!sameImage(specImage, image) && !sameImage(status.Image, image)) {
+				continue
+			}
+			_, d, ok := strings.Cut(status.ImageID, "@")
+			if !ok || (digest != "" && d != digest) {
+				return "", nil
+			}
+			digest = d
+		}
+	}
+	return digest, nil
+}
+
+// sameImage reports whether the image references a and b name the same
+// repository and tag once normalized, so "nginx:1.27" matches
+// "docker.io/library/nginx:1.27".
+func sameImage(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	namedA, errA := reference.ParseNormalizedNamed(a)
+	namedB, errB := reference.ParseNormalizedNamed(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return reference.TagNameOnly(namedA).String() == reference.TagNameOnly(namedB).String()
+}
+
+func newPodInfo(pod *corev1.Pod) PodInfo {
 	containers := make([]string, 0, len(pod.Spec.Containers))
 	for _, container := range pod.Spec.Containers {
 		containers = append(containers, container.Name)
 	}
-
-	return &PodInfo{
-		Name:       pod.Name,
-		Namespace:  pod.Namespace,
-		Containers: containers,
-		Status:     string(pod.Status.Phase),
-	}, nil
+	var initContainers []string
+	for _, container := range pod.Spec.InitContainers {
+		initContainers = append(initContainers, container.Name)
+	}
+	return PodInfo{
+		Name:           pod.Name,
+		Namespace:      pod.Namespace,
+		Containers:     containers,
+		InitContainers: initContainers,
+		Status:         string(pod.Status.Phase),
+	}
 }
 
 // GetPodStatus retrieves pod status information for a release.
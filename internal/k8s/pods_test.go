@@ -0,0 +1,111 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestListContainerNames(t *testing.T) {
+	t.Parallel()
+
+	pod := func(name string, labels map[string]string, inits []string, containers ...string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+		for _, c := range containers {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: c})
+		}
+		for _, c := range inits {
+			p.Spec.InitContainers = append(p.Spec.InitContainers, corev1.Container{Name: c})
+		}
+		return p
+	}
+	api := map[string]string{ProjectLabel: "shop", ComponentLabel: "api"}
+	cs := fake.NewClientset(
+		pod("api-1", api, []string{"migrate"}, "api", "log-shipper"),
+		pod("api-2", api, []string{"migrate"}, "api", "log-shipper"),
+		pod("web-1", map[string]string{ProjectLabel: "shop", ComponentLabel: "web"}, nil, "web"),
+	)
+	client := NewClient(cs, "default")
+
+	selector, err := BuildSelector("shop", "api", "")
+	require.NoError(t, err)
+	containers, inits, err := client.ListContainerNames(t.Context(), selector)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "log-shipper"}, containers)
+	assert.Equal(t, []string{"migrate"}, inits)
+}
+
+func TestRunningImageDigest(t *testing.T) {
+	t.Parallel()
+
+	const (
+		digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+	pod := func(name, component, image, imageID string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{ProjectLabel: "shop", ComponentLabel: component}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: component, Image: image}, {Name: "log-shipper", Image: "fluent/fluent-bit:3.0"}},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: component, Image: image, ImageID: imageID},
+					{Name: "log-shipper", Image: "docker.io/fluent/fluent-bit:3.0", ImageID: "docker.io/fluent/fluent-bit@" + digestB},
+				},
+			},
+		}
+	}
+	client := NewClient(fake.NewClientset(
+		pod("api-1", "api", "ghcr.io/acme/api:1.4.0", "ghcr.io/acme/api@"+digestA),
+		pod("api-2", "api", "ghcr.io/acme/api:1.4.0", "docker-pullable://ghcr.io/acme/api@"+digestA),
+		pod("api-3", "api", "ghcr.io/acme/api:1.5.0", "ghcr.io/acme/api@"+digestB),
+		pod("web-1", "web", "ghcr.io/acme/web:2.0", "ghcr.io/acme/web@"+digestA),
+		pod("web-2", "web", "ghcr.io/acme/web:2.0", "ghcr.io/acme/web@"+digestB),
+		pod("proxy-1", "proxy", "nginx:1.27", "docker.io/library/nginx@"+digestA),
+		pod("worker-1", "worker", "ghcr.io/acme/worker:1.0", digestA),
+	), "default")
+
+	tests := []struct {
+		component string
+		image     string
+		want      string
+	}{
+		{component: "api", image: "ghcr.io/acme/api:1.4.0", want: digestA},
+		{component: "api", image: "ghcr.io/acme/api:1.5.0", want: digestB},
+		{component: "api", image: "ghcr.io/acme/api:1.3.0", want: ""},
+		{component: "web", image: "ghcr.io/acme/web:2.0", want: ""},
+		{component: "proxy", image: "docker.io/library/nginx:1.27", want: digestA},
+		{component: "worker", image: "ghcr.io/acme/worker:1.0", want: ""},
+		{component: "missing", image: "ghcr.io/acme/missing:1.0", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			t.Parallel()
+			selector, err := BuildSelector("shop", tt.component, "")
+			require.NoError(t, err)
+			got, err := client.RunningImageDigest(t.Context(), selector, tt.component, tt.image)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -23,8 +23,8 @@ import (
 
 // RequiredAPIs derives the Kubernetes API group/version requirements for
 // components deployed in the target environment, including cert-manager
-// (TLS mode) via the resolved spec and prometheus-operator when metrics are
-// enabled.
+// (TLS mode) and the Gateway API (gateway-mode domains) via the resolved
+// spec and prometheus-operator when metrics are enabled.
 func RequiredAPIs(manifest *spec.Spec, environment string, resolved *spec.ResolvedSpec) []APIRequirement {
 	type entry struct {
 		groupVersions []string
@@ -54,16 +54,22 @@ func RequiredAPIs(manifest *spec.Spec, environment string, resolved *spec.Resolv
 		if component.Autoscaling != nil && component.Autoscaling.Enabled {
 			add([]string{"autoscaling/v2", "autoscaling/v2beta2"}, name)
 		}
+		var rc spec.ResolvedComponent
+		if resolved != nil {
+			rc = resolved.Components[name]
+		}
 		if component.Expose != nil {
-			add([]string{"networking.k8s.io/v1"}, name)
+			if rc.GatewayRef != nil {
+				add([]string{"gateway.networking.k8s.io/v1"}, name)
+			} else {
+				add([]string{"networking.k8s.io/v1"}, name)
+			}
 		}
 		if component.Metrics.IsEnabled() {
 			add([]string{"monitoring.coreos.com/v1"}, name)
 		}
-		if resolved != nil {
-			if rc, ok := resolved.Components[name]; ok && rc.TLSMode == spec.TLSModeCertManager {
-				add([]string{"cert-manager.io/v1"}, name)
-			}
+		if rc.TLSMode == spec.TLSModeCertManager {
+			add([]string{"cert-manager.io/v1"}, name)
 		}
 	}
 
@@ -87,6 +87,24 @@ func TestRequiredAPIs(t *testing.T) {
 			},
 			wantGVs: []string{"networking.k8s.io/v1", "cert-manager.io/v1"},
 		},
+		{
+			name: "gateway-mode domain requires gateway API",
+			manifest: &spec.Spec{
+				Components: map[string]spec.Component{
+					"api": {Expose: &spec.Expose{}},
+				},
+			},
+			environment: "production",
+			resolved: &spec.ResolvedSpec{
+				Components: map[string]spec.ResolvedComponent{
+					"api": {
+						TLSMode:    spec.TLSModeCertManager,
+						GatewayRef: &spec.GatewayRef{Name: "public", Namespace: "gateway-system"},
+					},
+				},
+			},
+			wantGVs: []string{"gateway.networking.k8s.io/v1", "cert-manager.io/v1"},
+		},
 		{
 			name: "autoscaling and shared monitoring noun is plural",
 			manifest: &spec.Spec{
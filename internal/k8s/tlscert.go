@@ -15,12 +15,17 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 
 	"deployah.dev/deployah/internal/spec"
@@ -32,8 +37,8 @@ import (
 )
 
 // selfSignedCertMaxAge is the validity window for a generated self-signed
-// certificate. Chosen to comfortably outlive typical local/dev cluster
-// lifetimes without requiring rotation.
+// certificate when no local CA signs it. Chosen to comfortably outlive
+// typical local/dev cluster lifetimes without requiring rotation.
 const selfSignedCertMaxAge = 3650 * 24 * time.Hour
 
 // selfSignedCertRenewBefore is how far ahead of expiry a reused certificate
@@ -50,16 +55,18 @@ func selfSignedSecretName(fqdn string) string {
 
 // EnsureSelfSignedCert returns a PEM cert/key pair for fqdn, reusing the
 // existing `kubernetes.io/tls` Secret named `<fqdn>-tls` in namespace when
-// present and not close to expiry. Otherwise it generates a new self-signed
-// pair. Reuse (rather than regenerating on every call) is what keeps the
+// present and not close to expiry, whichever CA issued it. Otherwise it
+// issues a new pair with ca, or generates a standalone self-signed one when
+// ca is nil.
+// Reuse (rather than regenerating on every call) is what keeps the
 // rendered manifest identical across plan/apply/re-deploy: a fresh keypair
 // every render would defeat both the apply-time verification and
 // skip-on-no-change.
-func EnsureSelfSignedCert(ctx context.Context, client kubernetes.Interface, namespace, fqdn string) (certPEM, keyPEM []byte, err error) {
+func EnsureSelfSignedCert(ctx context.Context, client kubernetes.Interface, ca *LocalCA, namespace, fqdn string) (certPEM, keyPEM []byte, err error) {
 	secret, getErr := client.CoreV1().Secrets(namespace).Get(ctx, selfSignedSecretName(fqdn), metav1.GetOptions{})
 	switch {
 	case getErr == nil:
-		if reusable, crt, key := reusableCert(secret, fqdn); reusable {
+		if reusable, crt, key := reusableCert(secret, fqdn, ca); reusable {
 			return crt, key, nil
 		}
 	case apierrors.IsNotFound(getErr):
@@ -68,12 +75,25 @@ func EnsureSelfSignedCert(ctx context.Context, client kubernetes.Interface, name
 		return nil, nil, fmt.Errorf("get TLS secret %s: %w", selfSignedSecretName(fqdn), getErr)
 	}
 
-	return GenerateSelfSignedCert(fqdn)
+	return issueCert(ca, fqdn)
+}
+
+// issueCert issues a cert/key pair for fqdn with ca, or generates a
+// standalone self-signed one when ca is nil.
+func issueCert(ca *LocalCA, fqdn string) (certPEM, keyPEM []byte, err error) {
+	if ca == nil {
+		return GenerateSelfSignedCert(fqdn)
+	}
+	return ca.IssueCert(fqdn)
 }
 
 // reusableCert reports whether secret holds a still-valid TLS keypair for
-// fqdn, returning the stored PEM bytes when it does.
-func reusableCert(secret *corev1.Secret, fqdn string) (ok bool, certPEM, keyPEM []byte) {
+// fqdn, returning the stored PEM bytes when it does. Any CA will do, so
+// deploys by people or CI runners with different local CAs do not rotate
+// each other's certificate. Only a standalone certificate from before the
+// local CA existed is not reusable when ca is set, so the next deploy
+// replaces it with a trusted one.
+func reusableCert(secret *corev1.Secret, fqdn string, ca *LocalCA) (ok bool, certPEM, keyPEM []byte) {
 	if secret.Type != corev1.SecretTypeTLS {
 		return false, nil, nil
 	}
@@ -97,10 +117,33 @@ func reusableCert(secret *corev1.Secret, fqdn string) (ok bool, certPEM, keyPEM
 	if err := leaf.VerifyHostname(fqdn); err != nil {
 		return false, nil, nil
 	}
+	if ca != nil && standaloneCert(leaf, crt) {
+		return false, nil, nil
+	}
 
 	return true, crt, key
 }
 
+// standaloneCert reports whether leaf, the first certificate of certPEM,
+// is one [GenerateSelfSignedCert] made: either self-issued, or signed by a
+// throwaway root that certPEM carries along with it. Certificates from a
+// local CA hold only the leaf.
+func standaloneCert(leaf *x509.Certificate, certPEM []byte) bool {
+	if bytes.Equal(leaf.RawIssuer, leaf.RawSubject) {
+		return true
+	}
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil {
+		return false
+	}
+	for _, issuer := range certs[1:] {
+		if bytes.Equal(issuer.RawIssuer, issuer.RawSubject) && leaf.CheckSignatureFrom(issuer) == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // GenerateSelfSignedCert generates a fresh self-signed PEM cert/key pair for
 // fqdn without any cluster access. Used for the plan --offline render, where
 // there is no cluster to fetch a reusable secret from.
@@ -137,8 +180,9 @@ func HasSelfSignedComponents(resolved *spec.ResolvedSpec) bool {
 // before any chart render, so the plan render, apply-time verification
 // render, and real apply all see identical certificate bytes. Pass a nil
 // client to force offline generation (no cluster access), as plan --offline
-// does.
-func MaterializeSelfSignedTLS(ctx context.Context, client kubernetes.Interface, namespace string, resolved *spec.ResolvedSpec) error {
+// does. New certificates are issued by ca, the user's [LocalCA]; a nil ca
+// falls back to standalone self-signed certificates.
+func MaterializeSelfSignedTLS(ctx context.Context, client kubernetes.Interface, ca *LocalCA, namespace string, resolved *spec.ResolvedSpec) error {
 	if resolved == nil {
 		return nil
 	}
@@ -153,9 +197,9 @@ func MaterializeSelfSignedTLS(ctx context.Context, client kubernetes.Interface,
 			err             error
 		)
 		if client != nil {
-			certPEM, keyPEM, err = EnsureSelfSignedCert(ctx, client, namespace, rc.FQDN)
+			certPEM, keyPEM, err = EnsureSelfSignedCert(ctx, client, ca, namespace, rc.FQDN)
 		} else {
-			certPEM, keyPEM, err = GenerateSelfSignedCert(rc.FQDN)
+			certPEM, keyPEM, err = issueCert(ca, rc.FQDN)
 		}
 		if err != nil {
 			return fmt.Errorf("component %s: %w", name, err)
@@ -168,3 +212,105 @@ func MaterializeSelfSignedTLS(ctx context.Context, client kubernetes.Interface,
 
 	return nil
 }
+
+// SelfSignedCert describes the TLS Secret of a selfSigned component, as
+// [RenewSelfSignedCerts] finds it.
+type SelfSignedCert struct {
+	Namespace  string
+	SecretName string
+	// Host is the component's FQDN the certificate is for.
+	Host string
+	// NotAfter is when the certificate expires, after any renewal.
+	NotAfter time.Time
+	// Trusted reports whether the local CA issued the certificate.
+	Trusted bool
+	// Renewed reports whether the certificate was re-issued because it was
+	// close to expiring.
+	Renewed bool
+}
+
+// RenewSelfSignedCerts lists the TLS Secrets of selfSigned components in
+// namespace (all namespaces when empty) that match selector, which may be
+// nil. It re-issues with ca, in place, every certificate within the renewal
+// window of its expiry; the next deploy then reuses the renewed Secret. A
+// nil ca only reports.
+func RenewSelfSignedCerts(ctx context.Context, client kubernetes.Interface, ca *LocalCA, namespace string, selector labels.Selector) ([]SelfSignedCert, error) {
+	// Deployah labels every Secret it renders; the selfSigned ones are the
+	// TLS Secrets named after their host.
+	managed, err := labels.NewRequirement(ProjectLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, fmt.Errorf("project label: %w", err)
+	}
+	if selector == nil {
+		selector = labels.NewSelector()
+	}
+	list, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.Add(*managed).String()})
+	if err != nil {
+		return nil, fmt.Errorf("list TLS secrets: %w", err)
+	}
+
+	var certs []SelfSignedCert
+	for i := range list.Items {
+		secret := &list.Items[i]
+		host, ok := strings.CutSuffix(secret.Name, "-tls")
+		if !ok || secret.Type != corev1.SecretTypeTLS {
+			continue
+		}
+		leaf, parseErr := leafCert(secret.Data[corev1.TLSCertKey])
+		if parseErr != nil || leaf.VerifyHostname(host) != nil {
+			continue
+		}
+		cert := SelfSignedCert{
+			Namespace:  secret.Namespace,
+			SecretName: secret.Name,
+			Host:       host,
+			NotAfter:   leaf.NotAfter,
+			Trusted:    ca != nil && ca.Signed(leaf),
+		}
+		if ca != nil && time.Until(leaf.NotAfter) < selfSignedCertRenewBefore {
+			if cert.NotAfter, err = renewCert(ctx, client, ca, secret, host); err != nil {
+				return certs, err
+			}
+			cert.Trusted, cert.Renewed = true, true
+		}
+		certs = append(certs, cert)
+	}
+	sort.Slice(certs, func(i, j int) bool {
+		if certs[i].Namespace != certs[j].Namespace {
+			return certs[i].Namespace < certs[j].Namespace
+		}
+		return certs[i].Host < certs[j].Host
+	})
+	return certs, nil
+}
+
+// renewCert re-issues the certificate in secret for host with ca and
+// returns its new expiry.
+func renewCert(ctx context.Context, client kubernetes.Interface, ca *LocalCA, secret *corev1.Secret, host string) (time.Time, error) {
+	certPEM, keyPEM, err := ca.IssueCert(host)
+	if err != nil {
+		return time.Time{}, err
+	}
+	leaf, err := leafCert(certPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+	renewed := secret.DeepCopy()
+	renewed.Data = map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+	}
+	if _, err = client.CoreV1().Secrets(secret.Namespace).Update(ctx, renewed, metav1.UpdateOptions{}); err != nil {
+		return time.Time{}, fmt.Errorf("renew TLS secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return leaf.NotAfter, nil
+}
+
+// leafCert parses the first certificate of certPEM.
+func leafCert(certPEM []byte) (*x509.Certificate, error) {
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse certificate: %w", err)
+	}
+	return certs[0], nil
+}
@@ -27,6 +27,7 @@ import (
 	"deployah.dev/deployah/internal/spec"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	certutil "k8s.io/client-go/util/cert"
 )
 
@@ -130,7 +131,7 @@ func TestEnsureSelfSignedCert(t *testing.T) {
 				client = fake.NewClientset(tt.seed)
 			}
 
-			gotCert, gotKey, ensureErr := EnsureSelfSignedCert(t.Context(), client, testNamespace, tlsTestFQDN)
+			gotCert, gotKey, ensureErr := EnsureSelfSignedCert(t.Context(), client, nil, testNamespace, tlsTestFQDN)
 			require.NoError(t, ensureErr)
 			require.NotEmpty(t, gotCert)
 			require.NotEmpty(t, gotKey)
@@ -160,7 +161,7 @@ func TestMaterializeSelfSignedTLS(t *testing.T) {
 
 	t.Run("nil resolved is a no-op", func(t *testing.T) {
 		t.Parallel()
-		require.NoError(t, MaterializeSelfSignedTLS(t.Context(), fake.NewClientset(), testNamespace, nil))
+		require.NoError(t, MaterializeSelfSignedTLS(t.Context(), fake.NewClientset(), nil, testNamespace, nil))
 	})
 
 	t.Run("materializes only selfSigned components, offline with nil client", func(t *testing.T) {
@@ -172,7 +173,7 @@ func TestMaterializeSelfSignedTLS(t *testing.T) {
 			},
 		}
 
-		require.NoError(t, MaterializeSelfSignedTLS(t.Context(), nil, testNamespace, resolved))
+		require.NoError(t, MaterializeSelfSignedTLS(t.Context(), nil, nil, testNamespace, resolved))
 
 		web := resolved.Components["web"]
 		assert.NotEmpty(t, web.TLSCertPEM)
@@ -194,8 +195,101 @@ func TestMaterializeSelfSignedTLS(t *testing.T) {
 			},
 		}
 
-		require.NoError(t, MaterializeSelfSignedTLS(t.Context(), client, testNamespace, resolved))
+		require.NoError(t, MaterializeSelfSignedTLS(t.Context(), client, nil, testNamespace, resolved))
 		assert.Equal(t, certPEM, resolved.Components["web"].TLSCertPEM)
 		assert.Equal(t, keyPEM, resolved.Components["web"].TLSKeyPEM)
 	})
 }
+
+// TestEnsureSelfSignedCert_localCA issues certificates with the local CA,
+// replaces a standalone certificate from before it, and reuses one any
+// local CA issued.
+func TestEnsureSelfSignedCert_localCA(t *testing.T) {
+	t.Parallel()
+	ca, err := EnsureLocalCA(t.TempDir())
+	require.NoError(t, err)
+
+	standaloneCert, standaloneKey, err := GenerateSelfSignedCert(tlsTestFQDN)
+	require.NoError(t, err)
+	client := fake.NewClientset(tlsSecret(standaloneCert, standaloneKey, corev1.SecretTypeTLS))
+
+	gotCert, _, err := EnsureSelfSignedCert(t.Context(), client, ca, testNamespace, tlsTestFQDN)
+	require.NoError(t, err)
+	assert.NotEqual(t, standaloneCert, gotCert, "a certificate from before the local CA must be replaced")
+	leaf := leafOf(t, gotCert)
+	assert.True(t, ca.Signed(leaf))
+	assert.WithinDuration(t, time.Now().Add(localCACertMaxAge), leaf.NotAfter, 24*time.Hour)
+
+	issuedCert, issuedKey, err := ca.IssueCert(tlsTestFQDN)
+	require.NoError(t, err)
+	client = fake.NewClientset(tlsSecret(issuedCert, issuedKey, corev1.SecretTypeTLS))
+	gotCert, _, err = EnsureSelfSignedCert(t.Context(), client, ca, testNamespace, tlsTestFQDN)
+	require.NoError(t, err)
+	assert.Equal(t, issuedCert, gotCert, "a certificate from the local CA must be reused")
+
+	other, err := EnsureLocalCA(t.TempDir())
+	require.NoError(t, err)
+	otherCert, otherKey, err := other.IssueCert(tlsTestFQDN)
+	require.NoError(t, err)
+	client = fake.NewClientset(tlsSecret(otherCert, otherKey, corev1.SecretTypeTLS))
+	gotCert, _, err = EnsureSelfSignedCert(t.Context(), client, ca, testNamespace, tlsTestFQDN)
+	require.NoError(t, err)
+	assert.Equal(t, otherCert, gotCert, "a certificate from another user's local CA must be reused")
+}
+
+// TestRenewSelfSignedCerts reports the TLS secrets of selfSigned
+// components and re-issues only those close to expiring.
+func TestRenewSelfSignedCerts(t *testing.T) {
+	t.Parallel()
+	ca, err := EnsureLocalCA(t.TempDir())
+	require.NoError(t, err)
+
+	const expiringFQDN = "api.127.0.0.1.nip.io"
+	validCert, validKey, err := ca.IssueCert(tlsTestFQDN)
+	require.NoError(t, err)
+	expiringCert, expiringKey, err := certutil.GenerateSelfSignedCertKeyWithOptions(certutil.SelfSignedCertKeyOptions{
+		Host:         expiringFQDN,
+		AlternateDNS: []string{expiringFQDN},
+		MaxAge:       1 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	managed := func(secret *corev1.Secret) *corev1.Secret {
+		secret.Labels = map[string]string{ProjectLabel: "shop"}
+		return secret
+	}
+	valid := managed(tlsSecret(validCert, validKey, corev1.SecretTypeTLS))
+	expiring := managed(tlsSecret(expiringCert, expiringKey, corev1.SecretTypeTLS))
+	expiring.Name = selfSignedSecretName(expiringFQDN)
+	unmanaged := tlsSecret(expiringCert, expiringKey, corev1.SecretTypeTLS)
+	unmanaged.Name = "other-tls"
+	unmanaged.Namespace = "other"
+	client := fake.NewClientset(valid, expiring, unmanaged)
+
+	t.Run("without a CA only reports", func(t *testing.T) {
+		t.Parallel()
+		reportOnly := fake.NewClientset(managed(tlsSecret(expiringCert, expiringKey, corev1.SecretTypeTLS)))
+		got, renewErr := RenewSelfSignedCerts(t.Context(), reportOnly, nil, "", nil)
+		require.NoError(t, renewErr)
+		require.Len(t, got, 1)
+		assert.False(t, got[0].Renewed)
+		assert.False(t, got[0].Trusted)
+	})
+
+	got, err := RenewSelfSignedCerts(t.Context(), client, ca, "", nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, expiringFQDN, got[0].Host)
+	assert.True(t, got[0].Renewed)
+	assert.True(t, got[0].Trusted)
+	assert.WithinDuration(t, time.Now().Add(localCACertMaxAge), got[0].NotAfter, 24*time.Hour)
+
+	assert.Equal(t, tlsTestFQDN, got[1].Host)
+	assert.False(t, got[1].Renewed)
+	assert.True(t, got[1].Trusted)
+
+	renewed, err := client.CoreV1().Secrets(testNamespace).Get(t.Context(), expiring.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, ca.Signed(leafOf(t, renewed.Data[corev1.TLSCertKey])))
+}
@@ -23,6 +23,8 @@ type PodInfo struct {
 	Namespace string
 	// Containers lists container names in the pod.
 	Containers []string
+	// InitContainers lists init container names in the pod, in run order.
+	InitContainers []string
 	// Status is the pod phase or ready summary string.
 	Status string
 }
@@ -41,9 +41,12 @@ type createConfig struct {
 	waitTimeout     time.Duration // how long to wait for nodes to become Ready
 	retainOnFail    bool          // keep the partial cluster on failure
 	portMappings    []PortMapping
-	rawKindConfig   []byte // WithKindConfig escape hatch; takes priority over portMappings
-	createIfMissing bool   // swallow ErrAlreadyExists when cluster already exists
-	kubeconfigPath  string // path for Kind to write its kubeconfig (never touches ~/.kube/config)
+	nodes           []NodeConfig     // empty → a single control-plane node
+	featureGates    map[string]bool  // Kubernetes feature gates for every component
+	rawKindConfig   []byte           // WithKindConfig escape hatch; takes priority over the above
+	mirrors         []RegistryMirror // written to the nodes after Kind creates them
+	createIfMissing bool             // swallow ErrAlreadyExists when cluster already exists
+	kubeconfigPath  string           // path for Kind to write its kubeconfig (never touches ~/.kube/config)
 }
 
 // emit fires ev on both the per-call handler (if set) and the manager-level
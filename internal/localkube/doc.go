@@ -32,13 +32,28 @@
 //	kc, err := m.KubeConfig(ctx, "dev")
 //	_ = kc.Path() // stable path on disk
 //
+// # Multi-node clusters
+//
+// Create makes a single control-plane node by default. [WithNodes] replaces
+// it with a node list; each [NodeConfig] carries its own labels, taints,
+// port mappings, and host mounts. [WithFeatureGates] toggles Kubernetes
+// feature gates on every component.
+//
+//	err := m.Create(ctx, "dev", localkube.WithNodes(
+//		localkube.NodeConfig{Role: localkube.NodeRoleControlPlane},
+//		localkube.NodeConfig{Role: localkube.NodeRoleWorker, Labels: map[string]string{"zone": "a"}},
+//	))
+//
 // # Image loading
 //
-// Two methods are available:
+// Three methods are available:
 //
 //   - [Manager.LoadImage] resolves an image reference (daemon image, remote
 //     registry, or local .tar file) and pipes the result into
 //     [Manager.LoadImageArchive].
+//   - [Manager.LoadImagePinned] does the same, and also tags an image
+//     referenced as "latest", without a tag, or by digest as
+//     <repository>:dev-<id>, so pods can run it without pulling.
 //   - [Manager.LoadImageArchive] is the low-level primitive that accepts any
 //     [io.Reader] of a Docker/OCI tar archive and loads it into every cluster
 //     node.
@@ -68,6 +83,27 @@
 // Docker and Podman are fully supported. Returns [ErrUnsupported] for
 // nerdctl/finch (cluster create/delete/kubeconfig still work on all engines).
 //
+// # Local registry
+//
+// [Manager.StartRegistry] runs a registry container on the same engine and
+// network as the cloud provider and points the cluster's containerd at it,
+// so images pushed to [Manager.RegistryAddress] (localhost:5001) are pulled
+// by the nodes without loading them. The registry outlives the cluster.
+//
+//	if err := m.StartRegistry(ctx, "dev"); err != nil { ... }
+//	status, err := m.RegistryStatus(ctx)
+//
+// # Registry mirrors
+//
+// [WithRegistryMirrors] points the nodes' containerd at a mirror for a
+// registry, such as a pull-through cache for docker.io; the nodes fall back
+// to the registry itself. The mirrors are remembered with the cluster, so
+// [Manager.Recreate] keeps them and [Manager.VerifyRegistryMirrors] checks
+// that every node still has them and reaches their endpoints.
+//
+//	mirror, err := localkube.ParseRegistryMirror("docker.io=mirror.local:5000")
+//	err = m.Create(ctx, "dev", localkube.WithRegistryMirrors(mirror))
+//
 // # Concurrency
 //
 // [Manager] is safe for concurrent use. Its config is immutable after [New].
@@ -91,7 +127,8 @@
 //
 //  1. Kubeconfig copy under XDG StateHome at
 //     deployah/localkube/kubeconfigs/<name>.yaml: written by
-//     [Manager.KubeConfig], accessible via [KubeConfig.Path].
+//     [Manager.KubeConfig], accessible via [KubeConfig.Path]. Cluster
+//     metadata such as registry mirrors sits next to it in <name>.json.
 //
 // Kind itself writes to two additional locations:
 //
@@ -30,13 +30,19 @@ import (
 // (Docker or Podman). Podman exposes a Docker-compatible socket when
 // "podman system service" is running; set DOCKER_HOST accordingly.
 func openFromDaemon(ctx context.Context, ref name.Reference) (io.ReadCloser, error) {
-	img, err := daemon.Image(ref, daemon.WithContext(ctx))
+	img, err := imageFromDaemon(ctx, ref)
 	if err != nil {
 		return nil, err
 	}
 	return tarballPipe(ref, img), nil
 }
 
+// imageFromDaemon looks ref up in the local container daemon without
+// exporting it yet.
+func imageFromDaemon(ctx context.Context, ref name.Reference) (v1.Image, error) {
+	return daemon.Image(ref, daemon.WithContext(ctx))
+}
+
 // tarballPipe streams img as a Docker tar archive via an [io.Pipe] so the
 // caller can start reading while the archive is being written.
 //
@@ -45,6 +51,12 @@ func openFromDaemon(ctx context.Context, ref name.Reference) (io.ReadCloser, err
 // reader is never left hanging. See https://pkg.go.dev/io#Pipe for the
 // CloseWithError contract (idempotent, never overwrites a previous error).
 func tarballPipe(ref name.Reference, img v1.Image) io.ReadCloser {
+	return multiRefPipe(map[name.Reference]v1.Image{ref: img})
+}
+
+// multiRefPipe is [tarballPipe] for an archive that carries several
+// references, such as one image under two tags.
+func multiRefPipe(refs map[name.Reference]v1.Image) io.ReadCloser {
 	pr, pw := io.Pipe()
 	go func() {
 		var err error
@@ -54,7 +66,7 @@ func tarballPipe(ref name.Reference, img v1.Image) io.ReadCloser {
 			}
 			pw.CloseWithError(err) // idempotent; nil becomes EOF for the reader
 		}()
-		err = tarball.Write(ref, img, pw)
+		err = tarball.MultiRefWrite(refs, pw)
 	}()
 	return pr
 }
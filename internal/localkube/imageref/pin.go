@@ -0,0 +1,131 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageref
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/distribution/reference"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// PinnedTagPrefix starts the tag [OpenPinned] gives an image that has no
+// fixed tag. The rest is the first 12 hex digits of the image ID.
+const PinnedTagPrefix = "dev-"
+
+// resolvers bundles the image lookups behind [OpenPinned] so tests can
+// substitute fakes, like [openers] does for [Open].
+type resolvers struct {
+	daemon   func(ctx context.Context, ref name.Reference) (v1.Image, error)
+	registry func(ctx context.Context, ref name.Reference) (v1.Image, error)
+}
+
+var defaultResolvers = resolvers{
+	daemon:   imageFromDaemon,
+	registry: imageFromRegistry,
+}
+
+// NeedsPin reports whether a cluster would pull ref from its registry
+// again even after the image was loaded into the nodes: the reference has
+// no tag, the tag "latest" (the default pull policy for both is Always),
+// or only a digest, which an archive cannot carry as a name.
+func NeedsPin(ref string) bool {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return false
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return true
+	}
+	tagged, ok := named.(reference.Tagged)
+	return !ok || tagged.Tag() == "latest"
+}
+
+// OpenPinned is [Open] for an image a cluster should run without pulling
+// it. When [NeedsPin] reports ref, the archive also tags the image
+// <repository>:dev-<id>, and pinned is that reference; deploy it instead
+// of ref. Otherwise, and for archive files, whose tags come from the file,
+// pinned is ref.
+//
+// Example:
+//
+//	rc, pinned, err := imageref.OpenPinned(ctx, "myapp:latest")
+//	if err != nil { ... }
+//	defer rc.Close()
+//	err = m.LoadImageArchive(ctx, "dev", rc) // pinned is "myapp:dev-3f2a9c1b7d4e"
+func OpenPinned(ctx context.Context, ref string) (rc io.ReadCloser, pinned string, err error) {
+	return openPinnedWith(ctx, ref, defaultResolvers)
+}
+
+// openPinnedWith is the testable core of OpenPinned.
+func openPinnedWith(ctx context.Context, ref string, rs resolvers) (io.ReadCloser, string, error) {
+	if info, statErr := os.Stat(ref); statErr == nil && info.Mode().IsRegular() {
+		rc, err := Open(ctx, ref)
+		return rc, ref, err
+	}
+
+	parsed, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("imageref: invalid reference %q: %w", ref, err)
+	}
+	img, daemonErr := rs.daemon(ctx, parsed)
+	if daemonErr != nil {
+		var regErr error
+		if img, regErr = rs.registry(ctx, parsed); regErr != nil {
+			return nil, "", fmt.Errorf("imageref: %q: %w", ref, errors.Join(daemonErr, regErr))
+		}
+	}
+	if !NeedsPin(ref) {
+		return tarballPipe(parsed, img), ref, nil
+	}
+
+	id, err := img.ConfigName()
+	if err != nil {
+		return nil, "", fmt.Errorf("imageref: %q: image ID: %w", ref, err)
+	}
+	pinned, err := pinnedTag(ref, id)
+	if err != nil {
+		return nil, "", err
+	}
+	tag, err := name.NewTag(pinned)
+	if err != nil {
+		return nil, "", fmt.Errorf("imageref: pinned tag %q: %w", pinned, err)
+	}
+	refs := map[name.Reference]v1.Image{tag: img}
+	if _, isTag := parsed.(name.Tag); isTag {
+		refs[parsed] = img
+	}
+	return multiRefPipe(refs), pinned, nil
+}
+
+// pinnedTag returns ref's repository, written the way ref writes it, with
+// the tag dev-<first 12 hex digits of id>.
+func pinnedTag(ref string, id v1.Hash) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("imageref: invalid reference %q: %w", ref, err)
+	}
+	hex := id.Hex
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	return reference.FamiliarName(named) + ":" + PinnedTagPrefix + hex, nil
+}
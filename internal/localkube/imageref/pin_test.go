@@ -0,0 +1,123 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageref
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+func TestNeedsPin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{ref: "myapp", want: true},
+		{ref: "myapp:latest", want: true},
+		{ref: "ghcr.io/acme/api@sha256:1111111111111111111111111111111111111111111111111111111111111111", want: true},
+		{ref: "myapp:1.4.0", want: false},
+		{ref: "localhost:5000/myapp:dev", want: false},
+		{ref: ":::invalid:::", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, NeedsPin(tt.ref))
+		})
+	}
+}
+
+func imageResolver(img v1.Image) func(context.Context, name.Reference) (v1.Image, error) {
+	return func(context.Context, name.Reference) (v1.Image, error) { return img, nil }
+}
+
+func failResolver(err error) func(context.Context, name.Reference) (v1.Image, error) {
+	return func(context.Context, name.Reference) (v1.Image, error) { return nil, err }
+}
+
+// readArchive reads the archive rc streams and returns the image it holds
+// under tag.
+func readArchive(t *testing.T, rc io.ReadCloser, tag string) v1.Image {
+	t.Helper()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	parsed, err := name.NewTag(tag)
+	require.NoError(t, err)
+	img, err := tarball.Image(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}, &parsed)
+	require.NoError(t, err)
+	return img
+}
+
+func TestOpenPinned_TagsLatestImage(t *testing.T) {
+	t.Parallel()
+
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	id, err := img.ConfigName()
+	require.NoError(t, err)
+
+	rs := resolvers{daemon: imageResolver(img), registry: failResolver(errFakeRegistry)}
+	rc, pinned, err := openPinnedWith(t.Context(), "myapp:latest", rs)
+	require.NoError(t, err)
+	assert.Equal(t, "myapp:"+PinnedTagPrefix+id.Hex[:12], pinned)
+
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	for _, tag := range []string{pinned, "myapp:latest"} {
+		got := readArchive(t, io.NopCloser(bytes.NewReader(data)), tag)
+		gotID, idErr := got.ConfigName()
+		require.NoError(t, idErr)
+		assert.Equal(t, id, gotID, tag)
+	}
+}
+
+func TestOpenPinned_KeepsFixedTag(t *testing.T) {
+	t.Parallel()
+
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+
+	rs := resolvers{daemon: failResolver(errFakeDaemon), registry: imageResolver(img)}
+	rc, pinned, err := openPinnedWith(t.Context(), "ghcr.io/acme/api:1.4.0", rs)
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/acme/api:1.4.0", pinned)
+	readArchive(t, rc, pinned)
+}
+
+func TestOpenPinned_JoinsBothErrors(t *testing.T) {
+	t.Parallel()
+
+	rs := resolvers{daemon: failResolver(errFakeDaemon), registry: failResolver(errFakeRegistry)}
+	_, _, err := openPinnedWith(t.Context(), "myapp:latest", rs)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errFakeDaemon)
+	assert.ErrorIs(t, err, errFakeRegistry)
+}
@@ -22,11 +22,23 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 )
 
 // openFromRegistry pulls an image from a remote OCI registry.
 // Uses the keychain from the host's credential helpers (same as docker pull).
 func openFromRegistry(ctx context.Context, ref name.Reference) (io.ReadCloser, error) {
+	img, err := imageFromRegistry(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return tarballPipe(ref, img), nil
+}
+
+// imageFromRegistry fetches the manifest of ref from its registry; layers
+// are pulled lazily when the image is written out.
+func imageFromRegistry(ctx context.Context, ref name.Reference) (v1.Image, error) {
 	img, err := remote.Image(ref,
 		remote.WithContext(ctx),
 		remote.WithAuthFromKeychain(authn.DefaultKeychain),
@@ -34,5 +46,5 @@ func openFromRegistry(ctx context.Context, ref name.Reference) (io.ReadCloser, e
 	if err != nil {
 		return nil, fmt.Errorf("imageref: pull %q: %w", ref, err)
 	}
-	return tarballPipe(ref, img), nil
+	return img, nil
 }
@@ -15,11 +15,16 @@
 package localkube
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
+	"path"
+	"slices"
 	"strings"
 
 	"golang.org/x/sync/errgroup"
@@ -27,6 +32,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/yaml"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -129,14 +135,98 @@ func protocolToKind(p Protocol) (kindv1alpha4.PortMappingProtocol, error) {
 	}
 }
 
-// buildKindConfig builds a Kind cluster spec from cfg.
-// Unknown [Protocol] returns wrapped [ErrUnsupported].
+// buildKindConfig builds a Kind cluster spec from cfg: one control-plane
+// node unless [WithNodes] was given. Port mappings from [WithPortMappings]
+// go to the first control-plane node. Unknown [Protocol] returns wrapped
+// [ErrUnsupported], and so does a host port mapped twice.
 func buildKindConfig(cfg *createConfig) (*kindv1alpha4.Cluster, error) {
-	node := kindv1alpha4.Node{Role: kindv1alpha4.ControlPlaneRole}
-	for _, pm := range cfg.portMappings {
+	nodes := cfg.nodes
+	if len(nodes) == 0 {
+		nodes = []NodeConfig{{Role: NodeRoleControlPlane}}
+	}
+	kc := &kindv1alpha4.Cluster{
+		Kind:         "Cluster",
+		APIVersion:   "kind.x-k8s.io/v1alpha4",
+		FeatureGates: cfg.featureGates,
+	}
+	controlPlanes := 0
+	for i, n := range nodes {
+		var extra []PortMapping
+		if n.Role == NodeRoleControlPlane && controlPlanes == 0 {
+			extra = cfg.portMappings
+		}
+		node, err := kindNode(n, controlPlanes == 0, extra)
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %w", i, err)
+		}
+		if n.Role == NodeRoleControlPlane {
+			controlPlanes++
+		}
+		kc.Nodes = append(kc.Nodes, node)
+	}
+	if controlPlanes == 0 {
+		return nil, fmt.Errorf("%w: a cluster needs a control-plane node", ErrUnsupported)
+	}
+	if err := checkHostPorts(kc.Nodes); err != nil {
+		return nil, err
+	}
+	return kc, nil
+}
+
+// checkHostPorts rejects two port mappings that bind the same host port
+// and protocol on overlapping listen addresses, such as a node's own
+// mapping and one from [WithPortMappings]. Kind would only fail once the
+// node containers start.
+func checkHostPorts(nodes []kindv1alpha4.Node) error {
+	bound := map[string][]string{}
+	for i, node := range nodes {
+		for _, pm := range node.ExtraPortMappings {
+			port := fmt.Sprintf("%d/%s", pm.HostPort, pm.Protocol)
+			if slices.ContainsFunc(bound[port], func(addr string) bool { return addressesOverlap(addr, pm.ListenAddress) }) {
+				return fmt.Errorf("%w: node %d: host port %s:%s is already mapped", ErrUnsupported, i, pm.ListenAddress, port)
+			}
+			bound[port] = append(bound[port], pm.ListenAddress)
+		}
+	}
+	return nil
+}
+
+// addressesOverlap reports whether listening on a and on b would contend
+// for the same host port: they are equal, or either is a wildcard such as
+// 0.0.0.0 that listens on every address.
+func addressesOverlap(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, addr := range []string{a, b} {
+		if ip := net.ParseIP(addr); ip != nil && ip.IsUnspecified() {
+			return true
+		}
+	}
+	return false
+}
+
+// kindNode converts n to a Kind node. first is true until the first
+// control-plane node is seen; that node registers through kubeadm init,
+// every other node through kubeadm join, which decides where taints go.
+func kindNode(n NodeConfig, first bool, extra []PortMapping) (kindv1alpha4.Node, error) {
+	node := kindv1alpha4.Node{Labels: n.Labels}
+	kubeadmKind := "JoinConfiguration"
+	switch n.Role {
+	case NodeRoleControlPlane:
+		node.Role = kindv1alpha4.ControlPlaneRole
+		if first {
+			kubeadmKind = "InitConfiguration"
+		}
+	case NodeRoleWorker:
+		node.Role = kindv1alpha4.WorkerRole
+	default:
+		return node, fmt.Errorf("%w: unknown node role %q", ErrUnsupported, n.Role)
+	}
+	for _, pm := range slices.Concat(extra, n.PortMappings) {
 		proto, err := protocolToKind(pm.Protocol)
 		if err != nil {
-			return nil, err
+			return node, err
 		}
 		listenAddr := pm.ListenAddress
 		if listenAddr == "" {
@@ -149,11 +239,48 @@ func buildKindConfig(cfg *createConfig) (*kindv1alpha4.Cluster, error) {
 			ListenAddress: listenAddr,
 		})
 	}
-	return &kindv1alpha4.Cluster{
-		Kind:       "Cluster",
-		APIVersion: "kind.x-k8s.io/v1alpha4",
-		Nodes:      []kindv1alpha4.Node{node},
-	}, nil
+	for _, m := range n.Mounts {
+		node.ExtraMounts = append(node.ExtraMounts, kindv1alpha4.Mount{
+			HostPath:      m.HostPath,
+			ContainerPath: m.ContainerPath,
+			Readonly:      m.ReadOnly,
+		})
+	}
+	if len(n.Taints) > 0 {
+		patch, err := taintPatch(kubeadmKind, n.Taints)
+		if err != nil {
+			return node, err
+		}
+		node.KubeadmConfigPatches = append(node.KubeadmConfigPatches, patch)
+	}
+	return node, nil
+}
+
+// taintPatch renders a kubeadm config patch that registers the node with
+// taints. Kind has no taint field, so this is the only way to set them
+// before workloads can land on the node.
+func taintPatch(kubeadmKind string, taints []Taint) (string, error) {
+	list := make([]map[string]string, 0, len(taints))
+	for _, t := range taints {
+		switch t.Effect {
+		case TaintNoSchedule, TaintPreferNoSchedule, TaintNoExecute:
+		default:
+			return "", fmt.Errorf("%w: unknown taint effect %q", ErrUnsupported, t.Effect)
+		}
+		entry := map[string]string{"key": t.Key, "effect": string(t.Effect)}
+		if t.Value != "" {
+			entry["value"] = t.Value
+		}
+		list = append(list, entry)
+	}
+	out, err := yaml.Marshal(map[string]any{
+		"kind":             kubeadmKind,
+		"nodeRegistration": map[string]any{"taints": list},
+	})
+	if err != nil {
+		return "", fmt.Errorf("render taint patch: %w", err)
+	}
+	return string(out), nil
 }
 
 // classifyKindErr maps Kind text to [ErrNotFound] or [ErrAlreadyExists].
@@ -192,8 +319,8 @@ func (p *kindProvider) create(ctx context.Context, name string, cfg *createConfi
 	}
 
 	if len(cfg.rawKindConfig) > 0 {
-		if len(cfg.portMappings) > 0 {
-			p.cfg.logger.Warn("localkube: WithKindConfig takes priority; WithPortMappings is ignored")
+		if len(cfg.portMappings) > 0 || len(cfg.nodes) > 0 || len(cfg.featureGates) > 0 {
+			p.cfg.logger.Warn("localkube: WithKindConfig takes priority; WithPortMappings, WithNodes, and WithFeatureGates are ignored")
 		}
 		createOpts = append(createOpts, cluster.CreateWithRawConfig(cfg.rawKindConfig))
 	} else {
@@ -413,6 +540,100 @@ func (p *kindProvider) loadImageArchive(ctx context.Context, name string, archiv
 	return nil
 }
 
+// containerdCertsDir is where Kind node images point containerd's registry
+// config_path, so a hosts.toml there takes effect without a restart.
+const containerdCertsDir = "/etc/containerd/certs.d"
+
+// writeRegistryHosts writes a containerd hosts.toml per registry host on
+// every node, so pulls of <host>/<image> go to the mapped endpoint.
+func (p *kindProvider) writeRegistryHosts(ctx context.Context, name string, hosts map[string]string) error {
+	nodes, err := p.p.ListNodes(name)
+	if err != nil {
+		if classified, ok := classifyKindErr(err); ok {
+			return classified
+		}
+		return fmt.Errorf("kind list nodes: %w", err)
+	}
+	if len(nodes) == 0 {
+		return ErrNotFound
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(min(len(nodes), 4))
+	for _, n := range nodes {
+		g.Go(func() error {
+			for host, endpoint := range hosts {
+				dir := path.Join(containerdCertsDir, host)
+				if mkErr := n.CommandContext(gctx, "mkdir", "-p", dir).Run(); mkErr != nil {
+					return fmt.Errorf("node %s: create %s: %w", n.String(), dir, mkErr)
+				}
+				file := path.Join(dir, "hosts.toml")
+				cmd := n.CommandContext(gctx, "cp", "/dev/stdin", file).SetStdin(strings.NewReader(registryHostsTOML(endpoint)))
+				if cpErr := cmd.Run(); cpErr != nil {
+					return fmt.Errorf("node %s: write %s: %w", n.String(), file, cpErr)
+				}
+			}
+			return nil
+		})
+	}
+	if waitErr := g.Wait(); waitErr != nil {
+		return fmt.Errorf("configure registry hosts: %w", waitErr)
+	}
+	return nil
+}
+
+// registryHostsTOML renders a containerd hosts.toml that sends pulls and
+// resolves to endpoint. Plain http endpoints are trusted as-is.
+func registryHostsTOML(endpoint string) string {
+	return fmt.Sprintf("[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", endpoint)
+}
+
+// verifyRegistryHosts reads each host's hosts.toml back from every node and
+// asks the first node to reach the endpoint's registry API. containerd
+// silently falls back to the upstream registry, so a mirror that is
+// misconfigured or down only shows up as slow pulls without this check.
+func (p *kindProvider) verifyRegistryHosts(ctx context.Context, name string, hosts map[string]string) (map[string]error, error) {
+	nodes, err := p.p.ListNodes(name)
+	if err != nil {
+		if classified, ok := classifyKindErr(err); ok {
+			return nil, classified
+		}
+		return nil, fmt.Errorf("kind list nodes: %w", err)
+	}
+	if len(nodes) == 0 {
+		return nil, ErrNotFound
+	}
+
+	results := make(map[string]error, len(hosts))
+	for host, endpoint := range hosts {
+		file := path.Join(containerdCertsDir, host, "hosts.toml")
+		want := registryHostsTOML(endpoint)
+		var errs []error
+		for _, n := range nodes {
+			var got bytes.Buffer
+			if catErr := n.CommandContext(ctx, "cat", file).SetStdout(&got).Run(); catErr != nil {
+				errs = append(errs, fmt.Errorf("node %s: %s is missing", n.String(), file))
+				continue
+			}
+			if got.String() != want {
+				errs = append(errs, fmt.Errorf("node %s: %s does not point at %s", n.String(), file, endpoint))
+			}
+		}
+		if len(errs) == 0 {
+			var code bytes.Buffer
+			probe := nodes[0].CommandContext(ctx, "curl", "-ks", "-o", "/dev/null", "-m", "5",
+				"-w", "%{http_code}", endpoint+"/v2/").SetStdout(&code)
+			if probeErr := probe.Run(); probeErr != nil {
+				errs = append(errs, fmt.Errorf("node %s cannot reach %s: %w", nodes[0].String(), endpoint, probeErr))
+			} else if c := code.String(); c != "200" && c != "401" {
+				errs = append(errs, fmt.Errorf("%s/v2/ answered HTTP %s from node %s", endpoint, c, nodes[0].String()))
+			}
+		}
+		results[host] = errors.Join(errs...)
+	}
+	return results, nil
+}
+
 // isNodeReady reports whether node is Ready.
 func isNodeReady(node *corev1.Node) bool {
 	for _, c := range node.Status.Conditions {
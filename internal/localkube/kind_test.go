@@ -125,6 +125,90 @@ func TestBuildKindConfig_invalidProtocol(t *testing.T) {
 	assert.ErrorIs(t, err, ErrUnsupported)
 }
 
+// TestBuildKindConfig_hostPortMappedTwice rejects a node mapping that
+// binds a host port a WithPortMappings entry already binds, including
+// through a wildcard listen address.
+func TestBuildKindConfig_hostPortMappedTwice(t *testing.T) {
+	cfg := &createConfig{
+		base:         &config{},
+		portMappings: []PortMapping{{HostPort: 80, ContainerPort: 80}},
+		nodes: []NodeConfig{
+			{Role: NodeRoleControlPlane, PortMappings: []PortMapping{{HostPort: 80, ContainerPort: 30080, ListenAddress: "0.0.0.0"}}},
+		},
+	}
+	_, err := buildKindConfig(cfg)
+	require.ErrorIs(t, err, ErrUnsupported)
+	assert.Contains(t, err.Error(), "host port 0.0.0.0:80/TCP is already mapped")
+
+	cfg.nodes[0].PortMappings[0].ListenAddress = "192.168.1.10"
+	_, err = buildKindConfig(cfg)
+	require.NoError(t, err, "distinct specific addresses may share a port")
+}
+
+// TestBuildKindConfig_withNodes builds a multi-node cluster with labels,
+// taints, mounts, and feature gates. WithPortMappings entries land on the
+// control-plane node ahead of its own mappings.
+func TestBuildKindConfig_withNodes(t *testing.T) {
+	cfg := &createConfig{
+		base:         &config{},
+		featureGates: map[string]bool{"InPlacePodVerticalScaling": true},
+		portMappings: []PortMapping{{HostPort: 8080, ContainerPort: 80}},
+		nodes: []NodeConfig{
+			{
+				Role:         NodeRoleControlPlane,
+				Taints:       []Taint{{Key: "control", Effect: TaintPreferNoSchedule}},
+				PortMappings: []PortMapping{{HostPort: 30080, ContainerPort: 30080}},
+			},
+			{Role: NodeRoleWorker, Labels: map[string]string{"zone": "a"}},
+			{
+				Role:   NodeRoleWorker,
+				Taints: []Taint{{Key: "dedicated", Value: "batch", Effect: TaintNoSchedule}},
+				Mounts: []Mount{{HostPath: "/srv/data", ContainerPath: "/data", ReadOnly: true}},
+			},
+		},
+	}
+	kc, err := buildKindConfig(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"InPlacePodVerticalScaling": true}, kc.FeatureGates)
+	require.Len(t, kc.Nodes, 3)
+
+	cp := kc.Nodes[0]
+	assert.Equal(t, kindv1alpha4.ControlPlaneRole, cp.Role)
+	require.Len(t, cp.ExtraPortMappings, 2)
+	assert.Equal(t, int32(8080), cp.ExtraPortMappings[0].HostPort)
+	assert.Equal(t, int32(30080), cp.ExtraPortMappings[1].HostPort)
+	require.Len(t, cp.KubeadmConfigPatches, 1)
+	assert.Contains(t, cp.KubeadmConfigPatches[0], "kind: InitConfiguration")
+	assert.Contains(t, cp.KubeadmConfigPatches[0], "effect: PreferNoSchedule")
+
+	assert.Equal(t, kindv1alpha4.WorkerRole, kc.Nodes[1].Role)
+	assert.Equal(t, map[string]string{"zone": "a"}, kc.Nodes[1].Labels)
+	assert.Empty(t, kc.Nodes[1].KubeadmConfigPatches)
+
+	w := kc.Nodes[2]
+	require.Len(t, w.KubeadmConfigPatches, 1)
+	assert.Contains(t, w.KubeadmConfigPatches[0], "kind: JoinConfiguration")
+	assert.Contains(t, w.KubeadmConfigPatches[0], "value: batch")
+	assert.Equal(t, []kindv1alpha4.Mount{{HostPath: "/srv/data", ContainerPath: "/data", Readonly: true}}, w.ExtraMounts)
+}
+
+// TestBuildKindConfig_invalidNodes rejects node lists Kind cannot create.
+func TestBuildKindConfig_invalidNodes(t *testing.T) {
+	cases := map[string][]NodeConfig{
+		"no control plane": {{Role: NodeRoleWorker}},
+		"unknown role":     {{Role: NodeRoleControlPlane}, {Role: "etcd"}},
+		"unknown effect":   {{Role: NodeRoleControlPlane, Taints: []Taint{{Key: "k", Effect: "Never"}}}},
+	}
+	for name, nodes := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := buildKindConfig(&createConfig{base: &config{}, nodes: nodes})
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrUnsupported)
+		})
+	}
+}
+
 // TestProtocolToKind maps Protocol constants to Kind protocol constants and
 // rejects unknown values.
 func TestProtocolToKind(t *testing.T) {
@@ -15,6 +15,7 @@
 package localkube
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -48,7 +49,8 @@ func safeClusterName(name string) error {
 	return nil
 }
 
-// kubeconfigStore atomically writes kubeconfig copies to a configurable dir.
+// kubeconfigStore atomically writes kubeconfig copies to a configurable dir,
+// next to each cluster's [clusterMetadata].
 type kubeconfigStore struct {
 	root string
 }
@@ -74,8 +76,8 @@ func (s *kubeconfigStore) kubeconfigPath(name string) string {
 	return filepath.Join(s.root, name+".yaml")
 }
 
-// remove deletes a cluster's kubeconfig file and its lock file.
-// Missing files are not treated as errors.
+// remove deletes a cluster's kubeconfig file, its lock file, and its
+// metadata. Missing files are not treated as errors.
 func (s *kubeconfigStore) remove(name string) error {
 	path := s.kubeconfigPath(name)
 	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
@@ -84,6 +86,54 @@ func (s *kubeconfigStore) remove(name string) error {
 	if err := os.Remove(path + ".lock"); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("remove kubeconfig lock %s: %w", name, err)
 	}
+	if err := os.Remove(s.metadataPath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove cluster metadata %s: %w", name, err)
+	}
+	return nil
+}
+
+// clusterMetadata is what localkube remembers about a cluster beyond what
+// the backend reports, so [Manager.Recreate] can restore it.
+type clusterMetadata struct {
+	// RegistryMirrors are the mirrors written to the cluster's nodes.
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+}
+
+// metadataPath returns the path of a cluster's metadata file.
+func (s *kubeconfigStore) metadataPath(name string) string {
+	return filepath.Join(s.root, name+".json")
+}
+
+// readMetadata loads a cluster's metadata. A missing file yields empty
+// metadata, since clusters created before it existed, or outside localkube,
+// have none.
+func (s *kubeconfigStore) readMetadata(name string) (*clusterMetadata, error) {
+	md := &clusterMetadata{}
+	data, err := os.ReadFile(s.metadataPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return md, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cluster metadata %s: %w", name, err)
+	}
+	if err = json.Unmarshal(data, md); err != nil {
+		return nil, fmt.Errorf("parse cluster metadata %s: %w", name, err)
+	}
+	return md, nil
+}
+
+// writeMetadata atomically replaces a cluster's metadata.
+func (s *kubeconfigStore) writeMetadata(name string, md *clusterMetadata) error {
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cluster metadata %s: %w", name, err)
+	}
+	if err = os.MkdirAll(s.root, 0o700); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	if err = renameio.WriteFile(s.metadataPath(name), append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write cluster metadata %s: %w", name, err)
+	}
 	return nil
 }
 
@@ -30,6 +30,7 @@ import (
 
 	"deployah.dev/deployah/internal/localkube/cloudprovider"
 	"deployah.dev/deployah/internal/localkube/imageref"
+	"deployah.dev/deployah/internal/localkube/registry"
 )
 
 var _ io.Closer = (*Manager)(nil)
@@ -126,6 +127,11 @@ func (m *Manager) Create(ctx context.Context, name string, opts ...CreateOption)
 			o(cc)
 		}
 	}
+	for _, mirror := range cc.mirrors {
+		if err := mirror.validate(); err != nil {
+			return fmt.Errorf("localkube: create %q: %w", name, err)
+		}
+	}
 
 	cc.emit(Event{Step: StepCreating, Status: StepStarted})
 
@@ -137,7 +143,10 @@ func (m *Manager) Create(ctx context.Context, name string, opts ...CreateOption)
 		if errors.Is(err, ErrAlreadyExists) && cc.createIfMissing {
 			wait()
 			cc.emit(Event{Step: StepCreating, Status: StepCompleted})
-			return nil
+			if len(cc.mirrors) == 0 {
+				return nil
+			}
+			return m.configureMirrors(ctx, name, cc)
 		}
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			// Context was canceled; emit failure and schedule best-effort
@@ -166,6 +175,25 @@ func (m *Manager) Create(ctx context.Context, name string, opts ...CreateOption)
 	wait()
 
 	cc.emit(Event{Step: StepCreating, Status: StepCompleted})
+	// Always record the new cluster's mirrors, even none, so metadata left
+	// by a cluster deleted outside localkube does not carry over.
+	return m.configureMirrors(ctx, name, cc)
+}
+
+// configureMirrors writes cc.mirrors to the nodes of the named cluster and
+// remembers them in its metadata.
+func (m *Manager) configureMirrors(ctx context.Context, name string, cc *createConfig) error {
+	if len(cc.mirrors) > 0 {
+		cc.emit(Event{Step: StepConfiguringMirrors, Status: StepStarted})
+		if err := m.prov.writeRegistryHosts(ctx, name, mirrorHosts(cc.mirrors)); err != nil {
+			cc.emit(Event{Step: StepConfiguringMirrors, Status: StepFailed, Detail: err.Error(), Err: err})
+			return fmt.Errorf("localkube: configure registry mirrors for %q: %w", name, err)
+		}
+		cc.emit(Event{Step: StepConfiguringMirrors, Status: StepCompleted})
+	}
+	if err := m.kcs.writeMetadata(name, &clusterMetadata{RegistryMirrors: cc.mirrors}); err != nil {
+		return fmt.Errorf("localkube: %w", err)
+	}
 	return nil
 }
 
@@ -208,11 +236,23 @@ func (m *Manager) Delete(ctx context.Context, name string, opts ...DeleteOption)
 	return nil
 }
 
-// Recreate deletes an existing cluster (if any) and creates a fresh one.
+// Recreate deletes an existing cluster (if any) and creates a fresh one. The
+// new cluster keeps the old one's registry mirrors unless opts include
+// [WithRegistryMirrors].
 func (m *Manager) Recreate(ctx context.Context, name string, opts ...CreateOption) error {
-	if err := m.Delete(ctx, name, WithIgnoreMissing()); err != nil {
+	if err := safeClusterName(name); err != nil {
 		return err
 	}
+	md, err := m.kcs.readMetadata(name)
+	if err != nil {
+		return fmt.Errorf("localkube: recreate %q: %w", name, err)
+	}
+	if err = m.Delete(ctx, name, WithIgnoreMissing()); err != nil {
+		return err
+	}
+	if len(md.RegistryMirrors) > 0 {
+		opts = append([]CreateOption{WithRegistryMirrors(md.RegistryMirrors...)}, opts...)
+	}
 	return m.Create(ctx, name, opts...)
 }
 
@@ -230,7 +270,14 @@ func (m *Manager) Get(ctx context.Context, name string) (*Cluster, error) {
 	if err != nil {
 		return nil, fmt.Errorf("localkube: get %q: %w", name, err)
 	}
-	return backendInfoToCluster(name, m.prov.backendName(), info), nil
+	c := backendInfoToCluster(name, m.prov.backendName(), info)
+	if md, mdErr := m.kcs.readMetadata(name); mdErr == nil {
+		c.RegistryMirrors = md.RegistryMirrors
+	} else {
+		m.cfg.logger.Warn("localkube: read cluster metadata failed",
+			slog.String("cluster", name), slog.Any("err", mdErr))
+	}
+	return c, nil
 }
 
 // List returns all clusters known to the backend, sorted by name.
@@ -434,6 +481,114 @@ func (m *Manager) AttachCloudProvider(ctx context.Context, opts ...CloudProvider
 	return nil
 }
 
+// StartRegistry starts the local registry container and configures every
+// node of the named cluster to pull images referenced as
+// <[Manager.RegistryAddress]>/<image> from it.
+//
+// It is idempotent: a running registry is left as is and the node
+// configuration is rewritten, so calling it again after recreating the
+// cluster reconnects the new nodes. Returns [ErrUnsupported] when no Docker
+// or Podman engine is available.
+func (m *Manager) StartRegistry(ctx context.Context, clusterName string) error {
+	if err := safeClusterName(clusterName); err != nil {
+		return err
+	}
+	ctrl, err := m.registryController()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := contextWithBudget(ctx, m.cfg.timeout)
+	defer cancel()
+
+	m.cfg.eventFunc(Event{Step: StepStartingRegistry, Status: StepStarted, Detail: ctrl.Address()})
+	if startErr := ctrl.Start(ctx); startErr != nil {
+		m.cfg.eventFunc(Event{Step: StepStartingRegistry, Status: StepFailed, Detail: startErr.Error(), Err: startErr})
+		return fmt.Errorf("localkube: start registry: %w", startErr)
+	}
+	hosts := map[string]string{ctrl.Address(): ctrl.Endpoint()}
+	if hostsErr := m.prov.writeRegistryHosts(ctx, clusterName, hosts); hostsErr != nil {
+		m.cfg.eventFunc(Event{Step: StepStartingRegistry, Status: StepFailed, Detail: hostsErr.Error(), Err: hostsErr})
+		return fmt.Errorf("localkube: connect %q to registry: %w", clusterName, hostsErr)
+	}
+	m.cfg.eventFunc(Event{Step: StepStartingRegistry, Status: StepCompleted, Detail: ctrl.Address()})
+	return nil
+}
+
+// StopRegistry removes the local registry container and the images pushed
+// to it. It is idempotent: calling it when no registry exists returns nil.
+func (m *Manager) StopRegistry(ctx context.Context) error {
+	ctrl, err := m.registryController()
+	if err != nil {
+		return err
+	}
+	m.cfg.eventFunc(Event{Step: StepStoppingRegistry, Status: StepStarted})
+	if stopErr := ctrl.Stop(ctx); stopErr != nil {
+		m.cfg.eventFunc(Event{Step: StepStoppingRegistry, Status: StepFailed, Detail: stopErr.Error(), Err: stopErr})
+		return fmt.Errorf("localkube: stop registry: %w", stopErr)
+	}
+	m.cfg.eventFunc(Event{Step: StepStoppingRegistry, Status: StepCompleted})
+	return nil
+}
+
+// RegistryAddress returns the host address of the local registry, e.g.
+// "localhost:5001". Pure helper: performs no I/O.
+func (m *Manager) RegistryAddress() string {
+	cfg := registry.Config{}
+	return cfg.Address()
+}
+
+// RegistryStatus reports the local registry's health: [StatusRunning] when
+// its container runs and the registry API answers on the host,
+// [StatusUnhealthy] (with the probe error) when it runs but does not answer,
+// and [StatusStopped] when the container exists but is stopped. Returns
+// [ErrNotFound] when no registry was started and [ErrUnsupported] when no
+// Docker or Podman engine is available.
+func (m *Manager) RegistryStatus(ctx context.Context) (Status, error) {
+	ctrl, err := m.registryController()
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if !ctrl.Exists(ctx) {
+		return StatusUnknown, ErrNotFound
+	}
+	if !ctrl.Running(ctx) {
+		return StatusStopped, nil
+	}
+	if healthErr := ctrl.Healthy(ctx); healthErr != nil {
+		return StatusUnhealthy, healthErr
+	}
+	return StatusRunning, nil
+}
+
+// VerifyRegistryMirrors checks the registry mirrors the named cluster was
+// created with: every node must still carry each mirror's configuration
+// and reach its endpoint. It returns one [MirrorStatus] per mirror, and
+// nil when the cluster has none.
+func (m *Manager) VerifyRegistryMirrors(ctx context.Context, name string) ([]MirrorStatus, error) {
+	if err := safeClusterName(name); err != nil {
+		return nil, err
+	}
+	md, err := m.kcs.readMetadata(name)
+	if err != nil {
+		return nil, fmt.Errorf("localkube: %w", err)
+	}
+	if len(md.RegistryMirrors) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := contextWithBudget(ctx, m.cfg.timeout)
+	defer cancel()
+
+	results, err := m.prov.verifyRegistryHosts(ctx, name, mirrorHosts(md.RegistryMirrors))
+	if err != nil {
+		return nil, fmt.Errorf("localkube: verify registry mirrors for %q: %w", name, err)
+	}
+	out := make([]MirrorStatus, 0, len(md.RegistryMirrors))
+	for _, mirror := range md.RegistryMirrors {
+		out = append(out, MirrorStatus{RegistryMirror: mirror, Err: results[mirror.Registry]})
+	}
+	return out, nil
+}
+
 // LoadImage resolves an image reference, fetches it (from local daemon,
 // registry, or file), and loads it into the named cluster.
 //
@@ -464,6 +619,39 @@ func (m *Manager) LoadImage(ctx context.Context, clusterName, imageRef string) e
 	return m.loadImageArchiveInternal(ctx, clusterName, rc)
 }
 
+// LoadImagePinned is [Manager.LoadImage] for an image pods should run
+// straight from the nodes. A reference the cluster would pull again (no
+// tag, "latest", or a digest; see [imageref.NeedsPin]) is also loaded as
+// <repository>:dev-<id>, and pinned returns that reference. Otherwise
+// pinned is imageRef.
+func (m *Manager) LoadImagePinned(ctx context.Context, clusterName, imageRef string) (pinned string, err error) {
+	if err := safeClusterName(clusterName); err != nil {
+		return "", err
+	}
+	ctx, cancel := contextWithBudget(ctx, m.cfg.timeout)
+	defer cancel()
+
+	m.cfg.eventFunc(Event{Step: StepResolvingImage, Status: StepStarted, Detail: imageRef})
+
+	rc, pinned, err := imageref.OpenPinned(ctx, imageRef)
+	if err != nil {
+		m.cfg.eventFunc(Event{Step: StepResolvingImage, Status: StepFailed, Detail: err.Error(), Err: err})
+		return "", fmt.Errorf("localkube: resolve image %q: %w", imageRef, err)
+	}
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			m.cfg.logger.Warn("localkube: close image reader failed",
+				slog.String("image", imageRef), slog.Any("err", closeErr))
+		}
+	}()
+
+	m.cfg.eventFunc(Event{Step: StepResolvingImage, Status: StepCompleted, Detail: pinned})
+	if err := m.loadImageArchiveInternal(ctx, clusterName, rc); err != nil {
+		return "", err
+	}
+	return pinned, nil
+}
+
 // LoadImageArchive loads a Docker/OCI tar archive into the named cluster.
 // The archive is read until EOF; the caller is responsible for closing it.
 func (m *Manager) LoadImageArchive(ctx context.Context, clusterName string, archive io.Reader) error {
@@ -503,6 +691,15 @@ func (m *Manager) cloudProviderController(opts ...CloudProviderOption) (*cloudpr
 	return cloudprovider.New(m.eng, cfg.toControllerConfig()), nil
 }
 
+// registryController creates a controller for the local registry container.
+// Returns ErrUnsupported when no Docker/Podman engine is available.
+func (m *Manager) registryController() (*registry.Controller, error) {
+	if m.eng == nil {
+		return nil, fmt.Errorf("%w: local registry requires Docker or Podman", ErrUnsupported)
+	}
+	return registry.New(m.eng, registry.Config{}), nil
+}
+
 // contextWithBudget wraps ctx with a timeout only when the caller has not
 // already set a deadline, protecting against runaway operations.
 func contextWithBudget(ctx context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
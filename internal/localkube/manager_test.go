@@ -43,11 +43,16 @@ type fakeProvider struct {
 	kubeconfigResult []byte
 	kubeconfigErr    error
 	loadArchiveErr   error
+	registryHostsErr error
+	verifyHostsErr   error
+	verifyResult     map[string]error
 
 	createCalls int
 	deleteCalls int
 	loadCalls   int
 	lastArchive []byte // contents read from last loadImageArchive call
+	// registryHosts records the hosts passed to the last writeRegistryHosts call.
+	registryHosts map[string]string
 
 	// createBlockCh, when non-nil, causes the wait func returned by create()
 	// to block until the channel is closed, mimicking a slow Kind goroutine.
@@ -146,6 +151,29 @@ func (f *fakeProvider) loadImageArchive(_ context.Context, _ string, archive io.
 	return nil
 }
 
+func (f *fakeProvider) writeRegistryHosts(_ context.Context, _ string, hosts map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.registryHostsErr != nil {
+		return f.registryHostsErr
+	}
+	f.registryHosts = hosts
+	return nil
+}
+
+func (f *fakeProvider) verifyRegistryHosts(_ context.Context, _ string, hosts map[string]string) (map[string]error, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.verifyHostsErr != nil {
+		return nil, f.verifyHostsErr
+	}
+	out := make(map[string]error, len(hosts))
+	for host := range hosts {
+		out[host] = f.verifyResult[host]
+	}
+	return out, nil
+}
+
 // newTestManager creates a Manager backed by a fakeProvider and a temp
 // kubeconfig dir.
 func newTestManager(t *testing.T, fp *fakeProvider) *Manager {
@@ -531,7 +559,10 @@ func TestInvalidClusterName_allEntryPoints(t *testing.T) {
 			_, err = m.KubeConfig(ctx, name)
 			assert.ErrorIs(t, err, ErrInvalidName)
 			assert.ErrorIs(t, m.LoadImage(ctx, name, "ubuntu:latest"), ErrInvalidName)
+			_, err = m.LoadImagePinned(ctx, name, "ubuntu:latest")
+			assert.ErrorIs(t, err, ErrInvalidName)
 			assert.ErrorIs(t, m.LoadImageArchive(ctx, name, bytes.NewReader(nil)), ErrInvalidName)
+			assert.ErrorIs(t, m.StartRegistry(ctx, name), ErrInvalidName)
 		})
 	}
 }
@@ -0,0 +1,100 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localkube
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RegistryMirror sends the cluster's pulls from Registry to Endpoint first,
+// e.g. a pull-through cache for docker.io. Nodes fall back to Registry
+// itself when the mirror does not have an image or cannot be reached.
+type RegistryMirror struct {
+	// Registry is the upstream registry host as it appears in image
+	// references, e.g. "docker.io" or "ghcr.io".
+	Registry string `json:"registry"`
+	// Endpoint is the mirror URL, e.g. "http://mirror.local:5000". The
+	// nodes, not the host, must be able to reach it.
+	Endpoint string `json:"endpoint"`
+}
+
+// MirrorStatus is the result of [Manager.VerifyRegistryMirrors] for one
+// mirror.
+type MirrorStatus struct {
+	RegistryMirror
+	// Err says what is wrong, or is nil when every node has the mirror
+	// configured and reaches its endpoint.
+	Err error
+}
+
+// String renders the mirror the way [ParseRegistryMirror] reads it.
+func (r RegistryMirror) String() string {
+	return r.Registry + "=" + r.Endpoint
+}
+
+// ParseRegistryMirror parses "registry=endpoint", e.g.
+// "docker.io=mirror.local:5000". An endpoint without a scheme is taken as
+// plain HTTP, which is how pull-through caches usually listen.
+func ParseRegistryMirror(s string) (RegistryMirror, error) {
+	registry, endpoint, ok := strings.Cut(s, "=")
+	if !ok {
+		return RegistryMirror{}, fmt.Errorf("registry mirror %q: want registry=endpoint, e.g. docker.io=mirror.local:5000", s)
+	}
+	m := RegistryMirror{Registry: strings.TrimSpace(registry), Endpoint: strings.TrimSpace(endpoint)}
+	if m.Endpoint != "" && !strings.Contains(m.Endpoint, "://") {
+		m.Endpoint = "http://" + m.Endpoint
+	}
+	if err := m.validate(); err != nil {
+		return RegistryMirror{}, err
+	}
+	return m, nil
+}
+
+// validate checks that Registry is a bare host[:port] and Endpoint an
+// http or https URL without a path.
+func (r RegistryMirror) validate() error {
+	if !isRegistryHost(r.Registry) {
+		return fmt.Errorf("registry mirror %q: registry must be a host such as docker.io", r.String())
+	}
+	u, err := url.Parse(r.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("registry mirror %q: endpoint must be an http:// or https:// address", r.String())
+	}
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		return fmt.Errorf("registry mirror %q: endpoint must not have a path", r.String())
+	}
+	return nil
+}
+
+// isRegistryHost reports whether s is a bare host or host:port.
+func isRegistryHost(s string) bool {
+	if s == "" || strings.ContainsAny(s, " \t") {
+		return false
+	}
+	u, err := url.Parse("//" + s)
+	return err == nil && u.Host == s
+}
+
+// mirrorHosts maps each mirrored registry to its endpoint, the form
+// writeRegistryHosts takes.
+func mirrorHosts(mirrors []RegistryMirror) map[string]string {
+	hosts := make(map[string]string, len(mirrors))
+	for _, m := range mirrors {
+		hosts[m.Registry] = strings.TrimSuffix(m.Endpoint, "/")
+	}
+	return hosts
+}
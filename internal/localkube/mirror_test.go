@@ -0,0 +1,152 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localkube
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRegistryMirror accepts registry=endpoint, defaults the endpoint
+// scheme to http, and rejects malformed mappings.
+func TestParseRegistryMirror(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    RegistryMirror
+		wantErr bool
+	}{
+		{"docker.io=mirror.local:5000", RegistryMirror{"docker.io", "http://mirror.local:5000"}, false},
+		{"ghcr.io = https://cache.example.com", RegistryMirror{"ghcr.io", "https://cache.example.com"}, false},
+		{"localhost:5001=http://deployah-registry:5000", RegistryMirror{"localhost:5001", "http://deployah-registry:5000"}, false},
+		{"docker.io", RegistryMirror{}, true},
+		{"=mirror.local:5000", RegistryMirror{}, true},
+		{"docker.io/library=mirror.local:5000", RegistryMirror{}, true},
+		{"docker.io=", RegistryMirror{}, true},
+		{"docker.io=ftp://mirror.local", RegistryMirror{}, true},
+		{"docker.io=http://mirror.local:5000/v2", RegistryMirror{}, true},
+	}
+	for _, tc := range cases {
+		got, err := ParseRegistryMirror(tc.input)
+		if tc.wantErr {
+			assert.Error(t, err, "input: %q", tc.input)
+			continue
+		}
+		require.NoError(t, err, "input: %q", tc.input)
+		assert.Equal(t, tc.want, got, "input: %q", tc.input)
+	}
+}
+
+// TestCreate_registryMirrors writes the mirrors to the nodes and remembers
+// them, so Get reports them.
+func TestCreate_registryMirrors(t *testing.T) {
+	fp := &fakeProvider{}
+	m := newTestManager(t, fp)
+	mirror := RegistryMirror{Registry: "docker.io", Endpoint: "http://mirror.local:5000"}
+
+	require.NoError(t, m.Create(t.Context(), "dev", WithRegistryMirrors(mirror)))
+	assert.Equal(t, map[string]string{"docker.io": "http://mirror.local:5000"}, fp.registryHosts)
+
+	c, err := m.Get(t.Context(), "dev")
+	require.NoError(t, err)
+	assert.Equal(t, []RegistryMirror{mirror}, c.RegistryMirrors)
+}
+
+// TestCreate_registryMirrors_existingCluster applies mirrors to a cluster
+// that already exists when WithCreateIfMissing is set.
+func TestCreate_registryMirrors_existingCluster(t *testing.T) {
+	fp := &fakeProvider{createErr: ErrAlreadyExists}
+	m := newTestManager(t, fp)
+
+	require.NoError(t, m.Create(t.Context(), "dev", WithCreateIfMissing(),
+		WithRegistryMirrors(RegistryMirror{Registry: "docker.io", Endpoint: "http://mirror.local:5000"})))
+	assert.Contains(t, fp.registryHosts, "docker.io")
+}
+
+// TestCreate_registryMirrors_invalid rejects a malformed mirror before
+// creating anything.
+func TestCreate_registryMirrors_invalid(t *testing.T) {
+	fp := &fakeProvider{}
+	m := newTestManager(t, fp)
+
+	err := m.Create(t.Context(), "dev", WithRegistryMirrors(RegistryMirror{Registry: "docker.io", Endpoint: "mirror.local"}))
+	require.Error(t, err)
+	assert.Zero(t, fp.createCalls)
+}
+
+// TestCreate_registryMirrors_nodeError returns a failure to configure the
+// nodes.
+func TestCreate_registryMirrors_nodeError(t *testing.T) {
+	fp := &fakeProvider{registryHostsErr: errors.New("exec failed")}
+	m := newTestManager(t, fp)
+
+	err := m.Create(t.Context(), "dev", WithRegistryMirrors(RegistryMirror{Registry: "docker.io", Endpoint: "http://mirror.local:5000"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exec failed")
+}
+
+// TestRecreate_keepsRegistryMirrors re-applies the deleted cluster's
+// mirrors unless new ones are passed.
+func TestRecreate_keepsRegistryMirrors(t *testing.T) {
+	fp := &fakeProvider{}
+	m := newTestManager(t, fp)
+	require.NoError(t, m.Create(t.Context(), "dev",
+		WithRegistryMirrors(RegistryMirror{Registry: "docker.io", Endpoint: "http://mirror.local:5000"})))
+
+	fp.registryHosts = nil
+	require.NoError(t, m.Recreate(t.Context(), "dev"))
+	assert.Equal(t, map[string]string{"docker.io": "http://mirror.local:5000"}, fp.registryHosts)
+
+	require.NoError(t, m.Recreate(t.Context(), "dev",
+		WithRegistryMirrors(RegistryMirror{Registry: "ghcr.io", Endpoint: "http://cache:5000"})))
+	assert.Equal(t, map[string]string{"ghcr.io": "http://cache:5000"}, fp.registryHosts)
+}
+
+// TestDelete_forgetsRegistryMirrors drops the metadata with the cluster.
+func TestDelete_forgetsRegistryMirrors(t *testing.T) {
+	fp := &fakeProvider{}
+	m := newTestManager(t, fp)
+	require.NoError(t, m.Create(t.Context(), "dev",
+		WithRegistryMirrors(RegistryMirror{Registry: "docker.io", Endpoint: "http://mirror.local:5000"})))
+	require.NoError(t, m.Delete(t.Context(), "dev"))
+
+	c, err := m.Get(t.Context(), "dev")
+	require.NoError(t, err)
+	assert.Empty(t, c.RegistryMirrors)
+}
+
+// TestVerifyRegistryMirrors reports each remembered mirror with the
+// provider's verdict, and nothing for a cluster without mirrors.
+func TestVerifyRegistryMirrors(t *testing.T) {
+	fp := &fakeProvider{verifyResult: map[string]error{"ghcr.io": errors.New("node dev-worker: hosts.toml is missing")}}
+	m := newTestManager(t, fp)
+
+	got, err := m.VerifyRegistryMirrors(t.Context(), "dev")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, m.Create(t.Context(), "dev", WithRegistryMirrors(
+		RegistryMirror{Registry: "docker.io", Endpoint: "http://mirror.local:5000"},
+		RegistryMirror{Registry: "ghcr.io", Endpoint: "http://cache:5000"},
+	)))
+	got, err = m.VerifyRegistryMirrors(t.Context(), "dev")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "docker.io", got[0].Registry)
+	assert.NoError(t, got[0].Err)
+	assert.ErrorContains(t, got[1].Err, "hosts.toml is missing")
+}
@@ -100,15 +100,38 @@ func WithRetainOnFailure(retain bool) CreateOption {
 	return func(c *createConfig) { c.retainOnFail = retain }
 }
 
-// WithPortMappings adds host-to-container port mappings to the cluster nodes.
-// Ignored if [WithKindConfig] is also set (raw config takes priority).
+// WithPortMappings adds host-to-container port mappings to the first
+// control-plane node. Ignored if [WithKindConfig] is also set (raw config
+// takes priority).
 func WithPortMappings(pms ...PortMapping) CreateOption {
 	return func(c *createConfig) { c.portMappings = append(c.portMappings, pms...) }
 }
 
+// WithNodes replaces the default single control-plane node with nodes.
+// nodes must include at least one [NodeRoleControlPlane] node. Ignored if
+// [WithKindConfig] is also set.
+func WithNodes(nodes ...NodeConfig) CreateOption {
+	return func(c *createConfig) { c.nodes = append(c.nodes, nodes...) }
+}
+
+// WithFeatureGates enables or disables Kubernetes feature gates on every
+// cluster component. Ignored if [WithKindConfig] is also set.
+func WithFeatureGates(gates map[string]bool) CreateOption {
+	return func(c *createConfig) { c.featureGates = gates }
+}
+
+// WithRegistryMirrors points the nodes' pulls from each mirror's registry
+// at its endpoint first. The mirrors are remembered with the cluster, so
+// [Manager.Recreate] applies them again, and they are written to an
+// existing cluster too when [WithCreateIfMissing] is set. Each call replaces
+// the mirrors of earlier ones.
+func WithRegistryMirrors(mirrors ...RegistryMirror) CreateOption {
+	return func(c *createConfig) { c.mirrors = mirrors }
+}
+
 // WithKindConfig supplies a raw Kind cluster config YAML.
-// When set, it takes priority over [WithPortMappings]. A warning is logged
-// if both are provided.
+// When set, it takes priority over [WithPortMappings], [WithNodes], and
+// [WithFeatureGates]. A warning is logged if any of them is also provided.
 func WithKindConfig(raw []byte) CreateOption {
 	return func(c *createConfig) { c.rawKindConfig = raw }
 }
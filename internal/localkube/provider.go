@@ -63,6 +63,18 @@ type provider interface {
 	// the named cluster. Ref-resolution is handled upstream by Manager.
 	// Returns ErrNotFound when no nodes exist for the named cluster.
 	loadImageArchive(ctx context.Context, name string, archive io.Reader) error
+
+	// writeRegistryHosts points every node of the named cluster at a
+	// registry endpoint per registry host, e.g. "localhost:5001" to
+	// "http://deployah-registry:5000". Returns ErrNotFound when no nodes
+	// exist for the named cluster.
+	writeRegistryHosts(ctx context.Context, name string, hosts map[string]string) error
+
+	// verifyRegistryHosts checks that every node of the named cluster has
+	// the configuration writeRegistryHosts writes for each host and can
+	// reach its endpoint. The result maps each host to nil or to what is
+	// wrong. Returns ErrNotFound when no nodes exist for the named cluster.
+	verifyRegistryHosts(ctx context.Context, name string, hosts map[string]string) (map[string]error, error)
 }
 
 // backendInfo carries metadata that a provider can supply for a cluster.
@@ -0,0 +1,28 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry manages the local OCI registry container that serves
+// images to local Kind clusters.
+//
+// The controller runs the upstream registry image as a detached container
+// (via a [gopherly.dev/currus] Engine), publishes its port on the host so
+// `docker push localhost:<port>/app:dev` works, and joins the "kind"
+// network so cluster nodes reach it by container name. The registry
+// outlives the cluster: images pushed to it survive a delete and recreate.
+//
+// Lifecycle: [Controller.Start] is idempotent: calling it when the container
+// is already running returns nil. [Controller.Stop] removes the container;
+// the images pushed to it go with it. [Controller.Healthy] probes the
+// registry API from the host.
+package registry
@@ -0,0 +1,235 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopherly.dev/currus"
+)
+
+const (
+	// DefaultImage is the pinned registry image used when no image override
+	// is provided.
+	DefaultImage = "docker.io/library/registry:2.8.3"
+
+	// DefaultPort is the host port the registry is published on.
+	DefaultPort = 5001
+
+	// ContainerName is the well-known name for the managed container. Cluster
+	// nodes on the same network reach the registry by this name.
+	ContainerName = "deployah-registry"
+
+	// containerPort is the port the registry listens on inside the container.
+	containerPort = 5000
+
+	// ownerLabel and ownerValue mark containers managed by this package.
+	ownerLabel = "deployah.dev/registry"
+	ownerValue = "true"
+
+	// healthTimeout bounds a single health probe.
+	healthTimeout = 3 * time.Second
+)
+
+// errContainerNotFound is returned by findContainer when no managed
+// container exists.
+var errContainerNotFound = errors.New("container not found")
+
+// Config holds the runtime configuration for the registry container.
+type Config struct {
+	// Image is the container image to run. Defaults to DefaultImage.
+	Image string
+
+	// Network is the container network to join. Defaults to "kind".
+	Network string
+
+	// Port is the host port to publish the registry on. Defaults to
+	// DefaultPort.
+	Port uint16
+}
+
+func (c *Config) image() string {
+	if c.Image != "" {
+		return c.Image
+	}
+	return DefaultImage
+}
+
+func (c *Config) network() string {
+	if c.Network != "" {
+		return c.Network
+	}
+	return "kind"
+}
+
+func (c *Config) port() uint16 {
+	if c.Port != 0 {
+		return c.Port
+	}
+	return DefaultPort
+}
+
+// Address returns the host address the registry is published on, e.g.
+// "localhost:5001".
+func (c *Config) Address() string {
+	return "localhost:" + strconv.Itoa(int(c.port()))
+}
+
+// Controller manages the lifecycle of the registry container.
+type Controller struct {
+	eng    currus.Engine
+	cfg    Config
+	client *http.Client
+}
+
+// New returns a Controller that uses eng to manage the registry container
+// with the given configuration.
+func New(eng currus.Engine, cfg Config) *Controller {
+	return &Controller{eng: eng, cfg: cfg, client: &http.Client{Timeout: healthTimeout}}
+}
+
+// Address returns the host address images are pushed to and referenced by,
+// e.g. "localhost:5001".
+func (c *Controller) Address() string {
+	return c.cfg.Address()
+}
+
+// Endpoint returns the URL cluster nodes pull from, which resolves on the
+// container network rather than the host.
+func (c *Controller) Endpoint() string {
+	return "http://" + ContainerName + ":" + strconv.Itoa(containerPort)
+}
+
+// Start ensures the registry container is running. It is idempotent: if the
+// container is already running, Start returns nil.
+func (c *Controller) Start(ctx context.Context) error {
+	existing, err := c.findContainer(ctx)
+	if err != nil && !errors.Is(err, errContainerNotFound) {
+		return fmt.Errorf("registry: find container: %w", err)
+	}
+	if existing != nil {
+		if existing.State == "running" {
+			return nil // already running
+		}
+		// Stopped container, e.g. after a host reboot. Starting it again keeps
+		// the images pushed to it.
+		if startErr := c.eng.StartContainer(ctx, existing.ID); startErr == nil {
+			return nil
+		}
+		if removeErr := c.eng.RemoveContainer(ctx, existing.ID, currus.RemoveContainerOpts{Force: true}); removeErr != nil {
+			return fmt.Errorf("registry: remove stale container: %w", removeErr)
+		}
+	}
+
+	if pullErr := c.eng.PullImage(ctx, c.cfg.image(), currus.PullImageOpts{}); pullErr != nil {
+		return fmt.Errorf("registry: pull image: %w", pullErr)
+	}
+
+	spec := currus.ContainerSpec{
+		Image:   c.cfg.image(),
+		Name:    ContainerName,
+		Restart: currus.RestartPolicy{Mode: currus.RestartUnlessStopped},
+		Labels: map[string]string{
+			ownerLabel: ownerValue,
+		},
+		Networks: []currus.NetworkAttachment{{Name: c.cfg.network()}},
+		Ports:    []currus.Port{{Container: containerPort, Host: c.cfg.port()}},
+	}
+
+	id, err := c.eng.CreateContainer(ctx, spec)
+	if err != nil {
+		return fmt.Errorf("registry: create container: %w", err)
+	}
+	if startErr := c.eng.StartContainer(ctx, id); startErr != nil {
+		// Best-effort cleanup of the created but unstarted container.
+		_ = c.eng.RemoveContainer(context.WithoutCancel(ctx), id, currus.RemoveContainerOpts{Force: true}) //nolint:errcheck
+		return fmt.Errorf("registry: start container: %w", startErr)
+	}
+	return nil
+}
+
+// Stop removes the registry container. It is idempotent: if no container is
+// found, Stop returns nil.
+func (c *Controller) Stop(ctx context.Context) error {
+	existing, err := c.findContainer(ctx)
+	if err != nil {
+		if errors.Is(err, errContainerNotFound) {
+			return nil
+		}
+		return fmt.Errorf("registry: find container: %w", err)
+	}
+	if removeErr := c.eng.RemoveContainer(ctx, existing.ID, currus.RemoveContainerOpts{Force: true}); removeErr != nil {
+		return fmt.Errorf("registry: remove container: %w", removeErr)
+	}
+	return nil
+}
+
+// Exists reports whether a registry container exists, running or not. It
+// returns false if detection fails.
+func (c *Controller) Exists(ctx context.Context) bool {
+	existing, err := c.findContainer(ctx)
+	return err == nil && existing != nil
+}
+
+// Running reports whether the registry container is currently running. It
+// returns false if no container is found or if detection fails.
+func (c *Controller) Running(ctx context.Context) bool {
+	existing, err := c.findContainer(ctx)
+	if err != nil || existing == nil {
+		return false
+	}
+	return existing.State == "running"
+}
+
+// Healthy probes the registry's /v2/ API on the published port. A running
+// container that does not answer, e.g. because another process holds the
+// port, returns an error.
+func (c *Controller) Healthy(ctx context.Context) error {
+	url := "http://127.0.0.1:" + strconv.Itoa(int(c.cfg.port())) + "/v2/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("registry: build health request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry: %s unreachable: %w", c.Address(), err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	// A registry with auth answers 401; either way the API is up.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("registry: %s answered /v2/ with %s", c.Address(), resp.Status)
+	}
+	return nil
+}
+
+// findContainer returns the registry container if it exists, or nil if it
+// does not. It filters by the owner label.
+func (c *Controller) findContainer(ctx context.Context) (*currus.Container, error) {
+	all, err := c.eng.ListContainers(ctx, currus.ListContainersOpts{All: true})
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].Labels[ownerLabel] == ownerValue {
+			return &all[i], nil
+		}
+	}
+	return nil, errContainerNotFound
+}
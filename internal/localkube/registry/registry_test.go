@@ -0,0 +1,140 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopherly.dev/currus"
+	"gopherly.dev/currus/currustest"
+)
+
+// TestStart_PublishesPort verifies that the registry container is labeled as
+// managed and publishes the registry port on the configured host port.
+func TestStart_PublishesPort(t *testing.T) {
+	eng := currustest.New()
+	ctx := t.Context()
+
+	ctrl := New(eng, Config{Port: 5050})
+	require.NoError(t, ctrl.Start(ctx))
+
+	containers, err := eng.ListContainers(ctx, currus.ListContainersOpts{All: true})
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, ownerValue, containers[0].Labels[ownerLabel])
+
+	info, err := eng.Inspect(ctx, containers[0].ID)
+	require.NoError(t, err)
+	require.Len(t, info.Ports, 1)
+	assert.Equal(t, uint16(containerPort), info.Ports[0].Container)
+	assert.Equal(t, uint16(5050), info.Ports[0].Host)
+	assert.Equal(t, "localhost:5050", ctrl.Address())
+}
+
+// TestStart_Idempotent verifies that a second Start leaves the running
+// container alone.
+func TestStart_Idempotent(t *testing.T) {
+	eng := currustest.New()
+	ctx := t.Context()
+
+	ctrl := New(eng, Config{})
+	require.NoError(t, ctrl.Start(ctx))
+	require.NoError(t, ctrl.Start(ctx))
+
+	containers, err := eng.ListContainers(ctx, currus.ListContainersOpts{All: true})
+	require.NoError(t, err)
+	assert.Len(t, containers, 1)
+	assert.True(t, ctrl.Running(ctx))
+}
+
+// TestStop_RemovesOnlyRegistry verifies that Stop removes the registry
+// container, leaves unrelated containers alone, and is idempotent.
+func TestStop_RemovesOnlyRegistry(t *testing.T) {
+	eng := currustest.New()
+	ctx := t.Context()
+
+	unrelatedID, err := eng.CreateContainer(ctx, currus.ContainerSpec{
+		Image: "registry:2",
+		Name:  "my-registry",
+	})
+	require.NoError(t, err)
+	require.NoError(t, eng.StartContainer(ctx, unrelatedID))
+
+	ctrl := New(eng, Config{})
+	require.NoError(t, ctrl.Start(ctx))
+	require.NoError(t, ctrl.Stop(ctx))
+	require.NoError(t, ctrl.Stop(ctx))
+
+	remaining, err := eng.ListContainers(ctx, currus.ListContainersOpts{All: true})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, unrelatedID, remaining[0].ID)
+	assert.False(t, ctrl.Exists(ctx))
+}
+
+// TestHealthy probes /v2/ on the configured port: 200 and 401 are healthy,
+// anything else or no listener is not.
+func TestHealthy(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"ok", http.StatusOK, false},
+		{"auth required", http.StatusUnauthorized, false},
+		{"not a registry", http.StatusNotFound, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/", r.URL.Path)
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			ctrl := New(currustest.New(), Config{Port: listenerPort(t, srv.Listener)})
+			err := ctrl.Healthy(t.Context())
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		port := listenerPort(t, srv.Listener)
+		srv.Close()
+
+		ctrl := New(currustest.New(), Config{Port: port})
+		assert.Error(t, ctrl.Healthy(t.Context()))
+	})
+}
+
+func listenerPort(t *testing.T, l net.Listener) uint16 {
+	t.Helper()
+	_, portStr, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	require.NoError(t, err)
+	return uint16(port)
+}
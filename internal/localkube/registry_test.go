@@ -0,0 +1,80 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localkube
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopherly.dev/currus/currustest"
+)
+
+// TestStartRegistry_ErrUnsupported verifies that a Manager with no engine
+// returns ErrUnsupported from every registry method.
+func TestStartRegistry_ErrUnsupported(t *testing.T) {
+	m := newTestManager(t, &fakeProvider{})
+
+	assert.ErrorIs(t, m.StartRegistry(t.Context(), "mycluster"), ErrUnsupported)
+	assert.ErrorIs(t, m.StopRegistry(t.Context()), ErrUnsupported)
+	_, err := m.RegistryStatus(t.Context())
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+// TestStartRegistry_connectsNodes verifies that StartRegistry points the
+// cluster nodes at the registry container under its host address.
+func TestStartRegistry_connectsNodes(t *testing.T) {
+	fp := &fakeProvider{}
+	m := newTestManager(t, fp)
+	m.eng = currustest.New()
+
+	require.NoError(t, m.StartRegistry(t.Context(), "mycluster"))
+	assert.Equal(t, map[string]string{"localhost:5001": "http://deployah-registry:5000"}, fp.registryHosts)
+	assert.Equal(t, "localhost:5001", m.RegistryAddress())
+}
+
+// TestStartRegistry_nodeError verifies a failure to configure the nodes is
+// returned.
+func TestStartRegistry_nodeError(t *testing.T) {
+	fp := &fakeProvider{registryHostsErr: errors.New("exec failed")}
+	m := newTestManager(t, fp)
+	m.eng = currustest.New()
+
+	err := m.StartRegistry(t.Context(), "mycluster")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exec failed")
+}
+
+// TestRegistryStatus_notStarted verifies that RegistryStatus returns
+// ErrNotFound until a registry has been started.
+func TestRegistryStatus_notStarted(t *testing.T) {
+	m := newTestManager(t, &fakeProvider{})
+	m.eng = currustest.New()
+
+	_, err := m.RegistryStatus(t.Context())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, m.StartRegistry(t.Context(), "mycluster"))
+	_, err = m.RegistryStatus(t.Context())
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+// TestRegistryHostsTOML verifies the containerd hosts.toml sends pulls to
+// the endpoint.
+func TestRegistryHostsTOML(t *testing.T) {
+	got := registryHostsTOML("http://deployah-registry:5000")
+	assert.Equal(t, "[host.\"http://deployah-registry:5000\"]\n  capabilities = [\"pull\", \"resolve\"]\n", got)
+}
@@ -37,6 +37,9 @@ type Cluster struct {
 	// CreatedAt is the time the first node container was started.
 	// May be zero if the backend cannot determine it.
 	CreatedAt time.Time
+	// RegistryMirrors are the mirrors set with [WithRegistryMirrors] when
+	// the cluster was created through this package.
+	RegistryMirrors []RegistryMirror
 }
 
 // KubeConfig is an immutable value returned by [Manager.KubeConfig].
@@ -84,6 +87,60 @@ type PortMapping struct {
 	ListenAddress string
 }
 
+// NodeRole is the role of a cluster node.
+type NodeRole string
+
+const (
+	// NodeRoleControlPlane runs the Kubernetes control plane.
+	NodeRoleControlPlane NodeRole = "control-plane"
+	// NodeRoleWorker runs workloads only.
+	NodeRoleWorker NodeRole = "worker"
+)
+
+// TaintEffect is the effect of a node [Taint].
+type TaintEffect string
+
+const (
+	// TaintNoSchedule keeps pods without a matching toleration off the node.
+	TaintNoSchedule TaintEffect = "NoSchedule"
+	// TaintPreferNoSchedule avoids the node for such pods when possible.
+	TaintPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	// TaintNoExecute also evicts such pods that already run on the node.
+	TaintNoExecute TaintEffect = "NoExecute"
+)
+
+// Taint is a Kubernetes taint registered on a node when it joins.
+type Taint struct {
+	Key    string
+	Value  string
+	Effect TaintEffect
+}
+
+// Mount bind-mounts a host path into a node container.
+type Mount struct {
+	// HostPath is the absolute path on the host.
+	HostPath string
+	// ContainerPath is the absolute path inside the node container.
+	ContainerPath string
+	// ReadOnly mounts the path read-only.
+	ReadOnly bool
+}
+
+// NodeConfig describes one node of a cluster created with [WithNodes].
+type NodeConfig struct {
+	// Role is [NodeRoleControlPlane] or [NodeRoleWorker].
+	Role NodeRole
+	// Labels are Kubernetes labels set on the node.
+	Labels map[string]string
+	// Taints are registered on the node. On a control-plane node they
+	// replace kubeadm's default control-plane taint.
+	Taints []Taint
+	// PortMappings forward host ports to ports of this node's container.
+	PortMappings []PortMapping
+	// Mounts bind-mount host paths into this node's container.
+	Mounts []Mount
+}
+
 // Runtime identifies the host container engine used to run cluster node
 // containers.
 type Runtime int
@@ -176,7 +233,8 @@ const (
 	StepDeleting Step = "deleting"
 	// StepWritingKubeconfig is emitted by Manager.KubeConfig.
 	StepWritingKubeconfig Step = "writing-kubeconfig"
-	// StepLoadingImage is emitted by Manager.LoadImage / Manager.LoadImageArchive.
+	// StepLoadingImage is emitted by Manager.LoadImage, Manager.LoadImagePinned,
+	// and Manager.LoadImageArchive.
 	StepLoadingImage Step = "loading-image"
 	// StepResolvingImage is emitted during image reference resolution.
 	StepResolvingImage Step = "resolving-image"
@@ -188,6 +246,16 @@ const (
 	// StepStoppingCloudProvider is emitted by Manager.StopCloudProvider.
 	StepStoppingCloudProvider Step = "stopping-cloud-provider"
 
+	// Registry steps.
+
+	// StepStartingRegistry is emitted by Manager.StartRegistry.
+	StepStartingRegistry Step = "starting-registry"
+	// StepStoppingRegistry is emitted by Manager.StopRegistry.
+	StepStoppingRegistry Step = "stopping-registry"
+	// StepConfiguringMirrors is emitted by Manager.Create when it writes
+	// registry mirrors to the nodes.
+	StepConfiguringMirrors Step = "configuring-registry-mirrors"
+
 	// Kind internal phases — emitted by the Kind backend as it provisions a cluster.
 	// These are normalized from Kind's raw log lines; callers can switch on them.
 
@@ -115,6 +115,9 @@ func TasksFromSpec(manifest *spec.Spec, environment string, resolved *spec.Resol
 			Timeout:    rt.Task.Timeout,
 			HookWeight: rt.HookWeight,
 			Manual:     rt.Task.On == spec.TaskOnManual,
+			Schedule:   rt.Task.Schedule,
+			TimeZone:   rt.Task.TimeZone,
+			Suspend:    rt.Task.Suspend,
 		})
 	}
 	return out, nil
@@ -128,6 +131,8 @@ func plannedTaskOn(on spec.TaskOn) string {
 		return TaskOnPostDeploy
 	case spec.TaskOnManual:
 		return TaskOnManual
+	case spec.TaskOnSchedule:
+		return TaskOnSchedule
 	default:
 		return string(on)
 	}
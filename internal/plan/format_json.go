@@ -23,11 +23,13 @@ import (
 // jsonFormatVersion is the schema version emitted by [NewJSONDocument]. Bump
 // it, and document the change, whenever a field is added, removed, or
 // changes meaning. Two deliberate omissions (no next revision, no
-// spec-vocabulary path) keep 1.0 stable.
-const jsonFormatVersion = "1.0"
+// spec-vocabulary path) keep 1.x stable.
+//
+//   - 1.1 adds plan_hash.
+const jsonFormatVersion = "1.1"
 
 // JSONDocument is the "--output json" wire format for a [Plan]
-// (format_version "1.0"). Field names use snake_case.
+// (format_version "1.1"). Field names use snake_case.
 type JSONDocument struct {
 	FormatVersion string `json:"format_version"`
 	Project       string `json:"project"`
@@ -53,6 +55,10 @@ type JSONDocument struct {
 	DriftIncomplete  []string     `json:"drift_incomplete,omitempty"`
 	Tasks            []JSONTask   `json:"tasks,omitempty"`
 	FirstInstallNote string       `json:"first_install_note,omitempty"`
+	// PlanHash is the content hash of the plan file written with
+	// `deployah plan --out`, to pass to `deployah deploy --plan-hash` so only
+	// that file is applied. Omitted when no plan file was written.
+	PlanHash string `json:"plan_hash,omitempty"`
 }
 
 // JSONTask is one entry in [JSONDocument.Tasks].
@@ -62,6 +68,9 @@ type JSONTask struct {
 	Timeout    string `json:"timeout,omitempty"`
 	HookWeight int    `json:"hook_weight"`
 	Manual     bool   `json:"manual,omitempty"`
+	Schedule   string `json:"schedule,omitempty"`
+	TimeZone   string `json:"time_zone,omitempty"`
+	Suspend    bool   `json:"suspend,omitempty"`
 }
 
 // JSONChange is one entry in [JSONDocument.Changes].
@@ -92,7 +101,7 @@ type JSONSummary struct {
 	Destroy int `json:"destroy"`
 }
 
-// NewJSONDocument converts p into the format_version "1.0" JSON document.
+// NewJSONDocument converts p into the format_version "1.1" JSON document.
 // It masks secret field values unconditionally (calling [ApplyMasking] is
 // safe to repeat): JSON output ignores --show-secrets by design, so a CI
 // job can pipe it anywhere without a credential-leak review.
@@ -134,6 +143,7 @@ func NewJSONDocument(p *Plan) *JSONDocument {
 		doc.Tasks = append(doc.Tasks, JSONTask(task))
 	}
 	doc.FirstInstallNote = p.FirstInstallTaskNote()
+	doc.PlanHash = p.Hash
 
 	return doc
 }
@@ -168,7 +178,7 @@ func toJSONChange(c Change) JSONChange {
 	return jc
 }
 
-// RenderJSON writes p to w as pretty-printed format_version "1.0" JSON; see
+// RenderJSON writes p to w as pretty-printed format_version "1.1" JSON; see
 // [NewJSONDocument].
 func RenderJSON(w io.Writer, p *Plan) error {
 	if p == nil {
@@ -43,7 +43,7 @@ func TestRenderJSON_HeaderAndMixedChanges(t *testing.T) {
 	var doc map[string]any
 	require.NoError(t, json.Unmarshal([]byte(buf.String()), &doc))
 
-	assert.Equal(t, "1.0", doc["format_version"])
+	assert.Equal(t, "1.1", doc["format_version"])
 	assert.Equal(t, "web", doc["project"])
 	assert.Equal(t, "production", doc["environment"])
 	assert.Equal(t, "web-production", doc["release"])
@@ -357,3 +357,18 @@ func TestRenderJSON_TasksSection(t *testing.T) {
 		})
 	}
 }
+
+// TestRenderJSON_PlanHash verifies the saved plan hash is embedded when the
+// plan was written with --out and omitted otherwise.
+func TestRenderJSON_PlanHash(t *testing.T) {
+	t.Parallel()
+	p, err := ComputeDiff(deploymentV1, deploymentV1)
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, RenderJSON(&buf, p))
+	assert.NotContains(t, buf.String(), "plan_hash")
+
+	p.Hash = "abc123"
+	assert.Equal(t, "abc123", NewJSONDocument(p).PlanHash)
+}
@@ -392,6 +392,7 @@ func writeTasks(w io.Writer, p *Plan, opts TextOptions) error {
 	}{
 		{TaskOnPreDeploy, TaskOnPreDeploy},
 		{TaskOnPostDeploy, TaskOnPostDeploy},
+		{"schedule (CronJob)", TaskOnSchedule},
 		{"manual (CLI only)", TaskOnManual},
 	}
 	for _, g := range groups {
@@ -401,7 +402,7 @@ func writeTasks(w io.Writer, p *Plan, opts TextOptions) error {
 				items = append(items, task)
 			}
 		}
-		if g.on != TaskOnManual {
+		if g.on == TaskOnPreDeploy || g.on == TaskOnPostDeploy {
 			slices.SortFunc(items, func(a, b PlannedTask) int {
 				if a.HookWeight != b.HookWeight {
 					return a.HookWeight - b.HookWeight
@@ -417,10 +418,13 @@ func writeTasks(w io.Writer, p *Plan, opts TextOptions) error {
 		}
 		for _, task := range items {
 			line := "    " + task.Name
+			if task.Schedule != "" {
+				line += " " + scheduleLabel(task)
+			}
 			if task.Timeout != "" {
 				line += " (timeout " + task.Timeout + ")"
 			}
-			if !task.Manual {
+			if task.On == TaskOnPreDeploy || task.On == TaskOnPostDeploy {
 				line += fmt.Sprintf(" weight %d", task.HookWeight)
 			}
 			if _, err := fmt.Fprintln(w, line); err != nil {
@@ -438,6 +442,19 @@ func writeTasks(w io.Writer, p *Plan, opts TextOptions) error {
 	return nil
 }
 
+// scheduleLabel renders a scheduled task's cron line, e.g.
+// `"0 2 * * *" Europe/Berlin, suspended`.
+func scheduleLabel(task PlannedTask) string {
+	label := fmt.Sprintf("%q", task.Schedule)
+	if task.TimeZone != "" {
+		label += " " + task.TimeZone
+	}
+	if task.Suspend {
+		label += ", suspended"
+	}
+	return label
+}
+
 // String renders the summary trailer, e.g.
 // "1 to add, 1 to change, 1 to destroy".
 func (s Summary) String() string {
@@ -455,6 +472,8 @@ var compactPathMappings = []struct {
 	{regexp.MustCompile(`^spec\.template\.spec\.containers\.[^.]+\.image$`), "image"},
 	{regexp.MustCompile(`^spec\.template\.spec\.containers\.[^.]+\.ports\.[^.]+\.containerPort$`), "port"},
 	{regexp.MustCompile(`^spec\.template\.spec\.containers\.[^.]+\.env\.([^.]+)\.value$`), "env.$1"},
+	{regexp.MustCompile(`^spec\.template\.spec\.containers\.[^.]+\.resources\.requests\.([^.]+)$`), "resources.$1"},
+	{regexp.MustCompile(`^spec\.template\.spec\.containers\.[^.]+\.resources\.limits(\.[^.]+)?$`), "resources.limits$1"},
 }
 
 // mapCompactPath maps a raw dyff path to Deployah's compact vocabulary. It
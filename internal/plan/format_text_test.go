@@ -412,6 +412,21 @@ func TestRenderText_TasksSection(t *testing.T) {
 			contains: []string{"Tasks:", "manual (CLI only)", "backfill"},
 			omits:    []string{"preDeploy", "weight"},
 		},
+		{
+			name: "scheduled task shows its schedule",
+			plan: &Plan{
+				Tasks: []PlannedTask{
+					{Name: "report", On: TaskOnSchedule, Schedule: "0 2 * * *", TimeZone: "Europe/Berlin", Timeout: "30m"},
+					{Name: "cleanup", On: TaskOnSchedule, Schedule: "@hourly", Suspend: true},
+				},
+			},
+			contains: []string{
+				"schedule (CronJob)",
+				`report "0 2 * * *" Europe/Berlin (timeout 30m)`,
+				`cleanup "@hourly", suspended`,
+			},
+			omits: []string{"weight", "manual"},
+		},
 		{
 			name:  "no tasks omits section",
 			plan:  &Plan{Header: Header{FreshInstall: true}},
@@ -468,6 +483,7 @@ func TestTasksFromSpec(t *testing.T) {
 			"migrate": {From: "api", On: spec.TaskOnPreDeploy, Command: []string{"true"}},
 			"smoke":   {From: "api", On: spec.TaskOnPostDeploy, Environments: []string{"dev", "prod"}, Command: []string{"true"}},
 			"nightly": {From: "api", On: spec.TaskOnManual, Environments: []string{"prod"}, Command: []string{"true"}},
+			"report":  {Image: "busybox:1.36", On: spec.TaskOnSchedule, Schedule: "0 2 * * *", TimeZone: "UTC", Environments: []string{"prod"}},
 		},
 	}
 	tests := []struct {
@@ -488,6 +504,7 @@ func TestTasksFromSpec(t *testing.T) {
 			want: []PlannedTask{
 				{Name: "migrate", On: TaskOnPreDeploy},
 				{Name: "nightly", On: TaskOnManual, Manual: true},
+				{Name: "report", On: TaskOnSchedule, Schedule: "0 2 * * *", TimeZone: "UTC"},
 				{Name: "smoke", On: TaskOnPostDeploy},
 			},
 		},
@@ -527,6 +544,9 @@ func TestMapCompactPath(t *testing.T) {
 		{"spec.template.spec.containers.web.image", "image", true},
 		{"spec.template.spec.containers.web.ports.http.containerPort", "port", true},
 		{"spec.template.spec.containers.web.env.NODE_ENV.value", "env.NODE_ENV", true},
+		{"spec.template.spec.containers.web.resources.requests.cpu", "resources.cpu", true},
+		{"spec.template.spec.containers.web.resources.limits.memory", "resources.limits.memory", true},
+		{"spec.template.spec.containers.web.resources.limits", "resources.limits", true},
 		{"metadata.labels.foo", "", false},
 	}
 	for _, tt := range tests {
@@ -19,6 +19,8 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 
 	"helm.sh/helm/v4/pkg/release/common"
 
@@ -51,14 +53,7 @@ func LastSuccessfulRelease(ctx context.Context, client historyClient, project, e
 		return nil, "", fmt.Errorf("fetching release history: %w", err)
 	}
 
-	// The Kubernetes API (backing Helm's secret/configmap storage drivers)
-	// does not guarantee list order, so history must be sorted explicitly:
-	// newest revision (highest Version) first.
-	releases := slices.Clone(history)
-	slices.SortFunc(releases, func(a, b *v1.Release) int {
-		return b.Version - a.Version
-	})
-
+	releases := SortedReleases(history)
 	if len(releases) == 0 {
 		// No history at all: treat as a fresh install, where every
 		// resource in the current render is an addition.
@@ -66,15 +61,15 @@ func LastSuccessfulRelease(ctx context.Context, client historyClient, project, e
 	}
 
 	latest := releases[0]
-	if status := latest.Info.Status; status != common.StatusDeployed && status != common.StatusSuperseded {
+	if !Successful(latest) {
 		// Newest revision isn't itself successful (failed, or an
 		// install/upgrade/rollback still in progress): warn so the caller
 		// can surface this alongside the older successful revision below.
-		warning = fmt.Sprintf("latest revision %d is %s; comparing against the last successful revision instead", latest.Version, status)
+		warning = fmt.Sprintf("latest revision %d is %s; comparing against the last successful revision instead", latest.Version, latest.Info.Status)
 	}
 
 	for _, rel := range releases {
-		if rel.Info.Status == common.StatusDeployed || rel.Info.Status == common.StatusSuperseded {
+		if Successful(rel) {
 			return rel, warning, nil
 		}
 	}
@@ -87,3 +82,79 @@ func LastSuccessfulRelease(ctx context.Context, client historyClient, project, e
 	}
 	return nil, warning, nil
 }
+
+// LatestRevision returns the newest revision in a release's history,
+// whatever its status, or 0 when the release does not exist. A saved plan
+// records it as its base and is stale once it changes.
+func LatestRevision(ctx context.Context, client historyClient, project, environment string) (int, error) {
+	history, err := client.GetReleaseHistory(ctx, project, environment)
+	if err != nil {
+		if errors.Is(err, helm.ErrReleaseNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("fetching release history: %w", err)
+	}
+	latest := 0
+	for _, rel := range history {
+		if rel != nil && rel.Version > latest {
+			latest = rel.Version
+		}
+	}
+	return latest, nil
+}
+
+// Successful reports whether rel was deployed or superseded: a revision
+// whose manifest is known good, and so one to diff against or roll back
+// to.
+func Successful(rel *v1.Release) bool {
+	if rel == nil || rel.Info == nil {
+		return false
+	}
+	return rel.Info.Status == common.StatusDeployed || rel.Info.Status == common.StatusSuperseded
+}
+
+// SortedReleases drops nil entries from history and orders it newest
+// revision (highest Version) first. The Kubernetes API backing Helm's
+// secret and configmap storage drivers does not guarantee list order.
+func SortedReleases(history []*v1.Release) []*v1.Release {
+	releases := slices.DeleteFunc(slices.Clone(history), func(r *v1.Release) bool { return r == nil })
+	slices.SortFunc(releases, func(a, b *v1.Release) int {
+		return b.Version - a.Version
+	})
+	return releases
+}
+
+// FindRevision returns the revision numbered version in releases, as
+// sorted by [SortedReleases]. The error lists the revisions there are.
+func FindRevision(releases []*v1.Release, version int) (*v1.Release, error) {
+	for _, rel := range releases {
+		if rel != nil && rel.Version == version {
+			return rel, nil
+		}
+	}
+	available := make([]string, 0, len(releases))
+	for _, rel := range releases {
+		if rel != nil {
+			available = append(available, strconv.Itoa(rel.Version))
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found; available revisions: %s", version, strings.Join(available, ", "))
+}
+
+// ReleaseStatus returns rel's status for display, or "unknown" when Helm
+// recorded none.
+func ReleaseStatus(rel *v1.Release) string {
+	if rel.Info == nil {
+		return "unknown"
+	}
+	return rel.Info.Status.String()
+}
+
+// ChartValues returns the values of the chart rel was installed from, nil
+// when the release carries no chart.
+func ChartValues(rel *v1.Release) map[string]any {
+	if rel.Chart == nil {
+		return nil
+	}
+	return rel.Chart.Values
+}
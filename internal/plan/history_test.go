@@ -153,3 +153,40 @@ func TestLastSuccessfulRelease(t *testing.T) {
 		})
 	}
 }
+
+// TestLatestRevision returns the newest revision of any status, and 0 for
+// a release that does not exist.
+func TestLatestRevision(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeHistoryClient{releases: []*v1.Release{
+		releaseAt(2, common.StatusSuperseded),
+		releaseAt(4, common.StatusFailed),
+		releaseAt(3, common.StatusDeployed),
+	}}
+	rev, err := LatestRevision(t.Context(), client, "web", "production")
+	require.NoError(t, err)
+	assert.Equal(t, 4, rev)
+
+	rev, err = LatestRevision(t.Context(), &fakeHistoryClient{err: helm.ErrReleaseNotFound}, "web", "production")
+	require.NoError(t, err)
+	assert.Zero(t, rev)
+
+	_, err = LatestRevision(t.Context(), &fakeHistoryClient{err: errors.New("boom")}, "web", "production")
+	require.Error(t, err)
+}
+
+func TestFindRevision(t *testing.T) {
+	t.Parallel()
+
+	releases := SortedReleases([]*v1.Release{{Version: 1}, nil, {Version: 3}, {Version: 2}})
+	require.Len(t, releases, 3)
+	assert.Equal(t, 3, releases[0].Version)
+
+	rel, err := FindRevision(releases, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rel.Version)
+
+	_, err = FindRevision(releases, 7)
+	require.ErrorContains(t, err, "available revisions: 3, 2, 1")
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/renameio/v2"
+	"sigs.k8s.io/yaml"
+
+	"deployah.dev/deployah/internal/render"
+
+	v1 "helm.sh/helm/v4/pkg/release/v1"
+)
+
+const (
+	// savedPlanFormat identifies a file written by [WriteSavedPlan].
+	savedPlanFormat = "deployah-plan"
+	// savedPlanFormatVersion is bumped whenever [SavedPlan] changes in a
+	// way an older deployah would apply incorrectly.
+	savedPlanFormatVersion = 1
+)
+
+// ErrPlanCorrupt is returned by [ReadSavedPlan] when a plan file's content
+// no longer matches the hash recorded in it. The hash catches truncation
+// and stray edits, not tampering: anyone who can edit the file can also
+// recompute it. Pass the hash `deployah plan --out` printed to
+// `deployah deploy --plan-hash` to pin the file that was reviewed.
+var ErrPlanCorrupt = errors.New("plan file content does not match its recorded hash")
+
+// SavedPlan is the artifact `deployah plan --out` writes and `deployah
+// deploy --plan-file` applies: the reviewed render and everything it was
+// computed from, so the apply installs exactly what was shown instead of
+// rendering the spec again.
+//
+// Values hold decrypted provider secrets, so a plan file is as sensitive as
+// the secrets themselves.
+type SavedPlan struct {
+	Project     string `json:"project"`
+	Environment string `json:"environment"`
+	Release     string `json:"release"`
+	Namespace   string `json:"namespace"`
+	Context     string `json:"context"`
+	// APIVersion is the spec apiVersion the chart was generated from,
+	// recorded in the release labels on apply.
+	APIVersion string `json:"api_version"`
+	// BaseRevision is the newest release revision, whatever its status,
+	// when the plan was made; 0 when the release did not exist yet. A plan
+	// is stale once the live release moves past it.
+	BaseRevision int `json:"base_revision"`
+	// Manifest and Hooks are the reviewed render.
+	Manifest string     `json:"manifest"`
+	Hooks    []*v1.Hook `json:"hooks,omitempty"`
+	// Chart holds the prepared chart's files, keyed by slash-separated path
+	// relative to the chart root.
+	Chart map[string][]byte `json:"chart"`
+	// Values are passed to Helm on top of the chart's values.yaml.
+	Values map[string]any `json:"values,omitempty"`
+	// Extras and CRDs are the .deployah/manifests and .deployah/crds bundle
+	// the plan was rendered with.
+	Extras []SavedObject `json:"extras,omitempty"`
+	CRDs   []SavedObject `json:"crds,omitempty"`
+}
+
+// SavedObject is one extra manifest or CRD stored in a [SavedPlan].
+type SavedObject struct {
+	// Path is the file the object was loaded from.
+	Path string `json:"path"`
+	// YAML is the deploy-ready object.
+	YAML string `json:"yaml"`
+}
+
+// savedPlanFile is the on-disk envelope around a [SavedPlan]. SHA256 is
+// taken over the exact bytes of Plan, so an edit that does not also update
+// it is detected.
+type savedPlanFile struct {
+	Format        string          `json:"format"`
+	FormatVersion int             `json:"format_version"`
+	SHA256        string          `json:"sha256"`
+	Plan          json.RawMessage `json:"plan"`
+}
+
+// NewSavedPlan captures p and the render behind it. The chart files are
+// read from result.ChartPath, so call it before the render's cleanup runs.
+// baseRevision is the newest release revision (see [LatestRevision]).
+func NewSavedPlan(p *Plan, result *render.RenderResult, apiVersion string, baseRevision int) (*SavedPlan, error) {
+	files, err := readChartFiles(result.ChartPath)
+	if err != nil {
+		return nil, fmt.Errorf("read chart: %w", err)
+	}
+	return &SavedPlan{
+		Project:      p.Header.Project,
+		Environment:  p.Header.Environment,
+		Release:      p.Header.Release,
+		Namespace:    p.Header.Namespace,
+		Context:      p.Header.Context,
+		APIVersion:   apiVersion,
+		BaseRevision: baseRevision,
+		Manifest:     result.Manifest,
+		Hooks:        result.Hooks,
+		Chart:        files,
+		Values:       result.Values,
+	}, nil
+}
+
+// WriteSavedPlan writes sp to path, readable by the owner only, and
+// returns the content hash recorded in the file.
+func WriteSavedPlan(path string, sp *SavedPlan) (string, error) {
+	body, err := json.Marshal(sp)
+	if err != nil {
+		return "", fmt.Errorf("encode plan: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	data, err := json.Marshal(savedPlanFile{
+		Format:        savedPlanFormat,
+		FormatVersion: savedPlanFormatVersion,
+		SHA256:        hash,
+		Plan:          body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode plan file: %w", err)
+	}
+	if err := renameio.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+	return hash, nil
+}
+
+// ReadSavedPlan reads a plan file written by [WriteSavedPlan] and returns
+// it with its content hash. It fails with [ErrPlanCorrupt] when the plan
+// no longer matches the recorded hash.
+func ReadSavedPlan(path string) (*SavedPlan, string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied plan file path
+	if err != nil {
+		return nil, "", fmt.Errorf("read plan file: %w", err)
+	}
+	var file savedPlanFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Format != savedPlanFormat {
+		return nil, "", fmt.Errorf("%s is not a deployah plan file", path)
+	}
+	if file.FormatVersion != savedPlanFormatVersion {
+		return nil, "", fmt.Errorf("%s: plan format version %d is not supported (want %d); re-run 'deployah plan --out'",
+			path, file.FormatVersion, savedPlanFormatVersion)
+	}
+	sum := sha256.Sum256(file.Plan)
+	if hash := hex.EncodeToString(sum[:]); hash != file.SHA256 {
+		return nil, "", fmt.Errorf("%s: %w", path, ErrPlanCorrupt)
+	}
+	var sp SavedPlan
+	if err := json.Unmarshal(file.Plan, &sp); err != nil {
+		return nil, "", fmt.Errorf("%s: decode plan: %w", path, err)
+	}
+	return &sp, file.SHA256, nil
+}
+
+// ChartValues returns the saved chart's values.yaml, which a release
+// installed from sp records as its chart values. The workload guards read
+// the deployah.resolved block from it.
+func (sp *SavedPlan) ChartValues() (map[string]any, error) {
+	values := map[string]any{}
+	if err := yaml.Unmarshal(sp.Chart["values.yaml"], &values); err != nil {
+		return nil, fmt.Errorf("plan file values.yaml: %w", err)
+	}
+	return values, nil
+}
+
+// WriteChart restores the saved chart files under dir.
+func (sp *SavedPlan) WriteChart(dir string) error {
+	for name, data := range sp.Chart {
+		clean := path.Clean(name)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("chart file %q is outside the chart", name)
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(clean))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+			return fmt.Errorf("create %s: %w", filepath.Dir(dst), err)
+		}
+		if err := os.WriteFile(dst, data, 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", dst, err)
+		}
+	}
+	return nil
+}
+
+// readChartFiles reads every file under root, keyed by slash-separated
+// path relative to root.
+func readChartFiles(root string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || d.IsDir() {
+			return walkErr
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p) // #nosec G304 -- path from WalkDir within the prepared chart
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return files, err
+}
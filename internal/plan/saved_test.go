@@ -0,0 +1,118 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/render"
+)
+
+func newTestSavedPlan(t *testing.T) *SavedPlan {
+	t.Helper()
+	chartDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte("name: web\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(chartDir, "templates"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "templates", "app.yaml"), []byte("kind: Deployment\n"), 0o600))
+
+	p := &Plan{Header: Header{
+		Project:     "web",
+		Environment: "production",
+		Release:     "web-production",
+		Namespace:   "default",
+		Context:     "prod",
+	}}
+	result := &render.RenderResult{
+		ChartPath: chartDir,
+		Manifest:  deploymentV1,
+		Values:    map[string]any{"secrets": map[string]any{"API_KEY": "s3cret"}},
+	}
+	sp, err := NewSavedPlan(p, result, "v1-alpha.1", 3)
+	require.NoError(t, err)
+	return sp
+}
+
+// TestSavedPlan_RoundTrip writes a plan file and reads it back with the
+// same hash, then restores the chart files.
+func TestSavedPlan_RoundTrip(t *testing.T) {
+	t.Parallel()
+	sp := newTestSavedPlan(t)
+	path := filepath.Join(t.TempDir(), "plan.dpy")
+
+	hash, err := WriteSavedPlan(path, sp)
+	require.NoError(t, err)
+	require.Len(t, hash, 64)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	got, gotHash, err := ReadSavedPlan(path)
+	require.NoError(t, err)
+	assert.Equal(t, hash, gotHash)
+	assert.Equal(t, "web-production", got.Release)
+	assert.Equal(t, 3, got.BaseRevision)
+	assert.Equal(t, "v1-alpha.1", got.APIVersion)
+	assert.Equal(t, deploymentV1, got.Manifest)
+	assert.Equal(t, sp.Values, got.Values)
+
+	dir := t.TempDir()
+	require.NoError(t, got.WriteChart(dir))
+	data, err := os.ReadFile(filepath.Join(dir, "templates", "app.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Deployment\n", string(data))
+}
+
+// TestReadSavedPlan_Corrupt detects an edit to the plan body that left the
+// recorded hash alone.
+func TestReadSavedPlan_Corrupt(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "plan.dpy")
+	_, err := WriteSavedPlan(path, newTestSavedPlan(t))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	edited := strings.Replace(string(data), `"base_revision":3`, `"base_revision":4`, 1)
+	require.NotEqual(t, string(data), edited)
+	require.NoError(t, os.WriteFile(path, []byte(edited), 0o600))
+
+	_, _, err = ReadSavedPlan(path)
+	require.ErrorIs(t, err, ErrPlanCorrupt)
+}
+
+// TestReadSavedPlan_NotAPlan rejects files that are not plan files.
+func TestReadSavedPlan_NotAPlan(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "plan.dpy")
+	require.NoError(t, os.WriteFile(path, []byte(`{"format":"other"}`), 0o600))
+
+	_, _, err := ReadSavedPlan(path)
+	require.ErrorContains(t, err, "not a deployah plan file")
+}
+
+// TestSavedPlan_WriteChartRejectsEscape refuses chart paths that would
+// land outside the target directory.
+func TestSavedPlan_WriteChartRejectsEscape(t *testing.T) {
+	t.Parallel()
+	sp := &SavedPlan{Chart: map[string][]byte{"../evil.yaml": []byte("x")}}
+	require.ErrorContains(t, sp.WriteChart(t.TempDir()), "outside the chart")
+}
@@ -161,8 +161,12 @@ type Plan struct {
 	DriftIncomplete []string
 
 	// Tasks lists spec tasks active in this environment, grouped by the
-	// renderer into preDeploy, postDeploy, and manual.
+	// renderer into preDeploy, postDeploy, schedule, and manual.
 	Tasks []PlannedTask
+
+	// Hash is the content hash of the saved plan file this plan was written
+	// to with `deployah plan --out`; empty when it was not saved.
+	Hash string
 }
 
 const (
@@ -172,6 +176,8 @@ const (
 	TaskOnPostDeploy = "postDeploy"
 	// TaskOnManual is a task that runs only via the CLI.
 	TaskOnManual = "manual"
+	// TaskOnSchedule is a task that runs from a CronJob.
+	TaskOnSchedule = "schedule"
 )
 
 // PlannedTask is one spec task shown in the plan Tasks section.
@@ -181,6 +187,10 @@ type PlannedTask struct {
 	Timeout    string
 	HookWeight int
 	Manual     bool
+	// Schedule, TimeZone, and Suspend are set for scheduled tasks only.
+	Schedule string
+	TimeZone string
+	Suspend  bool
 }
 
 // FirstInstallTaskNote returns a warning when this plan is a fresh install
@@ -37,6 +37,10 @@ type RenderResult struct {
 	ChartPath string
 	// Manifest is the rendered, "---"-concatenated Kubernetes YAML.
 	Manifest string
+	// Values are the values passed to Helm on top of the chart's own
+	// values.yaml (the decrypted provider secrets). Together with the files
+	// under ChartPath they are everything the render was computed from.
+	Values map[string]any
 	// Hooks are the Helm hooks declared by the chart for this release.
 	Hooks []*v1.Hook
 	// IsUpgrade is true when an existing release was found and the render
@@ -0,0 +1,26 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves the provider-backed entries of a component's
+// secrets block. A [Provider] decrypts one local file; a [Resolver] picks the
+// provider named by each [deployah.dev/deployah/internal/spec.SecretSource],
+// extracts the requested key, and caches decrypted files in memory for its
+// own lifetime only.
+//
+// The built-in providers, [SOPS] and [Age], shell out to the sops and age
+// CLIs. Both work offline against local files and key material. Decrypted
+// values are never written to disk by this package: the Helm client passes
+// them to Helm as install values, outside the prepared chart directory that
+// [deployah.dev/deployah/internal/helm.ChartCache] keeps.
+package secrets
@@ -0,0 +1,110 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+// AgeIdentityEnvVar names the age identity file used by [Age] when its
+// IdentityFile is empty.
+const AgeIdentityEnvVar = "DEPLOYAH_AGE_IDENTITY_FILE"
+
+// sopsAgeKeyFileEnvVar is the sops variable for the age identity file;
+// [Age] falls back to it so one key file serves both providers.
+const sopsAgeKeyFileEnvVar = "SOPS_AGE_KEY_FILE"
+
+// SOPS decrypts files with the sops CLI. sops finds its own keys (age, PGP,
+// or a cloud KMS) from its usual environment variables and config files;
+// with age or PGP keys it needs no network access.
+type SOPS struct {
+	// Binary is the sops executable. Empty means "sops" on PATH.
+	Binary string
+}
+
+// Decrypt runs `sops --decrypt path` and returns its output, in the same
+// format as the encrypted file.
+func (p SOPS) Decrypt(ctx context.Context, path string) ([]byte, error) {
+	return runDecrypt(ctx, "sops", p.Binary, path, "--decrypt", path)
+}
+
+// Age decrypts files with the age CLI.
+type Age struct {
+	// Binary is the age executable. Empty means "age" on PATH.
+	Binary string
+	// IdentityFile is the age identity (private key) file. Empty falls back
+	// to $DEPLOYAH_AGE_IDENTITY_FILE, then $SOPS_AGE_KEY_FILE, then the sops
+	// default keys.txt under the user config directory.
+	IdentityFile string
+}
+
+// Decrypt runs `age --decrypt -i identity path` and returns its output.
+func (p Age) Decrypt(ctx context.Context, path string) ([]byte, error) {
+	identity := p.identityFile()
+	if _, err := os.Stat(identity); err != nil {
+		return nil, fmt.Errorf("age identity file %s: %w (set %s to point at your key)", identity, err, AgeIdentityEnvVar)
+	}
+	return runDecrypt(ctx, "age", p.Binary, path, "--decrypt", "-i", identity, path)
+}
+
+func (p Age) identityFile() string {
+	if p.IdentityFile != "" {
+		return p.IdentityFile
+	}
+	if v := os.Getenv(AgeIdentityEnvVar); v != "" {
+		return v
+	}
+	if v := os.Getenv(sopsAgeKeyFileEnvVar); v != "" {
+		return v
+	}
+	return filepath.Join(xdg.ConfigHome, "sops", "age", "keys.txt")
+}
+
+// runDecrypt runs binary (or name when binary is empty) with args and
+// returns stdout. stderr is folded into the error so a wrong key or a
+// corrupt file explains itself; it never contains the plaintext.
+func runDecrypt(ctx context.Context, name, binary, path string, args ...string) ([]byte, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("encrypted file %s: %w", path, err)
+	}
+	if binary == "" {
+		binary = name
+	}
+
+	// #nosec G204 -- binary is the provider's CLI, args are the spec's file path
+	cmd := exec.CommandContext(ctx, binary, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%s not found on PATH; install it to decrypt %s", name, path)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s could not decrypt %s: %s", name, path, msg)
+		}
+		return nil, fmt.Errorf("%s could not decrypt %s: %w", name, path, err)
+	}
+	return out, nil
+}
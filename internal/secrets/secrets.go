@@ -0,0 +1,185 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+
+	"deployah.dev/deployah/internal/spec"
+)
+
+// ErrKeyNotFound is returned when a decrypted document has no value at the
+// requested key path.
+var ErrKeyNotFound = errors.New("secret key not found")
+
+// Provider decrypts a local encrypted file.
+type Provider interface {
+	// Decrypt returns the plaintext of the file at path.
+	Decrypt(ctx context.Context, path string) ([]byte, error)
+}
+
+// ProviderFunc adapts a function to [Provider].
+type ProviderFunc func(ctx context.Context, path string) ([]byte, error)
+
+// Decrypt calls f.
+func (f ProviderFunc) Decrypt(ctx context.Context, path string) ([]byte, error) {
+	return f(ctx, path)
+}
+
+// Option configures a [Resolver].
+type Option func(*Resolver)
+
+// WithProvider registers p under name, replacing a built-in provider of the
+// same name.
+func WithProvider(name spec.SecretProvider, p Provider) Option {
+	return func(r *Resolver) {
+		r.providers[name] = p
+	}
+}
+
+// fileKey identifies one decrypted file in the [Resolver] cache.
+type fileKey struct {
+	provider spec.SecretProvider
+	path     string
+}
+
+// Resolver resolves [spec.SecretSource] values through registered
+// providers. Each file is decrypted once per Resolver, so several keys from
+// one file cost a single sops or age run. Methods are safe for concurrent
+// use.
+type Resolver struct {
+	providers map[spec.SecretProvider]Provider
+
+	mu    sync.Mutex
+	files map[fileKey][]byte
+}
+
+// NewResolver returns a resolver with the built-in [SOPS] and [Age]
+// providers, adjusted by opts.
+func NewResolver(opts ...Option) *Resolver {
+	r := &Resolver{
+		providers: map[spec.SecretProvider]Provider{
+			spec.SecretProviderSOPS: SOPS{},
+			spec.SecretProviderAge:  Age{},
+		},
+		files: make(map[fileKey][]byte),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Resolve returns the plaintext value src points at. Cluster references
+// (secretRef) are not resolvable here: their value stays in the cluster.
+func (r *Resolver) Resolve(ctx context.Context, src spec.SecretSource) (string, error) {
+	if src.IsClusterRef() {
+		return "", fmt.Errorf("%s is resolved by Kubernetes, not at render time", src)
+	}
+	data, err := r.decrypt(ctx, src.Provider, src.File)
+	if err != nil {
+		return "", err
+	}
+	value, err := extractKey(data, src.Key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", src, err)
+	}
+	return value, nil
+}
+
+// ResolveComponent returns every provider-backed secret of component, keyed
+// by environment variable name. Cluster references are skipped. The result
+// is nil when there is nothing to decrypt.
+func (r *Resolver) ResolveComponent(ctx context.Context, component spec.Component) (map[string]string, error) {
+	names := component.ProviderSecrets()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(names))
+	for _, name := range names {
+		value, err := r.Resolve(ctx, component.Secrets[name])
+		if err != nil {
+			return nil, fmt.Errorf("secrets.%s: %w", name, err)
+		}
+		out[name] = value
+	}
+	return out, nil
+}
+
+func (r *Resolver) decrypt(ctx context.Context, provider spec.SecretProvider, path string) ([]byte, error) {
+	p, ok := r.providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown secret provider %q", provider)
+	}
+
+	key := fileKey{provider: provider, path: path}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if data, cached := r.files[key]; cached {
+		return data, nil
+	}
+	data, err := p.Decrypt(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	r.files[key] = data
+	return data, nil
+}
+
+// extractKey returns the scalar at the dot-separated key path in the
+// decrypted YAML or JSON document data. An empty key returns the whole
+// document minus one trailing newline, which `echo value | age` and most
+// editors add.
+func extractKey(data []byte, key string) (string, error) {
+	if key == "" {
+		s := strings.TrimSuffix(string(data), "\n")
+		return strings.TrimSuffix(s, "\r"), nil
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("decrypted file is not YAML or JSON, so key %q cannot be looked up: %w", key, err)
+	}
+	node := doc
+	for part := range strings.SplitSeq(key, ".") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+		}
+		if node, ok = m[part]; !ok {
+			return "", fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+		}
+	}
+
+	switch v := node.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", fmt.Errorf("key %q is null", key)
+	default:
+		return "", fmt.Errorf("key %q is a map or list, not a single value", key)
+	}
+}
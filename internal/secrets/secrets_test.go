@@ -0,0 +1,163 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+)
+
+// fakeProvider returns a provider serving files from a map and counting
+// decrypt calls.
+func fakeProvider(files map[string]string, calls *atomic.Int32) Provider {
+	return ProviderFunc(func(_ context.Context, path string) ([]byte, error) {
+		calls.Add(1)
+		content, ok := files[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	})
+}
+
+// TestResolver_KeyPaths verifies nested keys and scalar types are extracted
+// and a file is decrypted once for several keys.
+func TestResolver_KeyPaths(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	r := NewResolver(WithProvider(spec.SecretProviderSOPS, fakeProvider(map[string]string{
+		"prod.enc.yaml": "db:\n  password: s3cret\n  port: 5432\nfeatures:\n  beta: true\n",
+	}, &calls)))
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "db.password", want: "s3cret"},
+		{key: "db.port", want: "5432"},
+		{key: "features.beta", want: "true"},
+	}
+	for _, tt := range tests {
+		got, err := r.Resolve(t.Context(), spec.SecretSource{Provider: spec.SecretProviderSOPS, File: "prod.enc.yaml", Key: tt.key})
+		require.NoError(t, err, tt.key)
+		assert.Equal(t, tt.want, got, tt.key)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// TestResolver_WholeFile verifies an empty key returns the whole decrypted
+// file without its trailing newline.
+func TestResolver_WholeFile(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	r := NewResolver(WithProvider(spec.SecretProviderAge, fakeProvider(map[string]string{
+		"token.age": "abc123\n",
+	}, &calls)))
+
+	got, err := r.Resolve(t.Context(), spec.SecretSource{Provider: spec.SecretProviderAge, File: "token.age"})
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", got)
+}
+
+// TestResolver_Errors covers a missing key, a non-scalar key, and a
+// cluster reference, which is never resolved locally.
+func TestResolver_Errors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	r := NewResolver(WithProvider(spec.SecretProviderSOPS, fakeProvider(map[string]string{
+		"prod.enc.yaml": "db:\n  password: s3cret\n",
+	}, &calls)))
+	ctx := t.Context()
+
+	_, err := r.Resolve(ctx, spec.SecretSource{Provider: spec.SecretProviderSOPS, File: "prod.enc.yaml", Key: "db.user"})
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = r.Resolve(ctx, spec.SecretSource{Provider: spec.SecretProviderSOPS, File: "prod.enc.yaml", Key: "db"})
+	require.ErrorContains(t, err, "not a single value")
+
+	_, err = r.Resolve(ctx, spec.SecretSource{SecretRef: &spec.SecretKeyRef{Name: "db", Key: "password"}})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// TestResolver_ResolveComponent verifies only provider-backed secrets are
+// resolved and the error names the failing variable.
+func TestResolver_ResolveComponent(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	r := NewResolver(WithProvider(spec.SecretProviderSOPS, fakeProvider(map[string]string{
+		"prod.enc.yaml": "stripe: sk_live\n",
+	}, &calls)))
+
+	component := spec.Component{Secrets: map[string]spec.SecretSource{
+		"DATABASE_PASSWORD": {SecretRef: &spec.SecretKeyRef{Name: "db", Key: "password"}},
+		"STRIPE_KEY":        {Provider: spec.SecretProviderSOPS, File: "prod.enc.yaml", Key: "stripe"},
+	}}
+	got, err := r.ResolveComponent(t.Context(), component)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"STRIPE_KEY": "sk_live"}, got)
+
+	component.Secrets["OTHER"] = spec.SecretSource{Provider: spec.SecretProviderSOPS, File: "missing.enc.yaml"}
+	_, err = r.ResolveComponent(t.Context(), component)
+	require.ErrorContains(t, err, "secrets.OTHER")
+}
+
+// TestSOPS_Decrypt runs the provider against a stand-in sops script to
+// check the arguments it passes and the missing-binary error.
+func TestSOPS_Decrypt(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("stand-in CLI is a shell script")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "prod.enc.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("db:\n  password: s3cret\n"), 0o600))
+	script := filepath.Join(dir, "sops")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n[ \"$1\" = --decrypt ] || exit 1\ncat \"$2\"\n"), 0o700)) // #nosec G306 -- test executable
+
+	out, err := SOPS{Binary: script}.Decrypt(t.Context(), file)
+	require.NoError(t, err)
+	assert.Equal(t, "db:\n  password: s3cret\n", string(out))
+
+	_, err = SOPS{Binary: "deployah-test-no-such-sops"}.Decrypt(t.Context(), file)
+	require.ErrorContains(t, err, "not found on PATH")
+}
+
+// TestAge_MissingIdentity verifies a missing identity file is reported
+// before age runs.
+func TestAge_MissingIdentity(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "token.age")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0o600))
+
+	_, err := Age{IdentityFile: filepath.Join(dir, "keys.txt")}.Decrypt(t.Context(), file)
+	require.ErrorContains(t, err, AgeIdentityEnvVar)
+}
@@ -40,6 +40,17 @@ type HelmClient interface {
 	// manifests before they are sent to the cluster.
 	InstallApp(ctx context.Context, manifest *spec.Spec, environment string, dryRun bool, resolved *spec.ResolvedSpec, postRenderer postrenderer.PostRenderer) error
 
+	// InstallChart installs or upgrades the release for project/environment
+	// from an already prepared chart directory and values, such as the
+	// chart a saved plan captured. postRenderer, when non-nil, is applied
+	// to the rendered manifests before they are sent to the cluster.
+	InstallChart(ctx context.Context, project, environment, apiVersion, chartPath string, values map[string]any, postRenderer postrenderer.PostRenderer) error
+
+	// RenderChart renders an already prepared chart directory with values
+	// client-side, the way InstallChart would apply it, without mutating
+	// the cluster or Helm's release history.
+	RenderChart(ctx context.Context, project, environment, apiVersion, chartPath string, values map[string]any, postRenderer postrenderer.PostRenderer) (*render.RenderResult, error)
+
 	// RenderManifests renders the chart for manifest/environment client-side,
 	// without mutating the cluster or Helm's release history. The caller must
 	// run the returned cleanup func once done with the result's ChartPath.
@@ -49,6 +49,7 @@ type Session struct {
 	storageDriver string
 	debug         bool
 	timeout       time.Duration
+	cliVersion    string
 
 	platform *spec.PlatformConfig
 	mu       sync.Mutex
@@ -118,6 +119,12 @@ func WithDebug(keep bool) Option {
 	return func(s *Session) { s.debug = keep }
 }
 
+// WithCLIVersion sets the Deployah version that Helm clients record on each
+// release revision.
+func WithCLIVersion(version string) Option {
+	return func(s *Session) { s.cliVersion = version }
+}
+
 // WithTimeout sets the timeout for Helm operations.
 func WithTimeout(timeout time.Duration) Option {
 	return func(s *Session) { s.timeout = timeout }
@@ -227,28 +234,50 @@ func (s *Session) Spec(ctx context.Context, environment string) (*spec.Spec, err
 	return m, nil
 }
 
-// Target resolves the Kubernetes context for env and returns a [Cluster] from
-// which Helm and Kubernetes clients can be obtained.
+// Target resolves the Kubernetes context and namespace for project and env
+// and returns a [Cluster] from which Helm and Kubernetes clients can be
+// obtained. project may be empty when the caller does not know it yet.
 //
 // Precedence for the kubeContext used by the returned Cluster:
 //  1. The global --context flag (already stored in s.kubeContext).
 //  2. The platform file's context for env (via [PlatformEnvContext]).
 //  3. The default context from the active kubeconfig (empty string).
-func (s *Session) Target(ctx context.Context, env string) (*Cluster, error) {
+//
+// Precedence for the namespace:
+//  1. The global --namespace flag (already stored in s.namespace).
+//  2. The platform file's namespace template for env, rendered for project
+//     (via [spec.PlatformEnvNamespace]). A template that needs the project
+//     is skipped when project is empty.
+//  3. [DefaultNamespace].
+func (s *Session) Target(ctx context.Context, project, env string) (*Cluster, error) {
 	kubeCtx := s.kubeContext
+	namespace := s.namespace
+	var platformNamespace *spec.PlatformNamespace
 
-	// When no --context override, try the platform file.
-	if kubeCtx == "" && env != "" {
+	if env != "" && (kubeCtx == "" || namespace == "") {
 		if p, err := s.Platform(); err == nil && p != nil {
-			if pCtx := spec.PlatformEnvContext(p, env); pCtx != "" {
-				kubeCtx = pCtx
+			// When no --context override, try the platform file.
+			if kubeCtx == "" {
+				kubeCtx = spec.PlatformEnvContext(p, env)
+			}
+			if ns := spec.PlatformEnvNamespace(p, env); namespace == "" && ns != nil {
+				if project != "" || !ns.NeedsProject() {
+					rendered, renderErr := ns.Render(project, spec.NormalizeEnv(env))
+					if renderErr != nil {
+						return nil, fmt.Errorf("environment %q: %w", env, renderErr)
+					}
+					namespace = rendered
+					platformNamespace = ns
+				}
 			}
 		}
 	}
 
 	cluster := &Cluster{
-		Session:     s,
-		kubeContext: kubeCtx,
+		Session:           s,
+		kubeContext:       kubeCtx,
+		namespace:         namespace,
+		platformNamespace: platformNamespace,
 	}
 	if kubeCtx == "" {
 		// Record the fallback where it happens so every command can warn
@@ -322,6 +351,7 @@ func (s *Session) cloneWithContext(kubeContext string) *Session {
 		storageDriver:        s.storageDriver,
 		debug:                s.debug,
 		timeout:              s.timeout,
+		cliVersion:           s.cliVersion,
 		helmFactory:          s.helmFactory,
 		k8sFactory:           s.k8sFactory,
 	}
@@ -351,6 +381,9 @@ func defaultHelmFactory(s *Session) (HelmClient, error) {
 	if s.debug {
 		opts = append(opts, helm.WithDebug(s.debug))
 	}
+	if s.cliVersion != "" {
+		opts = append(opts, helm.WithCLIVersion(s.cliVersion))
+	}
 	return helm.NewClient(opts...)
 }
 
@@ -418,6 +451,11 @@ func (s *Session) kubeconfigRESTConfig() (*rest.Config, error) {
 type Cluster struct {
 	*Session
 	kubeContext string
+	// namespace shadows Session.namespace with the resolved namespace.
+	namespace string
+	// platformNamespace is the platform config namespace came from, or nil
+	// when it came from --namespace or the default.
+	platformNamespace *spec.PlatformNamespace
 
 	// usedFallback is true when neither --context nor a platform context
 	// resolved, so clients follow the kubeconfig current-context
@@ -440,7 +478,7 @@ func (cl *Cluster) ContextFallback() (bool, string) {
 // An empty string means the default context from the active kubeconfig is used.
 func (cl *Cluster) Context() string { return cl.kubeContext }
 
-// Namespace returns the configured namespace, or "default" if none is set.
+// Namespace returns the resolved namespace, or "default" if none is set.
 func (cl *Cluster) Namespace() string {
 	if cl.namespace != "" {
 		return cl.namespace
@@ -448,12 +486,21 @@ func (cl *Cluster) Namespace() string {
 	return DefaultNamespace
 }
 
+// PlatformNamespace returns the platform file's namespace config when it
+// chose [Cluster.Namespace], so deploy can create the namespace with its
+// labels and annotations. Nil when --namespace or the default applied.
+func (cl *Cluster) PlatformNamespace() *spec.PlatformNamespace {
+	return cl.platformNamespace
+}
+
 // sessionForContext returns a shallow Session copy targeted at this
-// cluster's resolved kube context, with cached clients cleared. Used by
-// Helm, Kubernetes, and RESTConfig so the three paths share one clone
-// helper.
+// cluster's resolved kube context and namespace, with cached clients
+// cleared. Used by Helm, Kubernetes, and RESTConfig so the three paths
+// share one clone helper.
 func (cl *Cluster) sessionForContext() *Session {
-	return cl.cloneWithContext(cl.kubeContext)
+	tmp := cl.cloneWithContext(cl.kubeContext)
+	tmp.namespace = cl.namespace
+	return tmp
 }
 
 // Helm returns a memoized Helm client targeted at the resolved cluster.
@@ -86,6 +86,25 @@ func (m *MockHelmClient) InstallApp(ctx context.Context, manifest *spec.Spec, en
 	return args.Error(0)
 }
 
+// InstallChart implements [HelmClient].
+func (m *MockHelmClient) InstallChart(ctx context.Context, project, environment, apiVersion, chartPath string, values map[string]any, postRenderer postrenderer.PostRenderer) error {
+	args := m.Called(ctx, project, environment, apiVersion, chartPath, values, postRenderer)
+	return args.Error(0)
+}
+
+// RenderChart implements [HelmClient].
+func (m *MockHelmClient) RenderChart(ctx context.Context, project, environment, apiVersion, chartPath string, values map[string]any, postRenderer postrenderer.PostRenderer) (*render.RenderResult, error) {
+	args := m.Called(ctx, project, environment, apiVersion, chartPath, values, postRenderer)
+	if err := args.Error(1); err != nil {
+		return nil, err
+	}
+	result, ok := args.Get(0).(*render.RenderResult)
+	if !ok {
+		return nil, fmt.Errorf("unexpected mock return type %T", args.Get(0))
+	}
+	return result, nil
+}
+
 // RenderManifests implements [HelmClient].
 func (m *MockHelmClient) RenderManifests(ctx context.Context, manifest *spec.Spec, environment string, resolved *spec.ResolvedSpec, postRenderer postrenderer.PostRenderer) (*render.RenderResult, func(), error) {
 	args := m.Called(ctx, manifest, environment, resolved, postRenderer)
@@ -194,7 +213,7 @@ func TestSessionWithDependencyInjection(t *testing.T) {
 			return mockHelm, nil
 		}))
 
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		assert.NoError(t, err)
 
 		helmClient, err := cluster.Helm()
@@ -208,7 +227,7 @@ func TestSessionWithDependencyInjection(t *testing.T) {
 			return fakeCS, nil
 		}))
 
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		assert.NoError(t, err)
 
 		k8sClient, err := cluster.Kubernetes()
@@ -246,7 +265,7 @@ func TestSessionWithDependencyInjection(t *testing.T) {
 			return mockHelm, nil
 		}))
 
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		require.NoError(t, err)
 		c1, err1 := cluster.Helm()
 		c2, err2 := cluster.Helm()
@@ -263,7 +282,7 @@ func TestSessionWithDependencyInjection(t *testing.T) {
 			return nil, expectedError
 		}))
 
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		require.NoError(t, err)
 		client, err := cluster.Helm()
 
@@ -330,7 +349,7 @@ func TestContextOperations(t *testing.T) {
 func TestTarget(t *testing.T) {
 	t.Run("empty env returns cluster with empty context", func(t *testing.T) {
 		sess := New()
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		assert.NoError(t, err)
 		assert.NotNil(t, cluster)
 		assert.Equal(t, "", cluster.kubeContext)
@@ -338,14 +357,14 @@ func TestTarget(t *testing.T) {
 
 	t.Run("global context flag wins over platform", func(t *testing.T) {
 		sess := New(WithKubeContext("my-context"))
-		cluster, err := sess.Target(t.Context(), "prod")
+		cluster, err := sess.Target(t.Context(), "", "prod")
 		assert.NoError(t, err)
 		assert.Equal(t, "my-context", cluster.kubeContext)
 	})
 
 	t.Run("no platform file falls back to default context", func(t *testing.T) {
 		sess := New(WithSpecPath("/nonexistent/path/deployah.yaml"))
-		cluster, err := sess.Target(t.Context(), "prod")
+		cluster, err := sess.Target(t.Context(), "", "prod")
 		assert.NoError(t, err)
 		assert.NotNil(t, cluster)
 		assert.Equal(t, "", cluster.kubeContext)
@@ -366,7 +385,7 @@ environments:
 		require.NoError(t, writeFile(platformPath, platformYAML))
 
 		sess := New(WithPlatformFile(platformPath))
-		cluster, err := sess.Target(t.Context(), "production")
+		cluster, err := sess.Target(t.Context(), "shop", "production")
 		assert.NoError(t, err)
 		assert.Equal(t, "prod-eks", cluster.kubeContext)
 	})
@@ -389,21 +408,50 @@ environments:
 			WithPlatformFile(platformPath),
 			WithKubeContext("my-override"),
 		)
-		cluster, err := sess.Target(t.Context(), "production")
+		cluster, err := sess.Target(t.Context(), "shop", "production")
 		assert.NoError(t, err)
 		assert.Equal(t, "my-override", cluster.kubeContext)
 	})
 
+	t.Run("platform namespace template is rendered for project and env", func(t *testing.T) {
+		platformPath := t.TempDir() + "/deployah.platform.yaml"
+		platformYAML := `apiVersion: platform/v1-alpha.3
+environments:
+  review:
+    context: kind-deployah
+    namespace:
+      name: "{project}-{env}"
+      labels:
+        team: payments
+`
+		require.NoError(t, writeFile(platformPath, platformYAML))
+
+		cluster, err := New(WithPlatformFile(platformPath)).Target(t.Context(), "shop", "review/pr-123")
+		require.NoError(t, err)
+		assert.Equal(t, "shop-review-pr-123", cluster.Namespace())
+		require.NotNil(t, cluster.PlatformNamespace())
+		assert.Equal(t, map[string]string{"team": "payments"}, cluster.PlatformNamespace().Labels)
+
+		cluster, err = New(WithPlatformFile(platformPath), WithNamespace("manual")).Target(t.Context(), "shop", "review/pr-123")
+		require.NoError(t, err)
+		assert.Equal(t, "manual", cluster.Namespace(), "--namespace wins over the platform")
+		assert.Nil(t, cluster.PlatformNamespace())
+
+		cluster, err = New(WithPlatformFile(platformPath)).Target(t.Context(), "", "review/pr-123")
+		require.NoError(t, err)
+		assert.Equal(t, DefaultNamespace, cluster.Namespace(), "template needing a project is skipped without one")
+	})
+
 	t.Run("cluster namespace falls back to default", func(t *testing.T) {
 		sess := New()
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		require.NoError(t, err)
 		assert.Equal(t, DefaultNamespace, cluster.Namespace())
 	})
 
 	t.Run("cluster namespace uses session value", func(t *testing.T) {
 		sess := New(WithNamespace("my-ns"))
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		require.NoError(t, err)
 		assert.Equal(t, "my-ns", cluster.Namespace())
 	})
@@ -770,7 +818,7 @@ func TestClusterRESTConfig(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			cluster, err := tt.setup(t).Target(t.Context(), "")
+			cluster, err := tt.setup(t).Target(t.Context(), "", "")
 			require.NoError(t, err)
 
 			cfg, err := cluster.RESTConfig()
@@ -797,7 +845,7 @@ func TestIntegrationWithMocks(t *testing.T) {
 			return mockHelm, nil
 		}))
 
-		cluster, err := sess.Target(t.Context(), "")
+		cluster, err := sess.Target(t.Context(), "", "")
 		assert.NoError(t, err)
 
 		helmClient, err := cluster.Helm()
@@ -218,6 +218,11 @@ const (
 	// Kubernetes rejects Indexed Jobs when parallelism is above 10^5.
 	MaxFanoutParallelism = 100_000
 
+	// DefaultConcurrencyPolicy is the concurrencyPolicy of scheduled tasks
+	// when omitted. Forbid rather than the Kubernetes default of Allow, so
+	// a slow run is never stacked on by the next one.
+	DefaultConcurrencyPolicy = ConcurrencyPolicyForbid
+
 	// DefaultCLIJobTTLSeconds is how long CLI-triggered Jobs are kept
 	// after they finish (7 days).
 	DefaultCLIJobTTLSeconds = 7 * 24 * 60 * 60
@@ -243,6 +248,10 @@ const (
 	// DefaultResourcePreset is the default resource preset when none is specified
 	DefaultResourcePreset = "small"
 
+	// DefaultContainerResourcePreset is the preset for sidecars and init
+	// containers that set neither resources nor resourcePreset.
+	DefaultContainerResourcePreset = ResourcePresetNano
+
 	// MinCPUMillicores is the minimum CPU allocation in millicores
 	MinCPUMillicores = 10
 
@@ -273,6 +282,10 @@ const (
 	// LabelVersion is the label key for API version tracking
 	LabelVersion = LabelPrefix + "/version"
 
+	// LabelCLIVersion is the Helm release label key recording the Deployah
+	// release that installed or upgraded a revision.
+	LabelCLIVersion = LabelPrefix + "/cli-version"
+
 	// LabelComponent is the label key for component identification
 	LabelComponent = LabelPrefix + "/component"
 
@@ -0,0 +1,178 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// PersistenceVolumeName is the pod volume backing a component's
+// persistence block. Sidecars and init containers mount it by this name.
+const PersistenceVolumeName = "data"
+
+// Container is a sidecar or init container that shares the pod with a
+// component's main container. Its name is the key in the component's
+// sidecars or initContainers map.
+type Container struct {
+	// Image is the container image reference.
+	Image string `json:"image" yaml:"image"`
+	// Command overrides the container entrypoint.
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+	// Args overrides the container command arguments.
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+	// Env sets static environment variables for the container. Component
+	// env and secrets are not inherited.
+	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Resources sets explicit CPU, memory, and storage requests and limits.
+	Resources Resources `json:"resources,omitzero" yaml:"resources,omitempty"`
+	// ResourcePreset selects a named resource profile when Resources is
+	// empty. Defaults to [DefaultContainerResourcePreset].
+	ResourcePreset ResourcePreset `json:"resourcePreset,omitempty" yaml:"resourcePreset,omitempty"`
+	// VolumeMounts mounts shared pod volumes into the container.
+	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty" yaml:"volumeMounts,omitempty"`
+}
+
+// VolumeMount mounts a pod volume shared between a component's containers.
+// [PersistenceVolumeName] refers to the persistence volume; any other name
+// is an emptyDir that lives as long as the pod.
+type VolumeMount struct {
+	// Name is the shared volume name (a DNS-1123 label).
+	Name string `json:"name" yaml:"name"`
+	// MountPath is the absolute path inside the container.
+	MountPath string `json:"mountPath" yaml:"mountPath"`
+	// ReadOnly mounts the volume read-only.
+	ReadOnly bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+}
+
+// SidecarNames returns the component's sidecar names, sorted.
+func (c Component) SidecarNames() []string {
+	return slices.Sorted(maps.Keys(c.Sidecars))
+}
+
+// InitContainerNames returns the component's init container names in the
+// order they run, which is sorted by name.
+func (c Component) InitContainerNames() []string {
+	return slices.Sorted(maps.Keys(c.InitContainers))
+}
+
+// SharedVolumes returns the names of the emptyDir volumes the component's
+// containers mount, sorted. The persistence volume is not included.
+func (c Component) SharedVolumes() []string {
+	seen := map[string]struct{}{}
+	collect := func(mounts []VolumeMount) {
+		for _, m := range mounts {
+			if m.Name != PersistenceVolumeName {
+				seen[m.Name] = struct{}{}
+			}
+		}
+	}
+	collect(c.VolumeMounts)
+	for _, ctr := range c.Sidecars {
+		collect(ctr.VolumeMounts)
+	}
+	for _, ctr := range c.InitContainers {
+		collect(ctr.VolumeMounts)
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// ValidateComponentContainers checks sidecar and init container names,
+// their env, resources, and volume mounts, and the main container's shared
+// volume mounts. name is the component name, which is also the main
+// container name, so no extra container may reuse it.
+func ValidateComponentContainers(name string, component Component) error {
+	var errs []error
+	if err := validateVolumeMounts(component.VolumeMounts, component, true); err != nil {
+		errs = append(errs, err)
+	}
+	for _, kind := range []struct {
+		field      string
+		containers map[string]Container
+	}{
+		{"sidecars", component.Sidecars},
+		{"initContainers", component.InitContainers},
+	} {
+		for ctrName, ctr := range kind.containers {
+			field := kind.field + "." + ctrName
+			if msgs := k8svalidation.IsDNS1123Label(ctrName); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s: invalid container name: %s", field, strings.Join(msgs, "; ")))
+				continue
+			}
+			if ctrName == name {
+				errs = append(errs, fmt.Errorf("%s: name is taken by the component's main container", field))
+			}
+			if kind.field == "initContainers" {
+				if _, dup := component.Sidecars[ctrName]; dup {
+					errs = append(errs, fmt.Errorf("%s: name is also used by a sidecar", field))
+				}
+			}
+			if err := validateContainer(ctr, component); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", field, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateContainer(ctr Container, component Component) error {
+	if strings.TrimSpace(ctr.Image) == "" {
+		return errors.New("image is required")
+	}
+	for k := range ctr.Env {
+		if err := ValidateEnvVarName(k); err != nil {
+			return fmt.Errorf("env: %w", err)
+		}
+	}
+	if err := validateResources(ctr.Resources, ctr.ResourcePreset); err != nil {
+		return err
+	}
+	return validateVolumeMounts(ctr.VolumeMounts, component, false)
+}
+
+// validateVolumeMounts checks mount names and paths. The main container
+// already mounts the persistence volume at persistence.mountPath, so it may
+// not mount it again.
+func validateVolumeMounts(mounts []VolumeMount, component Component, main bool) error {
+	paths := make(map[string]struct{}, len(mounts))
+	for i, m := range mounts {
+		if msgs := k8svalidation.IsDNS1123Label(m.Name); len(msgs) > 0 {
+			return fmt.Errorf("volumeMounts[%d]: invalid volume name %q: %s", i, m.Name, strings.Join(msgs, "; "))
+		}
+		if !path.IsAbs(m.MountPath) {
+			return fmt.Errorf("volumeMounts[%d]: mountPath %q must be an absolute path", i, m.MountPath)
+		}
+		if _, dup := paths[path.Clean(m.MountPath)]; dup {
+			return fmt.Errorf("volumeMounts[%d]: mountPath %q is mounted twice", i, m.MountPath)
+		}
+		paths[path.Clean(m.MountPath)] = struct{}{}
+		if m.Name != PersistenceVolumeName {
+			continue
+		}
+		if component.Persistence == nil {
+			return fmt.Errorf("volumeMounts[%d]: volume %q requires persistence on the component", i, PersistenceVolumeName)
+		}
+		if main {
+			return fmt.Errorf("volumeMounts[%d]: volume %q is already mounted at persistence.mountPath", i, PersistenceVolumeName)
+		}
+	}
+	return nil
+}
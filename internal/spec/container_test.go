@@ -0,0 +1,171 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateComponentContainers(t *testing.T) {
+	t.Parallel()
+
+	shipper := Container{
+		Image:        "fluent/fluent-bit:3.1",
+		VolumeMounts: []VolumeMount{{Name: "logs", MountPath: "/var/log/app", ReadOnly: true}},
+	}
+	tests := []struct {
+		name      string
+		component Component
+		wantErr   string
+	}{
+		{
+			name: "sidecar and init container sharing a volume",
+			component: Component{
+				Sidecars:       map[string]Container{"log-shipper": shipper},
+				InitContainers: map[string]Container{"migrate": {Image: "api:2.0", Command: []string{"./migrate"}}},
+				VolumeMounts:   []VolumeMount{{Name: "logs", MountPath: "/var/log/app"}},
+			},
+		},
+		{
+			name:      "name taken by main container",
+			component: Component{Sidecars: map[string]Container{"api": shipper}},
+			wantErr:   "sidecars.api: name is taken by the component's main container",
+		},
+		{
+			name: "sidecar and init container share a name",
+			component: Component{
+				Sidecars:       map[string]Container{"proxy": shipper},
+				InitContainers: map[string]Container{"proxy": shipper},
+			},
+			wantErr: "initContainers.proxy: name is also used by a sidecar",
+		},
+		{
+			name:      "invalid container name",
+			component: Component{Sidecars: map[string]Container{"Log_Shipper": shipper}},
+			wantErr:   "invalid container name",
+		},
+		{
+			name:      "missing image",
+			component: Component{Sidecars: map[string]Container{"proxy": {}}},
+			wantErr:   "sidecars.proxy: image is required",
+		},
+		{
+			name: "resources and preset",
+			component: Component{Sidecars: map[string]Container{"proxy": {
+				Image:          "envoy",
+				Resources:      Resources{CPU: MustQuantity("50m")},
+				ResourcePreset: ResourcePresetNano,
+			}}},
+			wantErr: "cannot have both 'resources' and 'resourcePreset' fields",
+		},
+		{
+			name:      "invalid env name",
+			component: Component{Sidecars: map[string]Container{"proxy": {Image: "envoy", Env: map[string]string{"log-level": "info"}}}},
+			wantErr:   "sidecars.proxy: env:",
+		},
+		{
+			name: "relative mount path",
+			component: Component{Sidecars: map[string]Container{"proxy": {
+				Image:        "envoy",
+				VolumeMounts: []VolumeMount{{Name: "logs", MountPath: "logs"}},
+			}}},
+			wantErr: "must be an absolute path",
+		},
+		{
+			name:      "data without persistence",
+			component: Component{Sidecars: map[string]Container{"backup": {Image: "restic", VolumeMounts: []VolumeMount{{Name: "data", MountPath: "/data"}}}}},
+			wantErr:   `volume "data" requires persistence`,
+		},
+		{
+			name: "data on sidecar with persistence",
+			component: Component{
+				Persistence: &Persistence{Size: "1Gi", MountPath: "/var/lib/app"},
+				Sidecars:    map[string]Container{"backup": {Image: "restic", VolumeMounts: []VolumeMount{{Name: "data", MountPath: "/data", ReadOnly: true}}}},
+			},
+		},
+		{
+			name: "data on main container",
+			component: Component{
+				Persistence:  &Persistence{Size: "1Gi", MountPath: "/var/lib/app"},
+				VolumeMounts: []VolumeMount{{Name: "data", MountPath: "/data"}},
+			},
+			wantErr: "already mounted at persistence.mountPath",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateComponentContainers("api", tt.component)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestComponent_SharedVolumes(t *testing.T) {
+	t.Parallel()
+
+	c := Component{
+		VolumeMounts: []VolumeMount{{Name: "logs", MountPath: "/var/log/app"}},
+		Sidecars: map[string]Container{
+			"log-shipper": {VolumeMounts: []VolumeMount{{Name: "logs", MountPath: "/logs"}}},
+			"backup":      {VolumeMounts: []VolumeMount{{Name: "data", MountPath: "/data"}}},
+		},
+		InitContainers: map[string]Container{
+			"fetch-config": {VolumeMounts: []VolumeMount{{Name: "config", MountPath: "/config"}}},
+		},
+	}
+	assert.Equal(t, []string{"config", "logs"}, c.SharedVolumes())
+	assert.Equal(t, []string{"backup", "log-shipper"}, c.SidecarNames())
+	assert.Equal(t, []string{"fetch-config"}, c.InitContainerNames())
+}
+
+func TestFillSpecWithDefaults_ContainerPresets(t *testing.T) {
+	t.Parallel()
+
+	m := &Spec{
+		APIVersion: CurrentManifestVersion,
+		Project:    "shop",
+		Components: map[string]Component{
+			"api": {
+				Image: "api:latest",
+				Sidecars: map[string]Container{
+					"proxy":  {Image: "envoy"},
+					"shaper": {Image: "tc", ResourcePreset: ResourcePresetMicro},
+				},
+				InitContainers: map[string]Container{
+					"migrate": {Image: "api:latest", Resources: Resources{CPU: MustQuantity("250m")}},
+				},
+			},
+		},
+	}
+	require.NoError(t, FillSpecWithDefaults(m, CurrentManifestVersion))
+
+	api := m.Components["api"]
+	nano := ResourcePresetMappings[ResourcePresetNano]["requests"]
+	micro := ResourcePresetMappings[ResourcePresetMicro]["requests"]
+	assert.Equal(t, nano.Memory.String(), api.Sidecars["proxy"].Resources.Memory.String())
+	assert.Equal(t, micro.Memory.String(), api.Sidecars["shaper"].Resources.Memory.String())
+	assert.Empty(t, api.Sidecars["shaper"].ResourcePreset)
+	assert.Equal(t, "250m", api.InitContainers["migrate"].Resources.CPU.String())
+	assert.Nil(t, api.InitContainers["migrate"].Resources.Memory)
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embedded so timeZone validates the same way on hosts without a
+	// system zoneinfo database (distroless images, Windows).
+	_ "time/tzdata"
+)
+
+// cronMacros are the schedule shorthands the CronJob controller accepts.
+var cronMacros = map[string]bool{
+	"@yearly":   true,
+	"@annually": true,
+	"@monthly":  true,
+	"@weekly":   true,
+	"@daily":    true,
+	"@midnight": true,
+	"@hourly":   true,
+}
+
+// cronField is the range and optional names of one cron field.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] is the value min+i
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 6, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// ValidateCronSchedule reports whether schedule is a cron expression the
+// Kubernetes CronJob controller accepts: five space-separated fields
+// (minute, hour, day of month, month, day of week) with lists, ranges,
+// steps, and month/weekday names, or one of the @yearly, @monthly,
+// @weekly, @daily, and @hourly macros. Time zone prefixes are rejected in
+// favor of the timeZone field, as Kubernetes does.
+func ValidateCronSchedule(schedule string) error {
+	s := strings.TrimSpace(schedule)
+	if s == "" {
+		return errors.New("schedule is empty")
+	}
+	if strings.HasPrefix(s, "TZ=") || strings.HasPrefix(s, "CRON_TZ=") {
+		return errors.New("time zone prefixes are not supported in schedule; use timeZone")
+	}
+	if strings.HasPrefix(s, "@") {
+		if !cronMacros[strings.ToLower(s)] {
+			return fmt.Errorf("unknown schedule macro %q (use @yearly, @monthly, @weekly, @daily, or @hourly)", s)
+		}
+		return nil
+	}
+	parts := strings.Fields(s)
+	if len(parts) != len(cronFields) {
+		return fmt.Errorf("schedule %q must have 5 fields (minute hour day-of-month month day-of-week), got %d", s, len(parts))
+	}
+	for i, part := range parts {
+		if err := cronFields[i].validate(part); err != nil {
+			return fmt.Errorf("schedule %q: %w", s, err)
+		}
+	}
+	return nil
+}
+
+// validate checks one field: a comma-separated list of "*", "?", a value,
+// or a range, each optionally followed by "/step".
+func (f cronField) validate(expr string) error {
+	for item := range strings.SplitSeq(expr, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil || n < 1 {
+				return fmt.Errorf("%s: invalid step %q", f.name, step)
+			}
+		}
+		if rng == "*" || rng == "?" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rng, "-")
+		start, err := f.value(lo)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		end, err := f.value(hi)
+		if err != nil {
+			return err
+		}
+		if start > end {
+			return fmt.Errorf("%s: range %q is backwards", f.name, rng)
+		}
+	}
+	return nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", f.name, s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s: %d is outside %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
+
+// ValidateTimeZone reports whether tz names an IANA time zone.
+func ValidateTimeZone(tz string) error {
+	if tz == "" || tz == "Local" {
+		return fmt.Errorf("timeZone %q is not an IANA time zone name", tz)
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("timeZone %q is not a known IANA time zone", tz)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCronSchedule(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{
+		"* * * * *",
+		"*/15 * * * *",
+		"0 2 * * *",
+		"30 2 1,15 * *",
+		"0 9-17/2 * * MON-FRI",
+		"0 0 1 jan,jul ?",
+		"@daily",
+		"@HOURLY",
+	}
+	for _, s := range valid {
+		assert.NoError(t, ValidateCronSchedule(s), s)
+	}
+
+	invalid := map[string]string{
+		"":                      "empty",
+		"* * * *":               "must have 5 fields",
+		"* * * * * *":           "must have 5 fields",
+		"60 * * * *":            "minute: 60 is outside 0-59",
+		"0 0 0 * *":             "day of month: 0 is outside 1-31",
+		"0 0 * 13 *":            "month: 13 is outside 1-12",
+		"0 0 * * 7":             "day of week: 7 is outside 0-6",
+		"0 0 * * FUNDAY":        "invalid value",
+		"*/0 * * * *":           "invalid step",
+		"0 17-9 * * *":          "backwards",
+		"0 0 * * 1,":            "invalid value",
+		"@every 5m":             "unknown schedule macro",
+		"CRON_TZ=UTC 0 * * * *": "use timeZone",
+	}
+	for s, want := range invalid {
+		err := ValidateCronSchedule(s)
+		require.Error(t, err, s)
+		assert.Contains(t, err.Error(), want, s)
+	}
+}
+
+func TestValidateTimeZone(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateTimeZone("UTC"))
+	require.NoError(t, ValidateTimeZone("America/New_York"))
+	require.Error(t, ValidateTimeZone("Local"))
+	require.Error(t, ValidateTimeZone("Nowhere/Special"))
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/spf13/cast"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"deployah.dev/deployah/internal/spec/schema"
 )
@@ -35,13 +36,17 @@ import (
 // unexported fields must not be reflected into.
 var quantityType = reflect.TypeFor[resource.Quantity]()
 
+// intOrStringType is opaque for the same reason: Type, IntVal, and StrVal
+// are one value, not three fields with schema defaults.
+var intOrStringType = reflect.TypeFor[intstr.IntOrString]()
+
 // isOpaqueStruct reports types that should not be walked field-by-field
 // when applying schema defaults.
 func isOpaqueStruct(t reflect.Type) bool {
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
-	return t == quantityType
+	return t == quantityType || t == intOrStringType
 }
 
 // DefaultValues represents default values extracted from a JSON schema.
@@ -323,11 +328,13 @@ func GetDefaultValues(version string, schemaType schema.SchemaType) (DefaultValu
 // already set Resources are left untouched; components with neither
 // Resources nor a preset get [ResourcePresetSmall]. Tasks follow the same
 // rules, except a task with from and no own resources or preset is left
-// empty so [Task.MergeFrom] can copy the parent. Only the preset's
-// "requests" values are applied; "limits" are not used.
+// empty so [Task.MergeFrom] can copy the parent. The preset's limits are
+// applied too unless the resources block sets its own.
 func resolveResourcePresets(spec *Spec) {
 	for componentName, component := range spec.Components {
-		applyResourcePreset(&component.ResourcePreset, &component.Resources)
+		applyResourcePreset(&component.ResourcePreset, &component.Resources, ResourcePresetSmall)
+		component.Sidecars = resolveContainerPresets(component.Sidecars)
+		component.InitContainers = resolveContainerPresets(component.InitContainers)
 		spec.Components[componentName] = component
 	}
 	for taskName, task := range spec.Tasks {
@@ -336,22 +343,38 @@ func resolveResourcePresets(spec *Spec) {
 		if task.From != "" && task.ResourcePreset == "" && !task.Resources.ResourcesSet() {
 			continue
 		}
-		applyResourcePreset(&task.ResourcePreset, &task.Resources)
+		applyResourcePreset(&task.ResourcePreset, &task.Resources, ResourcePresetSmall)
 		spec.Tasks[taskName] = task
 	}
 }
 
+// resolveContainerPresets applies presets to sidecars or init containers,
+// falling back to [DefaultContainerResourcePreset]. It returns a new map
+// so the caller's spec does not share entries with the input.
+func resolveContainerPresets(containers map[string]Container) map[string]Container {
+	if len(containers) == 0 {
+		return containers
+	}
+	out := make(map[string]Container, len(containers))
+	for name, ctr := range containers {
+		applyResourcePreset(&ctr.ResourcePreset, &ctr.Resources, DefaultContainerResourcePreset)
+		out[name] = ctr
+	}
+	return out
+}
+
 // applyResourcePreset fills resources from preset when resources are empty,
 // then clears preset so validation does not see both. An empty preset falls
-// back to [ResourcePresetSmall]. Explicit resources win, and an unknown
-// preset is left in place for validation to report.
-func applyResourcePreset(preset *ResourcePreset, resources *Resources) {
+// back to fallback. Explicit resources win, and an unknown preset is left in
+// place for validation to report. A limits block set without requests is
+// kept, replacing the preset's limits.
+func applyResourcePreset(preset *ResourcePreset, resources *Resources, fallback ResourcePreset) {
 	if resources.ResourcesSet() {
 		return
 	}
 	name := *preset
 	if name == "" {
-		name = ResourcePresetSmall
+		name = fallback
 	}
 	presetResources, exists := ResourcePresetMappings[name]
 	if !exists {
@@ -359,11 +382,20 @@ func applyResourcePreset(preset *ResourcePreset, resources *Resources) {
 	}
 	// Clone quantities so callers do not share mutable pointers from the
 	// package-level ResourcePresetMappings table.
-	req := presetResources["requests"]
+	req, lim := presetResources["requests"], presetResources["limits"]
+	limits := resources.Limits
+	if limits == nil {
+		limits = &ResourceLimits{
+			CPU:              cloneQuantity(lim.CPU),
+			Memory:           cloneQuantity(lim.Memory),
+			EphemeralStorage: cloneQuantity(lim.EphemeralStorage),
+		}
+	}
 	*resources = Resources{
 		CPU:              cloneQuantity(req.CPU),
 		Memory:           cloneQuantity(req.Memory),
 		EphemeralStorage: cloneQuantity(req.EphemeralStorage),
+		Limits:           limits,
 	}
 	*preset = ""
 }
@@ -699,9 +731,16 @@ func applyDefaultsToMap(mapVal reflect.Value, defaults DefaultValues, currentPat
 				return fmt.Errorf("failed to apply defaults to slice at key %s in path %s: %w", keyStr, currentPath, err)
 			}
 		case reflect.Struct:
-			if err := applyDefaultsRecursively(value.Addr().Interface(), defaults, newPath, version); err != nil {
+			if isOpaqueStruct(value.Type()) {
+				continue
+			}
+			// Map values are not addressable: fill a copy and store it back.
+			elem := reflect.New(value.Type())
+			elem.Elem().Set(value)
+			if err := applyDefaultsRecursively(elem.Interface(), defaults, newPath, version); err != nil {
 				return fmt.Errorf("failed to apply defaults to struct at key %s in path %s: %w", keyStr, currentPath, err)
 			}
+			mapVal.SetMapIndex(key, elem.Elem())
 		}
 	}
 	return nil
@@ -915,6 +954,9 @@ func applyTaskDefaults(t *Task) {
 	if t.On.IsHook() && t.Timeout == "" {
 		t.Timeout = DefaultHookTaskTimeout
 	}
+	if t.On == TaskOnSchedule && t.ConcurrencyPolicy == "" {
+		t.ConcurrencyPolicy = DefaultConcurrencyPolicy
+	}
 }
 
 // CreateSpecWithDefaults creates a minimal [Spec] for projectName and fills
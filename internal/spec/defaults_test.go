@@ -1472,3 +1472,27 @@ func TestBuildFieldPath(t *testing.T) {
 		})
 	}
 }
+
+// TestFillSpecWithDefaults_MapOfStructs verifies a map with struct values,
+// like component secrets, is walked without taking the address of a map
+// value and keeps its entries.
+func TestFillSpecWithDefaults_MapOfStructs(t *testing.T) {
+	t.Parallel()
+
+	m := &Spec{
+		APIVersion: CurrentManifestVersion,
+		Project:    "shop",
+		Components: map[string]Component{
+			"api": {
+				Image: "api:latest",
+				Secrets: map[string]SecretSource{
+					"TOKEN": {Provider: SecretProviderAge, File: "token.age"},
+				},
+			},
+		},
+	}
+	require.NotPanics(t, func() {
+		require.NoError(t, FillSpecWithDefaults(m, CurrentManifestVersion))
+	})
+	assert.Equal(t, SecretSource{Provider: SecretProviderAge, File: "token.age"}, m.Components["api"].Secrets["TOKEN"])
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Disruption limits how many of a component's pods a voluntary disruption,
+// such as a node drain, may take down at once. It maps to a
+// PodDisruptionBudget. Exactly one field is set; each takes a pod count or
+// a percentage like "50%".
+type Disruption struct {
+	// MinAvailable is the number of pods that must stay running.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty" yaml:"minAvailable,omitempty"`
+	// MaxUnavailable is the number of pods that may be down at once.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty" yaml:"maxUnavailable,omitempty"`
+}
+
+// MinReplicas returns the fewest pods the component runs: minReplicas when
+// autoscaling is enabled, otherwise replicas (default 1).
+func (c Component) MinReplicas() int {
+	if c.Autoscaling != nil && c.Autoscaling.Enabled {
+		return c.Autoscaling.MinReplicas
+	}
+	if c.Replicas != nil {
+		return *c.Replicas
+	}
+	return 1
+}
+
+// EffectiveDisruption returns the disruption budget for a component: its
+// own disruption block, else the merged profile default. It returns nil
+// when neither is set or the component runs fewer than two pods, since a
+// budget on a single pod can only block drains.
+func EffectiveDisruption(component Component, profile *PlatformProfile) *Disruption {
+	d := component.Disruption
+	if d == nil && profile != nil {
+		d = profile.Disruption
+	}
+	if d == nil || component.MinReplicas() < 2 {
+		return nil
+	}
+	return d
+}
+
+// BlocksEvictions reports whether the budget lets no pod be evicted when
+// the component runs replicas pods, which stalls node drains until someone
+// scales up or deletes the budget. Percentages round up, as the disruption
+// controller does.
+func (d *Disruption) BlocksEvictions(replicas int) bool {
+	if d == nil {
+		return false
+	}
+	if d.MaxUnavailable != nil {
+		n, err := intstr.GetScaledValueFromIntOrPercent(d.MaxUnavailable, replicas, true)
+		return err == nil && n == 0
+	}
+	if d.MinAvailable != nil {
+		n, err := intstr.GetScaledValueFromIntOrPercent(d.MinAvailable, replicas, true)
+		return err == nil && n >= replicas
+	}
+	return false
+}
+
+// String renders the budget the way it is written in the spec, e.g.
+// "minAvailable 2" or "maxUnavailable 25%".
+func (d *Disruption) String() string {
+	switch {
+	case d == nil:
+		return ""
+	case d.MinAvailable != nil:
+		return "minAvailable " + d.MinAvailable.String()
+	case d.MaxUnavailable != nil:
+		return "maxUnavailable " + d.MaxUnavailable.String()
+	}
+	return ""
+}
+
+// ValidateDisruption checks that exactly one of minAvailable and
+// maxUnavailable is set and that it is a non-negative count or a
+// percentage from 0% to 100%.
+func ValidateDisruption(d *Disruption) error {
+	if d == nil {
+		return nil
+	}
+	switch {
+	case d.MinAvailable != nil && d.MaxUnavailable != nil:
+		return errors.New("disruption: minAvailable and maxUnavailable are mutually exclusive")
+	case d.MinAvailable != nil:
+		return validateDisruptionValue("minAvailable", d.MinAvailable)
+	case d.MaxUnavailable != nil:
+		return validateDisruptionValue("maxUnavailable", d.MaxUnavailable)
+	}
+	return errors.New("disruption: set minAvailable or maxUnavailable")
+}
+
+func validateDisruptionValue(field string, v *intstr.IntOrString) error {
+	if v.Type == intstr.Int {
+		if v.IntVal < 0 {
+			return fmt.Errorf("disruption.%s: must not be negative, got %d", field, v.IntVal)
+		}
+		return nil
+	}
+	pct, ok := strings.CutSuffix(v.StrVal, "%")
+	n, err := strconv.Atoi(pct)
+	if !ok || err != nil || n < 0 || n > 100 {
+		return fmt.Errorf("disruption.%s: %q must be a pod count or a percentage from 0%% to 100%%", field, v.StrVal)
+	}
+	return nil
+}
+
+// ValidateComponentDisruption validates the component's disruption block.
+func ValidateComponentDisruption(component Component) error {
+	return ValidateDisruption(component.Disruption)
+}
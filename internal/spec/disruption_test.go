@@ -0,0 +1,106 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidateDisruption(t *testing.T) {
+	t.Parallel()
+
+	count := func(n int32) *intstr.IntOrString { v := intstr.FromInt32(n); return &v }
+	pct := func(s string) *intstr.IntOrString { v := intstr.FromString(s); return &v }
+	tests := []struct {
+		name    string
+		d       *Disruption
+		wantErr string
+	}{
+		{name: "nil", d: nil},
+		{name: "min count", d: &Disruption{MinAvailable: count(1)}},
+		{name: "max percent", d: &Disruption{MaxUnavailable: pct("25%")}},
+		{name: "zero", d: &Disruption{MaxUnavailable: count(0)}},
+		{name: "empty", d: &Disruption{}, wantErr: "set minAvailable or maxUnavailable"},
+		{name: "both", d: &Disruption{MinAvailable: count(1), MaxUnavailable: count(1)}, wantErr: "mutually exclusive"},
+		{name: "negative", d: &Disruption{MinAvailable: count(-1)}, wantErr: "must not be negative"},
+		{name: "over 100 percent", d: &Disruption{MinAvailable: pct("150%")}, wantErr: "percentage from 0% to 100%"},
+		{name: "not a percentage", d: &Disruption{MaxUnavailable: pct("half")}, wantErr: `"half"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateDisruption(tt.d)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDisruption_BlocksEvictions(t *testing.T) {
+	t.Parallel()
+
+	count := func(n int32) *intstr.IntOrString { v := intstr.FromInt32(n); return &v }
+	pct := func(s string) *intstr.IntOrString { v := intstr.FromString(s); return &v }
+	tests := []struct {
+		name     string
+		d        *Disruption
+		replicas int
+		want     bool
+	}{
+		{name: "min equals replicas", d: &Disruption{MinAvailable: count(3)}, replicas: 3, want: true},
+		{name: "min below replicas", d: &Disruption{MinAvailable: count(2)}, replicas: 3, want: false},
+		{name: "min 100 percent", d: &Disruption{MinAvailable: pct("100%")}, replicas: 3, want: true},
+		{name: "min percent rounds up", d: &Disruption{MinAvailable: pct("60%")}, replicas: 2, want: true},
+		{name: "max zero", d: &Disruption{MaxUnavailable: count(0)}, replicas: 4, want: true},
+		{name: "max one", d: &Disruption{MaxUnavailable: count(1)}, replicas: 4, want: false},
+		{name: "max small percent rounds up", d: &Disruption{MaxUnavailable: pct("10%")}, replicas: 2, want: false},
+		{name: "nil", d: nil, replicas: 2, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.d.BlocksEvictions(tt.replicas))
+		})
+	}
+}
+
+func TestEffectiveDisruption(t *testing.T) {
+	t.Parallel()
+
+	one := intstr.FromInt32(1)
+	half := intstr.FromString("50%")
+	own := &Disruption{MaxUnavailable: &one}
+	profile := &PlatformProfile{Disruption: &Disruption{MinAvailable: &half}}
+	replicas := 3
+	single := 1
+
+	assert.Same(t, own, EffectiveDisruption(Component{Replicas: &replicas, Disruption: own}, profile), "component wins")
+	assert.Same(t, profile.Disruption, EffectiveDisruption(Component{Replicas: &replicas}, profile), "profile default")
+	assert.Nil(t, EffectiveDisruption(Component{Replicas: &single, Disruption: own}, profile), "single replica")
+	assert.Nil(t, EffectiveDisruption(Component{Disruption: own}, nil), "replicas default to 1")
+	assert.NotNil(t, EffectiveDisruption(Component{
+		Replicas:    &single,
+		Autoscaling: &Autoscaling{Enabled: true, MinReplicas: 2, MaxReplicas: 4},
+	}, profile), "autoscaling minReplicas counts")
+	assert.Nil(t, EffectiveDisruption(Component{Replicas: &replicas}, nil))
+}
@@ -27,7 +27,8 @@
 //
 //   - [ValidateSpec]: validate spec data against a schema version
 //   - [ValidateEnvironments]: validate environment definitions
-//   - [ValidateSpecComponents]: check component resources and autoscaling
+//   - [ValidateSpecComponents]: check component resources, autoscaling, secrets,
+//     and sidecar and init containers
 //   - [ValidateSpecTasks]: check task names, from, on, after, and fanout
 //
 // # Tasks
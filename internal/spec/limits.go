@@ -0,0 +1,168 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// LimitPolicy decides how a profile sets one container resource limit.
+type LimitPolicy string
+
+const (
+	// LimitPolicySpec uses the limit from resources.limits or the preset.
+	// It is the default.
+	LimitPolicySpec LimitPolicy = "spec"
+	// LimitPolicyNone sets no limit, so the container may use whatever the
+	// node has free. Common for CPU, where limits cause throttling.
+	LimitPolicyNone LimitPolicy = "none"
+	// LimitPolicyRequest sets the limit equal to the request. Common for
+	// memory, so a pod is never killed for using what it was promised.
+	LimitPolicyRequest LimitPolicy = "request"
+)
+
+// ProfileResourceLimits is a profile's limit policy per resource. An empty
+// field means [LimitPolicySpec].
+type ProfileResourceLimits struct {
+	CPU              LimitPolicy `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory           LimitPolicy `json:"memory,omitempty" yaml:"memory,omitempty"`
+	EphemeralStorage LimitPolicy `json:"ephemeralStorage,omitempty" yaml:"ephemeralStorage,omitempty"`
+}
+
+// Clone returns a deep copy of r, so callers do not share quantity
+// pointers.
+func (r Resources) Clone() Resources {
+	out := Resources{
+		CPU:              cloneQuantity(r.CPU),
+		Memory:           cloneQuantity(r.Memory),
+		EphemeralStorage: cloneQuantity(r.EphemeralStorage),
+	}
+	if r.Limits != nil {
+		out.Limits = &ResourceLimits{
+			CPU:              cloneQuantity(r.Limits.CPU),
+			Memory:           cloneQuantity(r.Limits.Memory),
+			EphemeralStorage: cloneQuantity(r.Limits.EphemeralStorage),
+		}
+	}
+	return out
+}
+
+// EffectiveLimits returns the limits deploy applies to r under the merged
+// profile's resourceLimits policy. A nil profile or policy keeps the spec
+// limits. The result is nil when no limit is left.
+func (r Resources) EffectiveLimits(profile *PlatformProfile) *ResourceLimits {
+	var policy ProfileResourceLimits
+	if profile != nil && profile.ResourceLimits != nil {
+		policy = *profile.ResourceLimits
+	}
+	var limits ResourceLimits
+	if r.Limits != nil {
+		limits = *r.Limits
+	}
+	out := &ResourceLimits{
+		CPU:              applyLimitPolicy(policy.CPU, limits.CPU, r.CPU),
+		Memory:           applyLimitPolicy(policy.Memory, limits.Memory, r.Memory),
+		EphemeralStorage: applyLimitPolicy(policy.EphemeralStorage, limits.EphemeralStorage, r.EphemeralStorage),
+	}
+	if out.CPU == nil && out.Memory == nil && out.EphemeralStorage == nil {
+		return nil
+	}
+	return out
+}
+
+func applyLimitPolicy(policy LimitPolicy, limit, request *resource.Quantity) *resource.Quantity {
+	switch policy {
+	case LimitPolicyNone:
+		return nil
+	case LimitPolicyRequest:
+		if quantitySet(request) {
+			return cloneQuantity(request)
+		}
+		return nil
+	}
+	if quantitySet(limit) {
+		return cloneQuantity(limit)
+	}
+	return nil
+}
+
+// validateResourceLimits checks that no limit in r is below its request,
+// which the API server would reject at deploy time.
+func validateResourceLimits(r Resources) error {
+	if r.Limits == nil {
+		return nil
+	}
+	for _, q := range []struct {
+		field          string
+		request, limit *resource.Quantity
+	}{
+		{"cpu", r.CPU, r.Limits.CPU},
+		{"memory", r.Memory, r.Limits.Memory},
+		{"ephemeralStorage", r.EphemeralStorage, r.Limits.EphemeralStorage},
+	} {
+		if quantitySet(q.request) && quantitySet(q.limit) && q.limit.Cmp(*q.request) < 0 {
+			return fmt.Errorf("resources.limits.%s %s is below the request %s", q.field, q.limit.String(), q.request.String())
+		}
+	}
+	return nil
+}
+
+// ValidateProfileResourceLimits checks that each policy is a known
+// [LimitPolicy].
+func ValidateProfileResourceLimits(p *ProfileResourceLimits) error {
+	if p == nil {
+		return nil
+	}
+	for _, f := range []struct {
+		field  string
+		policy LimitPolicy
+	}{
+		{"cpu", p.CPU},
+		{"memory", p.Memory},
+		{"ephemeralStorage", p.EphemeralStorage},
+	} {
+		switch f.policy {
+		case "", LimitPolicySpec, LimitPolicyNone, LimitPolicyRequest:
+		default:
+			return fmt.Errorf("resourceLimits.%s: unknown policy %q (use %s, %s, or %s)",
+				f.field, f.policy, LimitPolicySpec, LimitPolicyNone, LimitPolicyRequest)
+		}
+	}
+	return nil
+}
+
+// mergeResourceLimits overlays overlay onto base, last non-empty policy
+// per resource.
+func mergeResourceLimits(base, overlay *ProfileResourceLimits) *ProfileResourceLimits {
+	if overlay == nil {
+		return base
+	}
+	out := &ProfileResourceLimits{}
+	if base != nil {
+		*out = *base
+	}
+	if overlay.CPU != "" {
+		out.CPU = overlay.CPU
+	}
+	if overlay.Memory != "" {
+		out.Memory = overlay.Memory
+	}
+	if overlay.EphemeralStorage != "" {
+		out.EphemeralStorage = overlay.EphemeralStorage
+	}
+	return out
+}
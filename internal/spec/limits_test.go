@@ -0,0 +1,158 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFillSpecWithDefaults_PresetLimits(t *testing.T) {
+	t.Parallel()
+
+	m := &Spec{
+		APIVersion: CurrentManifestVersion,
+		Project:    "shop",
+		Components: map[string]Component{
+			"preset": {Image: "api:latest", ResourcePreset: ResourcePresetMicro},
+			"tuned": {
+				Image:          "api:latest",
+				ResourcePreset: ResourcePresetMicro,
+				Resources:      Resources{Limits: &ResourceLimits{Memory: MustQuantity("1Gi")}},
+			},
+			"explicit": {Image: "api:latest", Resources: Resources{CPU: MustQuantity("250m")}},
+		},
+	}
+	require.NoError(t, FillSpecWithDefaults(m, CurrentManifestVersion))
+
+	micro := ResourcePresetMappings[ResourcePresetMicro]
+	preset := m.Components["preset"].Resources
+	require.NotNil(t, preset.Limits)
+	assert.Equal(t, micro["limits"].CPU.String(), preset.Limits.CPU.String())
+	assert.Equal(t, micro["limits"].Memory.String(), preset.Limits.Memory.String())
+	assert.NotSame(t, micro["limits"].CPU, preset.Limits.CPU)
+
+	tuned := m.Components["tuned"].Resources
+	assert.Equal(t, micro["requests"].CPU.String(), tuned.CPU.String())
+	require.NotNil(t, tuned.Limits)
+	assert.Equal(t, "1Gi", tuned.Limits.Memory.String())
+	assert.Nil(t, tuned.Limits.CPU, "a limits block replaces the preset limits")
+
+	assert.Nil(t, m.Components["explicit"].Resources.Limits, "explicit requests get no preset limits")
+}
+
+func TestValidateComponentResources_Limits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		component Component
+		wantErr   string
+	}{
+		{
+			name: "limit above request",
+			component: Component{Resources: Resources{
+				Memory: MustQuantity("256Mi"),
+				Limits: &ResourceLimits{Memory: MustQuantity("512Mi")},
+			}},
+		},
+		{
+			name: "limits refine a preset",
+			component: Component{
+				ResourcePreset: ResourcePresetSmall,
+				Resources:      Resources{Limits: &ResourceLimits{CPU: MustQuantity("2")}},
+			},
+		},
+		{
+			name: "limit below request",
+			component: Component{Resources: Resources{
+				CPU:    MustQuantity("500m"),
+				Limits: &ResourceLimits{CPU: MustQuantity("250m")},
+			}},
+			wantErr: "resources.limits.cpu 250m is below the request 500m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateComponentResources(tt.component)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestResources_EffectiveLimits(t *testing.T) {
+	t.Parallel()
+
+	r := Resources{
+		CPU:    MustQuantity("250m"),
+		Memory: MustQuantity("512Mi"),
+		Limits: &ResourceLimits{CPU: MustQuantity("1"), Memory: MustQuantity("1Gi")},
+	}
+
+	t.Run("no policy keeps spec limits", func(t *testing.T) {
+		t.Parallel()
+		got := r.EffectiveLimits(nil)
+		require.NotNil(t, got)
+		assert.Equal(t, "1", got.CPU.String())
+		assert.Equal(t, "1Gi", got.Memory.String())
+	})
+
+	t.Run("none and request", func(t *testing.T) {
+		t.Parallel()
+		got := r.EffectiveLimits(&PlatformProfile{ResourceLimits: &ProfileResourceLimits{
+			CPU:    LimitPolicyNone,
+			Memory: LimitPolicyRequest,
+		}})
+		require.NotNil(t, got)
+		assert.Nil(t, got.CPU)
+		assert.Equal(t, "512Mi", got.Memory.String())
+	})
+
+	t.Run("request policy without a request sets no limit", func(t *testing.T) {
+		t.Parallel()
+		got := Resources{}.EffectiveLimits(&PlatformProfile{ResourceLimits: &ProfileResourceLimits{
+			Memory: LimitPolicyRequest,
+		}})
+		assert.Nil(t, got)
+	})
+}
+
+func TestMergeResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	got := mergeResourceLimits(
+		&ProfileResourceLimits{CPU: LimitPolicyNone, Memory: LimitPolicySpec},
+		&ProfileResourceLimits{Memory: LimitPolicyRequest},
+	)
+	assert.Equal(t, &ProfileResourceLimits{CPU: LimitPolicyNone, Memory: LimitPolicyRequest}, got)
+	assert.Nil(t, mergeResourceLimits(nil, nil))
+}
+
+func TestValidateProfileResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateProfileResourceLimits(&ProfileResourceLimits{CPU: LimitPolicyNone}))
+	err := ValidateProfileResourceLimits(&ProfileResourceLimits{Memory: "double"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `resourceLimits.memory: unknown policy "double"`)
+}
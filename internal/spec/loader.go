@@ -16,6 +16,7 @@ package spec
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"maps"
@@ -87,7 +88,8 @@ func DeclaredEnvironments(environments map[string]Environment, platform *Platfor
 // developer-owned overrides. Which names are valid (the registry) is owned by
 // the platform config when one exists; the spec's environments map supplies
 // optional per-environment overrides (envFile, variables) and acts as the
-// registry only when there is no platform config.
+// registry only when there is no platform config. Variables the platform
+// sets for the environment are merged under the spec entry's variables.
 //
 // When desiredEnvironment is empty:
 //   - Empty registry: returns a synthetic default environment.
@@ -126,11 +128,18 @@ func ResolveEnvironment(environments map[string]Environment, platform *PlatformC
 	}
 
 	// The spec entry is an optional override; absent means zero value.
+	resolved, env := name, &Environment{}
 	if matched, ok := matchEnvKey(name, slices.Collect(maps.Keys(environments))); ok {
 		cp := environments[matched]
-		return matched, &cp, nil
+		resolved, env = matched, &cp
 	}
-	return name, &Environment{}, nil
+	// Platform variables are defaults; the spec entry's own win.
+	if defaults := PlatformEnvVariables(platform, name); len(defaults) > 0 {
+		vars := maps.Clone(defaults)
+		maps.Copy(vars, env.Variables)
+		env.Variables = vars
+	}
+	return resolved, env, nil
 }
 
 // resolveEnvFile determines which env file to use for the given environment,
@@ -281,14 +290,31 @@ func Load(ctx context.Context, path, desiredEnv string, platform *PlatformConfig
 		return nil, fmt.Errorf("failed to parse substituted spec YAML: %w", err)
 	}
 
+	// Merge the environment's component overrides first, so the schema
+	// checks the components as they will be deployed.
+	overrides, err := applyComponentOverrides(substitutedObj, envName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
+	}
+
 	if err = ValidateSpec(substitutedObj, version); err != nil {
+		if len(overrides) > 0 {
+			return nil, fmt.Errorf("spec validation failed after applying environments.%s.components: %w", envName, err)
+		}
 		return nil, fmt.Errorf("spec validation failed: %w", err)
 	}
 
+	if len(overrides) > 0 {
+		if substituted, err = json.Marshal(substitutedObj); err != nil {
+			return nil, fmt.Errorf("failed to encode merged spec: %w", err)
+		}
+	}
+
 	var finalSpec Spec
 	if err = yaml.Unmarshal(substituted, &finalSpec); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal spec: %w", err)
 	}
+	finalSpec.Overrides = overrides
 	normalizeComponents(&finalSpec)
 
 	if err = ValidateSpecComponents(&finalSpec); err != nil {
@@ -15,6 +15,7 @@
 package spec
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -137,6 +138,31 @@ func TestResolveEnvironment(t *testing.T) {
 	}
 }
 
+// TestResolveEnvironment_PlatformVariables verifies platform variables are
+// defaults that the spec entry's variables override, and that the spec map
+// is not modified.
+func TestResolveEnvironment_PlatformVariables(t *testing.T) {
+	t.Parallel()
+
+	platform := &PlatformConfig{
+		Environments: map[string]PlatformEnvironment{
+			"local": {Variables: map[string]string{"REGISTRY": "localhost:5001", "TAG": "dev"}},
+		},
+	}
+	environments := map[string]Environment{
+		"local": {Variables: map[string]string{"TAG": "feature"}},
+	}
+
+	_, env, err := ResolveEnvironment(environments, platform, "local")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"REGISTRY": "localhost:5001", "TAG": "feature"}, env.Variables)
+	assert.Equal(t, map[string]string{"TAG": "feature"}, environments["local"].Variables)
+
+	_, env, err = ResolveEnvironment(nil, platform, "local")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:5001", env.Variables["REGISTRY"])
+}
+
 // TestLoad_NoEnvironmentsSection verifies a spec without an environments
 // section loads: the section is optional now that the platform file owns
 // the registry, and an entry only adds developer overrides.
@@ -431,3 +457,116 @@ environments:
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "profile")
 }
+
+// TestLoad_ScheduleCronFormat verifies the manifest schema checks
+// task.schedule with the cron format.
+func TestLoad_ScheduleCronFormat(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	content := `
+apiVersion: v1-alpha.5
+project: shop
+components:
+  api:
+    image: shop/api:1.0.0
+    port: 8080
+tasks:
+  nightly:
+    from: api
+    "on": schedule
+    schedule: %q
+    command: [report]
+environments:
+  production: {}
+`
+	require.NoError(t, os.WriteFile("deployah.yaml", fmt.Appendf(nil, content, "0 2 * * *"), 0o600))
+	s, err := Load(t.Context(), "deployah.yaml", "production", nil)
+	require.NoError(t, err)
+	assert.Equal(t, TaskOnSchedule, s.Tasks["nightly"].On)
+	assert.Equal(t, ConcurrencyPolicyForbid, s.Tasks["nightly"].ConcurrencyPolicy)
+
+	require.NoError(t, os.WriteFile("deployah.yaml", fmt.Appendf(nil, content, "every night"), 0o600))
+	_, err = Load(t.Context(), "deployah.yaml", "production", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schedule")
+}
+
+// TestLoad_Secrets verifies the schema accepts both secret forms and rejects
+// an entry that mixes them.
+func TestLoad_Secrets(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	content := `
+apiVersion: v1-alpha.5
+project: shop
+components:
+  api:
+    image: shop/api:1.0.0
+    port: 8080
+    secrets:
+      DATABASE_PASSWORD:
+        secretRef:
+          name: db-credentials
+          key: password
+      STRIPE_KEY:
+        provider: sops
+        file: secrets/production.enc.yaml
+        key: stripe.apiKey%s
+environments:
+  production: {}
+`
+	require.NoError(t, os.WriteFile("deployah.yaml", fmt.Appendf(nil, content, ""), 0o600))
+	s, err := Load(t.Context(), "deployah.yaml", "production", nil)
+	require.NoError(t, err)
+	api := s.Components["api"]
+	require.NotNil(t, api.Secrets["DATABASE_PASSWORD"].SecretRef)
+	assert.Equal(t, "db-credentials", api.Secrets["DATABASE_PASSWORD"].SecretRef.Name)
+	assert.Equal(t, SecretProviderSOPS, api.Secrets["STRIPE_KEY"].Provider)
+	assert.Equal(t, []string{"STRIPE_KEY"}, api.ProviderSecrets())
+
+	mixed := "\n        secretRef:\n          name: stripe\n          key: key"
+	require.NoError(t, os.WriteFile("deployah.yaml", fmt.Appendf(nil, content, mixed), 0o600))
+	_, err = Load(t.Context(), "deployah.yaml", "production", nil)
+	require.Error(t, err)
+}
+
+func TestLoad_Containers(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	content := `
+apiVersion: v1-alpha.5
+project: shop
+components:
+  api:
+    image: shop/api:1.0.0
+    port: 8080
+    volumeMounts:
+      - name: logs
+        mountPath: /var/log/app
+    sidecars:
+      log-shipper:
+        image: fluent/fluent-bit:3.1
+        volumeMounts:
+          - name: logs
+            mountPath: /logs
+            readOnly: true
+    initContainers:
+      %s:
+        image: shop/api:1.0.0
+        command: [./migrate, up]
+environments:
+  production: {}
+`
+	require.NoError(t, os.WriteFile("deployah.yaml", fmt.Appendf(nil, content, "migrate"), 0o600))
+	s, err := Load(t.Context(), "deployah.yaml", "production", nil)
+	require.NoError(t, err)
+	api := s.Components["api"]
+	assert.Equal(t, []string{"log-shipper"}, api.SidecarNames())
+	assert.Equal(t, []string{"./migrate", "up"}, api.InitContainers["migrate"].Command)
+	assert.True(t, api.Sidecars["log-shipper"].VolumeMounts[0].ReadOnly)
+	assert.True(t, api.Sidecars["log-shipper"].Resources.ResourcesSet(), "nano preset applied")
+
+	require.NoError(t, os.WriteFile("deployah.yaml", fmt.Appendf(nil, content, "Migrate"), 0o600))
+	_, err = Load(t.Context(), "deployah.yaml", "production", nil)
+	require.Error(t, err)
+}
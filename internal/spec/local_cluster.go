@@ -0,0 +1,298 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"deployah.dev/deployah/internal/spec/schema"
+
+	jsonschema "github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// DefaultLocalClusterPath is the default filename for the local cluster
+// config, looked up in the working directory by "cluster up".
+const DefaultLocalClusterPath = "deployah.cluster.yaml"
+
+// CurrentLocalClusterVersion is the only supported local cluster config
+// apiVersion.
+const CurrentLocalClusterVersion = "cluster/v1-alpha.1"
+
+// LocalClusterConfig is the top-level structure of the local cluster config
+// (deployah.cluster.yaml). It is read when "cluster up" creates the cluster
+// and is not subject to envsubst.
+type LocalClusterConfig struct {
+	// APIVersion is the cluster config schema version, e.g.
+	// "cluster/v1-alpha.1".
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	// KubernetesVersion pins the node version, e.g. "1.31" or "v1.31.2".
+	KubernetesVersion string `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+	// FeatureGates enables or disables Kubernetes feature gates on every
+	// cluster component.
+	FeatureGates map[string]bool `json:"featureGates,omitempty" yaml:"featureGates,omitempty"`
+	// ControlPlane configures the single control-plane node.
+	ControlPlane LocalClusterNode `json:"controlPlane,omitzero" yaml:"controlPlane,omitempty"`
+	// Workers lists worker node groups. Empty means a single-node cluster.
+	Workers []LocalClusterWorkerGroup `json:"workers,omitempty" yaml:"workers,omitempty"`
+	// RegistryMirrors maps a registry host, e.g. "docker.io", to a mirror
+	// endpoint the nodes pull from first, e.g. "http://mirror.local:5000".
+	// Unlike the other fields it is applied to an existing cluster too.
+	RegistryMirrors map[string]string `json:"registryMirrors,omitempty" yaml:"registryMirrors,omitempty"`
+}
+
+// LocalClusterNode holds the settings of one cluster node.
+type LocalClusterNode struct {
+	// Labels are Kubernetes labels set on the node.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Taints are Kubernetes taints set on the node.
+	Taints []LocalClusterTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
+	// PortMappings forward host ports to ports of the node container.
+	PortMappings []LocalClusterPortMapping `json:"portMappings,omitempty" yaml:"portMappings,omitempty"`
+	// Mounts mount host paths into the node container.
+	Mounts []LocalClusterMount `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+}
+
+// LocalClusterWorkerGroup is Count identical worker nodes.
+type LocalClusterWorkerGroup struct {
+	LocalClusterNode `json:",inline" yaml:",inline"`
+
+	// Count is the number of nodes in the group. Zero means 1.
+	Count int `json:"count,omitempty" yaml:"count,omitempty"`
+}
+
+// Nodes returns the group's node count, defaulting to 1.
+func (g LocalClusterWorkerGroup) Nodes() int {
+	return max(g.Count, 1)
+}
+
+// LocalClusterTaint is a node taint.
+type LocalClusterTaint struct {
+	// Key is the taint key (a qualified name).
+	Key string `json:"key" yaml:"key"`
+	// Value is the optional taint value.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Effect is NoSchedule, PreferNoSchedule, or NoExecute.
+	Effect string `json:"effect" yaml:"effect"`
+}
+
+// LocalClusterPortMapping forwards a host port to a node container port.
+type LocalClusterPortMapping struct {
+	// HostPort is the port bound on the host.
+	HostPort uint16 `json:"hostPort" yaml:"hostPort"`
+	// ContainerPort is the port inside the node container.
+	ContainerPort uint16 `json:"containerPort" yaml:"containerPort"`
+	// Protocol is TCP (default) or UDP.
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	// ListenAddress is the host address to bind; defaults to 127.0.0.1.
+	ListenAddress string `json:"listenAddress,omitempty" yaml:"listenAddress,omitempty"`
+}
+
+// LocalClusterMount mounts a host path into a node container.
+type LocalClusterMount struct {
+	// HostPath is the path on the host. [LoadLocalCluster] resolves
+	// relative paths against the config file's directory.
+	HostPath string `json:"hostPath" yaml:"hostPath"`
+	// ContainerPath is the absolute path inside the node container.
+	ContainerPath string `json:"containerPath" yaml:"containerPath"`
+	// ReadOnly mounts the path read-only.
+	ReadOnly bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+}
+
+// NodeCount returns the total number of nodes the config declares,
+// including the control plane.
+func (c *LocalClusterConfig) NodeCount() int {
+	n := 1
+	for _, g := range c.Workers {
+		n += g.Nodes()
+	}
+	return n
+}
+
+// LoadLocalCluster reads and validates the local cluster config at path. It
+// performs:
+//  1. YAML parse into a raw map for schema validation
+//  2. Schema validation against the embedded cluster schema
+//  3. Unmarshal into [LocalClusterConfig]
+//  4. Consistency checks (label and taint syntax, host port conflicts)
+//
+// Relative mount host paths are made absolute against the directory of path.
+func LoadLocalCluster(path string) (*LocalClusterConfig, error) {
+	if path == "" {
+		return nil, errors.New("cluster config path must not be empty")
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path chosen by the user via --config
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("cluster config not found: %s", path)
+		}
+		return nil, fmt.Errorf("failed to read cluster config %s: %w", path, err)
+	}
+
+	var raw map[string]any
+	if err = yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster config YAML: %w", err)
+	}
+
+	apiVersion, _ := raw["apiVersion"].(string)
+	if apiVersion == "" {
+		return nil, errors.New("cluster config is missing 'apiVersion' field")
+	}
+	clusterVersion, ok := strings.CutPrefix(apiVersion, "cluster/")
+	if !ok {
+		return nil, fmt.Errorf("cluster config apiVersion must start with 'cluster/' (got %q)", apiVersion)
+	}
+
+	schemaBytes, err := schema.GetClusterSchema(clusterVersion)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported cluster config version %q: %w", apiVersion, err)
+	}
+	if err = validateLocalClusterYAML(raw, schemaBytes, apiVersion); err != nil {
+		return nil, fmt.Errorf("cluster config validation failed: %w", err)
+	}
+
+	var cfg LocalClusterConfig
+	if err = yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cluster config: %w", err)
+	}
+
+	if err = ValidateLocalCluster(&cfg); err != nil {
+		return nil, fmt.Errorf("cluster config %s: %w", path, err)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("resolve cluster config directory: %w", err)
+	}
+	resolveMounts := func(mounts []LocalClusterMount) {
+		for i := range mounts {
+			if !filepath.IsAbs(mounts[i].HostPath) {
+				mounts[i].HostPath = filepath.Join(dir, mounts[i].HostPath)
+			}
+		}
+	}
+	resolveMounts(cfg.ControlPlane.Mounts)
+	for i := range cfg.Workers {
+		resolveMounts(cfg.Workers[i].Mounts)
+	}
+
+	return &cfg, nil
+}
+
+// validateLocalClusterYAML validates the raw cluster config map against the
+// schema.
+func validateLocalClusterYAML(raw map[string]any, schemaBytes []byte, version string) error {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+
+	schemaID := "cluster-schema-" + version + ".json"
+	jsonSchema, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaBytes))
+	if err != nil {
+		return fmt.Errorf("invalid cluster schema JSON for version %q: %w", version, err)
+	}
+	if err = compiler.AddResource(schemaID, jsonSchema); err != nil {
+		return fmt.Errorf("failed to add cluster schema: %w", err)
+	}
+	compiled, err := compiler.Compile(schemaID)
+	if err != nil {
+		return fmt.Errorf("failed to compile cluster schema: %w", err)
+	}
+	if err = compiled.Validate(raw); err != nil {
+		return fmt.Errorf("cluster schema validation failed for %q: %w", version, err)
+	}
+	return nil
+}
+
+// localIngressHostPorts are the TCP host ports the local cluster publishes
+// its Ingress controller on, at localkube.DefaultIngressIP. No node port
+// mapping may take them.
+var localIngressHostPorts = []uint16{80, 443}
+
+// ValidateLocalCluster checks what the schema cannot: label and taint
+// syntax, that a worker group with several nodes maps no host ports, and
+// that no host port is bound twice, by two mappings or by a mapping and
+// the Ingress controller. A wildcard listen address overlaps every other
+// address on the same port.
+func ValidateLocalCluster(cfg *LocalClusterConfig) error {
+	type binding struct{ addr, by string }
+	var errs []error
+	bound := map[string][]binding{}
+	for _, port := range localIngressHostPorts {
+		bound[fmt.Sprintf("%d/TCP", port)] = []binding{{addr: "127.0.0.1", by: "the Ingress controller"}}
+	}
+	check := func(field string, node LocalClusterNode, count int) {
+		for k, v := range node.Labels {
+			if msgs := k8svalidation.IsQualifiedName(k); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s.labels: invalid key %q: %s", field, k, strings.Join(msgs, "; ")))
+			}
+			if msgs := k8svalidation.IsValidLabelValue(v); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s.labels.%s: invalid value %q: %s", field, k, v, strings.Join(msgs, "; ")))
+			}
+		}
+		for i, t := range node.Taints {
+			if msgs := k8svalidation.IsQualifiedName(t.Key); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s.taints[%d]: invalid key %q: %s", field, i, t.Key, strings.Join(msgs, "; ")))
+			}
+			if msgs := k8svalidation.IsValidLabelValue(t.Value); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s.taints[%d]: invalid value %q: %s", field, i, t.Value, strings.Join(msgs, "; ")))
+			}
+		}
+		if len(node.PortMappings) > 0 && count > 1 {
+			errs = append(errs, fmt.Errorf("%s.portMappings: a host port binds to one node; set count to 1", field))
+			return
+		}
+		for i, pm := range node.PortMappings {
+			port := fmt.Sprintf("%d/%s", pm.HostPort, cmp.Or(pm.Protocol, "TCP"))
+			addr := cmp.Or(pm.ListenAddress, "127.0.0.1")
+			at := fmt.Sprintf("%s.portMappings[%d]", field, i)
+			if j := slices.IndexFunc(bound[port], func(b binding) bool { return addressesOverlap(b.addr, addr) }); j >= 0 {
+				errs = append(errs, fmt.Errorf("%s: host port %s:%s is already mapped by %s", at, addr, port, bound[port][j].by))
+				continue
+			}
+			bound[port] = append(bound[port], binding{addr: addr, by: at})
+		}
+	}
+	check("controlPlane", cfg.ControlPlane, 1)
+	for i, g := range cfg.Workers {
+		check(fmt.Sprintf("workers[%d]", i), g.LocalClusterNode, g.Nodes())
+	}
+	return errors.Join(errs...)
+}
+
+// addressesOverlap reports whether listening on a and on b would contend
+// for the same host port: they are equal, or either is a wildcard such as
+// 0.0.0.0 that listens on every address.
+func addressesOverlap(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, addr := range []string{a, b} {
+		if ip := net.ParseIP(addr); ip != nil && ip.IsUnspecified() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+)
+
+// TestLoadLocalCluster_Valid parses a multi-node config and resolves
+// relative mount paths against the file's directory.
+func TestLoadLocalCluster_Valid(t *testing.T) {
+	t.Parallel()
+	path := writeTempFile(t, `
+apiVersion: cluster/v1-alpha.1
+kubernetesVersion: "1.31"
+featureGates:
+  InPlacePodVerticalScaling: true
+controlPlane:
+  portMappings:
+    - hostPort: 30080
+      containerPort: 30080
+workers:
+  - count: 2
+    labels:
+      topology.kubernetes.io/zone: zone-a
+  - labels:
+      dedicated: batch
+    taints:
+      - key: dedicated
+        value: batch
+        effect: NoSchedule
+    mounts:
+      - hostPath: ./data
+        containerPath: /data
+registryMirrors:
+  docker.io: http://mirror.local:5000
+`)
+	cfg, err := spec.LoadLocalCluster(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.31", cfg.KubernetesVersion)
+	assert.Equal(t, map[string]bool{"InPlacePodVerticalScaling": true}, cfg.FeatureGates)
+	require.Len(t, cfg.Workers, 2)
+	assert.Equal(t, 2, cfg.Workers[0].Nodes())
+	assert.Equal(t, 1, cfg.Workers[1].Nodes())
+	assert.Equal(t, 4, cfg.NodeCount())
+	assert.Equal(t, "zone-a", cfg.Workers[0].Labels["topology.kubernetes.io/zone"])
+	assert.Equal(t, []spec.LocalClusterTaint{{Key: "dedicated", Value: "batch", Effect: "NoSchedule"}}, cfg.Workers[1].Taints)
+	require.Len(t, cfg.Workers[1].Mounts, 1)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "data"), cfg.Workers[1].Mounts[0].HostPath)
+	assert.Equal(t, map[string]string{"docker.io": "http://mirror.local:5000"}, cfg.RegistryMirrors)
+}
+
+// TestLoadLocalCluster_Errors rejects files the schema or the consistency
+// checks refuse.
+func TestLoadLocalCluster_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "missing apiVersion",
+			content: "workers: []\n",
+			wantErr: "missing 'apiVersion'",
+		},
+		{
+			name:    "wrong prefix",
+			content: "apiVersion: platform/v1-alpha.3\n",
+			wantErr: "must start with 'cluster/'",
+		},
+		{
+			name:    "unknown version",
+			content: "apiVersion: cluster/v9\n",
+			wantErr: "unsupported cluster config version",
+		},
+		{
+			name:    "unknown field",
+			content: "apiVersion: cluster/v1-alpha.1\nnodes: 3\n",
+			wantErr: "validation failed",
+		},
+		{
+			name: "bad taint effect",
+			content: `apiVersion: cluster/v1-alpha.1
+workers:
+  - taints:
+      - key: dedicated
+        effect: Never
+`,
+			wantErr: "validation failed",
+		},
+		{
+			name:    "registry mirror with a path",
+			content: "apiVersion: cluster/v1-alpha.1\nregistryMirrors:\n  docker.io: http://mirror.local:5000/v2\n",
+			wantErr: "validation failed",
+		},
+		{
+			name: "invalid label key",
+			content: `apiVersion: cluster/v1-alpha.1
+controlPlane:
+  labels:
+    "bad key": x
+`,
+			wantErr: "controlPlane.labels: invalid key",
+		},
+		{
+			name: "port mappings on a group of several nodes",
+			content: `apiVersion: cluster/v1-alpha.1
+workers:
+  - count: 2
+    portMappings:
+      - hostPort: 8080
+        containerPort: 30080
+`,
+			wantErr: "workers[0].portMappings: a host port binds to one node",
+		},
+		{
+			name: "host port mapped twice",
+			content: `apiVersion: cluster/v1-alpha.1
+controlPlane:
+  portMappings:
+    - hostPort: 8080
+      containerPort: 30080
+workers:
+  - portMappings:
+      - hostPort: 8080
+        containerPort: 30081
+`,
+			wantErr: "already mapped by controlPlane.portMappings[0]",
+		},
+		{
+			name: "wildcard listen address overlaps another address",
+			content: `apiVersion: cluster/v1-alpha.1
+controlPlane:
+  portMappings:
+    - hostPort: 8080
+      containerPort: 30080
+workers:
+  - portMappings:
+      - hostPort: 8080
+        containerPort: 30081
+        listenAddress: 0.0.0.0
+`,
+			wantErr: "workers[0].portMappings[0]: host port 0.0.0.0:8080/TCP is already mapped by controlPlane.portMappings[0]",
+		},
+		{
+			name: "Ingress host port",
+			content: `apiVersion: cluster/v1-alpha.1
+controlPlane:
+  portMappings:
+    - hostPort: 443
+      containerPort: 30443
+`,
+			wantErr: "host port 127.0.0.1:443/TCP is already mapped by the Ingress controller",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := spec.LoadLocalCluster(writeTempFile(t, tc.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+// TestLoadLocalCluster_MissingFile reports a missing file by path.
+func TestLoadLocalCluster_MissingFile(t *testing.T) {
+	t.Parallel()
+	_, err := spec.LoadLocalCluster(filepath.Join(t.TempDir(), spec.DefaultLocalClusterPath))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cluster config not found")
+}
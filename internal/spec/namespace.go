@@ -0,0 +1,125 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Namespace template placeholders.
+const (
+	// NamespaceProjectPlaceholder expands to the spec's project name.
+	NamespaceProjectPlaceholder = "{project}"
+	// NamespaceEnvPlaceholder expands to the Kubernetes-safe environment
+	// name, so each match of a wildcard environment like review/pr-123 gets
+	// its own namespace (review-pr-123).
+	NamespaceEnvPlaceholder = "{env}"
+)
+
+// namespacePlaceholderRe matches any {word} token in a namespace template.
+var namespacePlaceholderRe = regexp.MustCompile(`\{[^{}]*\}`)
+
+// PlatformNamespace places an environment's releases in a namespace the
+// platform team names, rather than the one the caller passes with -n.
+type PlatformNamespace struct {
+	// Name is the namespace name template, e.g. "{project}-{env}". See
+	// [NamespaceProjectPlaceholder] and [NamespaceEnvPlaceholder].
+	Name string `json:"name" yaml:"name"`
+	// Labels are set on the namespace when deploy creates it, and added to
+	// it on later deploys.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Annotations are set on the namespace like Labels.
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// NeedsProject reports whether the name template uses {project}, so it can
+// only be rendered once the project is known.
+func (n *PlatformNamespace) NeedsProject() bool {
+	return strings.Contains(n.Name, NamespaceProjectPlaceholder)
+}
+
+// Render expands the name template for project and env and checks the
+// result is a valid namespace name.
+func (n *PlatformNamespace) Render(project string, env EnvIdentity) (string, error) {
+	if n.NeedsProject() && project == "" {
+		return "", fmt.Errorf("namespace %q uses %s but no project is known", n.Name, NamespaceProjectPlaceholder)
+	}
+	name := strings.NewReplacer(
+		NamespaceProjectPlaceholder, project,
+		NamespaceEnvPlaceholder, env.K8sSafe,
+	).Replace(n.Name)
+	if msgs := k8svalidation.IsDNS1123Label(name); len(msgs) > 0 {
+		return "", fmt.Errorf("namespace %q renders to %q, which is not a valid namespace name: %s",
+			n.Name, name, strings.Join(msgs, "; "))
+	}
+	return name, nil
+}
+
+// ValidatePlatformNamespace checks that the name template uses only known
+// placeholders and renders to a valid namespace name, and that labels and
+// annotations have valid keys and values.
+func ValidatePlatformNamespace(n *PlatformNamespace) error {
+	if n == nil {
+		return nil
+	}
+	if strings.TrimSpace(n.Name) == "" {
+		return errors.New("namespace.name is required")
+	}
+	for _, token := range namespacePlaceholderRe.FindAllString(n.Name, -1) {
+		if token != NamespaceProjectPlaceholder && token != NamespaceEnvPlaceholder {
+			return fmt.Errorf("namespace.name: unknown placeholder %s (use %s or %s)",
+				token, NamespaceProjectPlaceholder, NamespaceEnvPlaceholder)
+		}
+	}
+	if _, err := n.Render("project", EnvIdentity{K8sSafe: "env"}); err != nil {
+		return fmt.Errorf("namespace.name: %w", err)
+	}
+	for k, v := range n.Labels {
+		if msgs := k8svalidation.IsQualifiedName(k); len(msgs) > 0 {
+			return fmt.Errorf("namespace.labels: invalid key %q: %s", k, strings.Join(msgs, "; "))
+		}
+		if msgs := k8svalidation.IsValidLabelValue(v); len(msgs) > 0 {
+			return fmt.Errorf("namespace.labels.%s: invalid value %q: %s", k, v, strings.Join(msgs, "; "))
+		}
+	}
+	for k := range n.Annotations {
+		if msgs := k8svalidation.IsQualifiedName(k); len(msgs) > 0 {
+			return fmt.Errorf("namespace.annotations: invalid key %q: %s", k, strings.Join(msgs, "; "))
+		}
+	}
+	return nil
+}
+
+// PlatformEnvNamespace returns the namespace config for the given
+// environment from the platform config, or nil when the platform does not
+// place that environment's releases.
+func PlatformEnvNamespace(platform *PlatformConfig, envName string) *PlatformNamespace {
+	if platform == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(platform.Environments))
+	for k := range platform.Environments {
+		keys = append(keys, k)
+	}
+	if matched, ok := matchEnvKey(envName, keys); ok {
+		return platform.Environments[matched].Namespace
+	}
+	return nil
+}
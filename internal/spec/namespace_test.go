@@ -0,0 +1,121 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+)
+
+func TestPlatformNamespace_Render(t *testing.T) {
+	t.Parallel()
+
+	ns := &spec.PlatformNamespace{Name: "{project}-{env}"}
+	got, err := ns.Render("shop", spec.NormalizeEnv("review/pr-123"))
+	require.NoError(t, err)
+	assert.Equal(t, "shop-review-pr-123", got)
+
+	_, err = ns.Render("", spec.NormalizeEnv("production"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no project is known")
+
+	_, err = ns.Render(strings.Repeat("a", 60), spec.NormalizeEnv("production"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a valid namespace name")
+
+	static := &spec.PlatformNamespace{Name: "payments"}
+	assert.False(t, static.NeedsProject())
+	got, err = static.Render("", spec.NormalizeEnv("production"))
+	require.NoError(t, err)
+	assert.Equal(t, "payments", got)
+}
+
+func TestValidatePlatformNamespace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ns      *spec.PlatformNamespace
+		wantErr string
+	}{
+		{name: "nil", ns: nil},
+		{name: "template", ns: &spec.PlatformNamespace{Name: "{project}-{env}", Labels: map[string]string{"team": "payments"}}},
+		{name: "empty name", ns: &spec.PlatformNamespace{}, wantErr: "namespace.name is required"},
+		{name: "unknown placeholder", ns: &spec.PlatformNamespace{Name: "{team}-{env}"}, wantErr: "unknown placeholder {team}"},
+		{name: "invalid characters", ns: &spec.PlatformNamespace{Name: "Shop_{env}"}, wantErr: "not a valid namespace name"},
+		{
+			name:    "invalid label value",
+			ns:      &spec.PlatformNamespace{Name: "{project}", Labels: map[string]string{"team": "pay ments"}},
+			wantErr: "namespace.labels.team",
+		},
+		{
+			name:    "invalid annotation key",
+			ns:      &spec.PlatformNamespace{Name: "{project}", Annotations: map[string]string{"bad key": "x"}},
+			wantErr: "namespace.annotations",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := spec.ValidatePlatformNamespace(tt.ns)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestResolve_Namespace renders the platform namespace template for the
+// spec's project and reports where it came from.
+func TestResolve_Namespace(t *testing.T) {
+	t.Parallel()
+
+	platform := minimalPlatform()
+	env := platform.Environments["production"]
+	env.Namespace = &spec.PlatformNamespace{Name: "{project}-{env}"}
+	platform.Environments["production"] = env
+
+	resolved, report, err := spec.Resolve(minimalSpec(new("api")), platform, spec.NormalizeEnv("production"), spec.SubstitutionReport{})
+	require.NoError(t, err)
+	assert.Equal(t, "shop-production", resolved.Namespace)
+	assert.Contains(t, report.Fields, spec.ResolvedField{
+		Path:   "namespace",
+		Value:  "shop-production",
+		Source: "platform environments.production.namespace.name",
+	})
+}
+
+func TestLoadPlatform_Namespace(t *testing.T) {
+	t.Parallel()
+
+	path := writeTempFile(t, `
+apiVersion: platform/v1-alpha.3
+environments:
+  review:
+    namespace:
+      name: "{project}-{stage}"
+`)
+	_, err := spec.LoadPlatform(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "environments.review.namespace.name: unknown placeholder {stage}")
+}
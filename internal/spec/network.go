@@ -0,0 +1,344 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NetworkPeer selects pods or addresses outside the project by namespace,
+// pod labels, or IP range. The platform file names peers under its root
+// peers map so components can list them in dependsOn and allowFrom.
+type NetworkPeer struct {
+	// Namespace selects pods in one namespace by name.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// NamespaceLabels selects pods in every namespace carrying these labels.
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty" yaml:"namespaceLabels,omitempty"`
+	// PodLabels narrows the selection to pods carrying these labels. Alone,
+	// it selects pods in the release namespace.
+	PodLabels map[string]string `json:"podLabels,omitempty" yaml:"podLabels,omitempty"`
+	// CIDRs selects IP ranges, such as a managed database subnet. Cannot be
+	// combined with the selector fields.
+	CIDRs []string `json:"cidrs,omitempty" yaml:"cidrs,omitempty"`
+}
+
+// ProfileNetworkPolicy is the platform traffic policy for components that
+// select the profile.
+type ProfileNetworkPolicy struct {
+	// DefaultDeny isolates the component's pods: only traffic declared with
+	// dependsOn and allowFrom, ingress controller traffic to exposed
+	// components, metrics scrapes, and DNS lookups are allowed.
+	DefaultDeny *bool `json:"defaultDeny,omitempty" yaml:"defaultDeny,omitempty"`
+	// IngressController names the platform peer that runs the ingress
+	// controller. Empty allows any source to reach an exposed component's
+	// http port.
+	IngressController string `json:"ingressController,omitempty" yaml:"ingressController,omitempty"`
+}
+
+// IsDefaultDeny reports whether p isolates the component's pods. False when
+// p is nil or defaultDeny is unset.
+func (p *ProfileNetworkPolicy) IsDefaultDeny() bool {
+	return p != nil && p.DefaultDeny != nil && *p.DefaultDeny
+}
+
+// NetworkEndpointKind says what the other end of a [NetworkRule] is.
+type NetworkEndpointKind string
+
+const (
+	// NetworkEndpointComponent is another component of the project.
+	NetworkEndpointComponent NetworkEndpointKind = "component"
+	// NetworkEndpointTask is a task of the project.
+	NetworkEndpointTask NetworkEndpointKind = "task"
+	// NetworkEndpointPeer is a peer named in the platform file.
+	NetworkEndpointPeer NetworkEndpointKind = "peer"
+	// NetworkEndpointIngress is the ingress controller serving expose.
+	NetworkEndpointIngress NetworkEndpointKind = "ingress"
+	// NetworkEndpointMonitoring is Prometheus scraping metrics.
+	NetworkEndpointMonitoring NetworkEndpointKind = "monitoring"
+	// NetworkEndpointDNS is the cluster DNS service.
+	NetworkEndpointDNS NetworkEndpointKind = "dns"
+)
+
+// dnsNamespace is where cluster DNS runs on every mainstream distribution.
+const dnsNamespace = "kube-system"
+
+// NetworkRule is one connection a component's NetworkPolicy allows: the
+// other end, selected by Peer, and an optional named container port.
+type NetworkRule struct {
+	// Kind is what the other end is.
+	Kind NetworkEndpointKind `json:"kind" yaml:"kind"`
+	// Name is the component, task, or platform peer name. Empty for the
+	// ingress controller, monitoring, and DNS unless a platform peer backs
+	// them.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Peer selects the other end's pods or addresses. An empty peer
+	// matches any source or destination.
+	Peer NetworkPeer `json:"peer" yaml:"peer"`
+	// Port limits the rule to a named container port of the isolated
+	// component. Empty allows every port. DNS rules use port 53 instead.
+	Port string `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// String describes the other end for resolve output, e.g. "component web",
+// "peer postgres", or "ingress".
+func (r NetworkRule) String() string {
+	s := string(r.Kind)
+	if r.Name != "" {
+		s += " " + r.Name
+	}
+	if r.Port != "" {
+		s += " (port " + r.Port + ")"
+	}
+	return s
+}
+
+// ComponentNetwork is the resolved connectivity of one component: who may
+// connect to it and what it may connect to.
+type ComponentNetwork struct {
+	// DefaultDeny is true when the merged profile isolates the component.
+	// Only then is a NetworkPolicy rendered and are the rules enforced.
+	DefaultDeny bool `json:"defaultDeny" yaml:"defaultDeny"`
+	// Ingress lists the allowed sources of incoming connections.
+	Ingress []NetworkRule `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+	// Egress lists the allowed destinations of outgoing connections.
+	Egress []NetworkRule `json:"egress,omitempty" yaml:"egress,omitempty"`
+}
+
+// ValidateComponentNetwork checks dependsOn and allowFrom entries: names
+// only, no duplicates, no self-reference, and no task in dependsOn since
+// tasks accept no connections. Names that are neither components nor tasks
+// must be platform peers; that is checked against the platform file during
+// resolve.
+func ValidateComponentNetwork(name string, component Component, spec *Spec) error {
+	var errs []error
+	for _, list := range []struct {
+		field string
+		names []string
+	}{
+		{"dependsOn", component.DependsOn},
+		{"allowFrom", component.AllowFrom},
+	} {
+		seen := make(map[string]bool, len(list.names))
+		for i, ref := range list.names {
+			field := fmt.Sprintf("%s[%d]", list.field, i)
+			if msgs := k8svalidation.IsDNS1123Label(ref); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s: invalid name %q: %s", field, ref, strings.Join(msgs, "; ")))
+				continue
+			}
+			if ref == name {
+				errs = append(errs, fmt.Errorf("%s: a component cannot reference itself", field))
+			}
+			if seen[ref] {
+				errs = append(errs, fmt.Errorf("%s: %q is listed twice", field, ref))
+			}
+			seen[ref] = true
+			if _, isTask := spec.Tasks[ref]; isTask && list.field == "dependsOn" {
+				errs = append(errs, fmt.Errorf("%s: %q is a task; tasks accept no connections (use allowFrom on this component to let the task reach it)", field, ref))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateNetworkPeer checks that a platform peer selects something and
+// does not mix IP ranges with pod selectors.
+func ValidateNetworkPeer(peer NetworkPeer) error {
+	selects := peer.Namespace != "" || len(peer.NamespaceLabels) > 0 || len(peer.PodLabels) > 0
+	switch {
+	case len(peer.CIDRs) > 0 && selects:
+		return errors.New("cidrs cannot be combined with namespace, namespaceLabels, or podLabels")
+	case len(peer.CIDRs) == 0 && !selects:
+		return errors.New("set namespace, namespaceLabels, podLabels, or cidrs")
+	}
+	for _, cidr := range peer.CIDRs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return fmt.Errorf("cidrs: %q is not a CIDR range", cidr)
+		}
+	}
+	if peer.Namespace != "" {
+		if msgs := k8svalidation.IsDNS1123Label(peer.Namespace); len(msgs) > 0 {
+			return fmt.Errorf("namespace %q: %s", peer.Namespace, strings.Join(msgs, "; "))
+		}
+	}
+	return nil
+}
+
+// resolveNetwork fills in [ResolvedComponent.Network] for every active
+// component that declares or receives any connection or is isolated. A
+// connection is declared from either end: "a dependsOn b" and "b allowFrom
+// a" both allow a to reach b, as ingress on b and egress on a. References
+// to components or tasks that are not deployed to env are dropped with a
+// warning.
+func resolveNetwork(appSpec *Spec, env EnvIdentity, platform *PlatformConfig, resolved *ResolvedSpec) ([]string, error) {
+	var peers map[string]NetworkPeer
+	if platform != nil {
+		peers = platform.Peers
+	}
+	podsOf := func(name string) NetworkPeer {
+		return NetworkPeer{PodLabels: map[string]string{
+			LabelProject:     appSpec.Project,
+			LabelComponent:   name,
+			LabelEnvironment: env.K8sSafe,
+		}}
+	}
+
+	var warnings []string
+	// endpoint turns a dependsOn or allowFrom reference into a rule. ok is
+	// false when the reference is not deployed to env.
+	endpoint := func(owner, field, ref string) (rule NetworkRule, ok bool, err error) {
+		if _, isComponent := appSpec.Components[ref]; isComponent {
+			if _, active := resolved.Components[ref]; !active {
+				warnings = append(warnings, fmt.Sprintf(
+					"component %q %s %q, which is not deployed to %s; the reference is ignored",
+					owner, field, ref, env.Original))
+				return NetworkRule{}, false, nil
+			}
+			return NetworkRule{Kind: NetworkEndpointComponent, Name: ref, Peer: podsOf(ref)}, true, nil
+		}
+		if _, isTask := appSpec.Tasks[ref]; isTask {
+			if _, active := resolved.Tasks[ref]; !active {
+				warnings = append(warnings, fmt.Sprintf(
+					"component %q %s %q, which is not deployed to %s; the reference is ignored",
+					owner, field, ref, env.Original))
+				return NetworkRule{}, false, nil
+			}
+			return NetworkRule{Kind: NetworkEndpointTask, Name: ref, Peer: podsOf(ref)}, true, nil
+		}
+		peer, isPeer := peers[ref]
+		if !isPeer {
+			return NetworkRule{}, false, &ResolutionError{
+				Code: ErrCodePeerNotFound,
+				Message: fmt.Sprintf(
+					"component %q %s %q, which is not a component, a task, or a platform peer (peers: %s)",
+					owner, field, ref, joinStrings(slices.Collect(maps.Keys(peers)))),
+			}
+		}
+		return NetworkRule{Kind: NetworkEndpointPeer, Name: ref, Peer: peer}, true, nil
+	}
+
+	names := slices.Sorted(maps.Keys(resolved.Components))
+	networks := make(map[string]*ComponentNetwork, len(names))
+	for _, name := range names {
+		var policy *ProfileNetworkPolicy
+		if mp := resolved.Components[name].MergedProfile; mp != nil {
+			policy = mp.NetworkPolicy
+		}
+		networks[name] = &ComponentNetwork{DefaultDeny: policy.IsDefaultDeny()}
+	}
+
+	for _, name := range names {
+		comp := appSpec.Components[name]
+		self := NetworkRule{Kind: NetworkEndpointComponent, Name: name, Peer: podsOf(name)}
+		for _, ref := range comp.DependsOn {
+			rule, ok, err := endpoint(name, "depends on", ref)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			networks[name].Egress = append(networks[name].Egress, rule)
+			if rule.Kind == NetworkEndpointComponent {
+				networks[ref].Ingress = append(networks[ref].Ingress, self)
+			}
+		}
+		for _, ref := range comp.AllowFrom {
+			rule, ok, err := endpoint(name, "allows traffic from", ref)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			networks[name].Ingress = append(networks[name].Ingress, rule)
+			if rule.Kind == NetworkEndpointComponent {
+				networks[ref].Egress = append(networks[ref].Egress, self)
+			}
+		}
+	}
+
+	for _, name := range names {
+		comp := appSpec.Components[name]
+		rc := resolved.Components[name]
+		n := networks[name]
+		if comp.Expose != nil && comp.ListensOnPort() {
+			rule := NetworkRule{Kind: NetworkEndpointIngress, Port: "http"}
+			if rc.MergedProfile != nil && rc.MergedProfile.NetworkPolicy != nil && rc.MergedProfile.NetworkPolicy.IngressController != "" {
+				ref := rc.MergedProfile.NetworkPolicy.IngressController
+				peer, ok := peers[ref]
+				if !ok {
+					return nil, &ResolutionError{
+						Code: ErrCodePeerNotFound,
+						Message: fmt.Sprintf(
+							"component %q: networkPolicy.ingressController %q is not a platform peer (peers: %s)",
+							name, ref, joinStrings(slices.Collect(maps.Keys(peers)))),
+					}
+				}
+				rule.Name, rule.Peer = ref, peer
+			}
+			n.Ingress = append(n.Ingress, rule)
+		}
+		if comp.Metrics.IsEnabled() {
+			rule := NetworkRule{Kind: NetworkEndpointMonitoring, Port: metricsPortName(comp)}
+			if rc.MergedProfile != nil && rc.MergedProfile.Metrics != nil && rc.MergedProfile.Metrics.MonitorNamespace != "" {
+				rule.Peer.Namespace = rc.MergedProfile.Metrics.MonitorNamespace
+			}
+			n.Ingress = append(n.Ingress, rule)
+		}
+		if n.DefaultDeny {
+			n.Egress = append(n.Egress, NetworkRule{Kind: NetworkEndpointDNS, Peer: NetworkPeer{Namespace: dnsNamespace}})
+		}
+		if !n.DefaultDeny && len(n.Ingress) == 0 && len(n.Egress) == 0 {
+			continue
+		}
+		n.Ingress = dedupeRules(n.Ingress)
+		n.Egress = dedupeRules(n.Egress)
+		rc.Network = n
+		resolved.Components[name] = rc
+	}
+	return warnings, nil
+}
+
+// metricsPortName is the container port name Prometheus scrapes: the
+// dedicated metrics port when the component has one, else http.
+func metricsPortName(comp Component) string {
+	if comp.Role.IsWorker() || (comp.Metrics.Port > 0 && comp.Metrics.Port != comp.Port) {
+		return MetricsPortName
+	}
+	return "http"
+}
+
+// dedupeRules drops repeated rules, which arise when both ends declare the
+// same connection, keeping first-seen order.
+func dedupeRules(rules []NetworkRule) []NetworkRule {
+	out := rules[:0]
+	seen := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		key := string(r.Kind) + "/" + r.Name + "/" + r.Port
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, r)
+	}
+	return out
+}
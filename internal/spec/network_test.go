@@ -0,0 +1,269 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+)
+
+// networkSpec is a shop with web -> api -> db, a migrate task reaching db,
+// and api calling an external payments peer.
+func networkSpec() *spec.Spec {
+	return &spec.Spec{
+		Project: "shop",
+		Components: map[string]spec.Component{
+			"web": {
+				Role:      spec.ComponentRoleService,
+				Image:     "ghcr.io/acme/web:1",
+				Port:      3000,
+				Expose:    &spec.Expose{Domain: "public"},
+				DependsOn: []string{"api"},
+			},
+			"api": {
+				Role:      spec.ComponentRoleService,
+				Image:     "ghcr.io/acme/api:1",
+				Port:      8080,
+				DependsOn: []string{"db", "payments"},
+				Metrics:   &spec.ComponentMetrics{Enabled: new(true)},
+			},
+			"db": {
+				Role:      spec.ComponentRoleService,
+				Image:     "postgres:17",
+				Port:      5432,
+				AllowFrom: []string{"api", "migrate"},
+			},
+		},
+		Tasks: map[string]spec.Task{
+			"migrate": {From: "api", On: spec.TaskOnPreDeploy, Command: []string{"./migrate"}},
+		},
+	}
+}
+
+func networkPlatform() *spec.PlatformConfig {
+	p := minimalPlatform()
+	p.Peers = map[string]spec.NetworkPeer{
+		"ingress-nginx": {Namespace: "ingress-nginx"},
+		"payments":      {CIDRs: []string{"203.0.113.0/24"}},
+	}
+	p.Profiles = map[string]spec.PlatformProfile{
+		"default": {
+			NetworkPolicy: &spec.ProfileNetworkPolicy{DefaultDeny: new(true), IngressController: "ingress-nginx"},
+			Metrics:       &spec.ProfileMetrics{MonitorLabels: map[string]string{"release": "prom"}, MonitorNamespace: "monitoring"},
+		},
+	}
+	return p
+}
+
+func ruleNames(rules []spec.NetworkRule) []string {
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.String())
+	}
+	return names
+}
+
+// TestResolve_Network verifies that connections declared from either end
+// become ingress on the target and egress on the source, and that expose,
+// metrics, and DNS add their rules.
+func TestResolve_Network(t *testing.T) {
+	t.Parallel()
+
+	resolved, _, err := spec.Resolve(networkSpec(), networkPlatform(), spec.NormalizeEnv("production"), spec.SubstitutionReport{})
+	require.NoError(t, err)
+
+	web := resolved.Components["web"].Network
+	require.NotNil(t, web)
+	assert.True(t, web.DefaultDeny)
+	assert.Equal(t, []string{"ingress ingress-nginx (port http)"}, ruleNames(web.Ingress))
+	assert.Equal(t, []string{"component api", "dns"}, ruleNames(web.Egress))
+	assert.Equal(t, "ingress-nginx", web.Ingress[0].Peer.Namespace)
+
+	api := resolved.Components["api"].Network
+	require.NotNil(t, api)
+	assert.Equal(t, []string{"component web", "monitoring (port http)"}, ruleNames(api.Ingress))
+	assert.Equal(t, "monitoring", api.Ingress[1].Peer.Namespace)
+	assert.Equal(t, []string{"component db", "peer payments", "dns"}, ruleNames(api.Egress))
+	assert.Equal(t, []string{"203.0.113.0/24"}, api.Egress[1].Peer.CIDRs)
+
+	db := resolved.Components["db"].Network
+	require.NotNil(t, db)
+	assert.Equal(t, []string{"component api", "task migrate"}, ruleNames(db.Ingress), "api declared twice, listed once")
+	assert.Equal(t, []string{"dns"}, ruleNames(db.Egress))
+	assert.Equal(t, map[string]string{
+		spec.LabelProject:     "shop",
+		spec.LabelComponent:   "migrate",
+		spec.LabelEnvironment: "production",
+	}, db.Ingress[1].Peer.PodLabels)
+}
+
+// TestResolve_NetworkOpenWithoutDefaultDeny verifies declared connections
+// are resolved but not isolated when no profile sets defaultDeny.
+func TestResolve_NetworkOpenWithoutDefaultDeny(t *testing.T) {
+	t.Parallel()
+
+	platform := networkPlatform()
+	platform.Profiles = nil
+	appSpec := networkSpec()
+	web := appSpec.Components["web"]
+	web.Expose = nil
+	appSpec.Components["web"] = web
+	api := appSpec.Components["api"]
+	api.Metrics = nil
+	appSpec.Components["api"] = api
+
+	resolved, _, err := spec.Resolve(appSpec, platform, spec.NormalizeEnv("production"), spec.SubstitutionReport{})
+	require.NoError(t, err)
+	db := resolved.Components["db"].Network
+	require.NotNil(t, db)
+	assert.False(t, db.DefaultDeny)
+	assert.Equal(t, []string{"component api", "task migrate"}, ruleNames(db.Ingress))
+	assert.Empty(t, db.Egress, "no DNS rule without isolation")
+}
+
+// TestResolve_NetworkUnknownPeer verifies a reference that is neither a
+// component, a task, nor a platform peer fails resolution.
+func TestResolve_NetworkUnknownPeer(t *testing.T) {
+	t.Parallel()
+
+	appSpec := networkSpec()
+	api := appSpec.Components["api"]
+	api.DependsOn = []string{"redis"}
+	appSpec.Components["api"] = api
+
+	_, report, err := spec.Resolve(appSpec, networkPlatform(), spec.NormalizeEnv("production"), spec.SubstitutionReport{})
+	require.Error(t, err)
+	assert.Equal(t, spec.ErrCodePeerNotFound, report.ErrorCode)
+	assert.Contains(t, err.Error(), `"redis"`)
+
+	problems, _ := spec.CrossCheckPlatformReferences(appSpec, networkPlatform())
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], `references "redis"`)
+}
+
+// TestResolve_NetworkInactiveReference verifies a reference to a component
+// not deployed to the environment is dropped with a warning.
+func TestResolve_NetworkInactiveReference(t *testing.T) {
+	t.Parallel()
+
+	appSpec := networkSpec()
+	db := appSpec.Components["db"]
+	db.Environments = []string{"local"}
+	appSpec.Components["db"] = db
+
+	resolved, report, err := spec.Resolve(appSpec, networkPlatform(), spec.NormalizeEnv("production"), spec.SubstitutionReport{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"peer payments", "dns"}, ruleNames(resolved.Components["api"].Network.Egress))
+	assert.Contains(t, report.Warnings, `component "api" depends on "db", which is not deployed to production; the reference is ignored`)
+}
+
+func TestValidateComponentNetwork(t *testing.T) {
+	t.Parallel()
+
+	s := networkSpec()
+	tests := []struct {
+		name      string
+		component spec.Component
+		wantErr   string
+	}{
+		{name: "valid", component: spec.Component{DependsOn: []string{"db"}, AllowFrom: []string{"migrate"}}},
+		{name: "self", component: spec.Component{DependsOn: []string{"api"}}, wantErr: "cannot reference itself"},
+		{name: "duplicate", component: spec.Component{AllowFrom: []string{"web", "web"}}, wantErr: `"web" is listed twice`},
+		{name: "task in dependsOn", component: spec.Component{DependsOn: []string{"migrate"}}, wantErr: "tasks accept no connections"},
+		{name: "invalid name", component: spec.Component{AllowFrom: []string{"Web"}}, wantErr: `invalid name "Web"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := spec.ValidateComponentNetwork("api", tt.component, s)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateNetworkPeer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		peer    spec.NetworkPeer
+		wantErr string
+	}{
+		{name: "namespace", peer: spec.NetworkPeer{Namespace: "ingress-nginx"}},
+		{name: "labels", peer: spec.NetworkPeer{NamespaceLabels: map[string]string{"team": "data"}, PodLabels: map[string]string{"app": "kafka"}}},
+		{name: "cidrs", peer: spec.NetworkPeer{CIDRs: []string{"10.0.0.0/8", "2001:db8::/32"}}},
+		{name: "empty", peer: spec.NetworkPeer{}, wantErr: "set namespace"},
+		{name: "mixed", peer: spec.NetworkPeer{Namespace: "db", CIDRs: []string{"10.0.0.0/8"}}, wantErr: "cannot be combined"},
+		{name: "bad cidr", peer: spec.NetworkPeer{CIDRs: []string{"10.0.0.1"}}, wantErr: "not a CIDR range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := spec.ValidateNetworkPeer(tt.peer)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestLoadPlatform_UnknownIngressController verifies a profile cannot name
+// an ingress controller that is not a peer.
+func TestLoadPlatform_UnknownIngressController(t *testing.T) {
+	t.Parallel()
+
+	path := writeTempFile(t, `
+apiVersion: platform/v1-alpha.3
+peers:
+  ingress-nginx:
+    namespace: ingress-nginx
+profiles:
+  default:
+    networkPolicy:
+      defaultDeny: true
+      ingressController: traefik
+environments:
+  production:
+    context: prod-eks
+`)
+	_, err := spec.LoadPlatform(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `networkPolicy.ingressController: "traefik" is not defined in peers`)
+}
+
+func TestMergeProfiles_NetworkPolicy(t *testing.T) {
+	t.Parallel()
+
+	merged, err := spec.MergeProfiles([]string{"default", "edge"}, map[string]spec.PlatformProfile{
+		"default": {NetworkPolicy: &spec.ProfileNetworkPolicy{DefaultDeny: new(true), IngressController: "ingress-nginx"}},
+		"edge":    {NetworkPolicy: &spec.ProfileNetworkPolicy{DefaultDeny: new(false)}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, merged.NetworkPolicy)
+	assert.False(t, merged.NetworkPolicy.IsDefaultDeny())
+	assert.Equal(t, "ingress-nginx", merged.NetworkPolicy.IngressController)
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ComponentOverride is one component's entry under
+// environments.<name>.components: a partial component in the same shape as
+// components.<name>. It is kept as a raw object so an unset field stays
+// distinct from a zero value.
+type ComponentOverride map[string]any
+
+// mergeOverride merges patch onto dst the way a JSON merge patch does:
+// objects merge key by key, lists and scalars replace, and null removes
+// the field. Each changed leaf is passed to record with its dotted path
+// below prefix.
+func mergeOverride(dst, patch map[string]any, prefix string, record func(path string, value any)) {
+	for _, key := range slices.Sorted(maps.Keys(patch)) {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		value := patch[key]
+		if value == nil {
+			delete(dst, key)
+			record(path, nil)
+			continue
+		}
+		if sub, ok := value.(map[string]any); ok {
+			existing, isMap := dst[key].(map[string]any)
+			if !isMap {
+				existing = map[string]any{}
+			}
+			mergeOverride(existing, sub, path, record)
+			dst[key] = existing
+			continue
+		}
+		dst[key] = value
+		record(path, value)
+	}
+}
+
+// overrideFieldValue renders an overridden value for the resolution report.
+// Strings print as-is, removed fields as "(removed)", and everything else as
+// compact JSON.
+func overrideFieldValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "(removed)"
+	case string:
+		return v
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+// applyComponentOverrides merges the component overrides of environment
+// envKey onto the components of the raw spec object, then drops them from
+// the environment entry so a second call is a no-op. It returns the
+// provenance of every overridden field.
+func applyComponentOverrides(specObj map[string]any, envKey string) ([]ResolvedField, error) {
+	envs, _ := specObj["environments"].(map[string]any)
+	env, _ := envs[envKey].(map[string]any)
+	overrides, _ := env["components"].(map[string]any)
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	components, _ := specObj["components"].(map[string]any)
+	fields, err := mergeComponentOverrides(components, overrides, envKey)
+	if err != nil {
+		return nil, err
+	}
+	delete(env, "components")
+	return fields, nil
+}
+
+// mergeComponentOverrides merges each entry of overrides onto the raw
+// component of the same name, in name order, and returns the provenance
+// of every overridden field.
+func mergeComponentOverrides(components, overrides map[string]any, envKey string) ([]ResolvedField, error) {
+	var fields []ResolvedField
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		base, ok := components[name].(map[string]any)
+		if !ok {
+			available := slices.Sorted(maps.Keys(components))
+			return nil, fmt.Errorf("environments.%s.components.%s: no such component (available: %s)",
+				envKey, name, strings.Join(available, ", "))
+		}
+		patch, ok := overrides[name].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("environments.%s.components.%s: must be an object", envKey, name)
+		}
+		mergeOverride(base, patch, "", func(path string, value any) {
+			fields = append(fields, ResolvedField{
+				Component: name,
+				Path:      path,
+				Value:     overrideFieldValue(value),
+				Source:    fmt.Sprintf("spec environments.%s.components.%s.%s", envKey, name, path),
+			})
+		})
+	}
+	return fields, nil
+}
+
+// ApplyComponentOverrides merges the environments.<env>.components entries
+// for environment env onto m.Components and appends their provenance to
+// m.Overrides. env may be a wildcard instance such as "review/pr-123"; it
+// is matched against the spec's environment keys like everywhere else.
+// [Load] applies overrides before validation and defaults; this form serves
+// callers holding a spec from [ParseManifest], and is a no-op once the
+// overrides have been applied.
+func ApplyComponentOverrides(m *Spec, env string) error {
+	key, ok := matchEnvKey(env, slices.Collect(maps.Keys(m.Environments)))
+	if !ok || len(m.Environments[key].Components) == 0 {
+		return nil
+	}
+	overrides := make(map[string]any, len(m.Environments[key].Components))
+	for name, patch := range m.Environments[key].Components {
+		overrides[name] = map[string]any(patch)
+	}
+	components := make(map[string]any, len(m.Components))
+	for name, comp := range m.Components {
+		raw, err := toRawObject(comp)
+		if err != nil {
+			return fmt.Errorf("component %s: %w", name, err)
+		}
+		components[name] = raw
+	}
+	fields, err := mergeComponentOverrides(components, overrides, key)
+	if err != nil {
+		return err
+	}
+
+	merged := maps.Clone(m.Components)
+	for name := range overrides {
+		b, err := json.Marshal(components[name])
+		if err != nil {
+			return fmt.Errorf("component %s: %w", name, err)
+		}
+		var comp Component
+		if err := json.Unmarshal(b, &comp); err != nil {
+			return fmt.Errorf("environments.%s.components.%s: %w", key, name, err)
+		}
+		merged[name] = comp
+	}
+	m.Components = merged
+	normalizeComponents(m)
+
+	envs := maps.Clone(m.Environments)
+	e := envs[key]
+	e.Components = nil
+	envs[key] = e
+	m.Environments = envs
+	m.Overrides = append(m.Overrides, fields...)
+	return nil
+}
+
+// toRawObject converts v to the generic object form its JSON encoding
+// decodes to.
+func toRawObject(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
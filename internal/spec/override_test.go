@@ -0,0 +1,179 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const overridesSpec = `apiVersion: v1-alpha.5
+project: shop
+components:
+  api:
+    image: ghcr.io/acme/api:1.0.0
+    replicas: 1
+    resourcePreset: small
+    env:
+      LOG_LEVEL: info
+      FEATURE_X: "on"
+  worker:
+    role: worker
+    image: ghcr.io/acme/worker:1.0.0
+environments:
+  staging: {}
+  production:
+    components:
+      api:
+        replicas: 3
+        resourcePreset: large
+        env:
+          LOG_LEVEL: warn
+          FEATURE_X: null
+  review:
+    components:
+      api:
+        env:
+          LOG_LEVEL: debug
+`
+
+func writeOverridesSpec(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, "deployah.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// TestLoad_ComponentOverrides verifies the selected environment's overrides
+// are merged before defaults, and other environments are untouched.
+func TestLoad_ComponentOverrides(t *testing.T) {
+	path := writeOverridesSpec(t, overridesSpec)
+
+	m, err := Load(t.Context(), path, "production", nil)
+	require.NoError(t, err)
+	api := m.Components["api"]
+	require.NotNil(t, api.Replicas)
+	assert.Equal(t, 3, *api.Replicas)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "warn"}, api.Env)
+	large := ResourcePresetMappings[ResourcePresetLarge]["requests"]
+	assert.Equal(t, large.Memory.String(), api.Resources.Memory.String())
+	assert.Empty(t, m.Environments["production"].Components, "applied overrides are consumed")
+	assert.Equal(t, []ResolvedField{
+		{Component: "api", Path: "env.FEATURE_X", Value: "(removed)", Source: "spec environments.production.components.api.env.FEATURE_X"},
+		{Component: "api", Path: "env.LOG_LEVEL", Value: "warn", Source: "spec environments.production.components.api.env.LOG_LEVEL"},
+		{Component: "api", Path: "replicas", Value: "3", Source: "spec environments.production.components.api.replicas"},
+		{Component: "api", Path: "resourcePreset", Value: "large", Source: "spec environments.production.components.api.resourcePreset"},
+	}, m.Overrides)
+
+	m, err = Load(t.Context(), path, "staging", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, *m.Components["api"].Replicas)
+	assert.Equal(t, "info", m.Components["api"].Env["LOG_LEVEL"])
+	assert.Empty(t, m.Overrides)
+
+	m, err = Load(t.Context(), path, "review/pr-42", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", m.Components["api"].Env["LOG_LEVEL"], "wildcard instance uses the review overrides")
+}
+
+// TestLoad_ComponentOverrideErrors verifies overrides are held to the
+// component schema and must name an existing component.
+func TestLoad_ComponentOverrideErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		wantErr string
+	}{
+		{
+			name:    "unknown component",
+			env:     "  production:\n    components:\n      web:\n        replicas: 2\n",
+			wantErr: "environments.production.components.web: no such component (available: api)",
+		},
+		{
+			name:    "schema violation",
+			env:     "  production:\n    components:\n      api:\n        replicas: three\n",
+			wantErr: "spec validation failed after applying environments.production.components",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeOverridesSpec(t, `apiVersion: v1-alpha.5
+project: shop
+components:
+  api:
+    image: ghcr.io/acme/api:1.0.0
+environments:
+`+tt.env)
+			_, err := Load(t.Context(), path, "production", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestResolve_ComponentOverrides verifies Resolve applies overrides to a
+// spec from ParseManifest and reports each field's source.
+func TestResolve_ComponentOverrides(t *testing.T) {
+	path := writeOverridesSpec(t, overridesSpec)
+	rawSpec, _, err := ParseManifest(path)
+	require.NoError(t, err)
+
+	resolved, report, err := Resolve(rawSpec, nil, NormalizeEnv("production"), SubstitutionReport{})
+	require.NoError(t, err)
+	api := resolved.Spec.Components["api"]
+	require.NotNil(t, api.Replicas)
+	assert.Equal(t, 3, *api.Replicas)
+	assert.Equal(t, ResourcePresetLarge, api.ResourcePreset)
+	assert.Contains(t, report.Fields, ResolvedField{
+		Component: "api",
+		Path:      "replicas",
+		Value:     "3",
+		Source:    "spec environments.production.components.api.replicas",
+	})
+
+	// A second pass finds nothing left to apply.
+	require.NoError(t, ApplyComponentOverrides(rawSpec, "production"))
+	assert.Len(t, rawSpec.Overrides, 4)
+}
+
+func TestMergeOverride(t *testing.T) {
+	t.Parallel()
+
+	dst := map[string]any{
+		"replicas": 1.0,
+		"args":     []any{"--a"},
+		"health":   map[string]any{"path": "/healthz", "port": 8080.0},
+	}
+	var paths []string
+	mergeOverride(dst, map[string]any{
+		"args":   []any{"--b"},
+		"health": map[string]any{"path": "/ready"},
+		"port":   nil,
+	}, "", func(path string, _ any) { paths = append(paths, path) })
+
+	assert.Equal(t, map[string]any{
+		"replicas": 1.0,
+		"args":     []any{"--b"},
+		"health":   map[string]any{"path": "/ready", "port": 8080.0},
+	}, dst)
+	assert.Equal(t, []string{"args", "health.path", "port"}, paths)
+	assert.Equal(t, `["--b"]`, overrideFieldValue([]any{"--b"}))
+}
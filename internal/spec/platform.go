@@ -38,6 +38,10 @@ type PlatformConfig struct {
 	// org-wide (root-level), not per-environment. A profile named "default" is
 	// prepended automatically when a component omits profiles.
 	Profiles map[string]PlatformProfile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	// Peers names traffic endpoints outside the project, such as a shared
+	// database or the ingress controller, for dependsOn, allowFrom, and
+	// networkPolicy.ingressController.
+	Peers map[string]NetworkPeer `json:"peers,omitempty" yaml:"peers,omitempty"`
 	// Environments is a map of environment names to their platform
 	// configuration. Wildcard matching (prefix-split on "/") is applied by
 	// [matchEnvKey].
@@ -70,13 +74,22 @@ type PlatformProfile struct {
 	// PVCRetentionPolicy overrides StatefulSet PVC retention for stateful
 	// components. Nil means chart defaults (Retain/Retain).
 	PVCRetentionPolicy *PVCRetentionPolicy `json:"pvcRetentionPolicy,omitempty" yaml:"pvcRetentionPolicy,omitempty"`
+	// Disruption is the default PodDisruptionBudget for multi-replica
+	// components that do not set their own.
+	Disruption *Disruption `json:"disruption,omitempty" yaml:"disruption,omitempty"`
+	// NetworkPolicy turns on default-deny NetworkPolicies for the
+	// component and names the ingress controller peer.
+	NetworkPolicy *ProfileNetworkPolicy `json:"networkPolicy,omitempty" yaml:"networkPolicy,omitempty"`
 	// AllowedDomains restricts which domain keys a component may expose on.
 	// nil means no constraint. A non-nil empty list means deny-all (no domain
 	// is allowed). Multiple profiles intersect. Neither JSON nor YAML uses
 	// omitempty, so deny-all serializes as [] rather than becoming nil.
 	AllowedDomains []string `json:"allowedDomains" yaml:"allowedDomains"`
-	// MaxResources is a ceiling on component resource requests.
+	// MaxResources is a ceiling on component resource requests and limits.
 	MaxResources *ProfileMaxResources `json:"maxResources,omitempty" yaml:"maxResources,omitempty"`
+	// ResourceLimits chooses, per resource, how container limits are set:
+	// from the spec, not at all, or equal to the request.
+	ResourceLimits *ProfileResourceLimits `json:"resourceLimits,omitempty" yaml:"resourceLimits,omitempty"`
 	// Metrics holds Prometheus Operator monitor defaults owned by the
 	// platform (discovery labels, scrape timing, relabelings). Nested under
 	// metrics so profile root stays free of scrape-specific field names.
@@ -118,12 +131,23 @@ type PVCRetentionPolicy struct {
 	WhenScaled string `json:"whenScaled,omitempty" yaml:"whenScaled,omitempty"`
 }
 
-// ProfileMaxResources caps component resource requests.
+// ProfileMaxResources caps component resource requests and limits.
 type ProfileMaxResources struct {
 	// CPU is the maximum CPU request (Kubernetes quantity).
 	CPU *resource.Quantity `json:"cpu,omitempty" yaml:"cpu,omitempty"`
 	// Memory is the maximum memory request (Kubernetes quantity).
 	Memory *resource.Quantity `json:"memory,omitempty" yaml:"memory,omitempty"`
+	// Limits caps the limits left after the profile's resourceLimits
+	// policy is applied.
+	Limits *ProfileMaxLimits `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+// ProfileMaxLimits caps component resource limits.
+type ProfileMaxLimits struct {
+	// CPU is the maximum CPU limit (Kubernetes quantity).
+	CPU *resource.Quantity `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	// Memory is the maximum memory limit (Kubernetes quantity).
+	Memory *resource.Quantity `json:"memory,omitempty" yaml:"memory,omitempty"`
 }
 
 // PlatformEnvironment holds platform-controlled settings for one environment.
@@ -138,6 +162,20 @@ type PlatformEnvironment struct {
 	// AllowStaticSubdomain suppresses the wildcard static-subdomain warning
 	// for this environment key when set to true.
 	AllowStaticSubdomain bool `json:"allowStaticSubdomain,omitempty" yaml:"allowStaticSubdomain,omitempty"`
+	// Namespace names the namespace releases for this environment deploy
+	// into. Nil leaves the choice to -n (default "default").
+	Namespace *PlatformNamespace `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// RollbackOnFailure makes every deploy to this environment roll back to
+	// the last successful revision when the release does not become ready,
+	// as if `deploy --rollback-on-failure` were passed.
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty" yaml:"rollbackOnFailure,omitempty"`
+	// PromoteFrom lists the environments `deployah promote` may copy a
+	// release from into this one. Empty accepts any environment.
+	PromoteFrom []string `json:"promoteFrom,omitempty" yaml:"promoteFrom,omitempty"`
+	// Variables are defaults for ${VAR} substitution in specs deployed to
+	// this environment. The spec's environment variables, env file, and
+	// DPY_VAR_-prefixed OS variables override them.
+	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
 }
 
 // PlatformDomain holds the base domain and TLS configuration for a logical
@@ -150,6 +188,65 @@ type PlatformDomain struct {
 	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
 	// TLS holds the TLS mode and associated parameters.
 	TLS *PlatformTLS `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// Ingress selects how exposed components on this domain are routed.
+	// Nil renders an Ingress.
+	Ingress *PlatformIngress `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+}
+
+// PlatformIngress selects the resource that routes a domain's traffic to
+// exposed components.
+type PlatformIngress struct {
+	// Mode is the routing resource kind. Defaults to [IngressModeIngress].
+	Mode IngressMode `json:"mode" yaml:"mode"`
+	// GatewayRef is the Gateway that HTTPRoutes attach to. Required when
+	// Mode is [IngressModeGateway].
+	GatewayRef *GatewayRef `json:"gatewayRef,omitempty" yaml:"gatewayRef,omitempty"`
+}
+
+// IngressMode specifies the routing resource rendered for exposed
+// components.
+type IngressMode string
+
+const (
+	// IngressModeIngress renders a networking.k8s.io/v1 Ingress.
+	IngressModeIngress IngressMode = "ingress"
+	// IngressModeGateway renders a Gateway API HTTPRoute attached to an
+	// existing Gateway. The Gateway's listener terminates TLS.
+	IngressModeGateway IngressMode = "gateway"
+)
+
+// GatewayRef names a Gateway API Gateway, and optionally one of its
+// listeners, that HTTPRoutes attach to.
+type GatewayRef struct {
+	// Name is the Gateway name.
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the Gateway namespace. Empty means the release namespace.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// SectionName is the listener to attach to. Empty attaches to every
+	// listener that allows routes from the release namespace.
+	SectionName string `json:"sectionName,omitempty" yaml:"sectionName,omitempty"`
+}
+
+// String renders the reference as "namespace/name" with a "#sectionName"
+// suffix when a listener is named.
+func (g GatewayRef) String() string {
+	s := g.Name
+	if g.Namespace != "" {
+		s = g.Namespace + "/" + s
+	}
+	if g.SectionName != "" {
+		s += "#" + g.SectionName
+	}
+	return s
+}
+
+// GatewayRef returns the Gateway that exposed components on this domain
+// attach to, or nil when the domain renders an Ingress.
+func (d PlatformDomain) GatewayRef() *GatewayRef {
+	if d.Ingress == nil || d.Ingress.Mode != IngressModeGateway {
+		return nil
+	}
+	return d.Ingress.GatewayRef
 }
 
 // PlatformTLS holds explicit TLS configuration for a domain.
@@ -187,12 +284,19 @@ type PlatformStorageClass struct {
 	ClassName string `json:"className" yaml:"className"`
 }
 
+// LocalRegistryVariable is the variable the local environment sets to the
+// address of the registry started by `deployah cluster up --registry`, so
+// specs can reference images as ${REGISTRY}/app:dev.
+const LocalRegistryVariable = "REGISTRY"
+
 // LocalPlatformEnvironment returns a PlatformEnvironment configured for local
-// development: kind-deployah context, nip.io base domain using ingressIP, and
-// selfSigned TLS. Pass the host IP at which the Ingress controller is reachable
-// (typically localkube.DefaultIngressIP).
-func LocalPlatformEnvironment(ingressIP string) PlatformEnvironment {
-	return PlatformEnvironment{
+// development: kind-deployah context, nip.io base domain using ingressIP,
+// and selfSigned TLS. Pass the host IP at which the Ingress controller is
+// reachable (typically localkube.DefaultIngressIP). registryAddr, when set,
+// is the local registry's address (localkube's Manager.RegistryAddress) and
+// becomes [LocalRegistryVariable].
+func LocalPlatformEnvironment(ingressIP, registryAddr string) PlatformEnvironment {
+	env := PlatformEnvironment{
 		Context: "kind-deployah",
 		Domains: map[string]PlatformDomain{
 			"public": {
@@ -201,4 +305,8 @@ func LocalPlatformEnvironment(ingressIP string) PlatformEnvironment {
 			},
 		},
 	}
+	if registryAddr != "" {
+		env.Variables = map[string]string{LocalRegistryVariable: registryAddr}
+	}
+	return env
 }
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/google/renameio/v2"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/yaml"
 
 	"deployah.dev/deployah/internal/spec/schema"
@@ -155,6 +156,24 @@ func validatePlatformConsistency(p *PlatformConfig) error {
 					return err
 				}
 			}
+			if domain.Ingress != nil {
+				if err := validatePlatformIngress(domain.Ingress, envKey, domainKey); err != nil {
+					return err
+				}
+			}
+		}
+		if err := ValidatePlatformNamespace(env.Namespace); err != nil {
+			return fmt.Errorf("environments.%s.%w", envKey, err)
+		}
+		for _, from := range env.PromoteFrom {
+			if from == envKey {
+				return fmt.Errorf("environments.%s.promoteFrom: an environment cannot promote from itself", envKey)
+			}
+			if _, ok := p.Environments[from]; !ok {
+				available := slices.Sorted(maps.Keys(p.Environments))
+				return fmt.Errorf("environments.%s.promoteFrom: %q is not a registered environment (available: %s)",
+					envKey, from, strings.Join(available, ", "))
+			}
 		}
 		if len(defaults) > 1 {
 			slices.Sort(defaults)
@@ -166,6 +185,12 @@ func validatePlatformConsistency(p *PlatformConfig) error {
 		}
 	}
 
+	for peerName, peer := range p.Peers {
+		if err := ValidateNetworkPeer(peer); err != nil {
+			return fmt.Errorf("peers.%s: %w", peerName, err)
+		}
+	}
+
 	for profileName, profile := range p.Profiles {
 		for _, domainKey := range profile.AllowedDomains {
 			if !allDomains[domainKey] {
@@ -183,6 +208,21 @@ func validatePlatformConsistency(p *PlatformConfig) error {
 				profileName, profile.StorageClass, strings.Join(available, ", "),
 			)
 		}
+		if err := ValidateDisruption(profile.Disruption); err != nil {
+			return fmt.Errorf("profiles.%s.%w", profileName, err)
+		}
+		if np := profile.NetworkPolicy; np != nil && np.IngressController != "" {
+			if _, ok := p.Peers[np.IngressController]; !ok {
+				available := slices.Sorted(maps.Keys(p.Peers))
+				return fmt.Errorf(
+					"profiles.%s.networkPolicy.ingressController: %q is not defined in peers (available: %s)",
+					profileName, np.IngressController, strings.Join(available, ", "),
+				)
+			}
+		}
+		if err := ValidateProfileResourceLimits(profile.ResourceLimits); err != nil {
+			return fmt.Errorf("profiles.%s.%w", profileName, err)
+		}
 		// maxResources quantities are validated when unmarshaling into
 		// resource.Quantity; no extra consistency check is required.
 	}
@@ -209,6 +249,41 @@ func validatePlatformTLS(tls *PlatformTLS, envKey, domainKey string) error {
 	return nil
 }
 
+// validatePlatformIngress checks that the routing mode and gatewayRef are
+// consistent and that the Gateway reference uses valid names.
+func validatePlatformIngress(ing *PlatformIngress, envKey, domainKey string) error {
+	prefix := fmt.Sprintf("environments.%s.domains.%s.ingress", envKey, domainKey)
+	switch ing.Mode {
+	case IngressModeIngress:
+		if ing.GatewayRef != nil {
+			return fmt.Errorf("%s: gatewayRef requires mode gateway", prefix)
+		}
+	case IngressModeGateway:
+		ref := ing.GatewayRef
+		if ref == nil || ref.Name == "" {
+			return fmt.Errorf("%s: gatewayRef.name is required when mode is gateway", prefix)
+		}
+		for _, f := range []struct {
+			field, value string
+			check        func(string) []string
+		}{
+			{"name", ref.Name, k8svalidation.IsDNS1123Subdomain},
+			{"namespace", ref.Namespace, k8svalidation.IsDNS1123Label},
+			{"sectionName", ref.SectionName, k8svalidation.IsDNS1123Subdomain},
+		} {
+			if f.value == "" {
+				continue
+			}
+			if msgs := f.check(f.value); len(msgs) > 0 {
+				return fmt.Errorf("%s: gatewayRef.%s %q: %s", prefix, f.field, f.value, strings.Join(msgs, "; "))
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unsupported mode %q (use %s or %s)", prefix, ing.Mode, IngressModeIngress, IngressModeGateway)
+	}
+	return nil
+}
+
 // IsSupportedPlatformVersion reports whether the given platform apiVersion
 // (e.g. "platform/v1-alpha.3") is supported by the current version of
 // Deployah.
@@ -218,11 +293,12 @@ func IsSupportedPlatformVersion(apiVersion string) bool {
 
 // ScaffoldPlatformFile writes a deployah.platform.yaml at path registering
 // the given environment names. "local" gets a full entry (kind-deployah
-// context, nip.io domain, self-signed TLS); every other name gets an empty
+// context, nip.io domain, self-signed TLS, and registryAddr as
+// [LocalRegistryVariable] when set); every other name gets an empty
 // entry with no context, meaning deploys to it follow the kubeconfig
 // current-context until one is set. New files include a `# $schema` modeline.
 // An existing file is left untouched so hand-written comments survive.
-func ScaffoldPlatformFile(path, ingressIP string, envNames []string) (created bool, err error) {
+func ScaffoldPlatformFile(path, ingressIP, registryAddr string, envNames []string) (created bool, err error) {
 	if path == "" {
 		path = DefaultPlatformPath
 	}
@@ -240,7 +316,7 @@ func ScaffoldPlatformFile(path, ingressIP string, envNames []string) (created bo
 		return false, nil
 	}
 
-	if writeErr := writePlatformFile(path, newPlatformEnvironments(ingressIP, envNames)); writeErr != nil {
+	if writeErr := writePlatformFile(path, newPlatformEnvironments(ingressIP, registryAddr, envNames)); writeErr != nil {
 		return false, writeErr
 	}
 	return true, nil
@@ -249,17 +325,18 @@ func ScaffoldPlatformFile(path, ingressIP string, envNames []string) (created bo
 // EnsurePlatformEnvironments creates path or adds missing environment keys.
 //
 // A new file gets a `# $schema` modeline, a full [LocalPlatformEnvironment]
-// for "local", and empty entries for every other name. An existing file is
-// loaded, missing keys are inserted (local as Kind, others empty), and the
-// file is rewritten. Existing keys are never overwritten, including an empty
-// `local: {}`. Merge re-marshals YAML and drops hand-written comments; that
-// is acceptable for init. Cluster up still prints a snippet instead of
-// merging, so comments in an existing file stay intact there.
+// with registryAddr for "local", and empty entries for every other name. An
+// existing file is loaded, missing keys are inserted (local as Kind, others
+// empty), and the file is rewritten. Existing keys are never overwritten,
+// including an empty `local: {}`. Merge re-marshals YAML and drops
+// hand-written comments; that is acceptable for init. Cluster up still
+// prints a snippet instead of merging, so comments in an existing file stay
+// intact there.
 //
 // created is true when the file did not exist and was written. added is the
 // names inserted into an existing file, in envNames order. Both are empty
 // when every requested name was already present.
-func EnsurePlatformEnvironments(path, ingressIP string, envNames []string) (created bool, added []string, err error) {
+func EnsurePlatformEnvironments(path, ingressIP, registryAddr string, envNames []string) (created bool, added []string, err error) {
 	if path == "" {
 		path = DefaultPlatformPath
 	}
@@ -268,7 +345,7 @@ func EnsurePlatformEnvironments(path, ingressIP string, envNames []string) (crea
 	}
 
 	if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
-		if writeErr := writePlatformFile(path, newPlatformEnvironments(ingressIP, envNames)); writeErr != nil {
+		if writeErr := writePlatformFile(path, newPlatformEnvironments(ingressIP, registryAddr, envNames)); writeErr != nil {
 			return false, nil, writeErr
 		}
 		return true, nil, nil
@@ -290,7 +367,7 @@ func EnsurePlatformEnvironments(path, ingressIP string, envNames []string) (crea
 		}
 		added = append(added, name)
 		if name == "local" {
-			platform.Environments[name] = LocalPlatformEnvironment(ingressIP)
+			platform.Environments[name] = LocalPlatformEnvironment(ingressIP, registryAddr)
 			continue
 		}
 		platform.Environments[name] = PlatformEnvironment{}
@@ -304,11 +381,11 @@ func EnsurePlatformEnvironments(path, ingressIP string, envNames []string) (crea
 	return false, added, nil
 }
 
-func newPlatformEnvironments(ingressIP string, envNames []string) *PlatformConfig {
+func newPlatformEnvironments(ingressIP, registryAddr string, envNames []string) *PlatformConfig {
 	envs := make(map[string]PlatformEnvironment, len(envNames))
 	for _, name := range envNames {
 		if name == "local" {
-			envs[name] = LocalPlatformEnvironment(ingressIP)
+			envs[name] = LocalPlatformEnvironment(ingressIP, registryAddr)
 			continue
 		}
 		envs[name] = PlatformEnvironment{}
@@ -15,6 +15,7 @@
 package spec_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -218,6 +219,73 @@ environments:
 	assert.Contains(t, err.Error(), "secretName")
 }
 
+// TestLoadPlatform_GatewayIngress verifies a gateway-mode domain loads with
+// its gatewayRef, and that gatewayRef is required by, and exclusive to,
+// gateway mode.
+func TestLoadPlatform_GatewayIngress(t *testing.T) {
+	t.Parallel()
+
+	const doc = `
+apiVersion: platform/v1-alpha.3
+environments:
+  prod:
+    domains:
+      public:
+        baseDomain: example.com
+        ingress:
+%s
+`
+	tests := []struct {
+		name    string
+		ingress string
+		wantErr string
+	}{
+		{
+			name: "gateway",
+			ingress: `          mode: gateway
+          gatewayRef:
+            name: public
+            namespace: gateway-system
+            sectionName: https`,
+		},
+		{
+			name:    "gateway without gatewayRef",
+			ingress: `          mode: gateway`,
+			wantErr: "gatewayRef",
+		},
+		{
+			name: "ingress with gatewayRef",
+			ingress: `          mode: ingress
+          gatewayRef:
+            name: public`,
+			wantErr: "gatewayRef requires mode gateway",
+		},
+		{
+			name: "invalid gateway namespace",
+			ingress: `          mode: gateway
+          gatewayRef:
+            name: public
+            namespace: Gateway_System`,
+			wantErr: "gatewayRef.namespace",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := writeTempFile(t, fmt.Sprintf(doc, tt.ingress))
+			p, err := spec.LoadPlatform(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &spec.GatewayRef{Name: "public", Namespace: "gateway-system", SectionName: "https"},
+				p.Environments["prod"].Domains["public"].GatewayRef())
+		})
+	}
+}
+
 // TestMatchEnvKey_ExactMatch verifies platform spec behavior.
 func TestMatchEnvKey_ExactMatch(t *testing.T) {
 	keys := []string{"production", "staging", "review"}
@@ -332,6 +400,37 @@ func TestResolve_FQDN(t *testing.T) {
 	assert.Equal(t, "prod-eks", resolved.KubeContext)
 }
 
+// TestResolve_GatewayRef verifies a gateway-mode domain hands its Gateway
+// to exposed components alongside the usual FQDN and TLS mode.
+func TestResolve_GatewayRef(t *testing.T) {
+	appSpec := minimalSpec(new("api"))
+	platform := minimalPlatform()
+	domain := platform.Environments["production"].Domains["public"]
+	domain.Ingress = &spec.PlatformIngress{
+		Mode:       spec.IngressModeGateway,
+		GatewayRef: &spec.GatewayRef{Name: "public", Namespace: "gateway-system"},
+	}
+	platform.Environments["production"].Domains["public"] = domain
+
+	resolved, report, err := spec.Resolve(appSpec, platform, spec.NormalizeEnv("production"), spec.SubstitutionReport{})
+	require.NoError(t, err)
+	rc := resolved.Components["api"]
+	assert.Equal(t, "api.example.com", rc.FQDN)
+	assert.Equal(t, spec.TLSModeCertManager, rc.TLSMode)
+	require.NotNil(t, rc.GatewayRef)
+	assert.Equal(t, "gateway-system/public", rc.GatewayRef.String())
+	assert.Contains(t, report.Fields, spec.ResolvedField{
+		Component: "api",
+		Path:      "expose.gatewayRef",
+		Value:     "gateway-system/public",
+		Source:    "platform environments.production.domains.public.ingress.gatewayRef",
+	})
+
+	resolved, _, err = spec.Resolve(appSpec, minimalPlatform(), spec.NormalizeEnv("production"), spec.SubstitutionReport{})
+	require.NoError(t, err)
+	assert.Nil(t, resolved.Components["api"].GatewayRef, "ingress is the default")
+}
+
 // TestResolve_UnknownEnvironmentNameWarnings verifies typo protection: spec
 // environment overrides and component filter entries that match nothing in
 // the platform registry warn, while prefix-style entries stay warning-free.
@@ -617,6 +716,24 @@ environments:
 	assert.Contains(t, err.Error(), "at most one domain may set default")
 }
 
+// TestLoadPlatform_RejectsUnknownPromoteFrom verifies promoteFrom may only
+// name other registered environments.
+func TestLoadPlatform_RejectsUnknownPromoteFrom(t *testing.T) {
+	t.Parallel()
+
+	path := writeTempFile(t, `apiVersion: platform/v1-alpha.3
+environments:
+  staging:
+    context: staging
+  production:
+    context: prod
+    promoteFrom: [stagign]
+`)
+	_, err := spec.LoadPlatform(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `environments.production.promoteFrom: "stagign" is not a registered environment (available: production, staging)`)
+}
+
 // TestScaffoldPlatformFile_RegistersAllEnvironments verifies that every
 // selected environment is registered: "local" with the full kind entry,
 // the rest as empty entries the user fills in later.
@@ -624,7 +741,7 @@ func TestScaffoldPlatformFile_RegistersAllEnvironments(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "deployah.platform.yaml")
 
-	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", []string{"local", "production"})
+	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", "localhost:5001", []string{"local", "production"})
 	require.NoError(t, err)
 	assert.True(t, created)
 
@@ -638,9 +755,11 @@ func TestScaffoldPlatformFile_RegistersAllEnvironments(t *testing.T) {
 	local, hasLocal := p.Environments["local"]
 	require.True(t, hasLocal)
 	assert.Equal(t, "kind-deployah", local.Context)
+	assert.Equal(t, map[string]string{spec.LocalRegistryVariable: "localhost:5001"}, local.Variables)
 	production, hasProduction := p.Environments["production"]
 	require.True(t, hasProduction)
 	assert.Empty(t, production.Context, "non-local entries are registered without a context")
+	assert.Empty(t, production.Variables)
 }
 
 // TestScaffoldPlatformFile_NoLocalStillCreatesFile verifies a file is
@@ -650,7 +769,7 @@ func TestScaffoldPlatformFile_NoLocalStillCreatesFile(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "deployah.platform.yaml")
 
-	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", []string{"staging", "production"})
+	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", "", []string{"staging", "production"})
 	require.NoError(t, err)
 	assert.True(t, created)
 
@@ -666,7 +785,7 @@ func TestScaffoldPlatformFile_NoEnvironmentsWritesNothing(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "deployah.platform.yaml")
 
-	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", nil)
+	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", "", nil)
 	require.NoError(t, err)
 	assert.False(t, created)
 
@@ -681,7 +800,7 @@ func TestScaffoldPlatformFile_DoesNotOverwriteExisting(t *testing.T) {
 	path := filepath.Join(dir, "deployah.platform.yaml")
 	require.NoError(t, os.WriteFile(path, []byte("apiVersion: platform/v1-alpha.3\nenvironments:\n  prod:\n    context: prod\n"), 0o600))
 
-	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", []string{"local"})
+	created, err := spec.ScaffoldPlatformFile(path, "127.0.0.1", "", []string{"local"})
 	require.NoError(t, err)
 	assert.False(t, created)
 
@@ -697,7 +816,7 @@ func TestEnsurePlatformEnvironments_CreatesAndMerges(t *testing.T) {
 	path := filepath.Join(dir, "deployah.platform.yaml")
 	require.NoError(t, os.WriteFile(path, []byte("apiVersion: platform/v1-alpha.3\nenvironments:\n  prod:\n    context: prod\n"), 0o600))
 
-	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", []string{"local", "staging"})
+	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", "", []string{"local", "staging"})
 	require.NoError(t, err)
 	assert.False(t, created)
 	assert.Equal(t, []string{"local", "staging"}, added)
@@ -708,11 +827,12 @@ func TestEnsurePlatformEnvironments_CreatesAndMerges(t *testing.T) {
 	local, hasLocal := p.Environments["local"]
 	require.True(t, hasLocal)
 	assert.Equal(t, "kind-deployah", local.Context)
+	assert.Empty(t, local.Variables, "no registry address, no REGISTRY variable")
 	staging, hasStaging := p.Environments["staging"]
 	require.True(t, hasStaging)
 	assert.Empty(t, staging.Context)
 
-	created, added, err = spec.EnsurePlatformEnvironments(path, "127.0.0.1", []string{"local", "staging"})
+	created, added, err = spec.EnsurePlatformEnvironments(path, "127.0.0.1", "", []string{"local", "staging"})
 	require.NoError(t, err)
 	assert.False(t, created)
 	assert.Empty(t, added)
@@ -724,7 +844,7 @@ func TestEnsurePlatformEnvironments_AddsOnlyMissingNames(t *testing.T) {
 	path := filepath.Join(dir, "deployah.platform.yaml")
 	require.NoError(t, os.WriteFile(path, []byte("apiVersion: platform/v1-alpha.3\nenvironments:\n  staging:\n    context: existing\n"), 0o600))
 
-	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", []string{"local", "staging", "production"})
+	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", "", []string{"local", "staging", "production"})
 	require.NoError(t, err)
 	assert.False(t, created)
 	assert.Equal(t, []string{"local", "production"}, added)
@@ -742,7 +862,7 @@ func TestEnsurePlatformEnvironments_DoesNotReplaceEmptyLocal(t *testing.T) {
 	path := filepath.Join(dir, "deployah.platform.yaml")
 	require.NoError(t, os.WriteFile(path, []byte("apiVersion: platform/v1-alpha.3\nenvironments:\n  local: {}\n"), 0o600))
 
-	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", []string{"local"})
+	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", "", []string{"local"})
 	require.NoError(t, err)
 	assert.False(t, created)
 	assert.Empty(t, added)
@@ -760,7 +880,7 @@ func TestEnsurePlatformEnvironments_NewFileWritesSchema(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "nested", "deployah.platform.yaml")
 
-	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", []string{"local", "production"})
+	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", "", []string{"local", "production"})
 	require.NoError(t, err)
 	assert.True(t, created)
 	assert.Empty(t, added)
@@ -779,7 +899,7 @@ func TestEnsurePlatformEnvironments_EmptyNames(t *testing.T) {
 	t.Parallel()
 	path := filepath.Join(t.TempDir(), "deployah.platform.yaml")
 
-	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", nil)
+	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", "", nil)
 	require.NoError(t, err)
 	assert.False(t, created)
 	assert.Empty(t, added)
@@ -791,7 +911,7 @@ func TestEnsurePlatformEnvironments_EmptyNames(t *testing.T) {
 func TestEnsurePlatformEnvironments_EmptyPathUsesDefault(t *testing.T) {
 	t.Chdir(t.TempDir())
 
-	created, added, err := spec.EnsurePlatformEnvironments("", "127.0.0.1", []string{"staging"})
+	created, added, err := spec.EnsurePlatformEnvironments("", "127.0.0.1", "", []string{"staging"})
 	require.NoError(t, err)
 	assert.True(t, created)
 	assert.Empty(t, added)
@@ -804,7 +924,7 @@ func TestEnsurePlatformEnvironments_EmptyPathUsesDefault(t *testing.T) {
 func TestScaffoldPlatformFile_EmptyPathUsesDefault(t *testing.T) {
 	t.Chdir(t.TempDir())
 
-	created, err := spec.ScaffoldPlatformFile("", "127.0.0.1", []string{"staging"})
+	created, err := spec.ScaffoldPlatformFile("", "127.0.0.1", "", []string{"staging"})
 	require.NoError(t, err)
 	assert.True(t, created)
 
@@ -815,14 +935,14 @@ func TestScaffoldPlatformFile_EmptyPathUsesDefault(t *testing.T) {
 
 func TestScaffoldPlatformFile_StatError(t *testing.T) {
 	t.Parallel()
-	_, err := spec.ScaffoldPlatformFile(fileAsParentPath(t), "127.0.0.1", []string{"local"})
+	_, err := spec.ScaffoldPlatformFile(fileAsParentPath(t), "127.0.0.1", "", []string{"local"})
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "stat platform file")
 }
 
 func TestEnsurePlatformEnvironments_StatError(t *testing.T) {
 	t.Parallel()
-	_, _, err := spec.EnsurePlatformEnvironments(fileAsParentPath(t), "127.0.0.1", []string{"local"})
+	_, _, err := spec.EnsurePlatformEnvironments(fileAsParentPath(t), "127.0.0.1", "", []string{"local"})
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "stat platform file")
 }
@@ -832,7 +952,7 @@ func TestEnsurePlatformEnvironments_LoadError(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "deployah.platform.yaml")
 	require.NoError(t, os.WriteFile(path, []byte("not: [valid"), 0o600))
 
-	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", []string{"staging"})
+	created, added, err := spec.EnsurePlatformEnvironments(path, "127.0.0.1", "", []string{"staging"})
 	require.Error(t, err)
 	assert.False(t, created)
 	assert.Empty(t, added)
@@ -930,6 +1050,25 @@ func TestPlatformEnvContext(t *testing.T) {
 	}
 }
 
+// TestPlatformEnvRollbackOnFailure verifies the rollback-on-failure default
+// lookup, including wildcard matches and missing entries.
+func TestPlatformEnvRollbackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	platform := &spec.PlatformConfig{
+		APIVersion: "platform/v1-alpha.3",
+		Environments: map[string]spec.PlatformEnvironment{
+			"production": {Context: "prod-eks", RollbackOnFailure: true},
+			"review":     {Context: "staging-eks"},
+		},
+	}
+
+	assert.True(t, spec.PlatformEnvRollbackOnFailure(platform, "production"))
+	assert.False(t, spec.PlatformEnvRollbackOnFailure(platform, "review/pr-42"))
+	assert.False(t, spec.PlatformEnvRollbackOnFailure(platform, "unknown"))
+	assert.False(t, spec.PlatformEnvRollbackOnFailure(nil, "production"))
+}
+
 // TestResolve_ErrorCode_PlatformNotFound verifies platform spec behavior.
 func TestResolve_ErrorCode_PlatformNotFound(t *testing.T) {
 	// Component uses expose but platform is nil.
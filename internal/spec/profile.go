@@ -92,6 +92,9 @@ func ResolveProfileNames(componentProfiles []string, platformProfiles map[string
 //     metrics.interval, metrics.scrapeTimeout): last non-empty wins
 //   - metrics.honorLabels: last non-nil wins
 //   - pvcRetentionPolicy: last non-nil wins (field overlay within the policy)
+//   - disruption: last non-nil wins
+//   - networkPolicy.defaultDeny: last non-nil wins;
+//     networkPolicy.ingressController: last non-empty wins
 //   - allowedDomains: intersection of explicit lists; omitted means no constraint
 //   - maxResources: minimum (strictest) ceiling per resource
 func MergeProfiles(names []string, profiles map[string]PlatformProfile) (PlatformProfile, error) {
@@ -126,6 +129,10 @@ func MergeProfiles(names []string, profiles map[string]PlatformProfile) (Platfor
 		if p.PVCRetentionPolicy != nil {
 			merged.PVCRetentionPolicy = mergePVCRetentionPolicy(merged.PVCRetentionPolicy, p.PVCRetentionPolicy)
 		}
+		if p.Disruption != nil {
+			merged.Disruption = p.Disruption
+		}
+		merged.NetworkPolicy = mergeNetworkPolicy(merged.NetworkPolicy, p.NetworkPolicy)
 		if p.AllowedDomains != nil {
 			if !allowedDomainsSet {
 				merged.AllowedDomains = slices.Clone(p.AllowedDomains)
@@ -135,12 +142,33 @@ func MergeProfiles(names []string, profiles map[string]PlatformProfile) (Platfor
 			}
 		}
 		merged.MaxResources = mergeMaxResources(merged.MaxResources, p.MaxResources)
+		merged.ResourceLimits = mergeResourceLimits(merged.ResourceLimits, p.ResourceLimits)
 		merged.Metrics = mergeProfileMetrics(merged.Metrics, p.Metrics)
 	}
 
 	return merged, nil
 }
 
+// mergeNetworkPolicy overlays overlay onto base: defaultDeny is last
+// non-nil, ingressController last non-empty.
+func mergeNetworkPolicy(base, overlay *ProfileNetworkPolicy) *ProfileNetworkPolicy {
+	if overlay == nil {
+		return base
+	}
+	out := &ProfileNetworkPolicy{}
+	if base != nil {
+		*out = *base
+	}
+	if overlay.DefaultDeny != nil {
+		v := *overlay.DefaultDeny
+		out.DefaultDeny = &v
+	}
+	if overlay.IngressController != "" {
+		out.IngressController = overlay.IngressController
+	}
+	return out
+}
+
 // mergeProfileMetrics overlays overlay onto base. Maps deep-merge, scalars
 // use last-non-empty, bools last-non-nil, and relabel lists concatenate.
 func mergeProfileMetrics(base, overlay *ProfileMetrics) *ProfileMetrics {
@@ -232,6 +260,9 @@ type ProfileTarget struct {
 	// Metrics is the component metrics block. Always nil for tasks, which
 	// cannot enable metrics.
 	Metrics *ComponentMetrics
+	// Containers are the component's sidecars and init containers, keyed
+	// by name. Each is held to maxResources on its own.
+	Containers map[string]Container
 }
 
 func componentProfileTarget(name string, comp Component) ProfileTarget {
@@ -241,9 +272,22 @@ func componentProfileTarget(name string, comp Component) ProfileTarget {
 		Resources:      comp.Resources,
 		ResourcePreset: comp.ResourcePreset,
 		Metrics:        comp.Metrics,
+		Containers:     extraContainers(comp),
 	}
 }
 
+// extraContainers merges a component's sidecars and init containers into
+// one map. Validation keeps their names distinct.
+func extraContainers(comp Component) map[string]Container {
+	if len(comp.Sidecars) == 0 && len(comp.InitContainers) == 0 {
+		return nil
+	}
+	out := make(map[string]Container, len(comp.Sidecars)+len(comp.InitContainers))
+	maps.Copy(out, comp.Sidecars)
+	maps.Copy(out, comp.InitContainers)
+	return out
+}
+
 func taskProfileTarget(name string, task Task) ProfileTarget {
 	return ProfileTarget{
 		Subject:        ProfileSubjectTask,
@@ -313,7 +357,7 @@ func ValidateProfile(
 	}
 
 	if merged.MaxResources != nil {
-		if err := checkResourceCeiling(target, merged.MaxResources); err != nil {
+		if err := checkResourceCeiling(target, &merged); err != nil {
 			return err
 		}
 	}
@@ -325,29 +369,51 @@ func ValidateProfile(
 	return nil
 }
 
-func checkResourceCeiling(target ProfileTarget, max *ProfileMaxResources) error {
-	subject, name := target.Subject, target.Name
-	// Compare against effective requests (explicit resources, named preset, or
-	// the default small preset) so validate/resolve match deploy after Load.
-	req := effectiveResourceRequests(target)
-	if quantitySet(max.CPU) && quantitySet(req.CPU) {
-		if req.CPU.Cmp(*max.CPU) > 0 {
-			return &ResolutionError{
-				Code: ErrCodeProfileResourceExceeded,
-				Message: fmt.Sprintf(
-					"%s %q CPU request %s exceeds profile maxResources.cpu %s",
-					subject, name, req.CPU.String(), max.CPU.String(),
-				),
-			}
+func checkResourceCeiling(target ProfileTarget, profile *PlatformProfile) error {
+	// Compare against effective resources (explicit resources, named preset,
+	// or the default small preset) so validate/resolve match deploy after
+	// Load.
+	subject := fmt.Sprintf("%s %q", target.Subject, target.Name)
+	if err := checkContainerCeiling(subject, effectiveResources(target), profile); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(target.Containers)) {
+		ctr := target.Containers[name]
+		r := presetResources(ctr.Resources, ctr.ResourcePreset, DefaultContainerResourcePreset)
+		if err := checkContainerCeiling(fmt.Sprintf("%s container %q", subject, name), r, profile); err != nil {
+			return err
 		}
 	}
-	if quantitySet(max.Memory) && quantitySet(req.Memory) {
-		if req.Memory.Cmp(*max.Memory) > 0 {
+	return nil
+}
+
+// checkContainerCeiling compares a container's requests, and its limits
+// after the profile's resourceLimits policy, against the profile's
+// maxResources. subject names the container in the error, e.g.
+// `component "api"`.
+func checkContainerCeiling(subject string, r Resources, profile *PlatformProfile) error {
+	type ceiling struct {
+		what, field string
+		value, max  *resource.Quantity
+	}
+	max := profile.MaxResources
+	checks := []ceiling{
+		{"CPU request", "maxResources.cpu", r.CPU, max.CPU},
+		{"memory request", "maxResources.memory", r.Memory, max.Memory},
+	}
+	if limits := r.EffectiveLimits(profile); limits != nil && max.Limits != nil {
+		checks = append(checks,
+			ceiling{"CPU limit", "maxResources.limits.cpu", limits.CPU, max.Limits.CPU},
+			ceiling{"memory limit", "maxResources.limits.memory", limits.Memory, max.Limits.Memory},
+		)
+	}
+	for _, c := range checks {
+		if quantitySet(c.max) && quantitySet(c.value) && c.value.Cmp(*c.max) > 0 {
 			return &ResolutionError{
 				Code: ErrCodeProfileResourceExceeded,
 				Message: fmt.Sprintf(
-					"%s %q memory request %s exceeds profile maxResources.memory %s",
-					subject, name, req.Memory.String(), max.Memory.String(),
+					"%s %s %s exceeds profile %s %s",
+					subject, c.what, c.value.String(), c.field, c.max.String(),
 				),
 			}
 		}
@@ -355,21 +421,19 @@ func checkResourceCeiling(target ProfileTarget, max *ProfileMaxResources) error
 	return nil
 }
 
-// effectiveResourceRequests returns the resource requests deploy would apply:
-// explicit resources when set, otherwise the named or default small preset.
-func effectiveResourceRequests(target ProfileTarget) Resources {
-	if target.Resources.ResourcesSet() {
-		return target.Resources
-	}
-	preset := target.ResourcePreset
-	if preset == "" {
-		preset = ResourcePresetSmall
-	}
-	mapping, ok := ResourcePresetMappings[preset]
-	if !ok {
-		return Resources{}
-	}
-	return mapping["requests"]
+// effectiveResources returns the resources deploy would apply: explicit
+// resources when set, otherwise the named or default small preset.
+func effectiveResources(target ProfileTarget) Resources {
+	return presetResources(target.Resources, target.ResourcePreset, ResourcePresetSmall)
+}
+
+// presetResources returns resources with the preset applied the way
+// [FillSpecWithDefaults] applies it: explicit requests win, otherwise the
+// requests and limits of preset, or of fallback when preset is empty.
+func presetResources(resources Resources, preset, fallback ResourcePreset) Resources {
+	out := resources.Clone()
+	applyResourcePreset(&preset, &out, fallback)
+	return out
 }
 
 func mergeStringMap(base, overlay map[string]string) map[string]string {
@@ -483,14 +547,30 @@ func mergeMaxResources(base, overlay *ProfileMaxResources) *ProfileMaxResources
 		return base
 	}
 	if base == nil {
-		return &ProfileMaxResources{
-			CPU:    cloneQuantity(overlay.CPU),
-			Memory: cloneQuantity(overlay.Memory),
-		}
+		base = &ProfileMaxResources{}
 	}
 	out := &ProfileMaxResources{
 		CPU:    minQuantity(base.CPU, overlay.CPU),
 		Memory: minQuantity(base.Memory, overlay.Memory),
+		Limits: mergeMaxLimits(base.Limits, overlay.Limits),
+	}
+	if !quantitySet(out.CPU) && !quantitySet(out.Memory) && out.Limits == nil {
+		return nil
+	}
+	return out
+}
+
+// mergeMaxLimits keeps the lower ceiling of base and overlay per resource.
+func mergeMaxLimits(base, overlay *ProfileMaxLimits) *ProfileMaxLimits {
+	if base == nil {
+		base = &ProfileMaxLimits{}
+	}
+	if overlay == nil {
+		overlay = &ProfileMaxLimits{}
+	}
+	out := &ProfileMaxLimits{
+		CPU:    minQuantity(base.CPU, overlay.CPU),
+		Memory: minQuantity(base.Memory, overlay.Memory),
 	}
 	if !quantitySet(out.CPU) && !quantitySet(out.Memory) {
 		return nil
@@ -456,6 +456,32 @@ func TestValidateProfile(t *testing.T) {
 		assert.NotContains(t, err.Error(), "component")
 	})
 
+	t.Run("sidecar exceeds ceiling", func(t *testing.T) {
+		t.Parallel()
+		withSidecar := target
+		withSidecar.Containers = map[string]spec.Container{
+			"log-shipper": {Image: "fluent-bit", ResourcePreset: spec.ResourcePresetXLarge},
+		}
+		err := spec.ValidateProfile(withSidecar, spec.PlatformProfile{
+			MaxResources: &spec.ProfileMaxResources{Memory: spec.MustQuantity("2Gi")},
+		}, env, "")
+		require.Error(t, err)
+		var re *spec.ResolutionError
+		require.ErrorAs(t, err, &re)
+		assert.Equal(t, spec.ErrCodeProfileResourceExceeded, re.Code)
+		assert.Contains(t, re.Error(), `component "api" container "log-shipper" memory request`)
+	})
+
+	t.Run("sidecar defaults to nano preset", func(t *testing.T) {
+		t.Parallel()
+		withSidecar := target
+		withSidecar.Containers = map[string]spec.Container{"log-shipper": {Image: "fluent-bit"}}
+		err := spec.ValidateProfile(withSidecar, spec.PlatformProfile{
+			MaxResources: &spec.ProfileMaxResources{CPU: spec.MustQuantity("2000m"), Memory: spec.MustQuantity("1Gi")},
+		}, env, "")
+		require.NoError(t, err)
+	})
+
 	t.Run("memory exceeds ceiling", func(t *testing.T) {
 		t.Parallel()
 		err := spec.ValidateProfile(target, spec.PlatformProfile{
@@ -468,6 +494,25 @@ func TestValidateProfile(t *testing.T) {
 		assert.Contains(t, re.Error(), "memory request")
 	})
 
+	t.Run("limit exceeds ceiling", func(t *testing.T) {
+		t.Parallel()
+		large := spec.ProfileTarget{Subject: spec.ProfileSubjectComponent, Name: "api", ResourcePreset: spec.ResourcePresetLarge}
+		max := &spec.ProfileMaxResources{Limits: &spec.ProfileMaxLimits{Memory: spec.MustQuantity("2Gi")}}
+		err := spec.ValidateProfile(large, spec.PlatformProfile{MaxResources: max}, env, "")
+		require.Error(t, err)
+		var re *spec.ResolutionError
+		require.ErrorAs(t, err, &re)
+		assert.Equal(t, spec.ErrCodeProfileResourceExceeded, re.Code)
+		assert.Contains(t, re.Error(), `component "api" memory limit 3Gi exceeds profile maxResources.limits.memory 2Gi`)
+
+		// Pinning the memory limit to the 2048Mi request brings it in.
+		err = spec.ValidateProfile(large, spec.PlatformProfile{
+			MaxResources:   max,
+			ResourceLimits: &spec.ProfileResourceLimits{Memory: spec.LimitPolicyRequest},
+		}, env, "")
+		require.NoError(t, err)
+	})
+
 	t.Run("storage class with no environment storageClasses", func(t *testing.T) {
 		t.Parallel()
 		err := spec.ValidateProfile(target, spec.PlatformProfile{
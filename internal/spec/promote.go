@@ -0,0 +1,155 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/renameio/v2"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+// CheckPromotion reports whether `deployah promote` may copy the release of
+// environment from into environment to. The two must differ, and when the
+// platform file gives to a promoteFrom list, from must be on it. Both names
+// are matched against the platform's environment keys, so a wildcard
+// instance such as "review/pr-1" counts as "review".
+func CheckPromotion(platform *PlatformConfig, from, to string) error {
+	if from == to {
+		return fmt.Errorf("cannot promote %s to itself", from)
+	}
+	if platform == nil {
+		return nil
+	}
+	keys := slices.Collect(maps.Keys(platform.Environments))
+	toKey, ok := matchEnvKey(to, keys)
+	if !ok {
+		return nil
+	}
+	allowed := platform.Environments[toKey].PromoteFrom
+	if len(allowed) == 0 {
+		return nil
+	}
+	fromKey, ok := matchEnvKey(from, keys)
+	if !ok || !slices.Contains(allowed, fromKey) {
+		return fmt.Errorf("environment %s accepts promotions only from %s (promoteFrom in the platform file), not from %s",
+			to, strings.Join(allowed, ", "), from)
+	}
+	return nil
+}
+
+// WritePromotedImages sets environments.<env>.components.<name>.image to
+// images[name] for every entry of images in the spec file at path, so the
+// promoted references survive the next plain deploy. It edits the YAML
+// document in place: comments, key order, and unrelated fields are kept.
+// env is matched against the spec's environment keys like everywhere else.
+func WritePromotedImages(path, env string, images map[string]string) error {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return fmt.Errorf("%s: writing promoted images back is only supported for YAML specs", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read spec: %w", err)
+	}
+	var doc yamlv3.Node
+	if unmarshalErr := yamlv3.Unmarshal(data, &doc); unmarshalErr != nil {
+		return fmt.Errorf("parse spec %s: %w", path, unmarshalErr)
+	}
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+		return fmt.Errorf("spec %s: top level must be a mapping", path)
+	}
+
+	envs := mappingValue(doc.Content[0], "environments")
+	key, ok := matchEnvKey(env, mappingKeys(envs))
+	if !ok {
+		key = env
+	}
+	components := mappingValue(mappingValue(envs, key), "components")
+	for _, name := range slices.Sorted(maps.Keys(images)) {
+		image := scalarValue(mappingValue(components, name), "image")
+		image.Value = images[name]
+	}
+
+	var buf bytes.Buffer
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if encodeErr := enc.Encode(&doc); encodeErr != nil {
+		return fmt.Errorf("encode spec: %w", encodeErr)
+	}
+	if closeErr := enc.Close(); closeErr != nil {
+		return fmt.Errorf("encode spec: %w", closeErr)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat spec: %w", err)
+	}
+	if writeErr := renameio.WriteFile(path, buf.Bytes(), info.Mode().Perm()); writeErr != nil {
+		return fmt.Errorf("write spec %s: %w", path, writeErr)
+	}
+	return nil
+}
+
+// mappingKeys returns the keys of mapping node m in document order.
+func mappingKeys(m *yamlv3.Node) []string {
+	keys := make([]string, 0, len(m.Content)/2)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		keys = append(keys, m.Content[i].Value)
+	}
+	return keys
+}
+
+// mappingValue returns the mapping stored under key in mapping node m,
+// adding an empty one when the key is missing or holds null.
+func mappingValue(m *yamlv3.Node, key string) *yamlv3.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != key {
+			continue
+		}
+		value := m.Content[i+1]
+		if value.Kind != yamlv3.MappingNode {
+			*value = yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map", HeadComment: value.HeadComment, LineComment: value.LineComment}
+		}
+		return value
+	}
+	value := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// scalarValue returns the scalar stored under key in mapping node m,
+// adding an empty string when the key is missing.
+func scalarValue(m *yamlv3.Node, key string) *yamlv3.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			value := m.Content[i+1]
+			if value.Kind != yamlv3.ScalarNode {
+				*value = yamlv3.Node{Kind: yamlv3.ScalarNode, LineComment: value.LineComment}
+			}
+			value.Tag = "!!str"
+			value.Style = 0
+			return value
+		}
+	}
+	value := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str"}
+	m.Content = append(m.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
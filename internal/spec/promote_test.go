@@ -0,0 +1,129 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"deployah.dev/deployah/internal/spec"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+// TestCheckPromotion verifies promoteFrom paths, including wildcard
+// instances and environments without a restriction.
+func TestCheckPromotion(t *testing.T) {
+	t.Parallel()
+
+	platform := &spec.PlatformConfig{
+		APIVersion: "platform/v1-alpha.3",
+		Environments: map[string]spec.PlatformEnvironment{
+			"review":     {Context: "staging-eks"},
+			"staging":    {Context: "staging-eks", PromoteFrom: []string{"review"}},
+			"production": {Context: "prod-eks", PromoteFrom: []string{"staging"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		platform *spec.PlatformConfig
+		from, to string
+		wantErr  string
+	}{
+		{name: "listed source", platform: platform, from: "staging", to: "production"},
+		{name: "wildcard source", platform: platform, from: "review/pr-42", to: "staging"},
+		{name: "unrestricted target", platform: platform, from: "production", to: "review"},
+		{name: "no platform", from: "staging", to: "production"},
+		{name: "skips a stage", platform: platform, from: "review/pr-42", to: "production", wantErr: "accepts promotions only from staging"},
+		{name: "unknown source", platform: platform, from: "dev", to: "production", wantErr: "not from dev"},
+		{name: "same environment", platform: platform, from: "staging", to: "staging", wantErr: "cannot promote staging to itself"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := spec.CheckPromotion(tt.platform, tt.from, tt.to)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestWritePromotedImages verifies the image overrides land under the
+// target environment and the rest of the file, comments included, is kept.
+func TestWritePromotedImages(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "deployah.yaml")
+	doc := `# shop spec
+apiVersion: v1-alpha.2
+project: shop
+environments:
+  staging: {}
+  production:
+    components:
+      api:
+        replicas: 3 # busy
+components:
+  api:
+    image: ghcr.io/acme/api:${TAG}
+  worker:
+    image: ghcr.io/acme/worker:${TAG}
+`
+	require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+
+	err := spec.WritePromotedImages(path, "production", map[string]string{
+		"api":    "ghcr.io/acme/api@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		"worker": "ghcr.io/acme/worker:1.4.0",
+	})
+	require.NoError(t, err)
+
+	out, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "# shop spec")
+	assert.Contains(t, string(out), "replicas: 3 # busy")
+	assert.Contains(t, string(out), "image: ghcr.io/acme/api:${TAG}")
+
+	var got struct {
+		Environments map[string]struct {
+			Components map[string]map[string]any `yaml:"components"`
+		} `yaml:"environments"`
+	}
+	require.NoError(t, yamlv3.Unmarshal(out, &got))
+	prod := got.Environments["production"].Components
+	assert.Equal(t, "ghcr.io/acme/api@sha256:1111111111111111111111111111111111111111111111111111111111111111", prod["api"]["image"])
+	assert.Equal(t, 3, prod["api"]["replicas"])
+	assert.Equal(t, "ghcr.io/acme/worker:1.4.0", prod["worker"]["image"])
+	assert.Empty(t, got.Environments["staging"].Components)
+}
+
+// TestWritePromotedImages_RejectsJSON verifies JSON specs are left alone.
+func TestWritePromotedImages_RejectsJSON(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "deployah.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"project":"shop"}`), 0o600))
+	err := spec.WritePromotedImages(path, "production", map[string]string{"api": "api:1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only supported for YAML specs")
+}
@@ -49,6 +49,16 @@ func Resolve(
 		Tasks:      make(map[string]ResolvedTask),
 	}
 
+	// Merge the environment's component overrides before anything reads the
+	// components. A spec from Load already has them applied.
+	if err := ApplyComponentOverrides(appSpec, env.Original); err != nil {
+		re := &ResolutionError{Code: ErrCodeInvalidOverride, Message: err.Error()}
+		report.ErrorCode = re.Code
+		report.ErrorMessage = re.Message
+		return nil, report, re
+	}
+	report.Fields = append(report.Fields, appSpec.Overrides...)
+
 	// Resolve Kubernetes context from platform.
 	var platformEnv *PlatformEnvironment
 	if platform != nil {
@@ -65,6 +75,21 @@ func Resolve(
 				Value:  pe.Context,
 				Source: fmt.Sprintf("platform environments.%s.context", matched),
 			})
+			if pe.Namespace != nil {
+				ns, nsErr := pe.Namespace.Render(appSpec.Project, env)
+				if nsErr != nil {
+					re := &ResolutionError{Code: ErrCodeInvalidNamespace, Message: fmt.Sprintf("environments.%s: %v", matched, nsErr)}
+					report.ErrorCode = re.Code
+					report.ErrorMessage = re.Message
+					return nil, report, re
+				}
+				resolved.Namespace = ns
+				report.Fields = append(report.Fields, ResolvedField{
+					Path:   "namespace",
+					Value:  ns,
+					Source: fmt.Sprintf("platform environments.%s.namespace.name", matched),
+				})
+			}
 		} else if env.Original != "" {
 			re := &ResolutionError{
 				Code: ErrCodePlatformEnvNotFound,
@@ -139,6 +164,17 @@ func Resolve(
 		return nil, report, err
 	}
 
+	networkWarnings, err := resolveNetwork(appSpec, env, platform, resolved)
+	if err != nil {
+		if re, ok := errors.AsType[*ResolutionError](err); ok {
+			report.ErrorCode = re.Code
+			report.ErrorMessage = re.Message
+		}
+		return nil, report, err
+	}
+	report.Warnings = append(report.Warnings, networkWarnings...)
+	resolved.Warnings = append(resolved.Warnings, networkWarnings...)
+
 	return resolved, report, nil
 }
 
@@ -341,6 +377,16 @@ func resolveComponent(
 	}
 	rc.FQDN = fqdn
 
+	if ref := domain.GatewayRef(); ref != nil {
+		rc.GatewayRef = ref
+		result.fields = append(result.fields, ResolvedField{
+			Component: name,
+			Path:      "expose.gatewayRef",
+			Value:     ref.String(),
+			Source:    fmt.Sprintf("platform environments.%s.domains.%s.ingress.gatewayRef", env.Original, domainKey),
+		})
+	}
+
 	// Resolve TLS.
 	if domain.TLS != nil {
 		rc.TLSMode = domain.TLS.Mode
@@ -515,7 +561,7 @@ func unknownEnvironmentNameWarnings(appSpec *Spec, registry []string) []string {
 
 // CrossCheckPlatformReferences checks spec references against the platform
 // file without picking an environment. It returns problems (expose.domain
-// keys defined in no platform environment, unknown profile names) and
+// keys defined in no platform environment, unknown profile and peer names) and
 // warnings (environment names unknown to the registry). Domains containing
 // ${VAR} tokens are skipped.
 func CrossCheckPlatformReferences(appSpec *Spec, platform *PlatformConfig) (problems, warnings []string) {
@@ -553,6 +599,16 @@ func CrossCheckPlatformReferences(appSpec *Spec, platform *PlatformConfig) (prob
 			}
 		}
 
+		for _, ref := range slices.Concat(comp.DependsOn, comp.AllowFrom) {
+			_, isComponent := appSpec.Components[ref]
+			_, isTask := appSpec.Tasks[ref]
+			if _, isPeer := platform.Peers[ref]; !isComponent && !isTask && !isPeer {
+				problems = append(problems, fmt.Sprintf(
+					"component %q references %q which is not a component, a task, or a platform peer (peers: %s)",
+					name, ref, joinStrings(slices.Collect(maps.Keys(platform.Peers)))))
+			}
+		}
+
 		if comp.Expose == nil || strings.Contains(comp.Expose.Domain, "${") {
 			continue
 		}
@@ -668,6 +724,40 @@ func PlatformEnvContext(platform *PlatformConfig, envName string) string {
 	return ""
 }
 
+// PlatformEnvRollbackOnFailure reports whether the platform config turns on
+// rollback on failure for the given environment. It is false when the
+// platform is nil or does not register the environment.
+func PlatformEnvRollbackOnFailure(platform *PlatformConfig, envName string) bool {
+	if platform == nil {
+		return false
+	}
+	keys := make([]string, 0, len(platform.Environments))
+	for k := range platform.Environments {
+		keys = append(keys, k)
+	}
+	if matched, ok := matchEnvKey(envName, keys); ok {
+		return platform.Environments[matched].RollbackOnFailure
+	}
+	return false
+}
+
+// PlatformEnvVariables returns the substitution defaults the platform config
+// sets for the given environment, or nil when the platform is nil or does
+// not register the environment.
+func PlatformEnvVariables(platform *PlatformConfig, envName string) map[string]string {
+	if platform == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(platform.Environments))
+	for k := range platform.Environments {
+		keys = append(keys, k)
+	}
+	if matched, ok := matchEnvKey(envName, keys); ok {
+		return platform.Environments[matched].Variables
+	}
+	return nil
+}
+
 func resolveTasks(appSpec *Spec, env EnvIdentity, platform *PlatformConfig, platformEnv *PlatformEnvironment, resolved *ResolvedSpec, report *ResolutionReport) error {
 	weights, err := AssignHookWeights(appSpec.Tasks)
 	if err != nil {
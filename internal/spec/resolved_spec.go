@@ -35,6 +35,9 @@ type ResolvedSpec struct {
 	// KubeContext is the Kubernetes context resolved from the platform file.
 	// Empty when no platform file was loaded.
 	KubeContext string
+	// Namespace is the namespace rendered from the platform environment's
+	// namespace template. Empty when the platform does not place releases.
+	Namespace string
 	// Components holds the per-component resolved data.
 	Components map[string]ResolvedComponent
 	// Tasks holds the per-task resolved data for tasks active in Env.
@@ -55,6 +58,10 @@ type ResolvedComponent struct {
 	TLSIssuer string
 	// TLSSecretName is the pre-existing TLS secret name (secretName mode only).
 	TLSSecretName string
+	// GatewayRef is the Gateway the component's HTTPRoute attaches to. Nil
+	// when the domain renders an Ingress or the component has no expose
+	// block.
+	GatewayRef *GatewayRef
 	// TLSCertPEM and TLSKeyPEM hold a materialized self-signed certificate
 	// (selfSigned mode only), filled in by
 	// [k8s.MaterializeSelfSignedTLS] before rendering. Never written into
@@ -74,6 +81,10 @@ type ResolvedComponent struct {
 	// DomainKey is the logical domain key used for expose resolution.
 	// Empty when the component has no expose block.
 	DomainKey string
+	// Network is the component's connectivity from dependsOn, allowFrom,
+	// expose, and metrics. Rendered as a NetworkPolicy only when
+	// Network.DefaultDeny is set.
+	Network *ComponentNetwork
 }
 
 // ResolvedTask holds the merged, environment-filtered task used for chart
@@ -139,6 +150,9 @@ const (
 	ErrCodeProfileResourceExceeded       = "PROFILE_RESOURCE_EXCEEDED"
 	ErrCodeProfileOptOutBlocked          = "PROFILE_OPT_OUT_BLOCKED"
 	ErrCodeProfileMonitorLabelsMissing   = "PROFILE_MONITOR_LABELS_MISSING"
+	ErrCodePeerNotFound                  = "PEER_NOT_FOUND"
+	ErrCodeInvalidNamespace              = "INVALID_NAMESPACE"
+	ErrCodeInvalidOverride               = "INVALID_OVERRIDE"
 )
 
 // ResolutionError is a resolution error that carries a machine-readable code.
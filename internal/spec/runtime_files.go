@@ -0,0 +1,143 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// RuntimeFiles is the container-facing content of the envFile and configFile
+// fields that apply to one component in one environment. Only files named
+// explicitly in the spec are read; the auto-discovered `.env` candidates
+// used for substitution never reach the container.
+type RuntimeFiles struct {
+	// Env holds the env-file entries without the DPY_VAR_ prefix: the
+	// environment's envFile, overlaid by the component's envFile.
+	Env map[string]string
+	// Config maps a config file's base name to its content. When the
+	// environment and component name files with the same base name, the
+	// component file is deep-merged over the environment file for YAML and
+	// JSON, and replaces it otherwise.
+	Config map[string]string
+}
+
+// Empty reports whether there is nothing to mount.
+func (r *RuntimeFiles) Empty() bool {
+	return r == nil || (len(r.Env) == 0 && len(r.Config) == 0)
+}
+
+// LoadRuntimeFiles reads the envFile and configFile declared for environment
+// (matched with exact-then-prefix semantics) and on component. Paths are
+// relative to the working directory, like the substitution env file. A
+// declared file that is missing is an error.
+func LoadRuntimeFiles(m *Spec, environment string, component Component) (*RuntimeFiles, error) {
+	var env Environment
+	keys := make([]string, 0, len(m.Environments))
+	for k := range m.Environments {
+		keys = append(keys, k)
+	}
+	if matched, ok := matchEnvKey(environment, keys); ok {
+		env = m.Environments[matched]
+	}
+
+	files := &RuntimeFiles{Env: map[string]string{}, Config: map[string]string{}}
+	for _, path := range []string{env.EnvFile, component.EnvFile} {
+		if path == "" {
+			continue
+		}
+		vars, err := parseEnvFile(path, true)
+		if err != nil {
+			return nil, err
+		}
+		_, runtimeVars := filterVariables(vars)
+		maps.Copy(files.Env, runtimeVars)
+	}
+	for _, path := range []string{env.ConfigFile, component.ConfigFile} {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- config file path from spec
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		key := filepath.Base(path)
+		base, exists := files.Config[key]
+		if !exists {
+			files.Config[key] = string(data)
+			continue
+		}
+		merged, err := mergeConfigFile(key, base, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge config file %s: %w", path, err)
+		}
+		files.Config[key] = merged
+	}
+	return files, nil
+}
+
+// mergeConfigFile deep-merges overlay over base when name is a YAML or JSON
+// file and returns overlay unchanged for any other format.
+func mergeConfigFile(name, base, overlay string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return overlay, nil
+	}
+	var baseMap, overlayMap map[string]any
+	if err := yaml.Unmarshal([]byte(base), &baseMap); err != nil {
+		return "", err
+	}
+	if err := yaml.Unmarshal([]byte(overlay), &overlayMap); err != nil {
+		return "", err
+	}
+	merged := deepMergeMaps(baseMap, overlayMap)
+
+	var out []byte
+	var err error
+	if ext == ".json" {
+		out, err = json.MarshalIndent(merged, "", "  ")
+	} else {
+		out, err = yaml.Marshal(merged)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// deepMergeMaps returns base with overlay merged in: nested maps merge key by
+// key, any other overlay value (lists included) replaces the base value.
+func deepMergeMaps(base, overlay map[string]any) map[string]any {
+	out := maps.Clone(base)
+	if out == nil {
+		out = map[string]any{}
+	}
+	for k, v := range overlay {
+		baseChild, baseIsMap := out[k].(map[string]any)
+		overlayChild, overlayIsMap := v.(map[string]any)
+		if baseIsMap && overlayIsMap {
+			out[k] = deepMergeMaps(baseChild, overlayChild)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRuntimeFile writes content to name under dir and returns the path.
+func writeRuntimeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// TestLoadRuntimeFiles_EnvPrecedence verifies the component envFile overlays
+// the environment envFile and DPY_VAR_ substitution keys are dropped.
+func TestLoadRuntimeFiles_EnvPrecedence(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	envFile := writeRuntimeFile(t, dir, ".env.production", "DPY_VAR_TAG=1.2.3\nLOG_LEVEL=info\nREGION=eu\n")
+	compFile := writeRuntimeFile(t, dir, "api.env", "# api\nLOG_LEVEL=debug\n")
+
+	m := &Spec{
+		Environments: map[string]Environment{
+			"production": {EnvFile: envFile},
+		},
+	}
+	files, err := LoadRuntimeFiles(m, "production", Component{EnvFile: compFile})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "REGION": "eu"}, files.Env)
+	assert.Empty(t, files.Config)
+}
+
+// TestLoadRuntimeFiles_ConfigMerge verifies same-named YAML config files are
+// deep-merged with the component winning, and differently named files are
+// both kept.
+func TestLoadRuntimeFiles_ConfigMerge(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	envConfig := writeRuntimeFile(t, dir, "review/config.yaml", "db:\n  host: shared\n  pool: 5\nfeatures: [a]\n")
+	compConfig := writeRuntimeFile(t, dir, "api/config.yaml", "db:\n  pool: 20\nfeatures: [b]\n")
+
+	m := &Spec{
+		Environments: map[string]Environment{
+			"review": {ConfigFile: envConfig},
+		},
+	}
+	files, err := LoadRuntimeFiles(m, "review/pr-42", Component{ConfigFile: compConfig})
+	require.NoError(t, err)
+
+	require.Contains(t, files.Config, "config.yaml")
+	assert.YAMLEq(t, "db:\n  host: shared\n  pool: 20\nfeatures: [b]\n", files.Config["config.yaml"])
+	assert.Empty(t, files.Env)
+}
+
+// TestLoadRuntimeFiles_NonStructuredConfigReplaced verifies formats that
+// cannot be deep-merged are replaced by the component file.
+func TestLoadRuntimeFiles_NonStructuredConfigReplaced(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	envConfig := writeRuntimeFile(t, dir, "env/app.ini", "[db]\nhost=shared\n")
+	compConfig := writeRuntimeFile(t, dir, "api/app.ini", "[db]\nhost=api\n")
+
+	m := &Spec{Environments: map[string]Environment{"staging": {ConfigFile: envConfig}}}
+	files, err := LoadRuntimeFiles(m, "staging", Component{ConfigFile: compConfig})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app.ini": "[db]\nhost=api\n"}, files.Config)
+}
+
+// TestLoadRuntimeFiles_MissingFile verifies a declared but missing file is
+// an error instead of a silently empty mount.
+func TestLoadRuntimeFiles_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "nope.yaml")
+	_, err := LoadRuntimeFiles(&Spec{}, "production", Component{ConfigFile: missing})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nope.yaml")
+
+	_, err = LoadRuntimeFiles(&Spec{}, "production", Component{EnvFile: missing})
+	require.Error(t, err)
+}
+
+// TestLoadRuntimeFiles_NothingDeclared verifies an unmatched environment and
+// a component without files produce an empty result.
+func TestLoadRuntimeFiles_NothingDeclared(t *testing.T) {
+	t.Parallel()
+
+	m := &Spec{Environments: map[string]Environment{"staging": {EnvFile: "/does/not/matter"}}}
+	files, err := LoadRuntimeFiles(m, "production", Component{})
+	require.NoError(t, err)
+	assert.True(t, files.Empty())
+}
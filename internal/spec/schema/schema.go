@@ -13,7 +13,7 @@ import (
 
 // FS is the embedded filesystem containing the schema files.
 //
-//go:embed **/*.json platform/**/*.json
+//go:embed **/*.json platform/**/*.json cluster/**/*.json
 var fs embed.FS
 
 // SchemaType is the type of schema.
@@ -31,12 +31,20 @@ const (
 	SchemaTypeEnvironments SchemaType = "environments"
 	// SchemaTypePlatform is the type of schema for validating platform configs.
 	SchemaTypePlatform SchemaType = "platform"
+	// SchemaTypeCluster is the type of schema for validating local cluster
+	// configs.
+	SchemaTypeCluster SchemaType = "cluster"
 )
 
 // platformSchemaDir is the top-level directory for platform schemas within the
 // embedded FS. It is skipped when iterating manifest schema versions.
 const platformSchemaDir = "platform"
 
+// clusterSchemaDir is the top-level directory for local cluster schemas
+// within the embedded FS. It is skipped when iterating manifest schema
+// versions.
+const clusterSchemaDir = "cluster"
+
 var (
 	// versionRegex matches version strings such as "v1-alpha.5", "v1-beta.2",
 	// and similar pre-release formats.
@@ -69,7 +77,7 @@ func GetEnvironmentsSchema(version string) ([]byte, error) {
 }
 
 // GetManifestSchemas returns a map of version string to manifest schema []byte.
-// It skips the platform/ subdirectory.
+// It skips the platform/ and cluster/ subdirectories.
 func GetManifestSchemas() (map[string][]byte, error) {
 	files, err := fs.ReadDir(".")
 	if err != nil {
@@ -82,8 +90,8 @@ func GetManifestSchemas() (map[string][]byte, error) {
 			continue
 		}
 		version := file.Name()
-		if version == platformSchemaDir {
-			continue // platform schemas live here, not manifest schemas
+		if version == platformSchemaDir || version == clusterSchemaDir {
+			continue // platform and cluster schemas live here, not manifest schemas
 		}
 		schema, schemaErr := GetManifestSchema(version)
 		if schemaErr != nil {
@@ -107,6 +115,17 @@ func GetPlatformSchema(version string) ([]byte, error) {
 	return fs.ReadFile(fileName)
 }
 
+// GetClusterSchema retrieves the JSON schema for validating local cluster
+// configs at a specific version. The schema file must be named
+// "cluster.json" within the cluster/VERSION directory.
+func GetClusterSchema(version string) ([]byte, error) {
+	fileName := clusterSchemaDir + "/" + version + "/" + SchemaTypeCluster.String() + ".json"
+	if _, err := fs.Open(fileName); err != nil {
+		return nil, fmt.Errorf("cluster schema not found for version %s: %w", version, err)
+	}
+	return fs.ReadFile(fileName)
+}
+
 // getSortedVersions returns a sorted slice of version strings (ascending order)
 func getSortedVersions() ([]string, error) {
 	schemas, err := GetManifestSchemas()
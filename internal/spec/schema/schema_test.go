@@ -45,6 +45,21 @@ func (s *SchemaTestSuite) TestGetEnvironmentsSchema_InvalidVersion() {
 	s.Require().Nil(schema)
 }
 
+// TestGetClusterSchema verifies local cluster schema retrieval and that
+// the cluster directory is not mistaken for a manifest version.
+func (s *SchemaTestSuite) TestGetClusterSchema() {
+	schema, err := GetClusterSchema("v1-alpha.1")
+	s.Require().NoError(err)
+	s.Require().NotNil(schema)
+
+	_, err = GetClusterSchema("invalid")
+	s.Require().Error(err)
+
+	schemas, err := GetManifestSchemas()
+	s.Require().NoError(err)
+	s.NotContains(schemas, clusterSchemaDir)
+}
+
 // TestCompareSchemaVersions tests compareSchemaVersions with table-driven
 // subtests.
 func (s *SchemaTestSuite) TestCompareSchemaVersions() {
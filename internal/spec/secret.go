@@ -0,0 +1,139 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SecretProvider names a backend that decrypts a local file into a secret
+// value at render time.
+type SecretProvider string
+
+const (
+	// SecretProviderSOPS decrypts a SOPS-encrypted YAML, JSON, dotenv, or
+	// binary file with the sops CLI.
+	SecretProviderSOPS SecretProvider = "sops"
+	// SecretProviderAge decrypts an age-encrypted file with the age CLI.
+	SecretProviderAge SecretProvider = "age"
+)
+
+// SecretSource is where one secret environment variable comes from: either
+// an existing cluster Secret (SecretRef) or a local encrypted file decrypted
+// by Provider. Exactly one of the two forms is set.
+type SecretSource struct {
+	// SecretRef points at a key of a Secret that already exists in the
+	// release namespace. The value never leaves the cluster.
+	SecretRef *SecretKeyRef `json:"secretRef,omitempty" yaml:"secretRef,omitempty"`
+	// Provider selects the backend that decrypts File.
+	Provider SecretProvider `json:"provider,omitempty" yaml:"provider,omitempty"`
+	// File is the encrypted file, relative to the working directory like
+	// envFile.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+	// Key is a dot-separated path into the decrypted YAML or JSON document
+	// (e.g. "db.password"). Empty uses the whole decrypted file.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// SecretKeyRef names one key of an existing Kubernetes Secret.
+type SecretKeyRef struct {
+	// Name is the Secret name.
+	Name string `json:"name" yaml:"name"`
+	// Key is the data key within the Secret.
+	Key string `json:"key" yaml:"key"`
+}
+
+// IsClusterRef reports whether s refers to an existing cluster Secret
+// rather than a file decrypted at render time.
+func (s SecretSource) IsClusterRef() bool {
+	return s.SecretRef != nil
+}
+
+// String describes the reference without its value, for display in resolve
+// and error messages: "secretRef db-credentials/password" or
+// "sops secrets/prod.enc.yaml#db.password".
+func (s SecretSource) String() string {
+	if s.SecretRef != nil {
+		return fmt.Sprintf("secretRef %s/%s", s.SecretRef.Name, s.SecretRef.Key)
+	}
+	if s.Key == "" {
+		return fmt.Sprintf("%s %s", s.Provider, s.File)
+	}
+	return fmt.Sprintf("%s %s#%s", s.Provider, s.File, s.Key)
+}
+
+// ProviderSecrets returns the names of the component's secrets decrypted
+// at render time, sorted.
+func (c Component) ProviderSecrets() []string {
+	var names []string
+	for name, src := range c.Secrets {
+		if !src.IsClusterRef() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ValidateComponentSecrets checks each secrets entry sets exactly one form,
+// that cluster references are valid Secret names and keys, and that no
+// secret shadows a plain env entry of the same name.
+func ValidateComponentSecrets(component Component) error {
+	var errs []error
+	for name, src := range component.Secrets {
+		if err := ValidateEnvVarName(name); err != nil {
+			errs = append(errs, fmt.Errorf("secrets.%s: %w", name, err))
+			continue
+		}
+		if _, dup := component.Env[name]; dup {
+			errs = append(errs, fmt.Errorf("secrets.%s: also set in env; a variable comes from one place", name))
+		}
+		if err := validateSecretSource(src); err != nil {
+			errs = append(errs, fmt.Errorf("secrets.%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateSecretSource(src SecretSource) error {
+	if src.SecretRef != nil {
+		if src.Provider != "" || src.File != "" || src.Key != "" {
+			return errors.New("secretRef cannot be combined with provider, file, or key")
+		}
+		if msgs := k8svalidation.IsDNS1123Subdomain(src.SecretRef.Name); len(msgs) > 0 {
+			return fmt.Errorf("secretRef.name %q: %s", src.SecretRef.Name, strings.Join(msgs, "; "))
+		}
+		if msgs := k8svalidation.IsConfigMapKey(src.SecretRef.Key); len(msgs) > 0 {
+			return fmt.Errorf("secretRef.key %q: %s", src.SecretRef.Key, strings.Join(msgs, "; "))
+		}
+		return nil
+	}
+	switch src.Provider {
+	case SecretProviderSOPS, SecretProviderAge:
+	case "":
+		return errors.New("set either secretRef or provider")
+	default:
+		return fmt.Errorf("unknown provider %q (use %s or %s)", src.Provider, SecretProviderSOPS, SecretProviderAge)
+	}
+	if strings.TrimSpace(src.File) == "" {
+		return fmt.Errorf("file is required with provider %s", src.Provider)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateComponentSecrets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		secrets map[string]SecretSource
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name: "cluster ref and provider",
+			secrets: map[string]SecretSource{
+				"DATABASE_PASSWORD": {SecretRef: &SecretKeyRef{Name: "db-credentials", Key: "password"}},
+				"STRIPE_KEY":        {Provider: SecretProviderSOPS, File: "secrets/prod.enc.yaml", Key: "stripe.key"},
+				"SIGNING_KEY":       {Provider: SecretProviderAge, File: "secrets/signing.age"},
+			},
+		},
+		{
+			name:    "neither form",
+			secrets: map[string]SecretSource{"TOKEN": {}},
+			wantErr: "set either secretRef or provider",
+		},
+		{
+			name: "both forms",
+			secrets: map[string]SecretSource{"TOKEN": {
+				SecretRef: &SecretKeyRef{Name: "tok", Key: "value"},
+				Provider:  SecretProviderSOPS, File: "x.yaml",
+			}},
+			wantErr: "cannot be combined",
+		},
+		{
+			name:    "invalid secret name",
+			secrets: map[string]SecretSource{"TOKEN": {SecretRef: &SecretKeyRef{Name: "Bad_Name", Key: "value"}}},
+			wantErr: "secretRef.name",
+		},
+		{
+			name:    "provider without file",
+			secrets: map[string]SecretSource{"TOKEN": {Provider: SecretProviderAge}},
+			wantErr: "file is required",
+		},
+		{
+			name:    "unknown provider",
+			secrets: map[string]SecretSource{"TOKEN": {Provider: "vault", File: "x"}},
+			wantErr: `unknown provider "vault"`,
+		},
+		{
+			name:    "shadows env",
+			secrets: map[string]SecretSource{"TOKEN": {Provider: SecretProviderAge, File: "t.age"}},
+			env:     map[string]string{"TOKEN": "plain"},
+			wantErr: "also set in env",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateComponentSecrets(Component{Secrets: tt.secrets, Env: tt.env})
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSecretSourceString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "secretRef db/password", SecretSource{SecretRef: &SecretKeyRef{Name: "db", Key: "password"}}.String())
+	assert.Equal(t, "sops prod.enc.yaml#db.password", SecretSource{Provider: SecretProviderSOPS, File: "prod.enc.yaml", Key: "db.password"}.String())
+	assert.Equal(t, "age token.age", SecretSource{Provider: SecretProviderAge, File: "token.age"}.String())
+}
@@ -32,6 +32,21 @@ const (
 	TaskOnPostDeploy TaskOn = "postDeploy"
 	// TaskOnManual runs the task only via the CLI.
 	TaskOnManual TaskOn = "manual"
+	// TaskOnSchedule runs the task from a CronJob on [Task.Schedule].
+	TaskOnSchedule TaskOn = "schedule"
+)
+
+// ConcurrencyPolicy is how a scheduled task treats a run that starts while
+// the previous one is still going. Values match the CronJob field.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyAllow lets runs overlap.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "Allow"
+	// ConcurrencyPolicyForbid skips a run while the previous one is active.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "Forbid"
+	// ConcurrencyPolicyReplace cancels the active run and starts the new one.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace"
 )
 
 // IsHook reports whether o is a deploy hook (preDeploy or postDeploy).
@@ -39,6 +54,13 @@ func (o TaskOn) IsHook() bool {
 	return o == TaskOnPreDeploy || o == TaskOnPostDeploy
 }
 
+// InChart reports whether tasks with this trigger are rendered into the
+// release: hooks as Jobs, scheduled tasks as CronJobs. Manual tasks exist
+// only in the CLI.
+func (o TaskOn) InChart() bool {
+	return o.IsHook() || o == TaskOnSchedule
+}
+
 // Task is run-to-completion work in a spec.
 type Task struct {
 	// From names a component whose env, envFile, configFile, environments,
@@ -56,7 +78,7 @@ type Task struct {
 	// On selects when the task runs. Required.
 	On TaskOn `json:"on" yaml:"on"`
 	// After lists task names that must finish first in the same On phase.
-	// Not allowed when On is manual.
+	// Not allowed when On is manual or schedule.
 	After []string `json:"after,omitempty" yaml:"after,omitempty"`
 	// Env overlays inherited environment variables.
 	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
@@ -72,7 +94,7 @@ type Task struct {
 	// Profiles lists platform profile names. Replaces the inherited list
 	// when set.
 	Profiles []string `json:"profiles,omitempty" yaml:"profiles,omitempty"`
-	// Resources sets explicit CPU, memory, and storage requests.
+	// Resources sets explicit CPU, memory, and storage requests and limits.
 	Resources Resources `json:"resources,omitzero" yaml:"resources,omitempty"`
 	// ResourcePreset selects a named resource profile when Resources is
 	// empty.
@@ -86,6 +108,24 @@ type Task struct {
 	BackoffLimit *int `json:"backoffLimit,omitempty" yaml:"backoffLimit,omitempty"`
 	// TTLSecondsAfterFinished is seconds to keep a finished run.
 	TTLSecondsAfterFinished *int `json:"ttlSecondsAfterFinished,omitempty" yaml:"ttlSecondsAfterFinished,omitempty"`
+	// Schedule is the five-field cron expression (or a macro such as
+	// @daily) a scheduled task runs on. Required when On is schedule and
+	// not allowed otherwise, like the other schedule fields below.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// ConcurrencyPolicy controls overlapping runs. Defaults to
+	// [DefaultConcurrencyPolicy].
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty" yaml:"concurrencyPolicy,omitempty"`
+	// TimeZone is the IANA time zone Schedule is evaluated in. Empty means
+	// the kube-controller-manager's zone, usually UTC.
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+	// Suspend stops new scheduled runs without removing the CronJob.
+	Suspend bool `json:"suspend,omitempty" yaml:"suspend,omitempty"`
+	// SuccessfulJobsHistoryLimit is how many finished runs to keep. Nil
+	// means the Kubernetes default of 3.
+	SuccessfulJobsHistoryLimit *int `json:"successfulJobsHistoryLimit,omitempty" yaml:"successfulJobsHistoryLimit,omitempty"`
+	// FailedJobsHistoryLimit is how many failed runs to keep. Nil means the
+	// Kubernetes default of 1.
+	FailedJobsHistoryLimit *int `json:"failedJobsHistoryLimit,omitempty" yaml:"failedJobsHistoryLimit,omitempty"`
 }
 
 // Fanout unmarshals a YAML/JSON integer (count, parallelism 1) or
@@ -196,10 +236,10 @@ func (t Task) MergeFrom(parent *Component) Task {
 	if out.ResourcePreset == "" && !out.Resources.ResourcesSet() {
 		out.ResourcePreset = parent.ResourcePreset
 		if parent.Resources.ResourcesSet() {
-			out.Resources = Resources{
-				CPU:              cloneQuantity(parent.Resources.CPU),
-				Memory:           cloneQuantity(parent.Resources.Memory),
-				EphemeralStorage: cloneQuantity(parent.Resources.EphemeralStorage),
+			limits := out.Resources.Limits
+			out.Resources = parent.Resources.Clone()
+			if limits != nil {
+				out.Resources.Limits = limits
 			}
 		}
 	}
@@ -215,7 +255,7 @@ func (t Task) MergeFrom(parent *Component) Task {
 }
 
 // HelmHookEvents returns the Helm hook event list for t.On, or empty for
-// manual tasks.
+// manual and scheduled tasks.
 func (t Task) HelmHookEvents() string {
 	switch t.On {
 	case TaskOnPreDeploy:
@@ -316,6 +356,3 @@ func (m *Spec) componentRef(name string) *Component {
 	cp := parent
 	return &cp
 }
-
-// scheduleOnToken is rejected with a pointer at issue #35.
-const scheduleOnToken = "schedule"
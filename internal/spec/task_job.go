@@ -80,11 +80,7 @@ func NewTaskJobSpec(task Task, count, parallelism int) (TaskJobSpec, error) {
 		Command:      slices.Clone(task.Command),
 		Args:         slices.Clone(task.Args),
 		Env:          maps.Clone(task.Env),
-		Resources: Resources{
-			CPU:              cloneQuantity(task.Resources.CPU),
-			Memory:           cloneQuantity(task.Resources.Memory),
-			EphemeralStorage: cloneQuantity(task.Resources.EphemeralStorage),
-		},
+		Resources:    task.Resources.Clone(),
 	}
 	if task.TTLSecondsAfterFinished != nil {
 		ttl32, ttlErr := toInt32("ttlSecondsAfterFinished", *task.TTLSecondsAfterFinished)
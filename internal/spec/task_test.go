@@ -493,6 +493,7 @@ func TestTask_HelmHookEvents(t *testing.T) {
 		{name: "preDeploy", on: TaskOnPreDeploy, want: "pre-install,pre-upgrade"},
 		{name: "postDeploy", on: TaskOnPostDeploy, want: "post-install,post-upgrade"},
 		{name: "manual", on: TaskOnManual, want: ""},
+		{name: "schedule", on: TaskOnSchedule, want: ""},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -542,11 +543,52 @@ func TestValidateSpecTasks(t *testing.T) {
 			wantErr: "on is required",
 		},
 		{
-			name: "on schedule points at issue 35",
+			name: "valid schedule",
 			spec: shopSpec(map[string]Task{
-				"nightly": {From: "api", On: TaskOn("schedule"), Command: []string{"true"}},
+				"nightly": {
+					From:              "api",
+					On:                TaskOnSchedule,
+					Command:           []string{"report"},
+					Schedule:          "30 2 * * MON-FRI",
+					ConcurrencyPolicy: ConcurrencyPolicyReplace,
+					TimeZone:          "Europe/Berlin",
+				},
+			}),
+		},
+		{
+			name: "schedule required when on is schedule",
+			spec: shopSpec(map[string]Task{
+				"nightly": {From: "api", On: TaskOnSchedule, Command: []string{"true"}},
+			}),
+			wantErr: "schedule is required when on is schedule",
+		},
+		{
+			name: "schedule with bad cron syntax",
+			spec: shopSpec(map[string]Task{
+				"nightly": {From: "api", On: TaskOnSchedule, Command: []string{"true"}, Schedule: "0 25 * * *"},
+			}),
+			wantErr: "hour: 25 is outside 0-23",
+		},
+		{
+			name: "schedule fields on a hook are rejected",
+			spec: shopSpec(map[string]Task{
+				"migrate": {From: "api", On: TaskOnPreDeploy, Command: []string{"true"}, Schedule: "@daily"},
+			}),
+			wantErr: "schedule is only allowed when on is schedule",
+		},
+		{
+			name: "unknown time zone",
+			spec: shopSpec(map[string]Task{
+				"nightly": {From: "api", On: TaskOnSchedule, Command: []string{"true"}, Schedule: "@daily", TimeZone: "Mars/Olympus"},
+			}),
+			wantErr: "not a known IANA time zone",
+		},
+		{
+			name: "after on schedule is rejected",
+			spec: shopSpec(map[string]Task{
+				"nightly": {From: "api", On: TaskOnSchedule, Command: []string{"true"}, Schedule: "@daily", After: []string{"migrate"}},
 			}),
-			wantErr: "issues/35",
+			wantErr: "after is not allowed on schedule tasks",
 		},
 		{
 			name: "on invalid value",
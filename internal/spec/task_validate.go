@@ -68,17 +68,16 @@ func validateTask(name string, task Task, spec *Spec) error {
 	}
 
 	switch task.On {
-	case TaskOnPreDeploy, TaskOnPostDeploy, TaskOnManual:
-	case TaskOn(scheduleOnToken):
-		errs = append(errs, fmt.Errorf("%s: on: schedule is not supported yet (see https://github.com/deployah-dev/deployah/issues/35)", prefix))
+	case TaskOnPreDeploy, TaskOnPostDeploy, TaskOnManual, TaskOnSchedule:
 	case "":
-		errs = append(errs, fmt.Errorf("%s: on is required (preDeploy, postDeploy, or manual)", prefix))
+		errs = append(errs, fmt.Errorf("%s: on is required (preDeploy, postDeploy, manual, or schedule)", prefix))
 	default:
-		errs = append(errs, fmt.Errorf("%s: on %q is invalid (preDeploy, postDeploy, or manual)", prefix, task.On))
+		errs = append(errs, fmt.Errorf("%s: on %q is invalid (preDeploy, postDeploy, manual, or schedule)", prefix, task.On))
 	}
+	errs = append(errs, validateTaskSchedule(prefix, task)...)
 
-	if len(task.After) > 0 && task.On == TaskOnManual {
-		errs = append(errs, fmt.Errorf("%s: after is not allowed on manual tasks", prefix))
+	if len(task.After) > 0 && (task.On == TaskOnManual || task.On == TaskOnSchedule) {
+		errs = append(errs, fmt.Errorf("%s: after is not allowed on %s tasks", prefix, task.On))
 	}
 	for _, dep := range task.After {
 		if strings.TrimSpace(dep) == "" {
@@ -144,6 +143,55 @@ func validateTask(name string, task Task, spec *Spec) error {
 	return nil
 }
 
+// validateTaskSchedule checks the CronJob fields: required and well-formed
+// on scheduled tasks, absent on every other trigger.
+func validateTaskSchedule(prefix string, task Task) []error {
+	var errs []error
+	if task.On != TaskOnSchedule {
+		set := map[string]bool{
+			"schedule":                   task.Schedule != "",
+			"concurrencyPolicy":          task.ConcurrencyPolicy != "",
+			"timeZone":                   task.TimeZone != "",
+			"suspend":                    task.Suspend,
+			"successfulJobsHistoryLimit": task.SuccessfulJobsHistoryLimit != nil,
+			"failedJobsHistoryLimit":     task.FailedJobsHistoryLimit != nil,
+		}
+		for _, field := range slices.Sorted(maps.Keys(set)) {
+			if set[field] {
+				errs = append(errs, fmt.Errorf("%s: %s is only allowed when on is schedule", prefix, field))
+			}
+		}
+		return errs
+	}
+
+	if task.Schedule == "" {
+		errs = append(errs, fmt.Errorf("%s: schedule is required when on is schedule", prefix))
+	} else if err := ValidateCronSchedule(task.Schedule); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+	}
+	switch task.ConcurrencyPolicy {
+	case "", ConcurrencyPolicyAllow, ConcurrencyPolicyForbid, ConcurrencyPolicyReplace:
+	default:
+		errs = append(errs, fmt.Errorf("%s: concurrencyPolicy %q is invalid (Allow, Forbid, or Replace)", prefix, task.ConcurrencyPolicy))
+	}
+	if task.TimeZone != "" {
+		if err := ValidateTimeZone(task.TimeZone); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+	}
+	if task.SuccessfulJobsHistoryLimit != nil {
+		if _, err := toInt32("successfulJobsHistoryLimit", *task.SuccessfulJobsHistoryLimit); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+	}
+	if task.FailedJobsHistoryLimit != nil {
+		if _, err := toInt32("failedJobsHistoryLimit", *task.FailedJobsHistoryLimit); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+	}
+	return errs
+}
+
 func validateTaskAfterGraph(spec *Spec) error {
 	var errs []error
 	tasks := spec.Tasks
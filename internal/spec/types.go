@@ -37,6 +37,10 @@ type Spec struct {
 	Components map[string]Component `json:"components" yaml:"components"`
 	// Tasks is a map of task names to run-to-completion work.
 	Tasks map[string]Task `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	// Overrides records the component fields set by the selected
+	// environment's components block, once it has been applied. It is
+	// never read from or written to the spec file.
+	Overrides []ResolvedField `json:"-" yaml:"-"`
 }
 
 // EnvironmentNames returns the sorted list of environment names defined in the
@@ -62,6 +66,10 @@ type Environment struct {
 	ConfigFile string `json:"configFile,omitempty" yaml:"configFile,omitempty"`
 	// Variables holds inline key-value overrides for this environment.
 	Variables map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	// Components holds per-component overrides for this environment, keyed
+	// by component name. Each is merged onto the component before
+	// validation; see [ApplyComponentOverrides].
+	Components map[string]ComponentOverride `json:"components,omitempty" yaml:"components,omitempty"`
 }
 
 // Component defines a deployable unit in the project.
@@ -95,6 +103,10 @@ type Component struct {
 	Persistence *Persistence `json:"persistence,omitempty" yaml:"persistence,omitempty"`
 	// Autoscaling configures horizontal pod autoscaling.
 	Autoscaling *Autoscaling `json:"autoscaling,omitempty" yaml:"autoscaling,omitempty"`
+	// Disruption sets the PodDisruptionBudget for the component. Overrides
+	// the profile default. Only applies when the component runs at least
+	// two pods.
+	Disruption *Disruption `json:"disruption,omitempty" yaml:"disruption,omitempty"`
 	// Resources sets explicit CPU, memory, and storage requests and limits.
 	Resources Resources `json:"resources,omitzero" yaml:"resources,omitempty"`
 	// ResourcePreset selects a named resource profile when Resources is empty.
@@ -102,12 +114,32 @@ type Component struct {
 	// Expose exposes the component via an ingress rule resolved against the
 	// platform domain configuration. Replaces the former ingress block.
 	Expose *Expose `json:"expose,omitempty" yaml:"expose,omitempty"`
+	// DependsOn lists the components and platform peers this component
+	// connects to. Enforced when a profile sets networkPolicy.defaultDeny.
+	DependsOn []string `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	// AllowFrom lists the components, tasks, and platform peers that may
+	// connect to this component. Enforced when a profile sets
+	// networkPolicy.defaultDeny.
+	AllowFrom []string `json:"allowFrom,omitempty" yaml:"allowFrom,omitempty"`
 	// Profiles lists platform-defined deployment profile names. Multiple
 	// profiles are merged left to right. Requires a profiles section in the
 	// platform file.
 	Profiles []string `json:"profiles,omitempty" yaml:"profiles,omitempty"`
 	// Env sets static environment variables for the container.
 	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Secrets maps environment variable names to secret references: an
+	// existing cluster Secret, or a local encrypted file decrypted at render
+	// time. Decrypted values reach the container through the chart Secret.
+	Secrets map[string]SecretSource `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	// Sidecars are extra containers that run alongside the main container
+	// for the life of the pod, keyed by container name.
+	Sidecars map[string]Container `json:"sidecars,omitempty" yaml:"sidecars,omitempty"`
+	// InitContainers run to completion, in name order, before the main
+	// container and sidecars start. Keyed by container name.
+	InitContainers map[string]Container `json:"initContainers,omitempty" yaml:"initContainers,omitempty"`
+	// VolumeMounts mounts volumes shared with sidecars and init containers
+	// into the main container.
+	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty" yaml:"volumeMounts,omitempty"`
 	// Health configures ready and alive checks for the component.
 	Health *Health `json:"health,omitempty" yaml:"health,omitempty"`
 	// ShutdownTimeout is how long Kubernetes waits after SIGTERM before
@@ -320,10 +352,22 @@ type Metric struct {
 }
 
 // Resources defines the resource requests and limits for the component.
+// The top-level fields are requests; Limits holds the matching limits.
 type Resources struct {
 	CPU              *resource.Quantity `json:"cpu,omitempty" yaml:"cpu,omitempty"`
 	Memory           *resource.Quantity `json:"memory,omitempty" yaml:"memory,omitempty"`
 	EphemeralStorage *resource.Quantity `json:"ephemeralStorage,omitempty" yaml:"ephemeralStorage,omitempty"`
+	// Limits caps what the container may use. When requests come from a
+	// preset, a nil Limits takes the preset's limits too.
+	Limits *ResourceLimits `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+// ResourceLimits defines container resource limits. An unset field means
+// no limit for that resource.
+type ResourceLimits struct {
+	CPU              *resource.Quantity `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory           *resource.Quantity `json:"memory,omitempty" yaml:"memory,omitempty"`
+	EphemeralStorage *resource.Quantity `json:"ephemeralStorage,omitempty" yaml:"ephemeralStorage,omitempty"`
 }
 
 // Expose declares that a component should be accessible via an ingress rule.
@@ -46,6 +46,19 @@ func SentinelSubstituteRaw(data []byte) []byte {
 	})
 }
 
+// cronFormat is the "cron" JSON schema format used by task.schedule.
+// Non-string values are left to the type keyword.
+var cronFormat = &jsonschema.Format{
+	Name: "cron",
+	Validate: func(v any) error {
+		s, ok := v.(string)
+		if !ok {
+			return nil
+		}
+		return ValidateCronSchedule(s)
+	},
+}
+
 // validateJSONAgainstSchema is a helper that validates JSON data against a
 // JSON schema. schemaLoader returns the schema bytes for a given version.
 func validateYAMLAgainstSchema(
@@ -63,6 +76,7 @@ func validateYAMLAgainstSchema(
 	// Compile schema with format assertions enabled
 	compiler := jsonschema.NewCompiler()
 	compiler.AssertFormat()
+	compiler.RegisterFormat(cronFormat)
 
 	schemaID := filepath.Join(version, schemaType.String()+".json")
 	jsonSchema, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaBytes))
@@ -147,8 +161,9 @@ func ValidateComponentResources(component Component) error {
 }
 
 // validateResources reports when resources and resourcePreset are both set,
-// or when a resources block is present but empty. Callers add the subject
-// (component or task name) when they wrap the error.
+// when a resources block is present but empty, or when a limit is below
+// its request. A limits block alone may refine a preset. Callers add the
+// subject (component or task name) when they wrap the error.
 func validateResources(resources Resources, preset ResourcePreset) error {
 	hasResources := resources.ResourcesSet()
 	hasPreset := preset != ""
@@ -165,7 +180,7 @@ func validateResources(resources Resources, preset ResourcePreset) error {
 
 	// Both empty is allowed (will use defaults)
 	// Either one is allowed
-	return nil
+	return validateResourceLimits(resources)
 }
 
 // ValidateComponentAutoscaling validates a component's autoscaling
@@ -419,6 +434,18 @@ func ValidateSpecComponents(spec *Spec) error {
 		if err := ValidateComponentProfiles(component); err != nil {
 			errs = append(errs, fmt.Errorf("component %s: %w", name, err))
 		}
+		if err := ValidateComponentSecrets(component); err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", name, err))
+		}
+		if err := ValidateComponentContainers(name, component); err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", name, err))
+		}
+		if err := ValidateComponentDisruption(component); err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", name, err))
+		}
+		if err := ValidateComponentNetwork(name, component, spec); err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", name, err))
+		}
 	}
 
 	if len(errs) > 0 {
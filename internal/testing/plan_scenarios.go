@@ -245,7 +245,7 @@ func renderManifestFile(t *testing.T, dir, filename string) manifestSide {
 		envIdentity := spec.NormalizeEnv(envName)
 		resolvedSpec, _, resolveErr := spec.Resolve(manifest, platform, envIdentity, spec.SubstitutionReport{})
 		require.NoError(t, resolveErr)
-		require.NoError(t, k8s.MaterializeSelfSignedTLS(ctx, nil, "", resolvedSpec))
+		require.NoError(t, k8s.MaterializeSelfSignedTLS(ctx, nil, nil, "", resolvedSpec))
 		resolved = resolvedSpec
 	}
 
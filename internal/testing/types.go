@@ -227,7 +227,7 @@ func (suite *IntegrationTestSuite) loadAndResolve(t *testing.T, scenario TestSce
 	// matching `deployah plan --offline`. Without this, a selfSigned
 	// expose scenario fails render with "certificate not materialized
 	// before render".
-	if tlsErr := k8s.MaterializeSelfSignedTLS(ctx, nil, "", resolved); tlsErr != nil {
+	if tlsErr := k8s.MaterializeSelfSignedTLS(ctx, nil, nil, "", resolved); tlsErr != nil {
 		return nil, "", nil, platform, tlsErr
 	}
 
@@ -0,0 +1,21 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package triage diagnoses a failed deploy or task run. [Collect] inspects
+// the pods of a release or Job for the usual causes (image pull back-off,
+// crash loops, unschedulable pods, OOM kills, failed probes), adds the
+// last log lines of the failing containers and the Warning events seen
+// while waiting, and returns a [Report] that prints as text and is written
+// as JSON for CI artifacts.
+package triage
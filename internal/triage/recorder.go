@@ -0,0 +1,82 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+
+	"deployah.dev/deployah/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Recorder collects the Warning events of objects named after a prefix,
+// such as the pods of one Job, while it runs. Deploys get the same from
+// [deployah.dev/deployah/internal/cmd/deploy.DeployWatcher.Warnings].
+type Recorder struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	warnings []k8s.DeployEvent
+}
+
+// Record starts recording Warning events for objects named "<prefix>-*"
+// in namespace via [k8s.WatchDeployEvents], until [Recorder.Stop].
+func Record(ctx context.Context, client kubernetes.Interface, namespace, prefix string) (*Recorder, error) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	ch, err := k8s.WatchDeployEvents(watchCtx, client, namespace, prefix)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r := &Recorder{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		for ev := range ch {
+			r.track(ev)
+		}
+	}()
+	return r, nil
+}
+
+// track records ev when it is a Warning, replacing an earlier copy of the
+// same event (same UID) rather than duplicating it.
+func (r *Recorder) track(ev k8s.DeployEvent) {
+	if ev.Type != corev1.EventTypeWarning {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, w := range r.warnings {
+		if w.UID == ev.UID {
+			r.warnings[i] = ev
+			return
+		}
+	}
+	r.warnings = append(r.warnings, ev)
+}
+
+// Stop ends the recording and returns the Warning events seen.
+func (r *Recorder) Stop() []k8s.DeployEvent {
+	r.cancel()
+	<-r.done
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]k8s.DeployEvent(nil), r.warnings...)
+}
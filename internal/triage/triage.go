@@ -0,0 +1,359 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"deployah.dev/deployah/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// formatVersion is the schema version of the JSON report. Bump the minor
+// version for additive changes and the major version for breaking ones.
+const formatVersion = "1.0"
+
+// DefaultLogLines is how many trailing log lines [Collect] keeps per
+// failing container.
+const DefaultLogLines = 20
+
+// Reason classifies one problem found on a pod.
+type Reason string
+
+const (
+	// ReasonImagePull is an image that cannot be pulled (ErrImagePull,
+	// ImagePullBackOff, InvalidImageName).
+	ReasonImagePull Reason = "ImagePullBackOff"
+	// ReasonCrashLoop is a container that keeps exiting and restarting.
+	ReasonCrashLoop Reason = "CrashLoopBackOff"
+	// ReasonUnschedulable is a pod the scheduler cannot place on any node.
+	ReasonUnschedulable Reason = "Unschedulable"
+	// ReasonOOMKilled is a container killed for exceeding its memory limit.
+	ReasonOOMKilled Reason = "OOMKilled"
+	// ReasonProbeFailed is a failing readiness, liveness, or startup probe.
+	ReasonProbeFailed Reason = "ProbeFailed"
+	// ReasonContainerFailed is a container that exited non-zero, such as
+	// the container of a failed task Job.
+	ReasonContainerFailed Reason = "ContainerFailed"
+)
+
+// Finding is one problem on one pod, or on one of its containers.
+type Finding struct {
+	// Object is the pod, as "pod/<name>".
+	Object string `json:"object"`
+	// Component is the Deployah component (or task) the pod belongs to.
+	Component string `json:"component,omitempty"`
+	// Container is the container the problem is on; empty for pod-level
+	// problems and for findings taken from events.
+	Container string `json:"container,omitempty"`
+	// Reason classifies the problem.
+	Reason Reason `json:"reason"`
+	// Message is the Kubernetes explanation, e.g. the scheduler's "0/3
+	// nodes are available" line.
+	Message string `json:"message"`
+	// Logs holds the container's last log lines, oldest first. For a
+	// restarted container they come from the previous, failed run.
+	Logs []string `json:"logs,omitempty"`
+}
+
+// Event is a Warning event seen while the deploy or task was running.
+type Event struct {
+	Object    string    `json:"object"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Report is the diagnosis of one failed deploy or task run.
+type Report struct {
+	FormatVersion string `json:"format_version"`
+	// Operation is what failed, e.g. "deploy" or "run migrate".
+	Operation string `json:"operation"`
+	Namespace string `json:"namespace"`
+	// Selector is the pod label selector the report was collected with.
+	Selector string `json:"selector"`
+	// Error is the failure being diagnosed.
+	Error       string    `json:"error"`
+	CollectedAt time.Time `json:"collected_at"`
+	// PodsInspected is false when the pods could not be listed, so the
+	// findings come from events alone.
+	PodsInspected bool      `json:"pods_inspected"`
+	Findings      []Finding `json:"findings"`
+	Events        []Event   `json:"events"`
+}
+
+// Collect lists the pods matching selector in namespace and reports the
+// problems on them, with the last logLines log lines of each failing
+// container. warnings are the Warning events seen while waiting, e.g.
+// [deployah.dev/deployah/internal/cmd/deploy.DeployWatcher.Warnings]; they
+// are kept in the report and also turned into findings, so a pod that was
+// already replaced (say by Helm's rollback) is still diagnosed. Log fetch
+// failures are logged at debug and leave Logs empty.
+func Collect(ctx context.Context, client kubernetes.Interface, namespace, selector string, warnings []k8s.DeployEvent, logLines int64) (*Report, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+
+	report := New(namespace, selector, warnings)
+	findings := []Finding{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		for _, f := range PodFindings(pod) {
+			if f.Container != "" && wantsLogs(f.Reason) {
+				f.Logs = containerLogs(ctx, client, pod, f.Container, logLines)
+			}
+			findings = append(findings, f)
+		}
+	}
+	for _, f := range report.Findings {
+		if !covered(findings, f) {
+			findings = append(findings, f)
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return strings.Compare(a.Object, b.Object)
+	})
+	report.Findings = findings
+	report.PodsInspected = true
+	return report, nil
+}
+
+// New returns a report holding warnings and the findings [EventFindings]
+// derives from them, for when the pods themselves cannot be inspected.
+func New(namespace, selector string, warnings []k8s.DeployEvent) *Report {
+	events := make([]Event, 0, len(warnings))
+	for _, w := range warnings {
+		events = append(events, Event{Object: w.Object, Reason: w.Reason, Message: w.Message, Count: w.Count, Timestamp: w.Timestamp})
+	}
+	findings := EventFindings(warnings)
+	if findings == nil {
+		findings = []Finding{}
+	}
+	return &Report{
+		FormatVersion: formatVersion,
+		Namespace:     namespace,
+		Selector:      selector,
+		CollectedAt:   time.Now().UTC(),
+		Findings:      findings,
+		Events:        events,
+	}
+}
+
+// PodFindings reports the problems visible in pod's status: an
+// unschedulable pod, and per container image pull failures, crash loops,
+// OOM kills, and non-zero exits. A healthy pod has none.
+func PodFindings(pod *corev1.Pod) []Finding {
+	object := "pod/" + pod.Name
+	component := pod.Labels[k8s.ComponentLabel]
+	var out []Finding
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+			out = append(out, Finding{Object: object, Component: component, Reason: ReasonUnschedulable, Message: cond.Message})
+		}
+	}
+	statuses := slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses)
+	for _, cs := range statuses {
+		if f, ok := containerFinding(cs); ok {
+			f.Object, f.Component, f.Container = object, component, cs.Name
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// containerFinding classifies one container status. OOM kills are checked
+// first, since a crash-looping container killed for memory is better
+// described by the cause than by the back-off.
+func containerFinding(cs corev1.ContainerStatus) (Finding, bool) {
+	for _, term := range []*corev1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+		if term != nil && term.Reason == "OOMKilled" {
+			return Finding{Reason: ReasonOOMKilled, Message: fmt.Sprintf("killed for exceeding its memory limit (restarts: %d)", cs.RestartCount)}, true
+		}
+	}
+	if w := cs.State.Waiting; w != nil {
+		switch w.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+			return Finding{Reason: ReasonImagePull, Message: w.Message}, true
+		case "CrashLoopBackOff":
+			msg := w.Message
+			if last := cs.LastTerminationState.Terminated; last != nil {
+				msg = fmt.Sprintf("exit code %d (restarts: %d): %s", last.ExitCode, cs.RestartCount, w.Message)
+			}
+			return Finding{Reason: ReasonCrashLoop, Message: msg}, true
+		}
+	}
+	if term := cs.State.Terminated; term != nil && term.ExitCode != 0 {
+		msg := fmt.Sprintf("exit code %d", term.ExitCode)
+		if term.Reason != "" {
+			msg += " (" + term.Reason + ")"
+		}
+		if term.Message != "" {
+			msg += ": " + term.Message
+		}
+		return Finding{Reason: ReasonContainerFailed, Message: msg}, true
+	}
+	return Finding{}, false
+}
+
+// EventFindings turns pod Warning events that name a known cause into
+// findings: failed probes, which pod status does not show, and back-offs
+// and scheduling failures of pods that no longer exist.
+func EventFindings(warnings []k8s.DeployEvent) []Finding {
+	var out []Finding
+	for _, ev := range warnings {
+		if !strings.HasPrefix(ev.Object, "pod/") {
+			continue
+		}
+		var reason Reason
+		switch {
+		case ev.Reason == "Unhealthy":
+			reason = ReasonProbeFailed
+		case ev.Reason == "FailedScheduling":
+			reason = ReasonUnschedulable
+		case ev.Reason == "OOMKilling":
+			reason = ReasonOOMKilled
+		case ev.Reason == "Failed" && strings.Contains(ev.Message, "image"),
+			ev.Reason == "BackOff" && strings.Contains(ev.Message, "pulling image"):
+			reason = ReasonImagePull
+		case ev.Reason == "BackOff" && strings.Contains(ev.Message, "restarting failed container"):
+			reason = ReasonCrashLoop
+		default:
+			continue
+		}
+		f := Finding{Object: ev.Object, Reason: reason, Message: ev.Message}
+		if !covered(out, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// covered reports whether findings already has f's reason for f's pod.
+func covered(findings []Finding, f Finding) bool {
+	return slices.ContainsFunc(findings, func(g Finding) bool {
+		return g.Object == f.Object && g.Reason == f.Reason
+	})
+}
+
+// wantsLogs reports whether the container's own output may explain the
+// problem. Image pull and scheduling problems happen before it runs.
+func wantsLogs(r Reason) bool {
+	return r == ReasonCrashLoop || r == ReasonOOMKilled || r == ReasonContainerFailed
+}
+
+// containerLogs returns the last lines of container's log. A container
+// that has restarted is read from its previous run, which is the one that
+// failed.
+func containerLogs(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod, container string, lines int64) []string {
+	if lines <= 0 {
+		return nil
+	}
+	previous := false
+	for _, cs := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if cs.Name == container {
+			previous = cs.RestartCount > 0 && cs.State.Terminated == nil
+		}
+	}
+	stream, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &lines,
+		Previous:  previous,
+	}).Stream(ctx)
+	if err != nil {
+		slog.DebugContext(ctx, "triage: fetch logs failed", "pod", pod.Name, "container", container, "err", err)
+		return nil
+	}
+	defer func() { _ = stream.Close() }()
+	var out []string
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		out = append(out, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		slog.DebugContext(ctx, "triage: read logs failed", "pod", pod.Name, "container", container, "err", err)
+	}
+	return out
+}
+
+// WriteText prints the findings, each followed by its log lines. Events
+// are left out: the commands print them as they fail.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	switch {
+	case len(r.Findings) == 0 && !r.PodsInspected:
+		b.WriteString("Diagnosis: pods could not be inspected and the events name no known cause.\n")
+	case len(r.Findings) == 0:
+		fmt.Fprintf(&b, "Diagnosis: no pod problems found (%s in %s).\n", r.Selector, r.Namespace)
+	}
+	if len(r.Findings) == 0 {
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	fmt.Fprintf(&b, "Diagnosis (%d %s):\n", len(r.Findings), plural(len(r.Findings), "problem", "problems"))
+	for _, f := range r.Findings {
+		target := f.Object
+		if f.Container != "" {
+			target += " container " + f.Container
+		}
+		fmt.Fprintf(&b, "  %s: %s", target, f.Reason)
+		if f.Message != "" {
+			fmt.Fprintf(&b, ": %s", f.Message)
+		}
+		b.WriteString("\n")
+		if len(f.Logs) > 0 {
+			fmt.Fprintf(&b, "    last %d log %s:\n", len(f.Logs), plural(len(f.Logs), "line", "lines"))
+			for _, line := range f.Logs {
+				fmt.Fprintf(&b, "      %s\n", line)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteFile writes the report to path as indented JSON. The file may hold
+// application log lines, so it is only readable by its owner.
+func (r *Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 The Deployah Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"deployah.dev/deployah/internal/k8s"
+	"deployah.dev/deployah/internal/triage"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPod(name string, status corev1.PodStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				"app.kubernetes.io/instance": "shop-prod",
+				k8s.ComponentLabel:           "api",
+			},
+		},
+		Status: status,
+	}
+}
+
+// TestPodFindings covers each problem read from pod status.
+func TestPodFindings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		status      corev1.PodStatus
+		wantReason  triage.Reason
+		wantMessage string
+	}{
+		{
+			name: "image pull",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "api",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: `Back-off pulling image "shop:nope"`}},
+			}}},
+			wantReason:  triage.ReasonImagePull,
+			wantMessage: `Back-off pulling image "shop:nope"`,
+		},
+		{
+			name: "crash loop",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "api",
+				RestartCount:         4,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 1m20s"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			}}},
+			wantReason:  triage.ReasonCrashLoop,
+			wantMessage: "exit code 1 (restarts: 4): back-off 1m20s",
+		},
+		{
+			name: "oom killed wins over crash loop",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "api",
+				RestartCount:         2,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+			}}},
+			wantReason:  triage.ReasonOOMKilled,
+			wantMessage: "killed for exceeding its memory limit (restarts: 2)",
+		},
+		{
+			name: "unschedulable",
+			status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available: 3 Insufficient cpu.",
+				}},
+			},
+			wantReason:  triage.ReasonUnschedulable,
+			wantMessage: "0/3 nodes are available: 3 Insufficient cpu.",
+		},
+		{
+			name: "init container failed",
+			status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "wait-db",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Reason: "Error"}},
+			}}},
+			wantReason:  triage.ReasonContainerFailed,
+			wantMessage: "exit code 2 (Error)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := triage.PodFindings(testPod("shop-prod-api-1", tt.status))
+			require.Len(t, got, 1)
+			assert.Equal(t, "pod/shop-prod-api-1", got[0].Object)
+			assert.Equal(t, "api", got[0].Component)
+			assert.Equal(t, tt.wantReason, got[0].Reason)
+			assert.Equal(t, tt.wantMessage, got[0].Message)
+		})
+	}
+
+	t.Run("healthy pod", func(t *testing.T) {
+		t.Parallel()
+		pod := testPod("shop-prod-api-1", corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "api", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+		})
+		assert.Empty(t, triage.PodFindings(pod))
+	})
+}
+
+// TestEventFindings maps known pod warnings to findings and ignores the
+// rest, once per pod and reason.
+func TestEventFindings(t *testing.T) {
+	t.Parallel()
+
+	got := triage.EventFindings([]k8s.DeployEvent{
+		{Object: "pod/shop-prod-api-1", Reason: "Unhealthy", Message: "Readiness probe failed: HTTP probe failed with statuscode: 503"},
+		{Object: "pod/shop-prod-api-1", Reason: "Unhealthy", Message: "Readiness probe failed: connection refused"},
+		{Object: "pod/shop-prod-api-2", Reason: "FailedScheduling", Message: "0/1 nodes are available"},
+		{Object: "pod/shop-prod-api-3", Reason: "BackOff", Message: "Back-off restarting failed container api"},
+		{Object: "replicaset/shop-prod-api", Reason: "FailedCreate", Message: "quota exceeded"},
+		{Object: "pod/shop-prod-api-4", Reason: "FailedMount", Message: "secret not found"},
+	})
+	require.Len(t, got, 3)
+	assert.Equal(t, triage.Finding{Object: "pod/shop-prod-api-1", Reason: triage.ReasonProbeFailed, Message: "Readiness probe failed: HTTP probe failed with statuscode: 503"}, got[0])
+	assert.Equal(t, triage.ReasonUnschedulable, got[1].Reason)
+	assert.Equal(t, triage.ReasonCrashLoop, got[2].Reason)
+}
+
+// TestCollect lists the release's pods, fetches logs for failing
+// containers, and adds event findings for pods it did not see.
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	crashing := testPod("shop-prod-api-1", corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:         "api",
+		RestartCount: 1,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}})
+	other := testPod("other-prod-api-1", corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  "api",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}})
+	other.Labels["app.kubernetes.io/instance"] = "other-prod"
+	client := fake.NewSimpleClientset(crashing, other)
+	warnings := []k8s.DeployEvent{
+		{Object: "pod/shop-prod-api-1", Reason: "BackOff", Message: "Back-off restarting failed container api"},
+		{Object: "pod/shop-prod-api-0", Reason: "Unhealthy", Message: "Liveness probe failed"},
+	}
+
+	report, err := triage.Collect(t.Context(), client, "default", "app.kubernetes.io/instance=shop-prod", warnings, 5)
+	require.NoError(t, err)
+	assert.True(t, report.PodsInspected)
+	require.Len(t, report.Findings, 2)
+	assert.Equal(t, "pod/shop-prod-api-0", report.Findings[0].Object)
+	assert.Equal(t, triage.ReasonProbeFailed, report.Findings[0].Reason)
+	assert.Equal(t, "pod/shop-prod-api-1", report.Findings[1].Object)
+	assert.Equal(t, triage.ReasonCrashLoop, report.Findings[1].Reason)
+	assert.Equal(t, []string{"fake logs"}, report.Findings[1].Logs)
+	assert.Len(t, report.Events, 2)
+}
+
+// TestReport_WriteText prints findings with their logs.
+func TestReport_WriteText(t *testing.T) {
+	t.Parallel()
+
+	report := triage.New("default", "app.kubernetes.io/instance=shop-prod", nil)
+	report.PodsInspected = true
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Equal(t, "Diagnosis: no pod problems found (app.kubernetes.io/instance=shop-prod in default).\n", buf.String())
+
+	report.Findings = []triage.Finding{{
+		Object:    "pod/shop-prod-api-1",
+		Container: "api",
+		Reason:    triage.ReasonCrashLoop,
+		Message:   "exit code 1 (restarts: 3): back-off 40s",
+		Logs:      []string{"connecting to db", "panic: connection refused"},
+	}}
+	buf.Reset()
+	require.NoError(t, report.WriteText(&buf))
+	assert.Equal(t, `Diagnosis (1 problem):
+  pod/shop-prod-api-1 container api: CrashLoopBackOff: exit code 1 (restarts: 3): back-off 40s
+    last 2 log lines:
+      connecting to db
+      panic: connection refused
+`, buf.String())
+}
+
+// TestReport_WriteFile writes the report as JSON only its owner can read.
+func TestReport_WriteFile(t *testing.T) {
+	t.Parallel()
+
+	report := triage.New("default", "app.kubernetes.io/instance=shop-prod", []k8s.DeployEvent{
+		{Object: "pod/shop-prod-api-1", Reason: "FailedScheduling", Message: "0/1 nodes are available", Count: 3},
+	})
+	report.Operation = "deploy"
+	report.Error = "deploy failed: timed out"
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, report.WriteFile(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path) // #nosec G304 -- test temp file
+	require.NoError(t, err)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "1.0", got["format_version"])
+	assert.Equal(t, "deploy", got["operation"])
+	assert.Equal(t, false, got["pods_inspected"])
+	require.Len(t, got["findings"], 1)
+	require.Len(t, got["events"], 1)
+}